@@ -32,12 +32,24 @@ var (
 			Help: "Number of successful node shutdowns",
 		},
 	)
+	ShutdownVerificationFailures = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "autoscaler_shutdown_verification_failures_total",
+			Help: "Shutdowns after which the node did not go NotReady within shutdownVerifyTimeout",
+		},
+	)
 	EvictionFailures = promauto.NewCounter(
 		prometheus.CounterOpts{
 			Name: "autoscaler_eviction_failures_total",
 			Help: "Number of eviction failures during drain",
 		},
 	)
+	DrainTimeouts = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "autoscaler_drain_timeouts_total",
+			Help: "Drains aborted because they exceeded drainTimeout (or the node's cba.dev/drain-timeout)",
+		},
+	)
 	PoweredOffNodes = promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Name: "cba_powered_off_nodes",
 		Help: "Number of nodes currently marked as powered off",
@@ -50,6 +62,57 @@ var (
 		Name: "power_on_successes_total",
 		Help: "Number of successful power-ons",
 	})
+	MACDrift = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "cba_mac_drift_total",
+		Help: "Pre-shutdown MAC checks where the WOL annotation no longer matched the node's interface",
+	}, []string{"node"})
+	PoweredOffFraction = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "cba_powered_off_fraction",
+		Help: "Fraction of managed nodes currently powered off (0-1)",
+	})
+	PoweredOffCount = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "cba_powered_off_count",
+		Help: "Managed nodes currently powered off, as checked against maxPoweredOff before scale-down",
+	})
+	PoweredOffAlert = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "cba_powered_off_alert",
+		Help: "1 while the powered-off fraction has exceeded alertPoweredOffPerc for alertPoweredOffDuration",
+	})
+	CircuitBreakerOpen = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "cba_circuit_breaker_open",
+		Help: "1 while repeated power-action failures have paused all scaling (circuitBreaker)",
+	})
+	NodePowerWatts = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "cluster_bare_autoscaler_node_power_watts",
+		Help: "Current power draw of a running node as reported by its BMC",
+	}, []string{"node"})
+	NodePoweredOffSeconds = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "cluster_bare_autoscaler_node_powered_off_seconds",
+		Help: "Seconds since a managed node was powered off (cba.dev/was-powered-off); absent for running nodes",
+	}, []string{"node"})
+	ScaleDownEligible = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "cluster_bare_autoscaler_scaledown_eligible",
+		Help: "1 for each managed node currently eligible for scale-down, 0 for the others",
+	}, []string{"node"})
+	AgentReadyRatio = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "cba_agent_ready_ratio",
+		Help: "Ready ratio of CBA's agent DaemonSets on powered-on nodes, checked against minAgentHealthRatio",
+	}, []string{"agent"})
+	PowerOnHostUpSeconds = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "cba_power_on_host_up_seconds",
+		Help:    "Time from power-on until the host answered the reachability probe",
+		Buckets: prometheus.ExponentialBuckets(5, 2, 8),
+	})
+	PowerOnReadySeconds = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "cba_power_on_ready_seconds",
+		Help:    "Time from power-on until the node reported Kubernetes Ready",
+		Buckets: prometheus.ExponentialBuckets(5, 2, 8),
+	})
+	KubeAPIThrottleSeconds = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "cba_kube_api_throttle_seconds",
+		Help:    "Time Kubernetes API requests waited on the client-side rate limiter (kubeAPI.qps/burst)",
+		Buckets: []float64{0.001, 0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5},
+	})
 )
 
 type Interface interface {
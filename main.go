@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"github.com/docent-net/cluster-bare-autoscaler/internal/bootstrap/metrics"
 	"github.com/docent-net/cluster-bare-autoscaler/internal/bootstrap/tracing"
@@ -11,6 +12,8 @@ import (
 	"os"
 	"time"
 
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
 	metricsclient "k8s.io/metrics/pkg/client/clientset/versioned"
 
 	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
@@ -27,16 +30,22 @@ func main() {
 	var (
 		configPath            string
 		dryRunFlag            bool
+		dryRunPowerFlag       bool
+		dryRunK8sFlag         bool
 		dryRunNodeLoad        float64
 		dryRunClusterLoadDown float64
 		dryRunClusterLoadUp   float64
+		planFlag              bool
 	)
 
 	flag.StringVar(&configPath, "config", "./config.yaml", "Path to config file")
 	flag.BoolVar(&dryRunFlag, "dry-run", false, "Run without making actual changes")
+	flag.BoolVar(&dryRunPowerFlag, "dry-run-power", false, "Simulate power-off/on but perform real cordon/drain")
+	flag.BoolVar(&dryRunK8sFlag, "dry-run-k8s", false, "Simulate cordon/drain/annotations but perform real power operations")
 	flag.Float64Var(&dryRunNodeLoad, "dry-run-node-load", -1, "Override normalized load for testing (0.0–1.0)")
 	flag.Float64Var(&dryRunClusterLoadDown, "dry-run-cluster-load-down", -1, "Override scale-down cluster-wide load")
 	flag.Float64Var(&dryRunClusterLoadUp, "dry-run-cluster-load-up", -1, "Override scale-up cluster-wide load")
+	flag.BoolVar(&planFlag, "plan", false, "Print the actions one reconcile loop would take as JSON and exit")
 	flag.Parse()
 
 	if err := tracing.Init("cluster-bare-autoscaler"); err != nil {
@@ -51,12 +60,6 @@ func main() {
 		os.Exit(1)
 	}
 
-	metricsClient, err := metricsclient.NewForConfig(restConfig)
-	if err != nil {
-		slog.Error("failed to init metrics client", "err", err)
-		os.Exit(1)
-	}
-
 	metrics.Init()
 
 	cfg, err := config.Load(configPath)
@@ -65,10 +68,22 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Override with CLI flag if set
-	if dryRunFlag {
-		cfg.DryRun = true
+	// Override with CLI flag if set; reapplied to every reloaded config.
+	applyFlags := func(cfg *config.Config) {
+		if dryRunFlag {
+			cfg.DryRun = true
+		}
+		if dryRunPowerFlag {
+			cfg.DryRunPower = true
+		}
+		if dryRunK8sFlag {
+			cfg.DryRunK8s = true
+		}
+		if planFlag {
+			cfg.DryRun = true // -plan never acts, whatever the config says
+		}
 	}
+	applyFlags(cfg)
 
 	var level slog.Level
 	switch cfg.LogLevel {
@@ -82,20 +97,26 @@ func main() {
 		level = slog.LevelInfo
 	}
 
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: level}))
+	logOut := os.Stdout
+	if planFlag {
+		logOut = os.Stderr // keep stdout for the plan
+	}
+	logger := slog.New(slog.NewJSONHandler(logOut, &slog.HandlerOptions{Level: level}))
 	slog.SetDefault(logger)
 
-	clientset, err := kubeclient.Get()
+	// Both clients share one client-side rate limit so a fast loop can't overwhelm the API server.
+	restConfig = kubeclient.WithRateLimit(restConfig, cfg.KubeAPI.QPS, cfg.KubeAPI.Burst)
+
+	clientset, err := kubernetes.NewForConfig(restConfig)
 	if err != nil {
 		slog.Error("failed to init k8s client", "err", err)
 		os.Exit(1)
 	}
 
-	startHealthEndpoints()
-
-	if cfg.BootstrapCooldownSeconds > 0 {
-		slog.Info("Waiting for bootstrap cooldown", "seconds", cfg.BootstrapCooldownSeconds)
-		time.Sleep(time.Duration(cfg.BootstrapCooldownSeconds) * time.Second)
+	metricsClient, err := metricsclient.NewForConfig(restConfig)
+	if err != nil {
+		slog.Error("failed to init metrics client", "err", err)
+		os.Exit(1)
 	}
 
 	var opts []controller.ReconcilerOption
@@ -109,25 +130,79 @@ func main() {
 		opts = append(opts, controller.WithDryRunClusterLoadUp(dryRunClusterLoadUp))
 	}
 
-	go nodeops.StartMACAnnotationUpdater(clientset, nodeops.MACUpdaterConfig{
-		DryRun:        cfg.DryRun,
-		ManagedLabel:  cfg.NodeLabels.Managed,
-		DisabledLabel: cfg.NodeLabels.Disabled,
-		IgnoreLabels:  cfg.IgnoreLabels,
-		Interval:      cfg.MACDiscoveryInterval,
-		Namespace:     cfg.ShutdownManager.Namespace,
-		PodLabel:      cfg.ShutdownManager.PodLabel,
-		Port:          cfg.ShutdownManager.Port,
-	})
+	if cfg.CustomResource.Enabled {
+		dynamicClient, err := dynamic.NewForConfig(restConfig)
+		if err != nil {
+			slog.Error("failed to init dynamic client", "err", err)
+			os.Exit(1)
+		}
+		opts = append(opts, controller.WithDynamicClient(dynamicClient))
+	}
+
+	if planFlag {
+		if err := printPlan(context.Background(), controller.NewReconciler(cfg, clientset, metricsClient, opts...)); err != nil {
+			slog.Error("plan failed", "err", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	startHealthEndpoints()
+
+	if cfg.BootstrapCooldownSeconds > 0 {
+		slog.Info("Waiting for bootstrap cooldown", "seconds", cfg.BootstrapCooldownSeconds)
+		time.Sleep(time.Duration(cfg.BootstrapCooldownSeconds) * time.Second)
+	}
+
+	if cfg.MigrateLabels.Enabled {
+		// Before NewReconciler, which restores powered-off state from annotations.
+		if n, err := nodeops.MigrateLabels(context.Background(), clientset, cfg); err != nil {
+			slog.Error("Label migration failed", "migrated", n, "err", err)
+		} else {
+			slog.Info("Label migration finished", "migrated", n)
+		}
+	}
 
 	r := controller.NewReconciler(cfg, clientset, metricsClient, opts...)
+	go nodeops.StartMACAnnotationUpdater(clientset, nodeops.NewMACUpdaterConfig(cfg, r.AgentHTTP))
+	http.Handle("/status", r.StatusHandler())
+	if cfg.AdminAPI.Enabled {
+		// Served by the health endpoint server started above.
+		slog.Info("Admin API enabled on :8080/admin/")
+		http.Handle("/admin/", r.AdminHandler())
+	}
 	ctx := context.Background()
+	err = config.Watch(ctx, configPath, func(reloaded *config.Config) {
+		applyFlags(reloaded)
+		r.ApplyConfig(reloaded)
+	})
+	if err != nil {
+		slog.Warn("Config reload disabled; changes need a restart", "err", err)
+	}
+	r.StartPowerDrawPoller(ctx, cfg.PowerDrawPollInterval)
+	r.StartReporter(ctx, cfg.Report.Interval)
+	poll := &controller.AdaptivePoll{Base: cfg.PollInterval, Max: cfg.MaxPollInterval, IdleLoops: cfg.PollBackoffAfter}
 	for {
 		if err := r.Reconcile(ctx); err != nil {
 			slog.Error("reconcile error", "err", err)
 		}
-		time.Sleep(cfg.PollInterval)
+		wait := poll.Next(r.LoopActive())
+		if wait != cfg.PollInterval {
+			slog.Debug("Idle loops; backing off polling", "wait", wait.String())
+		}
+		time.Sleep(wait)
+	}
+}
+
+// printPlan writes the reconciler's plan for the current cluster to stdout as indented JSON.
+func printPlan(ctx context.Context, r *controller.Reconciler) error {
+	plan, err := r.Plan(ctx)
+	if err != nil {
+		return err
 	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(plan)
 }
 
 func startHealthEndpoints() {
@@ -157,11 +232,17 @@ func init() {
 		println("        Path to config file (default \"./config.yaml\")")
 		println("  -dry-run")
 		println("        Run in dry-run mode (no real actions)")
+		println("  -dry-run-power")
+		println("        Simulate power-off/on but perform real cordon/drain")
+		println("  -dry-run-k8s")
+		println("        Simulate cordon/drain/annotations but perform real power operations")
 		println("  -dry-run-node-load float")
 		println("        Override normalized load for testing (0.0–1.0). Skips /load lookup")
 		println("  -dry-run-cluster-load-down float")
 		println("        Override cluster-wide aggregate load for scale-down")
 		println("  -dry-run-cluster-load-up float")
 		println("        Override cluster-wide aggregate load for scale-up")
+		println("  -plan")
+		println("        Print the actions one reconcile loop would take as JSON, then exit (forces dry-run)")
 	}
 }
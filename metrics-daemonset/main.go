@@ -10,8 +10,9 @@ import (
 )
 
 type LoadMetrics struct {
-	Load15   float64 `json:"load15"`
-	CPUCount int     `json:"cpuCount"`
+	Load15            float64 `json:"load15"`
+	CPUCount          int     `json:"cpuCount"`
+	PhysicalCoreCount int     `json:"physicalCoreCount"`
 }
 
 func getLoadAverage() (float64, error) {
@@ -26,18 +27,36 @@ func getLoadAverage() (float64, error) {
 	return strconv.ParseFloat(parts[2], 64) // 15-minute load avg
 }
 
-func getCPUCount() (int, error) {
+// getCPUCounts returns the logical CPU count and the number of distinct physical cores
+// (unique "physical id"/"core id" pairs). When cpuinfo carries no topology, as on some ARM
+// kernels, the physical count equals the logical one.
+func getCPUCounts() (int, int, error) {
 	data, err := os.ReadFile("/proc/cpuinfo")
 	if err != nil {
-		return 0, err
+		return 0, 0, err
 	}
 	count := 0
+	cores := map[string]struct{}{}
+	physicalID, coreID := "", ""
 	for _, line := range strings.Split(string(data), "\n") {
-		if strings.HasPrefix(line, "processor") {
+		key, val, _ := strings.Cut(line, ":")
+		key, val = strings.TrimSpace(key), strings.TrimSpace(val)
+		switch key {
+		case "processor":
 			count++
+			physicalID, coreID = "", ""
+		case "physical id":
+			physicalID = val
+		case "core id":
+			coreID = val
+			cores[physicalID+"/"+coreID] = struct{}{}
 		}
 	}
-	return count, nil
+	physical := len(cores)
+	if physical == 0 {
+		physical = count
+	}
+	return count, physical, nil
 }
 
 func loadHandler(w http.ResponseWriter, r *http.Request) {
@@ -46,12 +65,12 @@ func loadHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "failed to read loadavg", 500)
 		return
 	}
-	cpus, err := getCPUCount()
+	cpus, cores, err := getCPUCounts()
 	if err != nil {
 		http.Error(w, "failed to read cpuinfo", 500)
 		return
 	}
-	resp := LoadMetrics{Load15: load15, CPUCount: cpus}
+	resp := LoadMetrics{Load15: load15, CPUCount: cpus, PhysicalCoreCount: cores}
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(resp)
 }
@@ -0,0 +1,159 @@
+// Package agenthttp provides the HTTP client shared by calls to the in-cluster agents
+// (metrics DaemonSet, WOL agent and shutdown daemon), adding the configured auth.
+package agenthttp
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+)
+
+// tokenTTL bounds how long a bearer token read from a Secret is reused, so rotations are picked up.
+const tokenTTL = 5 * time.Minute
+
+// Client sends agent requests with the configured scheme, headers, token and client certificate.
+// A nil *Client behaves like plain http with http.DefaultClient.
+type Client struct {
+	scheme string
+	http   *http.Client
+}
+
+// NewClient builds a Client from cfg. The bearer token Secret is read lazily on first use.
+func NewClient(cfg config.AgentHTTPConfig, kube kubernetes.Interface) (*Client, error) {
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	if tlsCfg, err := buildTLSConfig(cfg.TLS); err != nil {
+		return nil, err
+	} else if tlsCfg != nil {
+		base.TLSClientConfig = tlsCfg
+	}
+
+	headers := http.Header{}
+	for k, v := range cfg.Headers {
+		headers.Set(k, v)
+	}
+
+	var tokens *tokenSource
+	if cfg.BearerTokenSecret.Name != "" {
+		tokens = &tokenSource{kube: kube, ref: cfg.BearerTokenSecret}
+	}
+
+	scheme := cfg.Scheme
+	if scheme == "" {
+		scheme = "http"
+	}
+	return &Client{
+		scheme: scheme,
+		http: &http.Client{Transport: &authTransport{
+			base:    base,
+			headers: headers,
+			tokens:  tokens,
+		}},
+	}, nil
+}
+
+// URL builds the agent URL for host:port and path (which may carry a query string).
+func (c *Client) URL(host string, port int, path string) string {
+	scheme := "http"
+	if c != nil {
+		scheme = c.scheme
+	}
+	return fmt.Sprintf("%s://%s:%d%s", scheme, host, port, path)
+}
+
+// Do sends req through the configured transport.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	if c == nil {
+		return http.DefaultClient.Do(req)
+	}
+	return c.http.Do(req)
+}
+
+func buildTLSConfig(cfg config.AgentTLSConfig) (*tls.Config, error) {
+	if cfg.CertFile == "" && cfg.CAFile == "" && !cfg.InsecureSkipVerify {
+		return nil, nil
+	}
+	tlsCfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	}
+	if cfg.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading agent client certificate: %w", err)
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading agent CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("agent CA file %s contains no certificates", cfg.CAFile)
+		}
+		tlsCfg.RootCAs = pool
+	}
+	return tlsCfg, nil
+}
+
+type authTransport struct {
+	base    http.RoundTripper
+	headers http.Header
+	tokens  *tokenSource
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for k, vals := range t.headers {
+		req.Header[k] = vals
+	}
+	if t.tokens != nil {
+		token, err := t.tokens.Token(req.Context())
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// tokenSource reads the bearer token from a Secret and caches it for tokenTTL.
+type tokenSource struct {
+	kube kubernetes.Interface
+	ref  config.SecretKeyRef
+
+	mu      sync.Mutex
+	token   string
+	fetched time.Time
+}
+
+func (s *tokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && time.Since(s.fetched) < tokenTTL {
+		return s.token, nil
+	}
+	secret, err := s.kube.CoreV1().Secrets(s.ref.Namespace).Get(ctx, s.ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("reading agent token secret %s/%s: %w", s.ref.Namespace, s.ref.Name, err)
+	}
+	raw, ok := secret.Data[s.ref.Key]
+	if !ok {
+		return "", fmt.Errorf("agent token secret %s/%s has no key %q", s.ref.Namespace, s.ref.Name, s.ref.Key)
+	}
+	s.token, s.fetched = strings.TrimSpace(string(raw)), time.Now()
+	return s.token, nil
+}
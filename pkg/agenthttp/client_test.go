@@ -0,0 +1,172 @@
+package agenthttp_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/agenthttp"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+)
+
+func hostPort(t *testing.T, rawURL string) (string, int) {
+	t.Helper()
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatalf("parse url: %v", err)
+	}
+	host, portStr, _ := net.SplitHostPort(u.Host)
+	port, _ := strconv.Atoi(portStr)
+	return host, port
+}
+
+func get(t *testing.T, c *agenthttp.Client, serverURL string) *http.Response {
+	t.Helper()
+	host, port := hostPort(t, serverURL)
+	req, _ := http.NewRequest(http.MethodGet, c.URL(host, port, "/load"), nil)
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	return resp
+}
+
+func TestClient_SendsHeadersAndSecretToken(t *testing.T) {
+	var got http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+	}))
+	defer srv.Close()
+
+	kube := fake.NewSimpleClientset(&v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent-auth", Namespace: "cba"},
+		Data:       map[string][]byte{"token": []byte("s3cret\n")},
+	})
+	c, err := agenthttp.NewClient(config.AgentHTTPConfig{
+		Scheme:            "http",
+		Headers:           map[string]string{"X-Cluster": "lab"},
+		BearerTokenSecret: config.SecretKeyRef{Namespace: "cba", Name: "agent-auth", Key: "token"},
+	}, kube)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	get(t, c, srv.URL)
+	if got.Get("X-Cluster") != "lab" {
+		t.Errorf("X-Cluster = %q, want lab", got.Get("X-Cluster"))
+	}
+	if got.Get("Authorization") != "Bearer s3cret" {
+		t.Errorf("Authorization = %q, want %q", got.Get("Authorization"), "Bearer s3cret")
+	}
+}
+
+func TestClient_MissingSecretFailsRequest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request should not reach the server without a token")
+	}))
+	defer srv.Close()
+
+	c, err := agenthttp.NewClient(config.AgentHTTPConfig{
+		BearerTokenSecret: config.SecretKeyRef{Namespace: "cba", Name: "missing", Key: "token"},
+	}, fake.NewSimpleClientset())
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	host, port := hostPort(t, srv.URL)
+	req, _ := http.NewRequest(http.MethodGet, c.URL(host, port, "/load"), nil)
+	if _, err := c.Do(req); err == nil {
+		t.Fatal("expected error for missing token secret")
+	}
+}
+
+func TestClient_PresentsClientCertificate(t *testing.T) {
+	var peerCN string
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) > 0 {
+			peerCN = r.TLS.PeerCertificates[0].Subject.CommonName
+		}
+	}))
+	srv.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	srv.StartTLS()
+	defer srv.Close()
+
+	dir := t.TempDir()
+	certFile, keyFile := writeClientCert(t, dir, "cba-controller")
+	caFile := filepath.Join(dir, "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	if err := os.WriteFile(caFile, caPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := agenthttp.NewClient(config.AgentHTTPConfig{
+		Scheme: "https",
+		TLS:    config.AgentTLSConfig{CertFile: certFile, KeyFile: keyFile, CAFile: caFile},
+	}, fake.NewSimpleClientset())
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	get(t, c, srv.URL)
+	if peerCN != "cba-controller" {
+		t.Errorf("server saw client cert CN %q, want cba-controller", peerCN)
+	}
+}
+
+func TestClient_NilUsesPlainHTTP(t *testing.T) {
+	var c *agenthttp.Client
+	if got := c.URL("10.0.0.1", 9100, "/load"); got != "http://10.0.0.1:9100/load" {
+		t.Errorf("URL = %q", got)
+	}
+}
+
+func writeClientCert(t *testing.T, dir, cn string) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile, keyFile := filepath.Join(dir, "client.pem"), filepath.Join(dir, "client-key.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
@@ -0,0 +1,66 @@
+// Package v1alpha1 holds the ClusterBareAutoscaler custom resource, a cluster-scoped object that
+// exposes CBA's live state via `kubectl get cba` and lets operators adjust a few settings without
+// editing the config file. The controller talks to it through the dynamic client, so no generated
+// clientset is needed.
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	Group   = "cba.dev"
+	Version = "v1alpha1"
+	Kind    = "ClusterBareAutoscaler"
+)
+
+// Resource identifies clusterbareautoscalers.cba.dev/v1alpha1 for the dynamic client.
+var Resource = schema.GroupVersionResource{Group: Group, Version: Version, Resource: "clusterbareautoscalers"}
+
+// Circuit-breaker states reported in status. The breaker opens, pausing scaling, while
+// circuitBreaker.cooldown runs after repeated power-action failures. Agent health, which also
+// pauses scaling, is reported separately in agentsHealthy.
+const (
+	CircuitClosed = "Closed"
+	CircuitOpen   = "Open"
+)
+
+type ClusterBareAutoscaler struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterBareAutoscalerSpec   `json:"spec,omitempty"`
+	Status ClusterBareAutoscalerStatus `json:"status,omitempty"`
+}
+
+// ClusterBareAutoscalerSpec mirrors config fields that can be changed at runtime.
+// Unset fields leave the config file value in effect.
+type ClusterBareAutoscalerSpec struct {
+	// Paused stops all scale-up, scale-down and maintenance actions; status is still reported.
+	Paused bool `json:"paused,omitempty"`
+	// MinNodes overrides the static minNodes; minNodesSource still takes precedence when set.
+	MinNodes *int `json:"minNodes,omitempty"`
+	// ScaleDownBuffer overrides scaleDownBuffer.
+	ScaleDownBuffer *int `json:"scaleDownBuffer,omitempty"`
+	// NodeThreshold, ScaleDownThreshold and ScaleUpThreshold override the loadAverageStrategy
+	// thresholds of the same name. Overrides that leave scaleUpThreshold at or below
+	// scaleDownThreshold are ignored.
+	NodeThreshold      *float64 `json:"nodeThreshold,omitempty"`
+	ScaleDownThreshold *float64 `json:"scaleDownThreshold,omitempty"`
+	ScaleUpThreshold   *float64 `json:"scaleUpThreshold,omitempty"`
+}
+
+// ClusterBareAutoscalerStatus is rewritten by the controller at the end of every loop.
+type ClusterBareAutoscalerStatus struct {
+	ActiveNodes     int          `json:"activeNodes"`
+	PoweredOffNodes []string     `json:"poweredOffNodes"`
+	MinNodes        int          `json:"minNodes"`
+	Paused          bool         `json:"paused"`
+	LastAction      string       `json:"lastAction,omitempty"` // "scale-up" or "scale-down"
+	LastActionNode  string       `json:"lastActionNode,omitempty"`
+	LastActionTime  *metav1.Time `json:"lastActionTime,omitempty"`
+	CircuitBreaker  string       `json:"circuitBreaker"`
+	AgentsHealthy   bool         `json:"agentsHealthy"` // false while agents below minAgentHealthRatio pause scaling
+	ObservedTime    metav1.Time  `json:"observedTime"`
+}
@@ -1,11 +1,71 @@
 package config
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"os"
+	"path"
+	"slices"
+	"strings"
+	"text/template"
 	"time"
 
 	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	LoadSourceAgent     = "agent"
+	LoadSourceSynthetic = "synthetic"
+)
+
+const (
+	MACDriftRefresh = "refresh"
+	MACDriftBlock   = "block"
+)
+
+const (
+	LoadUnavailableFailClosed = "failClosed"
+	LoadUnavailableFailOpen   = "failOpen"
+)
+
+const (
+	LoadNormalizationPerLogicalCore  = "perLogicalCore"  // load15 / logical CPUs
+	LoadNormalizationPerPhysicalCore = "perPhysicalCore" // load15 / physical cores
+	LoadNormalizationAbsolute        = "absolute"        // raw load15; thresholds are absolute run-queue lengths
+)
+
+const (
+	AggregateDenominatorOnNodes    = "onNodes"    // only nodes reporting load
+	AggregateDenominatorAllManaged = "allManaged" // powered-off managed nodes count as zero load
+)
+
+const (
+	MissingMetricsSkip        = "skip"        // nodes without a metrics pod are left out of load decisions
+	MissingMetricsTreatAsHigh = "treatAsHigh" // they count as busy, blocking scale-down
+	MissingMetricsTreatAsZero = "treatAsZero" // they count as idle
+)
+
+const (
+	ScaleUpPolicyLongestOff  = "longestOff"  // longest powered-off node first (wear leveling)
+	ScaleUpPolicyFastestBoot = "fastestBoot" // lowest recorded boot time first (time to capacity)
+)
+
+const (
+	ScaleUpPreferenceOldest = "oldest" // wake the node powered off longest ago
+	ScaleUpPreferenceNewest = "newest" // wake the node powered off most recently (least cold-start risk)
+)
+
+const (
+	CordonedOnActionPowerOff = "powerOff" // power off if drained, otherwise uncordon
+	CordonedOnActionUncordon = "uncordon" // always revert the cordon
+)
+
+const (
+	CordonModeUnschedulable = "unschedulable" // set spec.unschedulable
+	CordonModeTaint         = "taint"         // add the cba.dev/powering-off:NoSchedule taint instead
 )
 
 type NodeConfig struct {
@@ -16,49 +76,513 @@ type NodeConfig struct {
 }
 
 type NodeLabelConfig struct {
-	Managed  string `yaml:"managed"`
-	Disabled string `yaml:"disabled"`
+	Managed string `yaml:"managed"`
+	// ManagedSelector is a label selector (e.g. "node-pool in (batch,ci)") that, when set, selects
+	// managed nodes instead of requiring Managed == "true".
+	ManagedSelector string `yaml:"managedSelector,omitempty"`
+	Disabled        string `yaml:"disabled"`
+}
+
+// Selector parses ManagedSelector; it returns nil when unset.
+func (l NodeLabelConfig) Selector() (labels.Selector, error) {
+	if l.ManagedSelector == "" {
+		return nil, nil
+	}
+	return labels.Parse(l.ManagedSelector)
+}
+
+// LabelMigrationConfig drives the one-off startup pass that moves nodes to the current label scheme.
+type LabelMigrationConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Labels maps old label keys to new ones, e.g. scaling-managed-by-cba: cba.dev/is-managed.
+	// Values are carried over; the old key is removed.
+	Labels map[string]string `yaml:"labels"`
 }
 
+// DefaultScaleDownDisabledAnnotation is the cluster-autoscaler annotation honored by default.
+const DefaultScaleDownDisabledAnnotation = "cluster-autoscaler.kubernetes.io/scale-down-disabled"
+
 type NodeAnnotationConfig struct {
 	MAC string `yaml:"mac"`
+	// ScaleDownDisabled keeps nodes annotated with it set to "true" out of scale-down candidacy;
+	// defaults to DefaultScaleDownDisabledAnnotation.
+	ScaleDownDisabled string `yaml:"scaleDownDisabled"`
 }
 
 type Config struct {
+	// Revision identifies the config file contents this Config was parsed from (a short SHA-256).
+	Revision string `yaml:"-"`
+
 	LogLevel string `yaml:"logLevel"`
 
-	MinNodes        int                  `yaml:"minNodes"`
-	Cooldown        time.Duration        `yaml:"cooldown"`
-	BootCooldown    time.Duration        `yaml:"bootCooldown"`
-	PollInterval    time.Duration        `yaml:"pollInterval"`
-	IgnoreLabels    map[string]string    `yaml:"ignoreLabels"`
-	NodeLabels      NodeLabelConfig      `yaml:"nodeLabels"`
-	NodeAnnotations NodeAnnotationConfig `yaml:"nodeAnnotations"`
+	MinNodes       int               `yaml:"minNodes"`
+	MinNodesSource ValueSourceConfig `yaml:"minNodesSource,omitempty"` // optional dynamic override of minNodes
+	// AbsoluteMinNodes is a hard floor on Ready managed nodes: no scale-down (maintenance included)
+	// runs while only this many are Ready. Unlike minNodes it ignores eligibility filtering; 0 disables.
+	AbsoluteMinNodes int `yaml:"absoluteMinNodes"`
+	// ScaleDownBuffer stops load-driven scale-down this many nodes above minNodes.
+	ScaleDownBuffer int `yaml:"scaleDownBuffer"`
+	// NodeShutdownPriority weights scale-down candidates: each key ("name" or "name=value") is matched
+	// against node labels and annotations, a node takes its highest matching weight (0 without a
+	// match), and the highest-weighted eligible node is powered off first.
+	NodeShutdownPriority map[string]int `yaml:"nodeShutdownPriority"`
+	// MaxPoweredOff caps how many managed nodes may be powered off at once, on every power-off path
+	// except maintenance; 0 means no cap.
+	MaxPoweredOff int           `yaml:"maxPoweredOff"`
+	Cooldown      time.Duration `yaml:"cooldown"`
+	BootCooldown  time.Duration `yaml:"bootCooldown"`
+	PollInterval  time.Duration `yaml:"pollInterval"`
+	// MaxPollInterval enables adaptive polling: after PollBackoffAfter consecutive idle loops the
+	// wait doubles each loop up to this value. 0 keeps pollInterval fixed.
+	MaxPollInterval  time.Duration        `yaml:"maxPollInterval"`
+	PollBackoffAfter int                  `yaml:"pollBackoffAfter"` // idle loops before backing off; default 3
+	IgnoreLabels     map[string]string    `yaml:"ignoreLabels"`
+	ExcludeTaints    []TaintMatch         `yaml:"excludeTaints"` // tainted nodes are never scaled down but count as active
+	NodeLabels       NodeLabelConfig      `yaml:"nodeLabels"`
+	NodeAnnotations  NodeAnnotationConfig `yaml:"nodeAnnotations"`
+	// MigrateLabels relabels nodes from an old label scheme at startup and clears CBA state
+	// annotations from nodes that no longer match nodeLabels.managed.
+	MigrateLabels LabelMigrationConfig `yaml:"migrateLabels"`
+
+	// DesiredNodeCountSource, when set, declares the number of running nodes to converge to,
+	// replacing load-based scale decisions.
+	DesiredNodeCountSource ValueSourceConfig `yaml:"desiredNodeCountSource,omitempty"`
+
+	// PostScaleUpScaleDownHold suppresses scale-down for this long after any node is powered on.
+	PostScaleUpScaleDownHold time.Duration `yaml:"postScaleUpScaleDownHold"`
+
+	// PostCordonDelaySeconds waits between cordoning a node and evicting its pods,
+	// giving the scheduler time to stop targeting it.
+	PostCordonDelaySeconds int `yaml:"postCordonDelaySeconds"`
+
+	// DrainTimeout bounds one cordon-and-drain, post-cordon wait included; 0 means no limit.
+	// DrainGracePeriod overrides each evicted pod's termination grace period; 0 keeps the pod's own.
+	// Nodes can override both with cba.dev/drain-timeout and cba.dev/drain-grace.
+	DrainTimeout     time.Duration `yaml:"drainTimeout"`
+	DrainGracePeriod time.Duration `yaml:"drainGracePeriod"`
+	// DrainGracePeriodSeconds is drainGracePeriod in whole seconds, in the style of
+	// terminationGracePeriodSeconds; set one or the other.
+	DrainGracePeriodSeconds int `yaml:"drainGracePeriodSeconds"`
+	// DrainRetryInterval is the first wait before retrying an eviction a PodDisruptionBudget refused;
+	// it doubles per retry (capped at 1m) until the drain timeout. Without a drain timeout a refused
+	// eviction aborts the drain at once.
+	DrainRetryInterval time.Duration `yaml:"drainRetryInterval"`
+	// ForceDeleteAfterDrainTimeout deletes the pods still on a node once its drain times out (mirror
+	// and DaemonSet pods excepted), bypassing PodDisruptionBudgets, instead of aborting the drain.
+	// It only applies when a drain timeout is set.
+	ForceDeleteAfterDrainTimeout bool `yaml:"forceDeleteAfterDrainTimeout"`
+	// ForceDeleteGracePeriod caps the termination grace of force-deleted pods; 0 keeps the grace an
+	// eviction would use. Normal evictions always keep the pod's own grace unless drainGracePeriod is set.
+	ForceDeleteGracePeriod time.Duration `yaml:"forceDeleteGracePeriod"`
+	// DrainAllowBarePods deletes pods without a controller during drain, like `kubectl drain --force`.
+	// When false, such a pod (which nothing would recreate) keeps its node from being drained.
+	DrainAllowBarePods bool `yaml:"drainAllowBarePods"`
+	// MaxConcurrentEvictionsPerNode caps evictions in flight during one drain; an eviction counts
+	// until its pod is gone. 0 issues all evictions back to back without waiting.
+	MaxConcurrentEvictionsPerNode int `yaml:"maxConcurrentEvictionsPerNode"`
+
+	// ShutdownVerifyTimeout, when set, waits after a successful shutdown call until the node reports
+	// NotReady before marking it powered off; on timeout the power-off is treated as failed.
+	ShutdownVerifyTimeout time.Duration `yaml:"shutdownVerifyTimeout"`
+
+	// MaxCordonedOnDuration caps how long a node cordoned by CBA may stay powered on (e.g. after a
+	// failed drain or shutdown); MaxCordonedOnAction picks the resolution. 0 disables.
+	MaxCordonedOnDuration time.Duration `yaml:"maxCordonedOnDuration"`
+	MaxCordonedOnAction   string        `yaml:"maxCordonedOnAction"` // "powerOff" (default) or "uncordon"
+	// CordonMode is how CBA cordons nodes for scale-down: "unschedulable" (default) or "taint", which
+	// leaves spec.unschedulable to other controllers (e.g. GitOps) and uses a CBA-owned taint.
+	CordonMode string `yaml:"cordonMode"`
 
 	ResourceBufferCPUPerc    int `yaml:"resourceBufferCPUPerc"`
 	ResourceBufferMemoryPerc int `yaml:"resourceBufferMemoryPerc"`
+	// ResourceBufferEphemeralPerc is the ephemeral-storage margin (as %) kept free on the remaining nodes.
+	ResourceBufferEphemeralPerc int                 `yaml:"resourceBufferEphemeralPerc"`
+	ResourceAware               ResourceAwareConfig `yaml:"resourceAware"`
+
+	// NodeCapacityByType declares expected allocatable per node type, used when a powered-off node
+	// has no live allocatable to reason about, e.g. when ordering scale-up candidates by which of
+	// them the Unschedulable pods fit on.
+	NodeCapacityByType NodeCapacityByTypeConfig `yaml:"nodeCapacityByType"`
 
-	DryRun                   bool `yaml:"dryRun"` // NEW: dry-run mode
+	// WorkloadNamespaces, when non-empty, limits ResourceAware request and usage math to pods in these namespaces.
+	WorkloadNamespaces []string `yaml:"workloadNamespaces"`
+
+	DryRun                   bool `yaml:"dryRun"`      // simulate everything (power and Kubernetes mutations)
+	DryRunPower              bool `yaml:"dryRunPower"` // simulate only physical power-off/on
+	DryRunK8s                bool `yaml:"dryRunK8s"`   // simulate only Kubernetes mutations (cordon, evict, annotate)
 	BootstrapCooldownSeconds int  `yaml:"bootstrapCooldownSeconds"`
 
 	LoadAverageStrategy LoadAverageStrategyConfig `yaml:"loadAverageStrategy"`
-	ShutdownManager     ShutdownManagerConfig     `yaml:"shutdownManager"`
-	ShutdownMode        string                    `yaml:"shutdownMode"` // supported: "http", "disabled"
+	PrometheusStrategy  PrometheusStrategyConfig  `yaml:"prometheusStrategy"`
+	GPUAwareStrategy    GPUAwareStrategyConfig    `yaml:"gpuAwareStrategy"`
+	// ScaleDownStrategies and ScaleUpStrategies list the strategy chains in order, each entry with
+	// its own parameters. Left empty, they are derived from loadAverageStrategy.enabled,
+	// prometheusStrategy.enabled, gpuAwareStrategy.enabled and the resource buffer settings.
+	ScaleDownStrategies []StrategyEntry       `yaml:"scaleDownStrategies,omitempty"`
+	ScaleUpStrategies   []StrategyEntry       `yaml:"scaleUpStrategies,omitempty"`
+	ShutdownManager     ShutdownManagerConfig `yaml:"shutdownManager"`
+	ShutdownMode        string                `yaml:"shutdownMode"` // supported: "http", "exec", "redfish", "disabled"
+	// ShutdownMaxRetries retries a failed http or exec shutdown call that many times before the
+	// power-off is given up; 0 makes a single attempt. ShutdownRetryInterval is the first wait
+	// (default 2s), doubled per retry. Redfish retries on its own via redfish.maxRetries.
+	ShutdownMaxRetries    int           `yaml:"shutdownMaxRetries"`
+	ShutdownRetryInterval time.Duration `yaml:"shutdownRetryInterval"`
+
+	PowerOnMode      string `yaml:"powerOnMode"` // "disabled", "wol", "wol-direct", "ipmi", "exec", "redfish"
+	WOLBroadcastAddr string `yaml:"wolBroadcastAddr"`
+	// WOLBroadcastAddrs adds broadcast addresses for powerOnMode "wol-direct", which sends every
+	// magic packet to each of them (nodes spanning VLANs).
+	WOLBroadcastAddrs []string `yaml:"wolBroadcastAddrs"`
+	WOLBootTimeoutSec int      `yaml:"wolBootTimeoutSeconds"`
+	// WOLMatchRenamedNodes treats a woken node as booted when it rejoins under a new name,
+	// matched by MAC annotation or provider ID.
+	WOLMatchRenamedNodes bool `yaml:"wolMatchRenamedNodes"`
+	// BootReachabilityProbe optionally dials each booting node over TCP to log when the host is up,
+	// separately from (and usually well before) Kubernetes Ready.
+	BootReachabilityProbe BootReachabilityProbeConfig `yaml:"bootReachabilityProbe"`
+	// BootPollIntervalSeconds is the wait between readiness checks after a power-on; defaults to 5.
+	BootPollIntervalSeconds int            `yaml:"bootPollIntervalSeconds"`
+	WolAgent                WolAgentConfig `yaml:"wolAgent"`
+	// Exec configures powerOnMode / shutdownMode "exec": operator-supplied commands per node.
+	Exec ExecPowerConfig `yaml:"exec"`
+	// IPMI configures powerOnMode "ipmi"; the BMC address comes from each node's cba.dev/bmc-address.
+	IPMI IPMIConfig `yaml:"ipmi"`
+	// Redfish configures shutdownMode / powerOnMode "redfish" (ComputerSystem.Reset on each node's BMC).
+	Redfish              RedfishConfig `yaml:"redfish"`
+	MACDiscoveryInterval time.Duration `yaml:"macDiscoveryIntervalMin"`
+	// MACDiscoveryOnDemand runs an immediate MAC discovery for a power-on target that has no MAC yet,
+	// instead of leaving it to the next macDiscoveryInterval cycle.
+	MACDiscoveryOnDemand bool `yaml:"macDiscoveryOnDemand"`
+	// MACVerifyBeforeShutdown re-reads a drained node's MAC from its poweroff daemon right before
+	// power-off and compares it with the WOL annotation, catching NIC swaps while the node is still up.
+	MACVerifyBeforeShutdown bool `yaml:"macVerifyBeforeShutdown"`
+	// MACDriftAction decides what a mismatch does: "refresh" (default) re-annotates the current MAC,
+	// "block" keeps the node powered on (cordoned) until the annotation is fixed. A drifted manual
+	// override is never rewritten; "refresh" then only warns.
+	MACDriftAction string `yaml:"macDriftAction"`
+	// MACDiscoveryConcurrency bounds parallel MAC fetches per discovery cycle.
+	MACDiscoveryConcurrency int `yaml:"macDiscoveryConcurrency"`
+	// WOLInterfacePreference lists NIC name patterns (path.Match globs), in order of preference,
+	// used to choose which discovered MAC is annotated for WOL. Empty uses the default-route NIC.
+	WOLInterfacePreference []string `yaml:"wolInterfacePreference"`
+
+	// AlertPoweredOffPerc raises cba_powered_off_alert and an event once more than this percentage of
+	// managed nodes has been powered off continuously for AlertPoweredOffDuration. 0 disables.
+	AlertPoweredOffPerc     int           `yaml:"alertPoweredOffPerc"`
+	AlertPoweredOffDuration time.Duration `yaml:"alertPoweredOffDuration"`
+
+	// ScaleUpPolicy orders powered-off nodes when picking one to boot: "longestOff" (default) or
+	// "fastestBoot", which prefers nodes with the lowest recorded cba.dev/boot-duration.
+	ScaleUpPolicy string `yaml:"scaleUpPolicy"`
+	// ScaleUpPreference orders powered-off nodes by off-age before scaleUpPolicy applies: "oldest"
+	// (default) wakes the longest-off node, "newest" the most recently powered-off one.
+	ScaleUpPreference string `yaml:"scaleUpPreference"`
 
-	PowerOnMode          string         `yaml:"powerOnMode"` // "disabled", "wol"
-	WOLBroadcastAddr     string         `yaml:"wolBroadcastAddr"`
-	WOLBootTimeoutSec    int            `yaml:"wolBootTimeoutSeconds"`
-	WolAgent             WolAgentConfig `yaml:"wolAgent"`
-	MACDiscoveryInterval time.Duration  `yaml:"macDiscoveryIntervalMin"`
+	// PowerOnDedupWindow suppresses re-sending a power-on to the same node within this window,
+	// independent of bootCooldown. 0 disables.
+	PowerOnDedupWindow time.Duration `yaml:"powerOnDedupWindow"`
+
+	// MinAgentHealthRatio pauses scaling while fewer than this fraction (0-1) of the metrics or
+	// shutdown DaemonSet pods on powered-on nodes are Ready. 0 disables.
+	MinAgentHealthRatio float64 `yaml:"minAgentHealthRatio"`
+
+	// RequireAgentOnAlwaysOnNode refuses power-offs unless the WOL agent (powerOnMode "wol") and the
+	// shutdown manager (shutdownMode "http") each have a Ready pod on a node CBA never powers off.
+	RequireAgentOnAlwaysOnNode bool `yaml:"requireAgentOnAlwaysOnNode"`
+
+	// NodeStateRetention is how long in-memory per-node state is kept for nodes that no longer exist
+	// in the cluster. Defaults to 24h.
+	NodeStateRetention time.Duration `yaml:"nodeStateRetention"`
+
+	// PowerDrawPollInterval enables periodic BMC power-draw polling for running nodes; 0 disables.
+	PowerDrawPollInterval time.Duration `yaml:"powerDrawPollInterval"`
+
+	ForcePowerOnAllNodes bool `yaml:"forcePowerOnAllNodes"`
+	// ForcePowerOnReadyTimeout makes forcePowerOnAllNodes wait up to this long for the nodes it
+	// powered on to become Ready, counting any that do not as failed; 0 skips the wait.
+	ForcePowerOnReadyTimeout time.Duration `yaml:"forcePowerOnReadyTimeout"`
+
+	Rotation RotationConfig `yaml:"rotation"`
+	Recycle  RecycleConfig  `yaml:"recycle"`
+	// Schedule limits scale-down and scale-up to time windows; rotation and recycling are not gated.
+	Schedule ScheduleConfig `yaml:"schedule"`
+
+	UpgradeGuard UpgradeGuardConfig `yaml:"upgradeGuard"`
+
+	// DisruptionLease serializes drains with other tools through a shared coordination.k8s.io Lease.
+	DisruptionLease DisruptionLeaseConfig `yaml:"disruptionLease"`
+
+	BatchApproval BatchApprovalConfig `yaml:"batchApproval"`
+
+	// CriticalDaemonSets ("namespace/name") must keep all their pods on the remaining powered-on
+	// nodes Ready; otherwise retiring a node is refused.
+	CriticalDaemonSets []string `yaml:"criticalDaemonSets"`
+
+	// ApprovalWebhook, when set, must confirm each node retirement after the strategy chain approved it.
+	ApprovalWebhook ApprovalWebhookConfig `yaml:"approvalWebhook"`
+
+	// Notifications announces every node CBA powers on or off to a webhook (e.g. Slack).
+	Notifications NotificationsConfig `yaml:"notifications"`
+
+	// AuditLog writes one JSON line per power action to a dedicated append-only sink.
+	AuditLog AuditLogConfig `yaml:"auditLog"`
+
+	// CircuitBreaker pauses all scaling after repeated power-action failures.
+	CircuitBreaker CircuitBreakerConfig `yaml:"circuitBreaker"`
+
+	// KubeAPI rate-limits the controller's Kubernetes and metrics API clients.
+	KubeAPI KubeAPIConfig `yaml:"kubeAPI"`
+
+	// CustomResource reads runtime overrides from, and reports status to, a ClusterBareAutoscaler resource.
+	CustomResource CustomResourceConfig `yaml:"customResource"`
+
+	// AgentHTTP configures auth for calls to the metrics, WOL and shutdown agents.
+	AgentHTTP AgentHTTPConfig `yaml:"agentHTTP"`
+
+	// Report emits a periodic digest of power actions, off-hours and estimated savings.
+	Report ReportConfig `yaml:"report"`
+
+	// AdminAPI serves manual per-node power-on/power-off endpoints on the health port (:8080).
+	AdminAPI AdminAPIConfig `yaml:"adminAPI"`
+
+	// PersistState saves cooldown timestamps and the powered-off set to StateConfigMap after
+	// changes and loads them on startup, so a restart does not reset cooldowns.
+	PersistState   bool                 `yaml:"persistState"`
+	StateConfigMap StateConfigMapConfig `yaml:"stateConfigMap"`
+}
+
+// StateConfigMapConfig names the ConfigMap holding persisted state; name defaults to "cba-state".
+type StateConfigMapConfig struct {
+	Namespace string `yaml:"namespace"`
+	Name      string `yaml:"name"`
+}
+
+const (
+	ValueSourceConfigMap  = "configMap"
+	ValueSourceAnnotation = "annotation"
+	ValueSourceHTTP       = "http"
+)
+
+// ValueSourceConfig describes where to read an integer value at runtime.
+//   - configMap:  data[key] of ConfigMap namespace/name
+//   - annotation: annotation <annotation> on ConfigMap namespace/name (or on Node <name> when namespace is empty)
+//   - http:       GET url, body is a plain integer or {"value": <int>}
+type ValueSourceConfig struct {
+	Type           string `yaml:"type"` // "" (disabled), "configMap", "annotation", "http"
+	Namespace      string `yaml:"namespace,omitempty"`
+	Name           string `yaml:"name,omitempty"`
+	Key            string `yaml:"key,omitempty"`
+	Annotation     string `yaml:"annotation,omitempty"`
+	URL            string `yaml:"url,omitempty"`
+	TimeoutSeconds int    `yaml:"timeoutSeconds,omitempty"`
+}
+
+// Validate checks that the fields required by the selected source type are set.
+func (v ValueSourceConfig) Validate() error {
+	switch v.Type {
+	case "":
+		return nil
+	case ValueSourceConfigMap:
+		if v.Namespace == "" || v.Name == "" || v.Key == "" {
+			return fmt.Errorf("type %q requires namespace, name and key", v.Type)
+		}
+	case ValueSourceAnnotation:
+		if v.Name == "" || v.Annotation == "" {
+			return fmt.Errorf("type %q requires name and annotation", v.Type)
+		}
+	case ValueSourceHTTP:
+		if v.URL == "" {
+			return fmt.Errorf("type %q requires url", v.Type)
+		}
+	default:
+		return fmt.Errorf("unknown type %q", v.Type)
+	}
+	return nil
+}
+
+// ResourceAwareConfig tunes the ResourceAware scale-down strategy beyond the cluster-wide buffers.
+type ResourceAwareConfig struct {
+	// MaxCandidateUsageFraction denies scale-down while the candidate's own CPU or memory usage
+	// (metrics-server) exceeds this fraction of its allocatable; 0 disables.
+	MaxCandidateUsageFraction float64 `yaml:"maxCandidateUsageFraction"`
+	// MaxMetricsAge marks a node's metrics-server usage older than this as unknown; 0 disables.
+	// StaleMetricsPolicy then either denies the decision ("deny", default) or falls back to
+	// request-based math only ("requests").
+	MaxMetricsAge      time.Duration `yaml:"maxMetricsAge"`
+	StaleMetricsPolicy string        `yaml:"staleMetricsPolicy,omitempty"`
+}
+
+const (
+	StaleMetricsDeny     = "deny"
+	StaleMetricsRequests = "requests"
+)
+
+// NodeCapacityByTypeConfig maps the value of TypeLabel (default node.kubernetes.io/instance-type)
+// to the allocatable a node of that type offers once booted.
+type NodeCapacityByTypeConfig struct {
+	TypeLabel string                  `yaml:"typeLabel,omitempty"`
+	Types     map[string]NodeCapacity `yaml:"types,omitempty"`
+}
 
-	ForcePowerOnAllNodes bool           `yaml:"forcePowerOnAllNodes"`
-	Rotation             RotationConfig `yaml:"rotation"`
+// NodeCapacity is a declared allocatable in Kubernetes quantity notation (e.g. "31500m", "120Gi").
+type NodeCapacity struct {
+	CPU    string `yaml:"cpu"`
+	Memory string `yaml:"memory"`
 }
 
 type RotationConfig struct {
 	Enabled               bool          `yaml:"enabled"`
 	MaxPoweredOffDuration time.Duration `yaml:"maxPoweredOffDuration"` // e.g. "168h"
 	ExemptLabel           string        `yaml:"exemptLabel"`           // if set, nodes with this label are never rotated
+	// TieBreak picks among overdue nodes powered off at the same instant; see RotationTieBreak*.
+	TieBreak     string `yaml:"tieBreak"`
+	TieBreakSeed int64  `yaml:"tieBreakSeed"` // seed for tieBreak "random"
+}
+
+// Rotation tie-break orders.
+const (
+	RotationTieBreakName                 = "name"                 // lexically first node name (default)
+	RotationTieBreakLeastRecentlyRotated = "leastRecentlyRotated" // never rotated first, then oldest rotation; name breaks what remains
+	RotationTieBreakRandom               = "random"               // stable pseudo-random order derived from tieBreakSeed
+)
+
+// RecycleConfig drives forced power-cycling of nodes that have been running for too long.
+type RecycleConfig struct {
+	MaxOnDuration time.Duration `yaml:"maxOnDuration"` // 0 disables; e.g. "720h"
+}
+
+// UpgradeGuardConfig pauses scale-down while a cluster-wide upgrade or drain is in progress.
+// The guard trips when either signal is present:
+//   - notReadyThreshold: at least this many managed nodes are NotReady without CBA having powered them off
+//   - sentinel: label or annotation <key> is set on ConfigMap namespace/name (or on Node <name> when namespace is empty)
+type UpgradeGuardConfig struct {
+	Enabled           bool                  `yaml:"enabled"`
+	NotReadyThreshold int                   `yaml:"notReadyThreshold"` // 0 disables the NotReady check
+	Sentinel          UpgradeSentinelConfig `yaml:"sentinel"`
+}
+
+// DisruptionLeaseConfig names a Lease that CBA holds, renewing it, for the whole of every drain and
+// power-off, whichever path triggers it. Any tool that drains nodes can take the same lease; while
+// another holder's lease is unexpired, CBA neither drains nor powers off nodes.
+type DisruptionLeaseConfig struct {
+	Enabled   bool   `yaml:"enabled"`
+	Namespace string `yaml:"namespace"`
+	Name      string `yaml:"name"`
+	// Identity is written as holderIdentity; defaults to "cluster-bare-autoscaler".
+	Identity string `yaml:"identity,omitempty"`
+	// DurationSeconds is the lease duration. A holder that crashes blocks others this long, so it
+	// should only just exceed the longest drain plus shutdown. Defaults to 900.
+	DurationSeconds int `yaml:"durationSeconds"`
+}
+
+type UpgradeSentinelConfig struct {
+	Namespace string `yaml:"namespace,omitempty"`
+	Name      string `yaml:"name,omitempty"`
+	Key       string `yaml:"key,omitempty"` // label or annotation key; any value other than "false" means in progress
+}
+
+// BatchApprovalConfig gates multi-node operations (e.g. forcePowerOnAllNodes) behind an operator
+// confirmation. The plan is logged with an ID; it executes once annotation <annotation> on
+// ConfigMap namespace/name (or Node <name> when namespace is empty) equals that ID.
+// Dry-run batches never wait for approval.
+type BatchApprovalConfig struct {
+	RequireApproval bool   `yaml:"requireApproval"`
+	Namespace       string `yaml:"namespace,omitempty"`
+	Name            string `yaml:"name,omitempty"`
+	Annotation      string `yaml:"annotation,omitempty"`
+}
+
+type BootReachabilityProbeConfig struct {
+	Port           int `yaml:"port"`           // TCP port on the node's InternalIP, e.g. 22; 0 disables
+	TimeoutSeconds int `yaml:"timeoutSeconds"` // per-dial timeout; defaults to 1
+}
+
+// KubeAPIConfig is the client-side token bucket shared by all API calls the controller makes.
+type KubeAPIConfig struct {
+	QPS   float32 `yaml:"qps"`   // sustained requests per second; default 5 (client-go default)
+	Burst int     `yaml:"burst"` // default 10
+}
+
+// CustomResourceConfig names the cluster-scoped ClusterBareAutoscaler (cba.dev/v1alpha1) object
+// CBA reads its spec from and writes its status to. The object itself is created by the user.
+type CustomResourceConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Name    string `yaml:"name"` // defaults to "default"
+}
+
+// ApprovalWebhookConfig describes an external service that can veto a scale-down. CBA POSTs the
+// candidate and a cluster snapshot; only {"approved": true} lets the retirement proceed. Errors,
+// timeouts and non-2xx responses count as a veto.
+type ApprovalWebhookConfig struct {
+	URL            string `yaml:"url,omitempty"` // empty disables the webhook
+	TimeoutSeconds int    `yaml:"timeoutSeconds,omitempty"`
+}
+
+// NotificationsConfig describes a webhook that is POSTed a JSON message for every power action.
+// Delivery is best effort: failures are logged and never hold up the reconcile loop.
+type NotificationsConfig struct {
+	Enabled        bool              `yaml:"enabled"`
+	WebhookURL     string            `yaml:"webhookURL"`
+	Headers        map[string]string `yaml:"headers,omitempty"` // e.g. Authorization
+	TimeoutSeconds int               `yaml:"timeoutSeconds,omitempty"`
+}
+
+// AuditLogConfig enables the audit log. Path is a file opened for appending; empty or "stdout"
+// writes to stdout, where each line carries "stream": "audit". Changing it needs a restart.
+type AuditLogConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Path    string `yaml:"path"`
+}
+
+// CircuitBreakerConfig opens the breaker after FailureThreshold consecutive failed power actions
+// (shutdowns or power-ons) within Window; while open, reconcile loops take no scaling action for
+// Cooldown. A successful power action resets the count.
+type CircuitBreakerConfig struct {
+	FailureThreshold int           `yaml:"failureThreshold"` // 0 disables the breaker
+	Window           time.Duration `yaml:"window"`           // defaults to 1h
+	Cooldown         time.Duration `yaml:"cooldown"`         // defaults to 30m
+}
+
+// ReportConfig controls the periodic summary report, which is logged and optionally POSTed as JSON.
+type ReportConfig struct {
+	Interval       time.Duration `yaml:"interval"`             // 0 disables the report
+	WebhookURL     string        `yaml:"webhookURL,omitempty"` // empty: log only
+	TimeoutSeconds int           `yaml:"timeoutSeconds,omitempty"`
+	// NodeWatts is the assumed draw of a powered-on node for the savings estimate, used for nodes
+	// without a power-draw reading (powerDrawPollInterval). 0 counts them as saving nothing.
+	NodeWatts float64 `yaml:"nodeWatts"`
+	// BlockedAfter reports nodes CBA cordoned but left powered on for at least this long. Default 1h.
+	BlockedAfter time.Duration `yaml:"blockedAfter"`
+}
+
+// AgentHTTPConfig adds headers, a bearer token and client certificates to agent calls,
+// for endpoints that sit behind an auth proxy.
+type AgentHTTPConfig struct {
+	Scheme            string            `yaml:"scheme"` // "http" (default) or "https"
+	Headers           map[string]string `yaml:"headers"`
+	BearerTokenSecret SecretKeyRef      `yaml:"bearerTokenSecret"`
+	TLS               AgentTLSConfig    `yaml:"tls"`
+}
+
+// AdminAPIConfig enables POST /admin/nodes/{name}/poweron and /poweroff. Requests must carry
+// "Authorization: Bearer <token>" matching data[key] of TokenSecret, which is re-read per request.
+type AdminAPIConfig struct {
+	Enabled     bool         `yaml:"enabled"`
+	TokenSecret SecretKeyRef `yaml:"tokenSecret"`
+}
+
+// SecretKeyRef points at data[key] of Secret namespace/name.
+type SecretKeyRef struct {
+	Namespace string `yaml:"namespace,omitempty"`
+	Name      string `yaml:"name,omitempty"`
+	Key       string `yaml:"key,omitempty"`
+}
+
+type AgentTLSConfig struct {
+	CertFile           string `yaml:"certFile,omitempty"` // client certificate for mTLS
+	KeyFile            string `yaml:"keyFile,omitempty"`
+	CAFile             string `yaml:"caFile,omitempty"` // CA bundle to verify the agent; system roots when empty
+	InsecureSkipVerify bool   `yaml:"insecureSkipVerify,omitempty"`
 }
 
 type LoadAverageStrategyConfig struct {
@@ -72,6 +596,136 @@ type LoadAverageStrategyConfig struct {
 	TimeoutSeconds             int               `yaml:"timeoutSeconds"`
 	ClusterEval                string            `yaml:"clusterEval,omitempty"` // "average", "median", "p90", "p75"
 	ExcludeFromAggregateLabels map[string]string `yaml:"excludeFromAggregateLabels,omitempty"`
+
+	LoadUnavailablePolicy LoadUnavailablePolicyConfig `yaml:"loadUnavailablePolicy,omitempty"`
+	AllowLoadOverrides    bool                        `yaml:"allowLoadOverrides,omitempty"` // honor per-node cba.dev/load-override
+	MinLoadSamples        int                         `yaml:"minLoadSamples,omitempty"`     // fewest reporting nodes a cluster aggregate may be built from
+	// LoadNormalization selects what load15 is divided by before thresholds apply:
+	// "perLogicalCore" (default), "perPhysicalCore" or "absolute" (not divided).
+	LoadNormalization string `yaml:"loadNormalization,omitempty"`
+	// AggregateDenominator selects which nodes the cluster aggregate is taken over: "onNodes"
+	// (default) or "allManaged", which adds a zero sample for every powered-off managed node.
+	AggregateDenominator string `yaml:"aggregateDenominator,omitempty"`
+	// MissingMetricsBehavior decides how a node without a metrics pod counts: "skip" (default, left
+	// out of the aggregate), "treatAsHigh" (busy) or "treatAsZero" (idle).
+	MissingMetricsBehavior string `yaml:"missingMetricsBehavior,omitempty"`
+	// LoadFetchConcurrency bounds parallel per-node /load calls when building the cluster aggregate; defaults to 8.
+	LoadFetchConcurrency int `yaml:"loadFetchConcurrency,omitempty"`
+	// LoadSource is where per-node load comes from: "agent" (default, the metrics DaemonSet) or
+	// "synthetic", which replays SyntheticProfile so the decision pipeline runs without metrics pods.
+	LoadSource       string               `yaml:"loadSource,omitempty"`
+	SyntheticProfile SyntheticLoadProfile `yaml:"syntheticProfile,omitempty"`
+}
+
+// validate defaults timeoutSeconds and clusterEval and, when the strategy is enabled, checks that
+// the metrics pods can be found and the thresholds make sense. Thresholds are normalized loads in
+// [0,1] except under loadNormalization "absolute", where they are raw load15 values.
+func (l *LoadAverageStrategyConfig) validate() error {
+	if l.TimeoutSeconds == 0 {
+		l.TimeoutSeconds = 3
+	}
+	if l.TimeoutSeconds < 0 {
+		return fmt.Errorf("loadAverageStrategy.timeoutSeconds must be > 0, got %d", l.TimeoutSeconds)
+	}
+	switch l.ClusterEval {
+	case "":
+		l.ClusterEval = "average"
+	case "average", "median", "p90", "p75":
+	default:
+		return fmt.Errorf("loadAverageStrategy.clusterEval: unknown value %q", l.ClusterEval)
+	}
+
+	if !l.Enabled {
+		return nil
+	}
+	if l.LoadSource != LoadSourceSynthetic {
+		if l.Port <= 0 {
+			return fmt.Errorf("loadAverageStrategy.port must be > 0 when the strategy is enabled, got %d", l.Port)
+		}
+		if l.PodLabel == "" || l.Namespace == "" {
+			return fmt.Errorf("loadAverageStrategy: podLabel and namespace are required when the strategy is enabled")
+		}
+	}
+	for name, v := range map[string]float64{
+		"nodeThreshold":      l.NodeThreshold,
+		"scaleDownThreshold": l.ScaleDownThreshold,
+		"scaleUpThreshold":   l.ScaleUpThreshold,
+	} {
+		if v < 0 || (v > 1 && l.LoadNormalization != LoadNormalizationAbsolute) {
+			return fmt.Errorf("loadAverageStrategy.%s must be in [0,1], got %v", name, v)
+		}
+	}
+	if l.ScaleUpThreshold <= l.ScaleDownThreshold {
+		return fmt.Errorf("loadAverageStrategy.scaleUpThreshold (%v) must be greater than scaleDownThreshold (%v)",
+			l.ScaleUpThreshold, l.ScaleDownThreshold)
+	}
+	return nil
+}
+
+// SyntheticLoadProfile scripts normalized load over time, measured from controller start. Loads
+// between points are interpolated linearly; before the first point the first load applies and
+// after the last point the last load holds, unless Loop restarts the curve.
+type SyntheticLoadProfile struct {
+	Cluster []LoadPoint            `yaml:"cluster,omitempty"` // curve for every node without its own
+	Nodes   map[string][]LoadPoint `yaml:"nodes,omitempty"`   // per-node curves
+	Loop    bool                   `yaml:"loop,omitempty"`
+}
+
+// CordonWithTaint reports whether cordonMode is "taint".
+func (cfg *Config) CordonWithTaint() bool {
+	return cfg.CordonMode == CordonModeTaint
+}
+
+// TaintMatch selects node taints by key; an empty Value or Effect matches any.
+type TaintMatch struct {
+	Key    string `yaml:"key"`
+	Value  string `yaml:"value,omitempty"`
+	Effect string `yaml:"effect,omitempty"`
+}
+
+// WOLBroadcastAddresses returns wolBroadcastAddr followed by wolBroadcastAddrs, without duplicates.
+func (cfg *Config) WOLBroadcastAddresses() []string {
+	var out []string
+	for _, addr := range append([]string{cfg.WOLBroadcastAddr}, cfg.WOLBroadcastAddrs...) {
+		if addr != "" && !slices.Contains(out, addr) {
+			out = append(out, addr)
+		}
+	}
+	return out
+}
+
+func (p SyntheticLoadProfile) validate() error {
+	if len(p.Cluster) == 0 && len(p.Nodes) == 0 {
+		return fmt.Errorf("needs a cluster curve or at least one node curve")
+	}
+	curves := map[string][]LoadPoint{"cluster": p.Cluster}
+	for node, curve := range p.Nodes {
+		curves["nodes."+node] = curve
+	}
+	for name, curve := range curves {
+		for i, pt := range curve {
+			if pt.Load < 0 || pt.At < 0 {
+				return fmt.Errorf("%s[%d]: at and load must be >= 0", name, i)
+			}
+			if i > 0 && pt.At <= curve[i-1].At {
+				return fmt.Errorf("%s[%d]: points must be in increasing order of at", name, i)
+			}
+		}
+	}
+	return nil
+}
+
+// LoadPoint is one step of a synthetic load curve.
+type LoadPoint struct {
+	At   time.Duration `yaml:"at"`
+	Load float64       `yaml:"load"`
+}
+
+// LoadUnavailablePolicyConfig selects, per phase, what the load strategies do when
+// no node reports load at all (e.g. the metrics DaemonSet is down).
+type LoadUnavailablePolicyConfig struct {
+	ScaleDown string `yaml:"scaleDown"` // "failClosed" (default) or "failOpen"
+	ScaleUp   string `yaml:"scaleUp"`   // "failClosed" (default) or "failOpen"
 }
 
 type ShutdownManagerConfig struct {
@@ -79,6 +733,37 @@ type ShutdownManagerConfig struct {
 	Namespace string `yaml:"namespace"`
 	PodLabel  string `yaml:"podLabel"`
 }
+
+// ExecPowerConfig holds argv templates run by the exec power backend. Each element is a Go
+// text/template with {{.Node}}, {{.MAC}} and {{.IP}}; no shell is involved.
+type ExecPowerConfig struct {
+	PowerOn        []string `yaml:"powerOn"`
+	Shutdown       []string `yaml:"shutdown"`
+	TimeoutSeconds int      `yaml:"timeoutSeconds"` // per command; default 30
+}
+
+// IPMIConfig describes how ipmitool reaches node BMCs. Per-node cba.dev/bmc-user overrides User.
+type IPMIConfig struct {
+	Command        string       `yaml:"command"`   // ipmitool binary; default "ipmitool"
+	Interface      string       `yaml:"interface"` // ipmitool -I; default "lanplus"
+	User           string       `yaml:"user"`
+	PasswordSecret SecretKeyRef `yaml:"passwordSecret"`
+	TimeoutSeconds int          `yaml:"timeoutSeconds"` // per ipmitool call; default 10
+}
+
+// RedfishConfig holds defaults for Redfish BMC calls. Node annotations cba.dev/bmc-address,
+// cba.dev/redfish-system-id and cba.dev/bmc-user override Endpoint, SystemID and User.
+type RedfishConfig struct {
+	Endpoint               string       `yaml:"endpoint"` // BMC host or base URL; "https://" assumed without a scheme
+	SystemID               string       `yaml:"systemID"` // default "1"
+	User                   string       `yaml:"user"`
+	PasswordSecret         SecretKeyRef `yaml:"passwordSecret"`
+	InsecureSkipVerify     bool         `yaml:"insecureSkipVerify"`
+	TimeoutSeconds         int          `yaml:"timeoutSeconds"`         // per request; default 10
+	MaxRetries             int          `yaml:"maxRetries"`             // attempts per reset request; default 3
+	ShutdownTimeoutSeconds int          `yaml:"shutdownTimeoutSeconds"` // wait for NotReady after GracefulShutdown; default 300
+}
+
 type WolAgentConfig struct {
 	Enabled   bool   `yaml:"enabled"`
 	Port      int    `yaml:"port"`
@@ -91,7 +776,11 @@ func Load(path string) (*Config, error) {
 	if err != nil {
 		return nil, err
 	}
+	return Parse(data)
+}
 
+// Parse decodes a YAML config and applies defaults and validation, as Load does for a file.
+func Parse(data []byte) (*Config, error) {
 	var cfg Config
 	if err := yaml.Unmarshal(data, &cfg); err != nil {
 		return nil, err
@@ -100,10 +789,30 @@ func Load(path string) (*Config, error) {
 	if err := cfg.ApplyDefaultsAndValidate(); err != nil {
 		return nil, fmt.Errorf("invalid config: %w", err)
 	}
+	sum := sha256.Sum256(data)
+	cfg.Revision = hex.EncodeToString(sum[:6])
 
 	return &cfg, nil
 }
 
+// IsPowerDryRun reports whether physical power operations must be simulated.
+func (cfg *Config) IsPowerDryRun() bool {
+	return cfg.DryRun || cfg.DryRunPower
+}
+
+// IsK8sDryRun reports whether Kubernetes mutations (cordon, evict, annotate) must be simulated.
+func (cfg *Config) IsK8sDryRun() bool {
+	return cfg.DryRun || cfg.DryRunK8s
+}
+
+// InWorkloadScope reports whether pods in namespace count towards capacity decisions.
+func (cfg *Config) InWorkloadScope(namespace string) bool {
+	if len(cfg.WorkloadNamespaces) == 0 {
+		return true
+	}
+	return slices.Contains(cfg.WorkloadNamespaces, namespace)
+}
+
 func (cfg *Config) ApplyDefaultsAndValidate() error {
 	if cfg.MACDiscoveryInterval == 0 {
 		cfg.MACDiscoveryInterval = 30 * time.Minute
@@ -113,6 +822,488 @@ func (cfg *Config) ApplyDefaultsAndValidate() error {
 		return fmt.Errorf("macDiscoveryInterval too short: %s", cfg.MACDiscoveryInterval)
 	}
 
+	if cfg.MACDiscoveryConcurrency == 0 {
+		cfg.MACDiscoveryConcurrency = 8
+	}
+	if cfg.MACDiscoveryConcurrency < 0 {
+		return fmt.Errorf("macDiscoveryConcurrency must be > 0, got %d", cfg.MACDiscoveryConcurrency)
+	}
+
+	for _, pattern := range cfg.WOLInterfacePreference {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("wolInterfacePreference: invalid pattern %q: %w", pattern, err)
+		}
+	}
+
+	for _, t := range cfg.ExcludeTaints {
+		if t.Key == "" {
+			return fmt.Errorf("excludeTaints: key is required")
+		}
+		switch t.Effect {
+		case "", "NoSchedule", "PreferNoSchedule", "NoExecute":
+		default:
+			return fmt.Errorf("excludeTaints: unknown effect %q for key %q", t.Effect, t.Key)
+		}
+	}
+
+	if err := cfg.Schedule.validate(); err != nil {
+		return err
+	}
+
+	if err := cfg.resolveStrategies(); err != nil {
+		return err
+	}
+
+	if err := cfg.MinNodesSource.Validate(); err != nil {
+		return fmt.Errorf("minNodesSource: %w", err)
+	}
+
+	if cfg.AlertPoweredOffPerc < 0 || cfg.AlertPoweredOffPerc > 100 {
+		return fmt.Errorf("alertPoweredOffPerc must be within [0, 100], got %d", cfg.AlertPoweredOffPerc)
+	}
+	if cfg.AlertPoweredOffDuration < 0 {
+		return fmt.Errorf("alertPoweredOffDuration must be >= 0, got %s", cfg.AlertPoweredOffDuration)
+	}
+
+	if cfg.PowerOnDedupWindow < 0 {
+		return fmt.Errorf("powerOnDedupWindow must be >= 0, got %s", cfg.PowerOnDedupWindow)
+	}
+
+	if cfg.MaxPollInterval != 0 && cfg.MaxPollInterval < cfg.PollInterval {
+		return fmt.Errorf("maxPollInterval (%s) must be 0 or >= pollInterval (%s)", cfg.MaxPollInterval, cfg.PollInterval)
+	}
+	if cfg.PollBackoffAfter < 0 {
+		return fmt.Errorf("pollBackoffAfter must be >= 0, got %d", cfg.PollBackoffAfter)
+	}
+	if cfg.PollBackoffAfter == 0 {
+		cfg.PollBackoffAfter = 3
+	}
+
+	if cfg.PowerDrawPollInterval < 0 {
+		return fmt.Errorf("powerDrawPollInterval must be >= 0, got %s", cfg.PowerDrawPollInterval)
+	}
+
+	switch cfg.Rotation.TieBreak {
+	case "":
+		cfg.Rotation.TieBreak = RotationTieBreakName
+	case RotationTieBreakName, RotationTieBreakLeastRecentlyRotated, RotationTieBreakRandom:
+	default:
+		return fmt.Errorf("rotation.tieBreak: unknown value %q (want %s, %s or %s)", cfg.Rotation.TieBreak,
+			RotationTieBreakName, RotationTieBreakLeastRecentlyRotated, RotationTieBreakRandom)
+	}
+
+	if cfg.Recycle.MaxOnDuration < 0 {
+		return fmt.Errorf("recycle.maxOnDuration must be >= 0, got %s", cfg.Recycle.MaxOnDuration)
+	}
+
+	if cfg.PostCordonDelaySeconds < 0 {
+		return fmt.Errorf("postCordonDelaySeconds must be >= 0, got %d", cfg.PostCordonDelaySeconds)
+	}
+
+	if cfg.MaxCordonedOnDuration < 0 {
+		return fmt.Errorf("maxCordonedOnDuration must be >= 0, got %s", cfg.MaxCordonedOnDuration)
+	}
+	switch cfg.ScaleUpPolicy {
+	case "":
+		cfg.ScaleUpPolicy = ScaleUpPolicyLongestOff
+	case ScaleUpPolicyLongestOff, ScaleUpPolicyFastestBoot:
+	default:
+		return fmt.Errorf("scaleUpPolicy: unknown value %q", cfg.ScaleUpPolicy)
+	}
+	switch cfg.ScaleUpPreference {
+	case "":
+		cfg.ScaleUpPreference = ScaleUpPreferenceOldest
+	case ScaleUpPreferenceOldest, ScaleUpPreferenceNewest:
+	default:
+		return fmt.Errorf("scaleUpPreference: unknown value %q", cfg.ScaleUpPreference)
+	}
+
+	switch cfg.CordonMode {
+	case "":
+		cfg.CordonMode = CordonModeUnschedulable
+	case CordonModeUnschedulable, CordonModeTaint:
+	default:
+		return fmt.Errorf("cordonMode: unknown value %q", cfg.CordonMode)
+	}
+
+	switch cfg.MaxCordonedOnAction {
+	case "":
+		cfg.MaxCordonedOnAction = CordonedOnActionPowerOff
+	case CordonedOnActionPowerOff, CordonedOnActionUncordon:
+	default:
+		return fmt.Errorf("maxCordonedOnAction: unknown value %q", cfg.MaxCordonedOnAction)
+	}
+
+	if err := cfg.DesiredNodeCountSource.Validate(); err != nil {
+		return fmt.Errorf("desiredNodeCountSource: %w", err)
+	}
+
+	if f := cfg.ResourceAware.MaxCandidateUsageFraction; f < 0 || f > 1 {
+		return fmt.Errorf("resourceAware.maxCandidateUsageFraction must be within [0, 1], got %g", f)
+	}
+	if cfg.ResourceAware.MaxMetricsAge < 0 {
+		return fmt.Errorf("resourceAware.maxMetricsAge must be >= 0, got %s", cfg.ResourceAware.MaxMetricsAge)
+	}
+	switch cfg.ResourceAware.StaleMetricsPolicy {
+	case "":
+		cfg.ResourceAware.StaleMetricsPolicy = StaleMetricsDeny
+	case StaleMetricsDeny, StaleMetricsRequests:
+	default:
+		return fmt.Errorf("resourceAware.staleMetricsPolicy must be %q or %q, got %q",
+			StaleMetricsDeny, StaleMetricsRequests, cfg.ResourceAware.StaleMetricsPolicy)
+	}
+
+	if cfg.BatchApproval.RequireApproval {
+		if cfg.BatchApproval.Annotation == "" {
+			cfg.BatchApproval.Annotation = "cba.dev/approve-batch"
+		}
+		if cfg.BatchApproval.Name == "" {
+			return fmt.Errorf("batchApproval: name is required when requireApproval is set")
+		}
+	}
+
+	for nodeType, capacity := range cfg.NodeCapacityByType.Types {
+		for name, val := range map[string]string{"cpu": capacity.CPU, "memory": capacity.Memory} {
+			if val == "" {
+				continue
+			}
+			if _, err := resource.ParseQuantity(val); err != nil {
+				return fmt.Errorf("nodeCapacityByType.types.%s.%s: %w", nodeType, name, err)
+			}
+		}
+	}
+
+	if guard := cfg.UpgradeGuard; guard.Enabled {
+		if guard.NotReadyThreshold < 0 {
+			return fmt.Errorf("upgradeGuard.notReadyThreshold must be >= 0, got %d", guard.NotReadyThreshold)
+		}
+		if (guard.Sentinel.Name == "") != (guard.Sentinel.Key == "") {
+			return fmt.Errorf("upgradeGuard.sentinel: name and key must be set together")
+		}
+		if guard.NotReadyThreshold == 0 && guard.Sentinel.Name == "" {
+			return fmt.Errorf("upgradeGuard: enabled but neither notReadyThreshold nor sentinel is configured")
+		}
+	}
+
+	if cfg.PersistState {
+		if cfg.StateConfigMap.Namespace == "" {
+			return fmt.Errorf("stateConfigMap.namespace is required when persistState is enabled")
+		}
+		if cfg.StateConfigMap.Name == "" {
+			cfg.StateConfigMap.Name = "cba-state"
+		}
+	}
+
+	if lease := &cfg.DisruptionLease; lease.Enabled {
+		if lease.Namespace == "" || lease.Name == "" {
+			return fmt.Errorf("disruptionLease: namespace and name are required when enabled")
+		}
+		if lease.DurationSeconds < 0 {
+			return fmt.Errorf("disruptionLease.durationSeconds must be >= 0, got %d", lease.DurationSeconds)
+		}
+		if lease.DurationSeconds == 0 {
+			lease.DurationSeconds = 900
+		}
+		if lease.Identity == "" {
+			lease.Identity = "cluster-bare-autoscaler"
+		}
+	}
+
+	if cfg.AbsoluteMinNodes < 0 {
+		return fmt.Errorf("absoluteMinNodes must be >= 0, got %d", cfg.AbsoluteMinNodes)
+	}
+	if cfg.ScaleDownBuffer < 0 {
+		return fmt.Errorf("scaleDownBuffer must be >= 0, got %d", cfg.ScaleDownBuffer)
+	}
+	if cfg.MaxPoweredOff < 0 {
+		return fmt.Errorf("maxPoweredOff must be >= 0, got %d", cfg.MaxPoweredOff)
+	}
+
+	if _, err := cfg.NodeLabels.Selector(); err != nil {
+		return fmt.Errorf("nodeLabels.managedSelector: %w", err)
+	}
+	if cfg.NodeAnnotations.ScaleDownDisabled == "" {
+		cfg.NodeAnnotations.ScaleDownDisabled = DefaultScaleDownDisabledAnnotation
+	}
+	if cfg.MigrateLabels.Enabled && cfg.NodeLabels.Managed == "" && cfg.NodeLabels.ManagedSelector == "" {
+		return fmt.Errorf("migrateLabels requires nodeLabels.managed or nodeLabels.managedSelector")
+	}
+
+	if cfg.ShutdownVerifyTimeout < 0 {
+		return fmt.Errorf("shutdownVerifyTimeout must be >= 0, got %s", cfg.ShutdownVerifyTimeout)
+	}
+	if cfg.DrainTimeout < 0 || cfg.DrainGracePeriod < 0 {
+		return fmt.Errorf("drainTimeout and drainGracePeriod must be >= 0")
+	}
+	if cfg.DrainGracePeriodSeconds < 0 {
+		return fmt.Errorf("drainGracePeriodSeconds must be >= 0, got %d", cfg.DrainGracePeriodSeconds)
+	}
+	if cfg.DrainGracePeriodSeconds > 0 {
+		if cfg.DrainGracePeriod > 0 && cfg.DrainGracePeriod != time.Duration(cfg.DrainGracePeriodSeconds)*time.Second {
+			return fmt.Errorf("drainGracePeriod (%s) and drainGracePeriodSeconds (%d) disagree; set only one",
+				cfg.DrainGracePeriod, cfg.DrainGracePeriodSeconds)
+		}
+		cfg.DrainGracePeriod = time.Duration(cfg.DrainGracePeriodSeconds) * time.Second
+	}
+	if cfg.ForceDeleteGracePeriod < 0 {
+		return fmt.Errorf("forceDeleteGracePeriod must be >= 0, got %s", cfg.ForceDeleteGracePeriod)
+	}
+	if cfg.DrainRetryInterval < 0 {
+		return fmt.Errorf("drainRetryInterval must be >= 0, got %s", cfg.DrainRetryInterval)
+	}
+	if cfg.DrainRetryInterval == 0 {
+		cfg.DrainRetryInterval = 5 * time.Second
+	}
+
+	if cfg.MinAgentHealthRatio < 0 || cfg.MinAgentHealthRatio > 1 {
+		return fmt.Errorf("minAgentHealthRatio must be within [0,1], got %v", cfg.MinAgentHealthRatio)
+	}
+
+	if probe := &cfg.BootReachabilityProbe; probe.Port != 0 {
+		if probe.Port < 0 || probe.Port > 65535 {
+			return fmt.Errorf("bootReachabilityProbe.port out of range: %d", probe.Port)
+		}
+		if probe.TimeoutSeconds < 0 {
+			return fmt.Errorf("bootReachabilityProbe.timeoutSeconds must be >= 0, got %d", probe.TimeoutSeconds)
+		}
+		if probe.TimeoutSeconds == 0 {
+			probe.TimeoutSeconds = 1
+		}
+	}
+
+	if cfg.BootPollIntervalSeconds < 0 {
+		return fmt.Errorf("bootPollIntervalSeconds must be >= 0, got %d", cfg.BootPollIntervalSeconds)
+	}
+	if cfg.BootPollIntervalSeconds == 0 {
+		cfg.BootPollIntervalSeconds = 5
+	}
+
+	if cfg.NodeStateRetention < 0 {
+		return fmt.Errorf("nodeStateRetention must be >= 0, got %s", cfg.NodeStateRetention)
+	}
+	if cfg.NodeStateRetention == 0 {
+		cfg.NodeStateRetention = 24 * time.Hour
+	}
+
+	if cfg.KubeAPI.QPS < 0 || cfg.KubeAPI.Burst < 0 {
+		return fmt.Errorf("kubeAPI: qps and burst must be >= 0")
+	}
+	if cfg.KubeAPI.QPS == 0 {
+		cfg.KubeAPI.QPS = 5
+	}
+	if cfg.KubeAPI.Burst == 0 {
+		cfg.KubeAPI.Burst = 10
+	}
+
+	if cfg.CustomResource.Name == "" {
+		cfg.CustomResource.Name = "default"
+	}
+
+	for _, ref := range cfg.CriticalDaemonSets {
+		if ns, name, ok := strings.Cut(ref, "/"); !ok || ns == "" || name == "" {
+			return fmt.Errorf("criticalDaemonSets: %q must be namespace/name", ref)
+		}
+	}
+
+	switch cfg.ShutdownMode {
+	case "":
+		cfg.ShutdownMode = "disabled"
+	case "disabled", "exec", "redfish":
+	case "http":
+		if m := cfg.ShutdownManager; m.Port <= 0 || m.Namespace == "" || m.PodLabel == "" {
+			return fmt.Errorf("shutdownMode http requires shutdownManager {port, namespace, podLabel}")
+		}
+	default:
+		return fmt.Errorf("shutdownMode: unknown value %q", cfg.ShutdownMode)
+	}
+	if cfg.ForcePowerOnReadyTimeout < 0 {
+		return fmt.Errorf("forcePowerOnReadyTimeout must be >= 0, got %s", cfg.ForcePowerOnReadyTimeout)
+	}
+	if cfg.ShutdownMaxRetries < 0 {
+		return fmt.Errorf("shutdownMaxRetries must be >= 0, got %d", cfg.ShutdownMaxRetries)
+	}
+	if cfg.ShutdownRetryInterval < 0 {
+		return fmt.Errorf("shutdownRetryInterval must be >= 0, got %s", cfg.ShutdownRetryInterval)
+	}
+	if cfg.ShutdownRetryInterval == 0 {
+		cfg.ShutdownRetryInterval = 2 * time.Second
+	}
+
+	switch cfg.PowerOnMode {
+	case "":
+		cfg.PowerOnMode = "disabled"
+	case "disabled", "wol-direct", "ipmi", "exec", "redfish":
+	case "wol":
+		if cfg.WOLBroadcastAddr == "" {
+			return fmt.Errorf("powerOnMode wol requires wolBroadcastAddr")
+		}
+		if a := cfg.WolAgent; a.Port <= 0 || a.Namespace == "" || a.PodLabel == "" {
+			return fmt.Errorf("powerOnMode wol requires wolAgent {port, namespace, podLabel}")
+		}
+	default:
+		return fmt.Errorf("powerOnMode: unknown value %q", cfg.PowerOnMode)
+	}
+
+	if cfg.PowerOnMode == "wol-direct" && len(cfg.WOLBroadcastAddresses()) == 0 {
+		return fmt.Errorf("powerOnMode wol-direct requires wolBroadcastAddr or wolBroadcastAddrs")
+	}
+	if cfg.PowerOnMode == "exec" && len(cfg.Exec.PowerOn) == 0 {
+		return fmt.Errorf("powerOnMode exec requires exec.powerOn")
+	}
+	if cfg.ShutdownMode == "exec" && len(cfg.Exec.Shutdown) == 0 {
+		return fmt.Errorf("shutdownMode exec requires exec.shutdown")
+	}
+	for _, arg := range append(append([]string{}, cfg.Exec.PowerOn...), cfg.Exec.Shutdown...) {
+		if _, err := template.New("arg").Parse(arg); err != nil {
+			return fmt.Errorf("exec: invalid command template %q: %w", arg, err)
+		}
+	}
+
+	if cfg.PowerOnMode == "ipmi" {
+		if ref := cfg.IPMI.PasswordSecret; ref.Namespace == "" || ref.Name == "" || ref.Key == "" {
+			return fmt.Errorf("powerOnMode ipmi requires ipmi.passwordSecret {namespace, name, key}")
+		}
+	}
+
+	if cfg.ShutdownMode == "redfish" || cfg.PowerOnMode == "redfish" {
+		if ref := cfg.Redfish.PasswordSecret; ref.Namespace == "" || ref.Name == "" || ref.Key == "" {
+			return fmt.Errorf("redfish mode requires redfish.passwordSecret {namespace, name, key}")
+		}
+	}
+	if cfg.Redfish.MaxRetries <= 0 {
+		cfg.Redfish.MaxRetries = 3
+	}
+	if cfg.Redfish.ShutdownTimeoutSeconds <= 0 {
+		cfg.Redfish.ShutdownTimeoutSeconds = 300
+	}
+
+	switch cfg.LoadAverageStrategy.LoadSource {
+	case "":
+		cfg.LoadAverageStrategy.LoadSource = LoadSourceAgent
+	case LoadSourceAgent:
+	case LoadSourceSynthetic:
+		if err := cfg.LoadAverageStrategy.SyntheticProfile.validate(); err != nil {
+			return fmt.Errorf("loadAverageStrategy.syntheticProfile: %w", err)
+		}
+	default:
+		return fmt.Errorf("loadAverageStrategy.loadSource must be %q or %q, got %q",
+			LoadSourceAgent, LoadSourceSynthetic, cfg.LoadAverageStrategy.LoadSource)
+	}
+
+	switch cfg.MACDriftAction {
+	case "":
+		cfg.MACDriftAction = MACDriftRefresh
+	case MACDriftRefresh, MACDriftBlock:
+	default:
+		return fmt.Errorf("macDriftAction must be %q or %q, got %q", MACDriftRefresh, MACDriftBlock, cfg.MACDriftAction)
+	}
+
+	if cfg.MaxConcurrentEvictionsPerNode < 0 {
+		return fmt.Errorf("maxConcurrentEvictionsPerNode must be >= 0, got %d", cfg.MaxConcurrentEvictionsPerNode)
+	}
+
+	if cfg.Report.Interval < 0 || cfg.Report.TimeoutSeconds < 0 || cfg.Report.NodeWatts < 0 || cfg.Report.BlockedAfter < 0 {
+		return fmt.Errorf("report: interval, timeoutSeconds, nodeWatts and blockedAfter must be >= 0")
+	}
+	if cfg.Report.BlockedAfter == 0 {
+		cfg.Report.BlockedAfter = time.Hour
+	}
+
+	if cfg.ApprovalWebhook.TimeoutSeconds < 0 {
+		return fmt.Errorf("approvalWebhook.timeoutSeconds must be >= 0, got %d", cfg.ApprovalWebhook.TimeoutSeconds)
+	}
+
+	if cb := &cfg.CircuitBreaker; cb.FailureThreshold != 0 {
+		if cb.FailureThreshold < 0 {
+			return fmt.Errorf("circuitBreaker.failureThreshold must be >= 0, got %d", cb.FailureThreshold)
+		}
+		if cb.Window < 0 || cb.Cooldown < 0 {
+			return fmt.Errorf("circuitBreaker.window and circuitBreaker.cooldown must be >= 0")
+		}
+		if cb.Window == 0 {
+			cb.Window = time.Hour
+		}
+		if cb.Cooldown == 0 {
+			cb.Cooldown = 30 * time.Minute
+		}
+	}
+
+	if cfg.Notifications.Enabled && cfg.Notifications.WebhookURL == "" {
+		return fmt.Errorf("notifications.enabled requires notifications.webhookURL")
+	}
+	if cfg.Notifications.TimeoutSeconds < 0 {
+		return fmt.Errorf("notifications.timeoutSeconds must be >= 0, got %d", cfg.Notifications.TimeoutSeconds)
+	}
+
+	agent := &cfg.AgentHTTP
+	switch agent.Scheme {
+	case "":
+		agent.Scheme = "http"
+	case "http", "https":
+	default:
+		return fmt.Errorf("agentHTTP.scheme: unknown value %q", agent.Scheme)
+	}
+	if ref := agent.BearerTokenSecret; ref.Name != "" && (ref.Namespace == "" || ref.Key == "") {
+		return fmt.Errorf("agentHTTP.bearerTokenSecret: namespace, name and key must all be set")
+	}
+	if (agent.TLS.CertFile == "") != (agent.TLS.KeyFile == "") {
+		return fmt.Errorf("agentHTTP.tls: certFile and keyFile must be set together")
+	}
+
+	if ref := cfg.AdminAPI.TokenSecret; cfg.AdminAPI.Enabled && (ref.Namespace == "" || ref.Name == "" || ref.Key == "") {
+		return fmt.Errorf("adminAPI.tokenSecret: namespace, name and key are required when adminAPI is enabled")
+	}
+
+	if cfg.LoadAverageStrategy.MinLoadSamples < 0 {
+		return fmt.Errorf("loadAverageStrategy.minLoadSamples must be >= 0, got %d", cfg.LoadAverageStrategy.MinLoadSamples)
+	}
+
+	switch cfg.LoadAverageStrategy.LoadNormalization {
+	case "":
+		cfg.LoadAverageStrategy.LoadNormalization = LoadNormalizationPerLogicalCore
+	case LoadNormalizationPerLogicalCore, LoadNormalizationPerPhysicalCore, LoadNormalizationAbsolute:
+	default:
+		return fmt.Errorf("loadAverageStrategy.loadNormalization: unknown value %q", cfg.LoadAverageStrategy.LoadNormalization)
+	}
+
+	switch cfg.LoadAverageStrategy.AggregateDenominator {
+	case "":
+		cfg.LoadAverageStrategy.AggregateDenominator = AggregateDenominatorOnNodes
+	case AggregateDenominatorOnNodes, AggregateDenominatorAllManaged:
+	default:
+		return fmt.Errorf("loadAverageStrategy.aggregateDenominator: unknown value %q", cfg.LoadAverageStrategy.AggregateDenominator)
+	}
+
+	switch cfg.LoadAverageStrategy.MissingMetricsBehavior {
+	case "":
+		cfg.LoadAverageStrategy.MissingMetricsBehavior = MissingMetricsSkip
+	case MissingMetricsSkip, MissingMetricsTreatAsHigh, MissingMetricsTreatAsZero:
+	default:
+		return fmt.Errorf("loadAverageStrategy.missingMetricsBehavior: unknown value %q", cfg.LoadAverageStrategy.MissingMetricsBehavior)
+	}
+
+	if cfg.LoadAverageStrategy.LoadFetchConcurrency == 0 {
+		cfg.LoadAverageStrategy.LoadFetchConcurrency = 8
+	}
+	if cfg.LoadAverageStrategy.LoadFetchConcurrency < 0 {
+		return fmt.Errorf("loadAverageStrategy.loadFetchConcurrency must be > 0, got %d", cfg.LoadAverageStrategy.LoadFetchConcurrency)
+	}
+
+	policy := &cfg.LoadAverageStrategy.LoadUnavailablePolicy
+	for phase, val := range map[string]*string{"scaleDown": &policy.ScaleDown, "scaleUp": &policy.ScaleUp} {
+		switch *val {
+		case "":
+			*val = LoadUnavailableFailClosed
+		case LoadUnavailableFailClosed, LoadUnavailableFailOpen:
+		default:
+			return fmt.Errorf("loadAverageStrategy.loadUnavailablePolicy.%s: unknown value %q", phase, *val)
+		}
+	}
+
+	if err := cfg.LoadAverageStrategy.validate(); err != nil {
+		return err
+	}
+
 	// Add more defaults/validations here later
 
 	return nil
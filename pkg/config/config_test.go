@@ -154,3 +154,656 @@ rotation:
 		t.Fatalf("expected duration-related error, got: %v", err)
 	}
 }
+
+func TestApplyDefaultsAndValidate_LoadUnavailablePolicy(t *testing.T) {
+	cfg := &config.Config{}
+	if err := cfg.ApplyDefaultsAndValidate(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	policy := cfg.LoadAverageStrategy.LoadUnavailablePolicy
+	if policy.ScaleDown != config.LoadUnavailableFailClosed || policy.ScaleUp != config.LoadUnavailableFailClosed {
+		t.Errorf("expected both phases to default to failClosed, got %+v", policy)
+	}
+
+	cfg = &config.Config{}
+	cfg.LoadAverageStrategy.LoadUnavailablePolicy.ScaleUp = "sometimes"
+	if err := cfg.ApplyDefaultsAndValidate(); err == nil {
+		t.Fatal("expected error for unknown loadUnavailablePolicy value, got none")
+	}
+}
+
+func TestApplyDefaultsAndValidate_LoadNormalization(t *testing.T) {
+	cfg := &config.Config{}
+	if err := cfg.ApplyDefaultsAndValidate(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if got := cfg.LoadAverageStrategy.LoadNormalization; got != config.LoadNormalizationPerLogicalCore {
+		t.Errorf("expected default perLogicalCore, got %q", got)
+	}
+
+	cfg = &config.Config{}
+	cfg.LoadAverageStrategy.LoadNormalization = "perSocket"
+	if err := cfg.ApplyDefaultsAndValidate(); err == nil {
+		t.Fatal("expected error for unknown loadNormalization value, got none")
+	}
+}
+
+func TestApplyDefaultsAndValidate_IPMIRequiresPasswordSecret(t *testing.T) {
+	cfg := &config.Config{PowerOnMode: "ipmi"}
+	if err := cfg.ApplyDefaultsAndValidate(); err == nil {
+		t.Fatal("expected error for ipmi without passwordSecret, got none")
+	}
+
+	cfg = &config.Config{PowerOnMode: "ipmi"}
+	cfg.IPMI.PasswordSecret = config.SecretKeyRef{Namespace: "cba", Name: "bmc", Key: "password"}
+	if err := cfg.ApplyDefaultsAndValidate(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+}
+
+func TestApplyDefaultsAndValidate_SyntheticLoadSource(t *testing.T) {
+	cfg := &config.Config{}
+	if err := cfg.ApplyDefaultsAndValidate(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if cfg.LoadAverageStrategy.LoadSource != config.LoadSourceAgent {
+		t.Errorf("expected default loadSource %q, got %q", config.LoadSourceAgent, cfg.LoadAverageStrategy.LoadSource)
+	}
+
+	cfg = &config.Config{}
+	cfg.LoadAverageStrategy.LoadSource = config.LoadSourceSynthetic
+	if err := cfg.ApplyDefaultsAndValidate(); err == nil {
+		t.Fatal("expected error for synthetic loadSource without a profile, got none")
+	}
+
+	cfg.LoadAverageStrategy.SyntheticProfile.Cluster = []config.LoadPoint{{At: time.Minute, Load: 0.5}, {At: time.Minute, Load: 0.1}}
+	if err := cfg.ApplyDefaultsAndValidate(); err == nil {
+		t.Fatal("expected error for out-of-order points, got none")
+	}
+
+	cfg.LoadAverageStrategy.SyntheticProfile.Cluster[1].At = 2 * time.Minute
+	if err := cfg.ApplyDefaultsAndValidate(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+}
+
+func TestApplyDefaultsAndValidate_Redfish(t *testing.T) {
+	cfg := &config.Config{ShutdownMode: "redfish"}
+	if err := cfg.ApplyDefaultsAndValidate(); err == nil {
+		t.Fatal("expected error for redfish without passwordSecret, got none")
+	}
+
+	cfg = &config.Config{ShutdownMode: "redfish", PowerOnMode: "redfish"}
+	cfg.Redfish.PasswordSecret = config.SecretKeyRef{Namespace: "cba", Name: "bmc", Key: "password"}
+	if err := cfg.ApplyDefaultsAndValidate(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if cfg.Redfish.MaxRetries != 3 || cfg.Redfish.ShutdownTimeoutSeconds != 300 {
+		t.Errorf("expected defaults maxRetries=3 shutdownTimeoutSeconds=300, got %d/%d", cfg.Redfish.MaxRetries, cfg.Redfish.ShutdownTimeoutSeconds)
+	}
+}
+
+func TestApplyDefaultsAndValidate_ExecCommands(t *testing.T) {
+	cfg := &config.Config{ShutdownMode: "exec"}
+	if err := cfg.ApplyDefaultsAndValidate(); err == nil {
+		t.Fatal("expected error for shutdownMode exec without exec.shutdown, got none")
+	}
+
+	cfg = &config.Config{PowerOnMode: "exec"}
+	cfg.Exec.PowerOn = []string{"pdu-ctl", "{{.Node"}
+	if err := cfg.ApplyDefaultsAndValidate(); err == nil {
+		t.Fatal("expected error for unparsable template, got none")
+	}
+
+	cfg = &config.Config{PowerOnMode: "exec"}
+	cfg.Exec.PowerOn = []string{"pdu-ctl", "{{.Node}}", "on"}
+	if err := cfg.ApplyDefaultsAndValidate(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+}
+
+func TestApplyDefaultsAndValidate_PowerModes(t *testing.T) {
+	cfg := &config.Config{}
+	if err := cfg.ApplyDefaultsAndValidate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.ShutdownMode != "disabled" || cfg.PowerOnMode != "disabled" {
+		t.Errorf("modes = %q/%q, want disabled defaults", cfg.ShutdownMode, cfg.PowerOnMode)
+	}
+
+	wolAgent := config.WolAgentConfig{Port: 9101, Namespace: "cba", PodLabel: "app=wol-agent"}
+	shutdownManager := config.ShutdownManagerConfig{Port: 9102, Namespace: "cba", PodLabel: "app=shutdown"}
+	ok := &config.Config{
+		ShutdownMode: "http", ShutdownManager: shutdownManager,
+		PowerOnMode: "wol", WOLBroadcastAddr: "192.168.1.255", WolAgent: wolAgent,
+	}
+	if err := ok.ApplyDefaultsAndValidate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for name, bad := range map[string]*config.Config{
+		"unknown shutdown mode":        {ShutdownMode: "ssh"},
+		"unknown power-on mode":        {PowerOnMode: "magic"},
+		"http without shutdownManager": {ShutdownMode: "http"},
+		"http without manager port":    {ShutdownMode: "http", ShutdownManager: config.ShutdownManagerConfig{Namespace: "cba", PodLabel: "app=shutdown"}},
+		"wol without wolAgent":         {PowerOnMode: "wol", WOLBroadcastAddr: "192.168.1.255"},
+		"wol without wolBroadcastAddr": {PowerOnMode: "wol", WolAgent: wolAgent},
+	} {
+		if err := bad.ApplyDefaultsAndValidate(); err == nil {
+			t.Errorf("%s: expected error, got none", name)
+		}
+	}
+}
+
+func TestValueSourceConfig_Validate(t *testing.T) {
+	cases := []struct {
+		name    string
+		src     config.ValueSourceConfig
+		wantErr bool
+	}{
+		{"disabled", config.ValueSourceConfig{}, false},
+		{"configMap ok", config.ValueSourceConfig{Type: "configMap", Namespace: "ns", Name: "cm", Key: "k"}, false},
+		{"configMap missing key", config.ValueSourceConfig{Type: "configMap", Namespace: "ns", Name: "cm"}, true},
+		{"annotation ok", config.ValueSourceConfig{Type: "annotation", Name: "node1", Annotation: "a"}, false},
+		{"http missing url", config.ValueSourceConfig{Type: "http"}, true},
+		{"unknown type", config.ValueSourceConfig{Type: "etcd"}, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.src.Validate()
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("wantErr=%v, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestApplyDefaultsAndValidate_WOLInterfacePreference(t *testing.T) {
+	cfg := &config.Config{WOLInterfacePreference: []string{"eno1", "enp*"}}
+	if err := cfg.ApplyDefaultsAndValidate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cfg = &config.Config{WOLInterfacePreference: []string{"enp[0-"}}
+	if err := cfg.ApplyDefaultsAndValidate(); err == nil {
+		t.Fatal("expected error for malformed interface pattern, got none")
+	}
+}
+
+func TestConfig_DryRunSplit(t *testing.T) {
+	cases := []struct {
+		name             string
+		cfg              config.Config
+		wantPower, wantK bool
+	}{
+		{"off", config.Config{}, false, false},
+		{"full dry-run covers both", config.Config{DryRun: true}, true, true},
+		{"power only", config.Config{DryRunPower: true}, true, false},
+		{"k8s only", config.Config{DryRunK8s: true}, false, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.cfg.IsPowerDryRun(); got != tc.wantPower {
+				t.Errorf("IsPowerDryRun = %v, want %v", got, tc.wantPower)
+			}
+			if got := tc.cfg.IsK8sDryRun(); got != tc.wantK {
+				t.Errorf("IsK8sDryRun = %v, want %v", got, tc.wantK)
+			}
+		})
+	}
+}
+
+func TestApplyDefaultsAndValidate_UpgradeGuard(t *testing.T) {
+	cases := []struct {
+		name    string
+		guard   config.UpgradeGuardConfig
+		wantErr bool
+	}{
+		{"disabled", config.UpgradeGuardConfig{}, false},
+		{"threshold only", config.UpgradeGuardConfig{Enabled: true, NotReadyThreshold: 3}, false},
+		{"sentinel only", config.UpgradeGuardConfig{Enabled: true, Sentinel: config.UpgradeSentinelConfig{Name: "cp-1", Key: "upgrading"}}, false},
+		{"enabled without signal", config.UpgradeGuardConfig{Enabled: true}, true},
+		{"sentinel without key", config.UpgradeGuardConfig{Enabled: true, Sentinel: config.UpgradeSentinelConfig{Name: "cp-1"}}, true},
+		{"negative threshold", config.UpgradeGuardConfig{Enabled: true, NotReadyThreshold: -1}, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &config.Config{UpgradeGuard: tc.guard}
+			err := cfg.ApplyDefaultsAndValidate()
+			if (err != nil) != tc.wantErr {
+				t.Errorf("err = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestApplyDefaultsAndValidate_NodeCapacityByType(t *testing.T) {
+	cfg := &config.Config{NodeCapacityByType: config.NodeCapacityByTypeConfig{
+		Types: map[string]config.NodeCapacity{"small": {CPU: "4", Memory: "16Gi"}},
+	}}
+	if err := cfg.ApplyDefaultsAndValidate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cfg = &config.Config{NodeCapacityByType: config.NodeCapacityByTypeConfig{
+		Types: map[string]config.NodeCapacity{"small": {CPU: "four"}},
+	}}
+	if err := cfg.ApplyDefaultsAndValidate(); err == nil {
+		t.Fatal("expected error for malformed quantity, got none")
+	}
+}
+
+func TestApplyDefaultsAndValidate_AgentHTTP(t *testing.T) {
+	cases := []struct {
+		name    string
+		agent   config.AgentHTTPConfig
+		wantErr bool
+	}{
+		{"empty", config.AgentHTTPConfig{}, false},
+		{"https with mTLS", config.AgentHTTPConfig{Scheme: "https", TLS: config.AgentTLSConfig{CertFile: "c.pem", KeyFile: "k.pem"}}, false},
+		{"unknown scheme", config.AgentHTTPConfig{Scheme: "ftp"}, true},
+		{"cert without key", config.AgentHTTPConfig{TLS: config.AgentTLSConfig{CertFile: "c.pem"}}, true},
+		{"secret without key", config.AgentHTTPConfig{BearerTokenSecret: config.SecretKeyRef{Namespace: "cba", Name: "auth"}}, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &config.Config{AgentHTTP: tc.agent}
+			err := cfg.ApplyDefaultsAndValidate()
+			if (err != nil) != tc.wantErr {
+				t.Errorf("err = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+
+	cfg := &config.Config{}
+	if err := cfg.ApplyDefaultsAndValidate(); err != nil || cfg.AgentHTTP.Scheme != "http" {
+		t.Errorf("scheme default = %q (err %v), want http", cfg.AgentHTTP.Scheme, err)
+	}
+}
+
+func TestApplyDefaultsAndValidate_CriticalDaemonSets(t *testing.T) {
+	cfg := &config.Config{CriticalDaemonSets: []string{"storage/csi-node"}}
+	if err := cfg.ApplyDefaultsAndValidate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, bad := range []string{"csi-node", "/csi-node", "storage/"} {
+		cfg = &config.Config{CriticalDaemonSets: []string{bad}}
+		if err := cfg.ApplyDefaultsAndValidate(); err == nil {
+			t.Errorf("expected error for %q, got none", bad)
+		}
+	}
+}
+
+func TestApplyDefaultsAndValidate_DrainRetryInterval(t *testing.T) {
+	cfg := &config.Config{}
+	if err := cfg.ApplyDefaultsAndValidate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.DrainRetryInterval != 5*time.Second {
+		t.Errorf("DrainRetryInterval = %s, want 5s default", cfg.DrainRetryInterval)
+	}
+
+	cfg = &config.Config{DrainRetryInterval: -time.Second}
+	if err := cfg.ApplyDefaultsAndValidate(); err == nil {
+		t.Fatal("expected error for negative drainRetryInterval, got none")
+	}
+}
+
+func TestApplyDefaultsAndValidate_DisruptionLease(t *testing.T) {
+	cfg := &config.Config{DisruptionLease: config.DisruptionLeaseConfig{Enabled: true, Namespace: "kube-system", Name: "cluster-disruption"}}
+	if err := cfg.ApplyDefaultsAndValidate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := cfg.DisruptionLease; got.DurationSeconds != 900 || got.Identity != "cluster-bare-autoscaler" {
+		t.Errorf("defaults not applied: %+v", got)
+	}
+
+	cfg = &config.Config{DisruptionLease: config.DisruptionLeaseConfig{Enabled: true, Namespace: "kube-system"}}
+	if err := cfg.ApplyDefaultsAndValidate(); err == nil {
+		t.Fatal("expected error for missing lease name, got none")
+	}
+}
+
+func TestApplyDefaultsAndValidate_PersistState(t *testing.T) {
+	cfg := &config.Config{PersistState: true, StateConfigMap: config.StateConfigMapConfig{Namespace: "cba"}}
+	if err := cfg.ApplyDefaultsAndValidate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.StateConfigMap.Name != "cba-state" {
+		t.Errorf("StateConfigMap.Name = %q, want cba-state default", cfg.StateConfigMap.Name)
+	}
+
+	cfg = &config.Config{PersistState: true}
+	if err := cfg.ApplyDefaultsAndValidate(); err == nil {
+		t.Fatal("expected error for missing stateConfigMap.namespace, got none")
+	}
+}
+
+func TestApplyDefaultsAndValidate_DrainGracePeriodSeconds(t *testing.T) {
+	cfg := &config.Config{DrainGracePeriodSeconds: 20}
+	if err := cfg.ApplyDefaultsAndValidate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.DrainGracePeriod != 20*time.Second {
+		t.Errorf("DrainGracePeriod = %s, want 20s", cfg.DrainGracePeriod)
+	}
+
+	for _, bad := range []*config.Config{
+		{DrainGracePeriodSeconds: -1},
+		{DrainGracePeriodSeconds: 20, DrainGracePeriod: time.Minute},
+	} {
+		if err := bad.ApplyDefaultsAndValidate(); err == nil {
+			t.Errorf("expected error for %+v, got none", bad.DrainGracePeriodSeconds)
+		}
+	}
+}
+
+func TestApplyDefaultsAndValidate_StrategyLists(t *testing.T) {
+	cpu := 25
+	cfg := &config.Config{
+		ScaleDownStrategies: []config.StrategyEntry{
+			{Name: config.StrategyResourceAware, ResourceAware: &config.ResourceAwareParams{BufferCPUPerc: &cpu}},
+			{Name: config.StrategyLoadAverage, LoadAverage: &config.LoadAverageStrategyConfig{
+				ScaleDownThreshold: 0.3, ScaleUpThreshold: 0.8, PodLabel: "app=metrics", Namespace: "cba", Port: 9100,
+			}},
+		},
+	}
+	if err := cfg.ApplyDefaultsAndValidate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.ResourceBufferCPUPerc != 25 {
+		t.Errorf("ResourceBufferCPUPerc = %d, want 25 from the list entry", cfg.ResourceBufferCPUPerc)
+	}
+	if !cfg.LoadAverageStrategy.Enabled || cfg.LoadAverageStrategy.ScaleDownThreshold != 0.3 {
+		t.Errorf("loadAverage entry not translated: %+v", cfg.LoadAverageStrategy)
+	}
+	if got := cfg.ScaleUpChain(); len(got) != 1 || got[0] != config.StrategyMinNodeCount {
+		t.Errorf("ScaleUpChain = %v, want legacy [minNodeCount]", got)
+	}
+
+	for name, bad := range map[string]*config.Config{
+		"unknown":               {ScaleDownStrategies: []config.StrategyEntry{{Name: "gpuGuard"}}},
+		"wrong chain":           {ScaleUpStrategies: []config.StrategyEntry{{Name: config.StrategyResourceAware}}},
+		"gpu guard on scale-up": {ScaleUpStrategies: []config.StrategyEntry{{Name: config.StrategyGPUAware}}},
+		"duplicate":             {ScaleDownStrategies: []config.StrategyEntry{{Name: config.StrategyResourceAware}, {Name: config.StrategyResourceAware}}},
+		"params mismatch": {
+			ScaleDownStrategies: []config.StrategyEntry{{Name: config.StrategyLoadAverage, LoadAverage: &config.LoadAverageStrategyConfig{ScaleDownThreshold: 0.3}}},
+			ScaleUpStrategies:   []config.StrategyEntry{{Name: config.StrategyLoadAverage, LoadAverage: &config.LoadAverageStrategyConfig{ScaleUpThreshold: 0.8}}},
+		},
+	} {
+		if err := bad.ApplyDefaultsAndValidate(); err == nil {
+			t.Errorf("%s: expected error, got none", name)
+		}
+	}
+}
+
+func TestApplyDefaultsAndValidate_StaleMetricsPolicy(t *testing.T) {
+	cfg := &config.Config{ResourceAware: config.ResourceAwareConfig{MaxMetricsAge: time.Minute}}
+	if err := cfg.ApplyDefaultsAndValidate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.ResourceAware.StaleMetricsPolicy != config.StaleMetricsDeny {
+		t.Errorf("StaleMetricsPolicy = %q, want deny default", cfg.ResourceAware.StaleMetricsPolicy)
+	}
+
+	cfg = &config.Config{ResourceAware: config.ResourceAwareConfig{StaleMetricsPolicy: "ignore"}}
+	if err := cfg.ApplyDefaultsAndValidate(); err == nil {
+		t.Fatal("expected error for unknown staleMetricsPolicy, got none")
+	}
+}
+
+func TestApplyDefaultsAndValidate_ManagedSelector(t *testing.T) {
+	cfg := &config.Config{NodeLabels: config.NodeLabelConfig{ManagedSelector: "node-pool in (batch,ci)"}}
+	if err := cfg.ApplyDefaultsAndValidate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cfg = &config.Config{NodeLabels: config.NodeLabelConfig{ManagedSelector: "node-pool in (batch"}}
+	if err := cfg.ApplyDefaultsAndValidate(); err == nil {
+		t.Fatal("expected error for malformed managedSelector, got none")
+	}
+}
+
+func TestApplyDefaultsAndValidate_MaxPoweredOff(t *testing.T) {
+	cfg := &config.Config{MaxPoweredOff: -1}
+	if err := cfg.ApplyDefaultsAndValidate(); err == nil {
+		t.Fatal("expected error for negative maxPoweredOff, got none")
+	}
+}
+
+func TestApplyDefaultsAndValidate_AbsoluteMinNodes(t *testing.T) {
+	cfg := &config.Config{AbsoluteMinNodes: -1}
+	if err := cfg.ApplyDefaultsAndValidate(); err == nil {
+		t.Fatal("expected error for negative absoluteMinNodes, got none")
+	}
+}
+
+func TestParse_Revision(t *testing.T) {
+	a, err := config.Parse([]byte("minNodes: 1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := config.Parse([]byte("minNodes: 2\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Revision == "" || a.Revision == b.Revision {
+		t.Errorf("expected distinct non-empty revisions, got %q and %q", a.Revision, b.Revision)
+	}
+}
+
+func TestApplyDefaultsAndValidate_CircuitBreaker(t *testing.T) {
+	cfg := &config.Config{CircuitBreaker: config.CircuitBreakerConfig{FailureThreshold: 3}}
+	if err := cfg.ApplyDefaultsAndValidate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.CircuitBreaker.Window != time.Hour || cfg.CircuitBreaker.Cooldown != 30*time.Minute {
+		t.Errorf("expected window 1h and cooldown 30m by default, got %v / %v", cfg.CircuitBreaker.Window, cfg.CircuitBreaker.Cooldown)
+	}
+
+	cfg = &config.Config{CircuitBreaker: config.CircuitBreakerConfig{FailureThreshold: -1}}
+	if err := cfg.ApplyDefaultsAndValidate(); err == nil {
+		t.Error("expected error for negative circuitBreaker.failureThreshold, got none")
+	}
+}
+
+func TestApplyDefaultsAndValidate_AdminAPI(t *testing.T) {
+	cfg := &config.Config{AdminAPI: config.AdminAPIConfig{Enabled: true}}
+	if err := cfg.ApplyDefaultsAndValidate(); err == nil {
+		t.Fatal("expected error for adminAPI without tokenSecret, got none")
+	}
+
+	cfg = &config.Config{AdminAPI: config.AdminAPIConfig{
+		Enabled:     true,
+		TokenSecret: config.SecretKeyRef{Namespace: "cba", Name: "cba-admin", Key: "token"},
+	}}
+	if err := cfg.ApplyDefaultsAndValidate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestApplyDefaultsAndValidate_RotationTieBreak(t *testing.T) {
+	cfg := &config.Config{}
+	if err := cfg.ApplyDefaultsAndValidate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Rotation.TieBreak != config.RotationTieBreakName {
+		t.Errorf("Rotation.TieBreak = %q, want %q", cfg.Rotation.TieBreak, config.RotationTieBreakName)
+	}
+
+	cfg = &config.Config{Rotation: config.RotationConfig{TieBreak: "oldestFirst"}}
+	if err := cfg.ApplyDefaultsAndValidate(); err == nil {
+		t.Fatal("expected error for unknown rotation.tieBreak, got none")
+	}
+}
+
+func TestApplyDefaultsAndValidate_AdaptivePolling(t *testing.T) {
+	cfg := &config.Config{PollInterval: time.Minute, MaxPollInterval: 30 * time.Second}
+	if err := cfg.ApplyDefaultsAndValidate(); err == nil {
+		t.Fatal("expected error for maxPollInterval below pollInterval, got none")
+	}
+
+	cfg = &config.Config{PollInterval: time.Minute, MaxPollInterval: 10 * time.Minute}
+	if err := cfg.ApplyDefaultsAndValidate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.PollBackoffAfter != 3 {
+		t.Errorf("PollBackoffAfter = %d, want 3", cfg.PollBackoffAfter)
+	}
+}
+
+func TestApplyDefaultsAndValidate_ScaleDownDisabledAnnotation(t *testing.T) {
+	cfg := &config.Config{}
+	if err := cfg.ApplyDefaultsAndValidate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := cfg.NodeAnnotations.ScaleDownDisabled; got != config.DefaultScaleDownDisabledAnnotation {
+		t.Errorf("NodeAnnotations.ScaleDownDisabled = %q, want %q", got, config.DefaultScaleDownDisabledAnnotation)
+	}
+}
+
+func TestApplyDefaultsAndValidate_AggregateDenominator(t *testing.T) {
+	cfg := &config.Config{}
+	if err := cfg.ApplyDefaultsAndValidate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := cfg.LoadAverageStrategy.AggregateDenominator; got != config.AggregateDenominatorOnNodes {
+		t.Errorf("AggregateDenominator = %q, want %q", got, config.AggregateDenominatorOnNodes)
+	}
+
+	cfg = &config.Config{}
+	cfg.LoadAverageStrategy.AggregateDenominator = "everything"
+	if err := cfg.ApplyDefaultsAndValidate(); err == nil {
+		t.Fatal("expected error for unknown aggregateDenominator, got none")
+	}
+}
+
+func TestApplyDefaultsAndValidate_MissingMetricsBehavior(t *testing.T) {
+	cfg := &config.Config{}
+	if err := cfg.ApplyDefaultsAndValidate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := cfg.LoadAverageStrategy.MissingMetricsBehavior; got != config.MissingMetricsSkip {
+		t.Errorf("MissingMetricsBehavior = %q, want %q", got, config.MissingMetricsSkip)
+	}
+
+	cfg = &config.Config{}
+	cfg.LoadAverageStrategy.MissingMetricsBehavior = "treatAsBusy"
+	if err := cfg.ApplyDefaultsAndValidate(); err == nil {
+		t.Fatal("expected error for unknown missingMetricsBehavior, got none")
+	}
+}
+
+func TestApplyDefaultsAndValidate_LoadFetchConcurrency(t *testing.T) {
+	cfg := &config.Config{}
+	if err := cfg.ApplyDefaultsAndValidate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := cfg.LoadAverageStrategy.LoadFetchConcurrency; got != 8 {
+		t.Errorf("LoadFetchConcurrency = %d, want 8", got)
+	}
+
+	cfg = &config.Config{}
+	cfg.LoadAverageStrategy.LoadFetchConcurrency = -1
+	if err := cfg.ApplyDefaultsAndValidate(); err == nil {
+		t.Fatal("expected error for negative loadFetchConcurrency, got none")
+	}
+}
+
+func TestApplyDefaultsAndValidate_PrometheusStrategy(t *testing.T) {
+	params := &config.PrometheusStrategyConfig{URL: "http://prometheus:9090", NodeQuery: `load{node="$node"}`, ClusterQuery: "avg(load)"}
+	cfg := &config.Config{ScaleUpStrategies: []config.StrategyEntry{
+		{Name: config.StrategyMinNodeCount},
+		{Name: config.StrategyPrometheus, Prometheus: params},
+	}}
+	if err := cfg.ApplyDefaultsAndValidate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.PrometheusStrategy.Enabled || cfg.PrometheusStrategy.URL != params.URL {
+		t.Errorf("prometheus entry not translated: %+v", cfg.PrometheusStrategy)
+	}
+	if cfg.PrometheusStrategy.TimeoutSeconds != 5 {
+		t.Errorf("TimeoutSeconds = %d, want default 5", cfg.PrometheusStrategy.TimeoutSeconds)
+	}
+
+	cfg = &config.Config{PrometheusStrategy: config.PrometheusStrategyConfig{Enabled: true, URL: "http://prometheus:9090"}}
+	if err := cfg.ApplyDefaultsAndValidate(); err == nil {
+		t.Fatal("expected error for prometheusStrategy without queries, got none")
+	}
+}
+
+func TestApplyDefaultsAndValidate_ExcludeTaints(t *testing.T) {
+	cfg := &config.Config{ExcludeTaints: []config.TaintMatch{{Key: "maintenance", Value: "true", Effect: "NoSchedule"}}}
+	if err := cfg.ApplyDefaultsAndValidate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for name, bad := range map[string]config.TaintMatch{
+		"missing key":    {Effect: "NoSchedule"},
+		"unknown effect": {Key: "maintenance", Effect: "NoWay"},
+	} {
+		cfg := &config.Config{ExcludeTaints: []config.TaintMatch{bad}}
+		if err := cfg.ApplyDefaultsAndValidate(); err == nil {
+			t.Errorf("%s: expected error, got none", name)
+		}
+	}
+}
+
+func TestApplyDefaultsAndValidate_LoadAverageStrategy(t *testing.T) {
+	valid := func() config.LoadAverageStrategyConfig {
+		return config.LoadAverageStrategyConfig{
+			Enabled: true, NodeThreshold: 0.7, ScaleDownThreshold: 0.5, ScaleUpThreshold: 0.75,
+			PodLabel: "app=metrics", Namespace: "cba", Port: 9100,
+		}
+	}
+
+	cfg := &config.Config{LoadAverageStrategy: valid()}
+	if err := cfg.ApplyDefaultsAndValidate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := cfg.LoadAverageStrategy; got.TimeoutSeconds != 3 || got.ClusterEval != "average" {
+		t.Errorf("defaults not applied: timeoutSeconds=%d clusterEval=%q", got.TimeoutSeconds, got.ClusterEval)
+	}
+
+	// Disabled strategies are not checked beyond their defaults.
+	cfg = &config.Config{LoadAverageStrategy: config.LoadAverageStrategyConfig{ScaleUpThreshold: 5}}
+	if err := cfg.ApplyDefaultsAndValidate(); err != nil {
+		t.Fatalf("unexpected error for disabled strategy: %v", err)
+	}
+
+	absolute := valid()
+	absolute.LoadNormalization = config.LoadNormalizationAbsolute
+	absolute.NodeThreshold, absolute.ScaleDownThreshold, absolute.ScaleUpThreshold = 4, 8, 12
+	synthetic := valid()
+	synthetic.LoadSource = config.LoadSourceSynthetic
+	synthetic.SyntheticProfile = config.SyntheticLoadProfile{Cluster: []config.LoadPoint{{Load: 0.2}}}
+	synthetic.Port, synthetic.PodLabel, synthetic.Namespace = 0, "", ""
+	for name, ok := range map[string]config.LoadAverageStrategyConfig{
+		"absolute normalization allows raw thresholds": absolute,
+		"synthetic source needs no metrics pods":       synthetic,
+	} {
+		cfg := &config.Config{LoadAverageStrategy: ok}
+		if err := cfg.ApplyDefaultsAndValidate(); err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+		}
+	}
+
+	for name, mutate := range map[string]func(*config.LoadAverageStrategyConfig){
+		"zero port":               func(l *config.LoadAverageStrategyConfig) { l.Port = 0 },
+		"empty pod label":         func(l *config.LoadAverageStrategyConfig) { l.PodLabel = "" },
+		"empty namespace":         func(l *config.LoadAverageStrategyConfig) { l.Namespace = "" },
+		"threshold above one":     func(l *config.LoadAverageStrategyConfig) { l.NodeThreshold = 1.5 },
+		"negative threshold":      func(l *config.LoadAverageStrategyConfig) { l.ScaleDownThreshold = -0.1 },
+		"scale-up not above down": func(l *config.LoadAverageStrategyConfig) { l.ScaleUpThreshold = l.ScaleDownThreshold },
+		"unknown cluster eval":    func(l *config.LoadAverageStrategyConfig) { l.ClusterEval = "p99" },
+		"negative timeout":        func(l *config.LoadAverageStrategyConfig) { l.TimeoutSeconds = -1 },
+	} {
+		l := valid()
+		mutate(&l)
+		cfg := &config.Config{LoadAverageStrategy: l}
+		if err := cfg.ApplyDefaultsAndValidate(); err == nil {
+			t.Errorf("%s: expected error, got none", name)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata" // schedule.timezone must resolve in images without zoneinfo
+)
+
+// ScheduleConfig restricts scale-down and scale-up to time windows. An empty window list allows
+// that phase at any time.
+type ScheduleConfig struct {
+	Timezone  string       `yaml:"timezone"` // IANA name, e.g. Europe/Warsaw; defaults to UTC
+	ScaleDown []TimeWindow `yaml:"scaleDown"`
+	ScaleUp   []TimeWindow `yaml:"scaleUp"`
+}
+
+// TimeWindow is a daily window from Start to End ("HH:MM", End may be "24:00"). A window with End
+// before Start crosses midnight; its part after midnight belongs to the day it started on.
+type TimeWindow struct {
+	Name  string   `yaml:"name,omitempty"`
+	Start string   `yaml:"start"`
+	End   string   `yaml:"end"`
+	Days  []string `yaml:"days,omitempty"` // Mon..Sun; empty means every day
+}
+
+// Enabled reports whether any window is configured.
+func (s ScheduleConfig) Enabled() bool {
+	return len(s.ScaleDown) > 0 || len(s.ScaleUp) > 0
+}
+
+// ScaleDownWindow reports whether scale-down may run at now, and the name of the window allowing it.
+func (s ScheduleConfig) ScaleDownWindow(now time.Time) (string, bool) {
+	return s.activeWindow(s.ScaleDown, now)
+}
+
+// ScaleUpWindow reports whether scale-up may run at now, and the name of the window allowing it.
+func (s ScheduleConfig) ScaleUpWindow(now time.Time) (string, bool) {
+	return s.activeWindow(s.ScaleUp, now)
+}
+
+func (s ScheduleConfig) activeWindow(windows []TimeWindow, now time.Time) (string, bool) {
+	if len(windows) == 0 {
+		return "", true
+	}
+	loc, err := s.location()
+	if err != nil {
+		loc = time.UTC // rejected by validate; only reachable for unvalidated configs
+	}
+	local := now.In(loc)
+	for _, w := range windows {
+		if w.contains(local) {
+			return w.String(), true
+		}
+	}
+	return "", false
+}
+
+func (s ScheduleConfig) location() (*time.Location, error) {
+	if s.Timezone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(s.Timezone)
+}
+
+func (s ScheduleConfig) validate() error {
+	if _, err := s.location(); err != nil {
+		return fmt.Errorf("schedule.timezone: %w", err)
+	}
+	for i, w := range s.ScaleDown {
+		if err := w.validate(); err != nil {
+			return fmt.Errorf("schedule.scaleDown[%d]: %w", i, err)
+		}
+	}
+	for i, w := range s.ScaleUp {
+		if err := w.validate(); err != nil {
+			return fmt.Errorf("schedule.scaleUp[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// String names the window for logs and /status: Name if set, otherwise its days and hours.
+func (w TimeWindow) String() string {
+	if w.Name != "" {
+		return w.Name
+	}
+	hours := w.Start + "-" + w.End
+	if len(w.Days) == 0 {
+		return hours
+	}
+	return strings.Join(w.Days, ",") + " " + hours
+}
+
+func (w TimeWindow) validate() error {
+	start, err := parseClock(w.Start)
+	if err != nil {
+		return fmt.Errorf("start: %w", err)
+	}
+	end, err := parseClock(w.End)
+	if err != nil {
+		return fmt.Errorf("end: %w", err)
+	}
+	if start == end || start == 24*60 {
+		return fmt.Errorf("start %s and end %s do not form a window", w.Start, w.End)
+	}
+	for _, d := range w.Days {
+		if _, ok := weekdays[strings.ToLower(d)]; !ok {
+			return fmt.Errorf("unknown day %q (want Mon..Sun)", d)
+		}
+	}
+	return nil
+}
+
+func (w TimeWindow) contains(t time.Time) bool {
+	start, err := parseClock(w.Start)
+	if err != nil {
+		return false
+	}
+	end, err := parseClock(w.End)
+	if err != nil {
+		return false
+	}
+	minute := t.Hour()*60 + t.Minute()
+	if start < end {
+		return minute >= start && minute < end && w.onDay(t.Weekday())
+	}
+	if minute >= start {
+		return w.onDay(t.Weekday())
+	}
+	return minute < end && w.onDay((t.Weekday()+6)%7)
+}
+
+func (w TimeWindow) onDay(d time.Weekday) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+	for _, name := range w.Days {
+		if wd, ok := weekdays[strings.ToLower(name)]; ok && wd == d {
+			return true
+		}
+	}
+	return false
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// parseClock turns "HH:MM" into minutes after midnight; "24:00" is accepted as the end of the day.
+func parseClock(s string) (int, error) {
+	h, m, ok := strings.Cut(s, ":")
+	if !ok || len(m) != 2 {
+		return 0, fmt.Errorf("%q is not HH:MM", s)
+	}
+	hour, err := strconv.Atoi(h)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not HH:MM", s)
+	}
+	minute, err := strconv.Atoi(m)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not HH:MM", s)
+	}
+	if hour < 0 || minute < 0 || minute > 59 || hour > 24 || (hour == 24 && minute != 0) {
+		return 0, fmt.Errorf("%q is out of range", s)
+	}
+	return hour*60 + minute, nil
+}
@@ -0,0 +1,84 @@
+package config_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+)
+
+func TestScheduleConfig_ScaleDownWindow(t *testing.T) {
+	sched := config.ScheduleConfig{
+		Timezone: "Europe/Warsaw",
+		ScaleDown: []config.TimeWindow{
+			{Name: "weeknights", Start: "22:00", End: "06:00", Days: []string{"Mon", "Tue", "Wed", "Thu", "Fri"}},
+			{Start: "00:00", End: "24:00", Days: []string{"sat", "sun"}},
+		},
+	}
+	warsaw, err := time.LoadLocation("Europe/Warsaw")
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, time.January, day, hour, minute, 0, 0, warsaw) // Jan 1, 2024 is a Monday
+	}
+
+	tests := []struct {
+		name       string
+		now        time.Time
+		wantOK     bool
+		wantWindow string
+	}{
+		{"monday business hours", at(1, 12, 0), false, ""},
+		{"monday late evening", at(1, 23, 30), true, "weeknights"},
+		{"tuesday early morning continues monday's window", at(2, 5, 59), true, "weeknights"},
+		{"window end is exclusive", at(2, 6, 0), false, ""},
+		{"saturday early morning continues friday's window", at(6, 3, 0), true, "weeknights"},
+		{"saturday afternoon", at(6, 15, 0), true, "sat,sun 00:00-24:00"},
+		{"monday early morning does not continue sunday's window", at(8, 3, 0), false, ""},
+		{"timezone applies to UTC input", time.Date(2024, time.January, 1, 21, 30, 0, 0, time.UTC), true, "weeknights"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			window, ok := sched.ScaleDownWindow(tt.now)
+			if ok != tt.wantOK || window != tt.wantWindow {
+				t.Errorf("ScaleDownWindow(%s) = (%q, %v), want (%q, %v)", tt.now, window, ok, tt.wantWindow, tt.wantOK)
+			}
+		})
+	}
+
+	if window, ok := sched.ScaleUpWindow(at(1, 12, 0)); !ok || window != "" {
+		t.Errorf("empty scaleUp schedule must always allow, got (%q, %v)", window, ok)
+	}
+}
+
+func TestLoad_Schedule(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr bool
+	}{
+		{"valid", "schedule:\n  timezone: America/New_York\n  scaleDown:\n    - {start: \"20:00\", end: \"07:00\", days: [Fri]}\n", false},
+		{"unknown timezone", "schedule:\n  timezone: Mars/Olympus\n", true},
+		{"bad clock", "schedule:\n  scaleUp:\n    - {start: \"25:00\", end: \"07:00\"}\n", true},
+		{"empty window", "schedule:\n  scaleDown:\n    - {start: \"07:00\", end: \"07:00\"}\n", true},
+		{"unknown day", "schedule:\n  scaleDown:\n    - {start: \"01:00\", end: \"02:00\", days: [Someday]}\n", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmp, err := os.CreateTemp("", "schedule-config*.yaml")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.Remove(tmp.Name())
+			tmp.WriteString(tt.yaml)
+			tmp.Close()
+
+			_, err = config.Load(tmp.Name())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
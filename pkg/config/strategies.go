@@ -0,0 +1,228 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// Strategy names accepted in scaleDownStrategies / scaleUpStrategies.
+const (
+	StrategyResourceAware = "resourceAware" // scale-down only
+	StrategyLoadAverage   = "loadAverage"   // scale-down and scale-up
+	StrategyMinNodeCount  = "minNodeCount"  // scale-up only
+	StrategyPrometheus    = "prometheus"    // scale-down and scale-up
+	StrategyGPUAware      = "gpuAware"      // scale-down only
+)
+
+// StrategyEntry is one element of a strategy chain. Parameters go in the block named after the
+// strategy; without one, the strategy uses the top-level settings (loadAverageStrategy,
+// prometheusStrategy, resourceBuffer*Perc, resourceAware). gpuAware always reads gpuAwareStrategy.
+type StrategyEntry struct {
+	Name    string `yaml:"name"`
+	Enabled *bool  `yaml:"enabled,omitempty"` // default true
+
+	ResourceAware *ResourceAwareParams       `yaml:"resourceAware,omitempty"`
+	LoadAverage   *LoadAverageStrategyConfig `yaml:"loadAverage,omitempty"`
+	Prometheus    *PrometheusStrategyConfig  `yaml:"prometheus,omitempty"`
+}
+
+// ResourceAwareParams carries the resourceAware strategy's parameters in list form; unset fields
+// keep the top-level values.
+type ResourceAwareParams struct {
+	BufferCPUPerc             *int     `yaml:"bufferCPUPerc,omitempty"`
+	BufferMemoryPerc          *int     `yaml:"bufferMemoryPerc,omitempty"`
+	BufferEphemeralPerc       *int     `yaml:"bufferEphemeralPerc,omitempty"`
+	MaxCandidateUsageFraction *float64 `yaml:"maxCandidateUsageFraction,omitempty"`
+}
+
+// IsEnabled reports whether the entry takes part in its chain.
+func (e StrategyEntry) IsEnabled() bool {
+	return e.Enabled == nil || *e.Enabled
+}
+
+// ScaleDownChain returns the enabled scale-down strategy names in order. Before
+// ApplyDefaultsAndValidate has filled scaleDownStrategies, it derives them from the legacy fields.
+func (cfg *Config) ScaleDownChain() []string {
+	if len(cfg.ScaleDownStrategies) == 0 {
+		chain := cfg.legacyChain(StrategyResourceAware)
+		if cfg.GPUAwareStrategy.Enabled {
+			chain = append(chain, StrategyGPUAware)
+		}
+		return chain
+	}
+	return enabledNames(cfg.ScaleDownStrategies)
+}
+
+// ScaleUpChain is ScaleDownChain for scaleUpStrategies.
+func (cfg *Config) ScaleUpChain() []string {
+	if len(cfg.ScaleUpStrategies) == 0 {
+		return cfg.legacyChain(StrategyMinNodeCount)
+	}
+	return enabledNames(cfg.ScaleUpStrategies)
+}
+
+func (cfg *Config) legacyChain(base string) []string {
+	chain := []string{base}
+	if cfg.LoadAverageStrategy.Enabled {
+		chain = append(chain, StrategyLoadAverage)
+	}
+	if cfg.PrometheusStrategy.Enabled {
+		chain = append(chain, StrategyPrometheus)
+	}
+	return chain
+}
+
+func enabledNames(entries []StrategyEntry) []string {
+	var names []string
+	for _, e := range entries {
+		if e.IsEnabled() {
+			names = append(names, e.Name)
+		}
+	}
+	return names
+}
+
+// resolveStrategies makes the list form and the legacy fields agree. An empty list is filled from
+// the legacy fields (resourceAware for scale-down, minNodeCount for scale-up, each followed by
+// loadAverage and prometheus when enabled, and gpuAware last for scale-down); parameters given in list entries are copied to the
+// legacy fields, which the rest of the controller reads.
+func (cfg *Config) resolveStrategies() error {
+	if len(cfg.ScaleDownStrategies) == 0 {
+		for _, name := range cfg.ScaleDownChain() {
+			cfg.ScaleDownStrategies = append(cfg.ScaleDownStrategies, StrategyEntry{Name: name})
+		}
+	}
+	if len(cfg.ScaleUpStrategies) == 0 {
+		for _, name := range cfg.ScaleUpChain() {
+			cfg.ScaleUpStrategies = append(cfg.ScaleUpStrategies, StrategyEntry{Name: name})
+		}
+	}
+
+	var loadAvgParams *LoadAverageStrategyConfig
+	var promParams *PrometheusStrategyConfig
+	loadAvgEnabled, promEnabled, gpuEnabled := false, false, false
+	for _, chain := range []struct {
+		field   string
+		entries []StrategyEntry
+		allowed []string
+	}{
+		{"scaleDownStrategies", cfg.ScaleDownStrategies, []string{StrategyResourceAware, StrategyLoadAverage, StrategyPrometheus, StrategyGPUAware}},
+		{"scaleUpStrategies", cfg.ScaleUpStrategies, []string{StrategyMinNodeCount, StrategyLoadAverage, StrategyPrometheus}},
+	} {
+		seen := map[string]bool{}
+		for _, e := range chain.entries {
+			if !slices.Contains(chain.allowed, e.Name) {
+				return fmt.Errorf("%s: unknown strategy %q (want one of %v)", chain.field, e.Name, chain.allowed)
+			}
+			if seen[e.Name] {
+				return fmt.Errorf("%s: strategy %q listed twice", chain.field, e.Name)
+			}
+			seen[e.Name] = true
+			if e.ResourceAware != nil && e.Name != StrategyResourceAware {
+				return fmt.Errorf("%s: resourceAware parameters given for strategy %q", chain.field, e.Name)
+			}
+			if e.LoadAverage != nil && e.Name != StrategyLoadAverage {
+				return fmt.Errorf("%s: loadAverage parameters given for strategy %q", chain.field, e.Name)
+			}
+			if e.Prometheus != nil && e.Name != StrategyPrometheus {
+				return fmt.Errorf("%s: prometheus parameters given for strategy %q", chain.field, e.Name)
+			}
+			if !e.IsEnabled() {
+				continue
+			}
+			switch e.Name {
+			case StrategyGPUAware:
+				gpuEnabled = true
+			case StrategyResourceAware:
+				if p := e.ResourceAware; p != nil {
+					cfg.applyResourceAwareParams(*p)
+				}
+			case StrategyLoadAverage:
+				loadAvgEnabled = true
+				if e.LoadAverage == nil {
+					continue
+				}
+				if loadAvgParams != nil && !reflect.DeepEqual(*loadAvgParams, *e.LoadAverage) {
+					return fmt.Errorf("loadAverage parameters differ between scaleDownStrategies and scaleUpStrategies")
+				}
+				loadAvgParams = e.LoadAverage
+			case StrategyPrometheus:
+				promEnabled = true
+				if e.Prometheus == nil {
+					continue
+				}
+				if promParams != nil && !reflect.DeepEqual(*promParams, *e.Prometheus) {
+					return fmt.Errorf("prometheus parameters differ between scaleDownStrategies and scaleUpStrategies")
+				}
+				promParams = e.Prometheus
+			}
+		}
+	}
+
+	if loadAvgParams != nil {
+		cfg.LoadAverageStrategy = *loadAvgParams
+	}
+	if promParams != nil {
+		cfg.PrometheusStrategy = *promParams
+	}
+	// Rotation and other load-aware paths follow the chains.
+	cfg.LoadAverageStrategy.Enabled = loadAvgEnabled
+	cfg.PrometheusStrategy.Enabled = promEnabled
+	cfg.GPUAwareStrategy.Enabled = gpuEnabled
+	return cfg.PrometheusStrategy.validate()
+}
+
+func (cfg *Config) applyResourceAwareParams(p ResourceAwareParams) {
+	if p.BufferCPUPerc != nil {
+		cfg.ResourceBufferCPUPerc = *p.BufferCPUPerc
+	}
+	if p.BufferMemoryPerc != nil {
+		cfg.ResourceBufferMemoryPerc = *p.BufferMemoryPerc
+	}
+	if p.BufferEphemeralPerc != nil {
+		cfg.ResourceBufferEphemeralPerc = *p.BufferEphemeralPerc
+	}
+	if p.MaxCandidateUsageFraction != nil {
+		cfg.ResourceAware.MaxCandidateUsageFraction = *p.MaxCandidateUsageFraction
+	}
+}
+
+// PrometheusStrategyConfig configures the prometheus strategy, which reads node and cluster load
+// from PromQL instead of the metrics DaemonSet.
+type PrometheusStrategyConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	URL     string `yaml:"url"` // base URL of the Prometheus HTTP API, e.g. http://prometheus:9090
+	// NodeQuery returns the candidate's load; "$node" is replaced by the node name.
+	NodeQuery string `yaml:"nodeQuery"`
+	// ClusterQuery returns the cluster-wide load compared against the scale-down/up thresholds.
+	ClusterQuery       string  `yaml:"clusterQuery"`
+	NodeThreshold      float64 `yaml:"nodeThreshold"`
+	ScaleDownThreshold float64 `yaml:"scaleDownThreshold"`
+	ScaleUpThreshold   float64 `yaml:"scaleUpThreshold"`
+	TimeoutSeconds     int     `yaml:"timeoutSeconds,omitempty"` // defaults to 5
+}
+
+func (p *PrometheusStrategyConfig) validate() error {
+	if !p.Enabled {
+		return nil
+	}
+	if p.URL == "" || p.NodeQuery == "" || p.ClusterQuery == "" {
+		return fmt.Errorf("prometheusStrategy: url, nodeQuery and clusterQuery are required")
+	}
+	if p.TimeoutSeconds == 0 {
+		p.TimeoutSeconds = 5
+	}
+	if p.TimeoutSeconds < 0 {
+		return fmt.Errorf("prometheusStrategy.timeoutSeconds must be > 0, got %d", p.TimeoutSeconds)
+	}
+	return nil
+}
+
+// GPUAwareStrategyConfig configures the gpuAware scale-down guard, which keeps nodes with GPU
+// workloads powered on regardless of load.
+type GPUAwareStrategyConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// ResourceNames are the extended resources that mark a pod as a GPU workload; defaults to nvidia.com/gpu.
+	ResourceNames []string `yaml:"resourceNames,omitempty"`
+}
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Watch reloads the config file at path whenever it changes and passes each valid result to apply,
+// until ctx is done. A reload that fails to read, parse or validate is logged and dropped, so the
+// caller keeps its current config. The parent directory is watched rather than the file itself:
+// ConfigMap volumes update by swapping a "..data" symlink, which never writes to the file.
+func Watch(ctx context.Context, path string, apply func(*Config)) error {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("config watcher: %w", err)
+	}
+	dir := filepath.Dir(path)
+	if err := w.Add(dir); err != nil {
+		w.Close()
+		return fmt.Errorf("watch %s: %w", dir, err)
+	}
+
+	last, _ := os.ReadFile(path) // the caller already loaded this version
+	go func() {
+		defer w.Close()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case ev, ok := <-w.Events:
+				if !ok {
+					return
+				}
+				if !affectsConfig(ev, path) {
+					continue
+				}
+				data, err := os.ReadFile(path)
+				if err != nil {
+					slog.Warn("Config reload failed; keeping current config", "path", path, "err", err)
+					continue
+				}
+				// An empty file is usually an editor's truncate-then-write in progress.
+				if len(bytes.TrimSpace(data)) == 0 || bytes.Equal(data, last) {
+					continue
+				}
+				cfg, err := Parse(data)
+				if err != nil {
+					slog.Error("Config reload rejected; keeping current config", "path", path, "err", err)
+					continue
+				}
+				last = data
+				slog.Info("Config reloaded", "path", path)
+				apply(cfg)
+			case err, ok := <-w.Errors:
+				if !ok {
+					return
+				}
+				slog.Warn("Config watcher error", "err", err)
+			}
+		}
+	}()
+	return nil
+}
+
+// affectsConfig reports whether ev may have changed the contents of the config file at path.
+func affectsConfig(ev fsnotify.Event, path string) bool {
+	if ev.Op == fsnotify.Chmod {
+		return false
+	}
+	name := filepath.Base(ev.Name)
+	return name == filepath.Base(path) || name == "..data"
+}
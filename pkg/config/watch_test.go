@@ -0,0 +1,61 @@
+package config_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+)
+
+func TestWatch_ReloadsValidAndRejectsInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("minNodes: 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reloaded := make(chan *config.Config, 10)
+	if err := config.Watch(ctx, path, func(cfg *config.Config) { reloaded <- cfg }); err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+
+	// Replace the file atomically, as ConfigMap volume updates do.
+	write := func(content string) {
+		t.Helper()
+		tmp := path + ".tmp"
+		if err := os.WriteFile(tmp, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Rename(tmp, path); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expectNone := func() {
+		t.Helper()
+		select {
+		case cfg := <-reloaded:
+			t.Fatalf("unexpected reload: %+v", cfg)
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+
+	write("minNodes: 3\n")
+	select {
+	case cfg := <-reloaded:
+		if cfg.MinNodes != 3 {
+			t.Errorf("MinNodes = %d, want 3", cfg.MinNodes)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("valid change was not reloaded")
+	}
+
+	write("cordonMode: sideways\n")
+	expectNone()
+
+	write("minNodes: [\n")
+	expectNone()
+}
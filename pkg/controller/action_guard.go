@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// Power-action categories. At most one category may act per reconcile loop, so e.g. a rotation
+// boot is never followed by a scale-down in the same pass; several actions of the same category
+// (a force power-on of all nodes) are fine.
+const (
+	ActionScaleUp     = "scale-up"
+	ActionScaleDown   = "scale-down"
+	ActionRecycle     = "recycle"
+	ActionRotate      = "rotate"
+	ActionStaleCordon = "stale-cordon"
+	ActionForce       = "force-power-on"
+	ActionMaintenance = "maintenance"
+)
+
+// ErrActionConflict is returned by power primitives when another action category already acted
+// in the current loop.
+var ErrActionConflict = fmt.Errorf("another power action already ran in this reconcile loop")
+
+type (
+	actionCategoryKey struct{}
+	loopActionsKey    struct{}
+)
+
+// loopActions records the power-action category taken during one reconcile loop.
+type loopActions struct {
+	category string
+}
+
+// beginLoop attaches a fresh action record to ctx. Power primitives called with a ctx derived
+// from it enforce the one-category rule; calls outside Reconcile are not restricted.
+func beginLoop(ctx context.Context) context.Context {
+	return context.WithValue(ctx, loopActionsKey{}, &loopActions{})
+}
+
+// withAction tags ctx with the category of power action the caller is about to take.
+func withAction(ctx context.Context, category string) context.Context {
+	return context.WithValue(ctx, actionCategoryKey{}, category)
+}
+
+func actionFrom(ctx context.Context) string {
+	if c, ok := ctx.Value(actionCategoryKey{}).(string); ok {
+		return c
+	}
+	return "unclassified"
+}
+
+// claimAction is called by every primitive that changes a node's power or schedulability for
+// retirement (cordon/drain, shutdown, power-on). The first claim in a loop fixes the category;
+// claims from any other category are refused until the next loop.
+func (r *Reconciler) claimAction(ctx context.Context, node string) error {
+	loop, ok := ctx.Value(loopActionsKey{}).(*loopActions)
+	if !ok {
+		return nil
+	}
+	category := actionFrom(ctx)
+	if loop.category == "" || loop.category == category {
+		loop.category = category
+		return nil
+	}
+	slog.Warn("Refusing power action: another category already acted in this loop",
+		"node", node, "action", category, "alreadyActed", loop.category)
+	setReason(ctx, "action conflict")
+	return fmt.Errorf("%s on %s after %s: %w", category, node, loop.category, ErrActionConflict)
+}
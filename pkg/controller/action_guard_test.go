@@ -0,0 +1,74 @@
+package controller_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/controller"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// overlappingScaleUpStrategy stands in for a future feature that takes its own power action
+// (here: a scale-down) while the scale-up phase is running, then asks for a node to boot.
+type overlappingScaleUpStrategy struct {
+	r    *controller.Reconciler
+	node string
+}
+
+func (s *overlappingScaleUpStrategy) ShouldScaleUp(ctx context.Context) (string, bool, error) {
+	nodes, err := s.r.Client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", false, err
+	}
+	var running []*nodeops.NodeWrapper
+	for _, n := range nodeops.WrapNodes(nodes.Items, s.r.State, time.Now(), nodeops.NodeAnnotationConfig{}, nil) {
+		if _, off := n.Annotations[nodeops.AnnotationPoweredOff]; !off {
+			running = append(running, n)
+		}
+	}
+	s.r.MaybeScaleDown(ctx, running)
+	return s.node, true, nil
+}
+func (s *overlappingScaleUpStrategy) Name() string { return "overlapping" }
+
+func newActionGuardFixture(t *testing.T) (*controller.Reconciler, *bootSimulator, *fake.Clientset) {
+	t.Helper()
+	hourAgo := time.Now().Add(-time.Hour)
+	off := poweredOffNode("off")
+	off.Annotations[nodeops.AnnotationMACAuto] = "00:11:22:33:44:55"
+	off.Annotations[nodeops.AnnotationPoweredOff] = time.Now().Add(-48 * time.Hour).UTC().Format(time.RFC3339)
+	client := fake.NewSimpleClientset(runningNode("a", hourAgo, nil), runningNode("b", hourAgo, nil), off)
+	sim := &bootSimulator{client: client}
+	r := newSimReconciler(client, sim, &config.Config{
+		Rotation: config.RotationConfig{Enabled: true, MaxPoweredOffDuration: time.Hour},
+	})
+	r.ScaleUpStrategy = &fixedScaleUpStrategy{node: "off"}
+	return r, sim, client
+}
+
+func TestReconcile_ScaleUpExcludesScaleDownAndRotation(t *testing.T) {
+	r, sim, _ := newActionGuardFixture(t)
+
+	require.NoError(t, r.Reconcile(context.Background()))
+	require.Equal(t, []string{"off"}, sim.PoweredOn, "only the scale-up power-on; no rotation boot")
+	require.Empty(t, sim.ShutDown, "no scale-down in the loop that scaled up")
+}
+
+func TestReconcile_ActionGuardRefusesSecondCategory(t *testing.T) {
+	ctx := context.Background()
+	r, sim, client := newActionGuardFixture(t)
+	r.ScaleUpStrategy = &overlappingScaleUpStrategy{r: r, node: "off"}
+
+	require.NoError(t, r.Reconcile(ctx))
+	require.NotEmpty(t, sim.ShutDown, "the first action category of the loop goes through")
+	require.Empty(t, sim.PoweredOn, "a second action category in the same loop is refused")
+
+	off, err := client.CoreV1().Nodes().Get(ctx, "off", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Contains(t, off.Annotations, nodeops.AnnotationPoweredOff, "refused power-on leaves the node untouched")
+}
@@ -0,0 +1,156 @@
+package controller
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/docent-net/cluster-bare-autoscaler/internal/bootstrap/metrics"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
+)
+
+// ActionAdmin tags power actions requested through the admin API.
+const ActionAdmin = "admin"
+
+// adminResponse is the JSON body returned by the admin endpoints.
+type adminResponse struct {
+	Node   string `json:"node"`
+	Action string `json:"action"` // "powered-on", "powered-off" or "none"
+	DryRun bool   `json:"dryRun"`
+	Error  string `json:"error,omitempty"`
+}
+
+// adminError carries the HTTP status for a failed admin action.
+type adminError struct {
+	status int
+	err    error
+}
+
+func (e *adminError) Error() string { return e.err.Error() }
+
+// AdminHandler serves POST /admin/nodes/{name}/poweron and POST /admin/nodes/{name}/poweroff.
+// Every request needs the bearer token from adminAPI.tokenSecret. Actions wait for a running
+// reconcile loop to finish and skip strategies, minNodes and cooldowns, but otherwise use the
+// normal power-on and drain/shutdown paths, including dry-run.
+func (r *Reconciler) AdminHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /admin/nodes/{name}/poweron", r.adminAction("powered-on", r.adminPowerOn))
+	mux.HandleFunc("POST /admin/nodes/{name}/poweroff", r.adminAction("powered-off", r.adminPowerOff))
+	return mux
+}
+
+func (r *Reconciler) adminAction(done string, act func(ctx context.Context, node string) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if err := r.adminAuthorized(req); err != nil {
+			slog.Warn("Admin API: request rejected", "path", req.URL.Path, "err", err)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		name := req.PathValue("name")
+		resp := adminResponse{Node: name, Action: done, DryRun: r.config().IsPowerDryRun()}
+		status := http.StatusOK
+		slog.Info("Admin API: power action requested", "node", name, "action", done)
+
+		// A dropped connection must not abort a drain half-way.
+		ctx := withAction(context.WithoutCancel(req.Context()), ActionAdmin)
+		r.loopMu.Lock()
+		err := act(ctx, name)
+		r.loopMu.Unlock()
+		if err != nil {
+			slog.Warn("Admin API: power action failed", "node", name, "action", done, "err", err)
+			resp.Action, resp.Error = "none", err.Error()
+			status = http.StatusInternalServerError
+			var ae *adminError
+			if errors.As(err, &ae) {
+				status = ae.status
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(resp)
+	}
+}
+
+// adminAuthorized checks the request's bearer token against adminAPI.tokenSecret.
+func (r *Reconciler) adminAuthorized(req *http.Request) error {
+	given, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if !ok || given == "" {
+		return errors.New("missing bearer token")
+	}
+	ref := r.config().AdminAPI.TokenSecret
+	secret, err := r.Client.CoreV1().Secrets(ref.Namespace).Get(req.Context(), ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("reading token secret %s/%s: %w", ref.Namespace, ref.Name, err)
+	}
+	want := strings.TrimSpace(string(secret.Data[ref.Key]))
+	if want == "" || subtle.ConstantTimeCompare([]byte(given), []byte(want)) != 1 {
+		return errors.New("bearer token mismatch")
+	}
+	return nil
+}
+
+// adminNode fetches a managed node for an admin action.
+func (r *Reconciler) adminNode(ctx context.Context, name string) (*nodeops.NodeWrapper, error) {
+	node, err := r.Client.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, &adminError{http.StatusNotFound, fmt.Errorf("node %s not found", name)}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !nodeops.NewManagedNodeFilter(r.Cfg).Manages(node.Labels) {
+		return nil, &adminError{http.StatusNotFound, fmt.Errorf("node %s is not managed by CBA", name)}
+	}
+	wrapped := nodeops.NewNodeWrapper(node, r.State, time.Now(), nodeops.NodeAnnotationConfig{
+		MAC: r.Cfg.NodeAnnotations.MAC,
+	}, r.Cfg.IgnoreLabels)
+	if wrapped.IsObserveOnly() {
+		return nil, &adminError{http.StatusConflict, fmt.Errorf("node %s is observe-only", name)}
+	}
+	return wrapped, nil
+}
+
+func (r *Reconciler) adminPowerOn(ctx context.Context, name string) error {
+	node, err := r.adminNode(ctx, name)
+	if err != nil {
+		return err
+	}
+	if err := r.powerOn(ctx, node); err != nil {
+		return err
+	}
+	r.State.ClearPoweredOff(name)
+	metrics.PoweredOffNodes.WithLabelValues(name).Set(0)
+	r.recordAction("scale-up", name)
+	return nil
+}
+
+func (r *Reconciler) adminPowerOff(ctx context.Context, name string) error {
+	node, err := r.adminNode(ctx, name)
+	if err != nil {
+		return err
+	}
+	ctx, release, ok := r.holdDisruptionLease(ctx)
+	if !ok {
+		return &adminError{http.StatusConflict, errDisruptionLeaseHeld}
+	}
+	defer release()
+
+	if err := r.CordonAndDrain(ctx, node); err != nil {
+		if err := nodeops.ClearPoweredOffAnnotation(ctx, r.Client, name); err != nil {
+			slog.Warn("Failed to clear annotation from powered-off node", "node", name, "err", err)
+		}
+		return fmt.Errorf("drain: %w", err)
+	}
+	return r.powerOffDrained(ctx, node)
+}
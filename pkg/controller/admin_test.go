@@ -0,0 +1,106 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/controller"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestAdminHandler(t *testing.T) {
+	offNode := poweredOffNode("off")
+	offNode.Annotations[nodeops.AnnotationMACAuto] = "00:11:22:33:44:55"
+	token := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "cba-admin", Namespace: "cba"},
+		Data:       map[string][]byte{"token": []byte("s3cret\n")},
+	}
+	unmanaged := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "other"}}
+
+	tests := []struct {
+		name       string
+		path       string
+		token      string
+		dryRun     bool
+		wantStatus int
+		wantAction string
+		wantOn     []string
+		wantOff    []string
+	}{
+		{name: "missing token", path: "/admin/nodes/off/poweron", wantStatus: http.StatusUnauthorized},
+		{name: "wrong token", path: "/admin/nodes/off/poweron", token: "guess", wantStatus: http.StatusUnauthorized},
+		{name: "power on", path: "/admin/nodes/off/poweron", token: "s3cret", wantStatus: http.StatusOK,
+			wantAction: "powered-on", wantOn: []string{"off"}},
+		{name: "power off", path: "/admin/nodes/a/poweroff", token: "s3cret", wantStatus: http.StatusOK,
+			wantAction: "powered-off", wantOff: []string{"a"}},
+		{name: "dry-run power off", path: "/admin/nodes/a/poweroff", token: "s3cret", dryRun: true,
+			wantStatus: http.StatusOK, wantAction: "powered-off"},
+		{name: "unmanaged node", path: "/admin/nodes/other/poweroff", token: "s3cret",
+			wantStatus: http.StatusNotFound, wantAction: "none"},
+		{name: "unknown node", path: "/admin/nodes/ghost/poweron", token: "s3cret",
+			wantStatus: http.StatusNotFound, wantAction: "none"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(
+				runningNode("a", time.Now().Add(-time.Hour), nil), offNode.DeepCopy(), unmanaged, token)
+			sim := &bootSimulator{client: client}
+			r := &controller.Reconciler{
+				Client: client,
+				Cfg: &config.Config{
+					DryRun:     tt.dryRun,
+					NodeLabels: config.NodeLabelConfig{Managed: "cba.dev/is-managed"},
+					AdminAPI: config.AdminAPIConfig{
+						Enabled:     true,
+						TokenSecret: config.SecretKeyRef{Namespace: "cba", Name: "cba-admin", Key: "token"},
+					},
+				},
+				State:      nodeops.NewNodeStateTracker(),
+				Shutdowner: sim,
+				PowerOner:  sim,
+			}
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rec := httptest.NewRecorder()
+			r.AdminHandler().ServeHTTP(rec, req)
+
+			require.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+			require.Equal(t, tt.wantOn, sim.PoweredOn)
+			require.Equal(t, tt.wantOff, sim.ShutDown)
+			if tt.wantAction == "" {
+				return
+			}
+			var resp struct {
+				Node   string `json:"node"`
+				Action string `json:"action"`
+				DryRun bool   `json:"dryRun"`
+				Error  string `json:"error"`
+			}
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+			require.Equal(t, tt.wantAction, resp.Action)
+			require.Equal(t, tt.dryRun, resp.DryRun)
+			require.Equal(t, tt.wantAction == "none", resp.Error != "")
+		})
+	}
+}
+
+func TestAdminHandler_RejectsGet(t *testing.T) {
+	client := fake.NewSimpleClientset(runningNode("a", time.Now().Add(-time.Hour), nil))
+	r := &controller.Reconciler{Client: client, Cfg: &config.Config{}, State: nodeops.NewNodeStateTracker()}
+
+	rec := httptest.NewRecorder()
+	r.AdminHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/nodes/a/poweroff", nil))
+	require.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
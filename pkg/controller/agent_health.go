@@ -0,0 +1,116 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/docent-net/cluster-bare-autoscaler/internal/bootstrap/metrics"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/power"
+)
+
+// agentDaemonSet identifies one of CBA's own DaemonSets by the pod selector the controller
+// already uses to reach it.
+type agentDaemonSet struct {
+	role      string
+	namespace string
+	podLabel  string
+}
+
+// requiredAgentDaemonSets lists the DaemonSets the enabled features depend on.
+func (r *Reconciler) requiredAgentDaemonSets() []agentDaemonSet {
+	var out []agentDaemonSet
+	if lc := r.Cfg.LoadAverageStrategy; lc.Enabled {
+		out = append(out, agentDaemonSet{role: "metrics", namespace: lc.Namespace, podLabel: lc.PodLabel})
+	}
+	if r.Cfg.ShutdownMode == power.ShutdownModeHTTP {
+		sm := r.Cfg.ShutdownManager
+		out = append(out, agentDaemonSet{role: "shutdown", namespace: sm.Namespace, podLabel: sm.PodLabel})
+	}
+	return out
+}
+
+// agentsHealthy reports whether every required agent DaemonSet has at least minAgentHealthRatio of
+// its pods Ready. Pods scheduled on nodes CBA powered off are expected to be down and don't count.
+// Returns true when the gate is disabled.
+func (r *Reconciler) agentsHealthy(ctx context.Context) bool {
+	if r.Cfg.MinAgentHealthRatio <= 0 {
+		return true
+	}
+	required := r.requiredAgentDaemonSets()
+	if len(required) == 0 {
+		return true
+	}
+
+	healthy := true
+	for _, agent := range required {
+		ready, expected, err := r.agentReadiness(ctx, agent)
+		if err != nil {
+			slog.Warn("Agent health: cannot evaluate DaemonSet; pausing scaling", "agent", agent.role, "err", err)
+			metrics.AgentReadyRatio.WithLabelValues(agent.role).Set(0)
+			healthy = false
+			continue
+		}
+		ratio := 1.0
+		if expected > 0 {
+			ratio = float64(ready) / float64(expected)
+		}
+		metrics.AgentReadyRatio.WithLabelValues(agent.role).Set(ratio)
+		if ratio < r.Cfg.MinAgentHealthRatio {
+			slog.Warn("Agent health: DaemonSet below minAgentHealthRatio; pausing scaling",
+				"agent", agent.role, "ready", ready, "expected", expected,
+				"ratio", ratio, "minAgentHealthRatio", r.Cfg.MinAgentHealthRatio)
+			healthy = false
+		}
+	}
+	return healthy
+}
+
+// agentReadiness returns Ready and expected pod counts for the DaemonSet whose pod template
+// matches agent.podLabel, excluding pods on powered-off nodes from the expected count.
+func (r *Reconciler) agentReadiness(ctx context.Context, agent agentDaemonSet) (int, int, error) {
+	selector, err := labels.Parse(agent.podLabel)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid pod label %q: %w", agent.podLabel, err)
+	}
+	dsList, err := r.Client.AppsV1().DaemonSets(agent.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return 0, 0, fmt.Errorf("listing DaemonSets: %w", err)
+	}
+	var ds *appsv1.DaemonSet
+	for i := range dsList.Items {
+		if selector.Matches(labels.Set(dsList.Items[i].Spec.Template.Labels)) {
+			ds = &dsList.Items[i]
+			break
+		}
+	}
+	if ds == nil {
+		return 0, 0, fmt.Errorf("no DaemonSet in %s with pods matching %q", agent.namespace, agent.podLabel)
+	}
+
+	expected := int(ds.Status.DesiredNumberScheduled)
+	ready := int(ds.Status.NumberReady)
+
+	pods, err := r.Client.CoreV1().Pods(agent.namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return 0, 0, fmt.Errorf("listing agent pods: %w", err)
+	}
+	nodes, err := r.Client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return 0, 0, fmt.Errorf("listing nodes: %w", err)
+	}
+	poweredOff := make(map[string]bool, len(nodes.Items))
+	for _, n := range nodes.Items {
+		poweredOff[n.Name] = r.isPoweredOff(n)
+	}
+	for _, p := range pods.Items {
+		if poweredOff[p.Spec.NodeName] {
+			expected--
+		}
+	}
+	return ready, max(expected, 0), nil
+}
@@ -0,0 +1,106 @@
+package controller_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/controller"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func poweroffDaemonSet(desired, ready int32) *appsv1.DaemonSet {
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "cba-poweroff-manager", Namespace: "cba"},
+		Spec: appsv1.DaemonSetSpec{Template: v1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "poweroff"}},
+		}},
+		Status: appsv1.DaemonSetStatus{DesiredNumberScheduled: desired, NumberReady: ready},
+	}
+}
+
+func agentPod(name, node string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name, Namespace: "cba", Labels: map[string]string{"app": "poweroff"},
+			OwnerReferences: controlledBy("DaemonSet", "cba-poweroff-manager"),
+		},
+		Spec: v1.PodSpec{NodeName: node},
+	}
+}
+
+func TestReconcile_AgentHealthGate(t *testing.T) {
+	hourAgo := time.Now().Add(-time.Hour)
+	tests := []struct {
+		name         string
+		objs         []runtime.Object
+		wantShutdown bool
+	}{
+		{
+			name: "all agents ready",
+			objs: []runtime.Object{
+				runningNode("a", hourAgo, nil), runningNode("b", hourAgo, nil),
+				poweroffDaemonSet(2, 2), agentPod("p-a", "a"), agentPod("p-b", "b"),
+			},
+			wantShutdown: true,
+		},
+		{
+			name: "half the agents not ready pauses scaling",
+			objs: []runtime.Object{
+				runningNode("a", hourAgo, nil), runningNode("b", hourAgo, nil),
+				poweroffDaemonSet(2, 1), agentPod("p-a", "a"), agentPod("p-b", "b"),
+			},
+			wantShutdown: false,
+		},
+		{
+			name: "agents on powered-off nodes are not expected to be ready",
+			objs: []runtime.Object{
+				runningNode("a", hourAgo, nil), poweredOffNode("off"),
+				poweroffDaemonSet(2, 1), agentPod("p-a", "a"), agentPod("p-off", "off"),
+			},
+			wantShutdown: true,
+		},
+		{
+			name: "missing DaemonSet pauses scaling",
+			objs: []runtime.Object{
+				runningNode("a", hourAgo, nil), runningNode("b", hourAgo, nil),
+			},
+			wantShutdown: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(tt.objs...)
+			sim := &bootSimulator{client: client}
+			r := &controller.Reconciler{
+				Client: client,
+				Cfg: &config.Config{
+					NodeLabels:          config.NodeLabelConfig{Managed: "cba.dev/is-managed"},
+					ShutdownMode:        "http",
+					ShutdownManager:     config.ShutdownManagerConfig{Namespace: "cba", PodLabel: "app=poweroff"},
+					MinAgentHealthRatio: 0.8,
+				},
+				State:             nodeops.NewNodeStateTracker(),
+				Shutdowner:        sim,
+				PowerOner:         sim,
+				ScaleDownStrategy: approveAllStrategy{},
+				ScaleUpStrategy:   &mockScaleUpStrategy{},
+			}
+
+			require.NoError(t, r.Reconcile(context.Background()))
+			if tt.wantShutdown {
+				require.Len(t, sim.ShutDown, 1)
+			} else {
+				require.Empty(t, sim.ShutDown)
+			}
+		})
+	}
+}
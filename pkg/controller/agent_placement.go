@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/power"
+)
+
+// placementAgents lists the agent DaemonSets that must keep a pod on an always-on node: the WOL
+// agent when nodes are woken through it, and the shutdown manager when shutdowns go over HTTP.
+func (r *Reconciler) placementAgents() []agentDaemonSet {
+	var out []agentDaemonSet
+	if r.Cfg.PowerOnMode == power.PowerOnModeWOL {
+		wa := r.Cfg.WolAgent
+		out = append(out, agentDaemonSet{role: "wol", namespace: wa.Namespace, podLabel: wa.PodLabel})
+	}
+	if r.Cfg.ShutdownMode == power.ShutdownModeHTTP {
+		sm := r.Cfg.ShutdownManager
+		out = append(out, agentDaemonSet{role: "shutdown", namespace: sm.Namespace, podLabel: sm.PodLabel})
+	}
+	return out
+}
+
+// agentsOnAlwaysOnNodes reports whether every placement agent has a Ready pod on a node CBA never
+// powers off (unmanaged, disabled or matching ignoreLabels). Without one, powering off managed
+// nodes could leave no agent to wake them again, so power-offs are refused. Every managed node is
+// a potential power-off target, so the answer does not depend on which one is about to go. Returns
+// true when requireAgentOnAlwaysOnNode is off.
+func (r *Reconciler) agentsOnAlwaysOnNodes(ctx context.Context) bool {
+	if !r.Cfg.RequireAgentOnAlwaysOnNode {
+		return true
+	}
+	agents := r.placementAgents()
+	if len(agents) == 0 {
+		return true
+	}
+	managed, err := nodeops.ListManagedNodes(ctx, r.Client, nodeops.NewManagedNodeFilter(r.Cfg))
+	if err != nil {
+		slog.Warn("Agent placement: listing managed nodes failed; blocking power-off", "err", err)
+		return false
+	}
+	retirable := make(map[string]bool, len(managed))
+	for _, n := range managed {
+		retirable[n.Name] = true
+	}
+
+	for _, agent := range agents {
+		host, err := r.alwaysOnAgentHost(ctx, agent, retirable)
+		if err != nil {
+			slog.Warn("Agent placement: cannot evaluate; blocking power-off", "agent", agent.role, "err", err)
+			return false
+		}
+		if host == "" {
+			slog.Warn("Agent placement: no Ready agent pod on an always-on node; blocking power-off",
+				"agent", agent.role, "namespace", agent.namespace, "podLabel", agent.podLabel)
+			return false
+		}
+		slog.Debug("Agent placement: agent runs on an always-on node", "agent", agent.role, "host", host)
+	}
+	return true
+}
+
+// alwaysOnAgentHost returns a node outside retirable that runs a Ready pod of agent, or "".
+func (r *Reconciler) alwaysOnAgentHost(ctx context.Context, agent agentDaemonSet, retirable map[string]bool) (string, error) {
+	pods, err := r.Client.CoreV1().Pods(agent.namespace).List(ctx, metav1.ListOptions{LabelSelector: agent.podLabel})
+	if err != nil {
+		return "", fmt.Errorf("listing pods: %w", err)
+	}
+	for _, p := range pods.Items {
+		if p.Spec.NodeName != "" && !retirable[p.Spec.NodeName] && podReady(&p) {
+			return p.Spec.NodeName, nil
+		}
+	}
+	return "", nil
+}
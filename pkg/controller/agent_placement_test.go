@@ -0,0 +1,121 @@
+package controller_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/controller"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func wolAgentPod(name, node string, ready bool) *v1.Pod {
+	status := v1.ConditionFalse
+	if ready {
+		status = v1.ConditionTrue
+	}
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name, Namespace: "cba",
+			Labels:          map[string]string{"app": "wol-agent"},
+			OwnerReferences: controlledBy("DaemonSet", "wol-agent"),
+		},
+		Spec:   v1.PodSpec{NodeName: node},
+		Status: v1.PodStatus{Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: status}}},
+	}
+}
+
+func TestReconcile_RequireAgentOnAlwaysOnNode(t *testing.T) {
+	tests := []struct {
+		name    string
+		pods    []runtime.Object
+		wantOff bool
+	}{
+		{
+			name:    "agent on control-plane node",
+			pods:    []runtime.Object{wolAgentPod("wol-cp", "cp", true), wolAgentPod("wol-a", "a", true)},
+			wantOff: true,
+		},
+		{
+			name:    "agents only on managed nodes",
+			pods:    []runtime.Object{wolAgentPod("wol-a", "a", true), wolAgentPod("wol-b", "b", true)},
+			wantOff: false,
+		},
+		{
+			name:    "agent on control-plane node not Ready",
+			pods:    []runtime.Object{wolAgentPod("wol-cp", "cp", false), wolAgentPod("wol-a", "a", true)},
+			wantOff: false,
+		},
+		{
+			name:    "no agents at all",
+			wantOff: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			hourAgo := time.Now().Add(-time.Hour)
+			objects := []runtime.Object{
+				runningNode("a", hourAgo, nil),
+				runningNode("b", hourAgo, nil),
+				&v1.Node{ObjectMeta: metav1.ObjectMeta{
+					Name:   "cp",
+					Labels: map[string]string{"node-role.kubernetes.io/control-plane": ""},
+				}},
+			}
+			client := fake.NewSimpleClientset(append(objects, tt.pods...)...)
+			sim := &bootSimulator{client: client}
+			r := &controller.Reconciler{
+				Client: client,
+				Cfg: &config.Config{
+					NodeLabels:                 config.NodeLabelConfig{Managed: "cba.dev/is-managed"},
+					PowerOnMode:                "wol",
+					WolAgent:                   config.WolAgentConfig{Enabled: true, Namespace: "cba", PodLabel: "app=wol-agent"},
+					RequireAgentOnAlwaysOnNode: true,
+				},
+				State:             nodeops.NewNodeStateTracker(),
+				Shutdowner:        sim,
+				PowerOner:         sim,
+				ScaleDownStrategy: approveAllStrategy{},
+				ScaleUpStrategy:   &mockScaleUpStrategy{},
+			}
+
+			require.NoError(t, r.Reconcile(ctx))
+			if tt.wantOff {
+				require.Len(t, sim.ShutDown, 1)
+				return
+			}
+			require.Empty(t, sim.ShutDown, "scale-down must be refused without a WOL agent on an always-on node")
+			for _, name := range []string{"a", "b"} {
+				n, err := client.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+				require.NoError(t, err)
+				require.False(t, n.Spec.Unschedulable, "node %s must not be cordoned", name)
+			}
+		})
+	}
+}
+
+func TestMaybeResolveStaleCordons_RequiresAgentOnAlwaysOnNode(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset(
+		cordonedNode("cordoned", time.Now().Add(-time.Hour)),
+		runningNode("a", time.Now().Add(-time.Hour), nil),
+		wolAgentPod("wol-a", "a", true),
+	)
+	sim := &bootSimulator{client: client}
+	r := newStaleCordonReconciler(client, sim, config.CordonedOnActionPowerOff)
+	r.Cfg.PowerOnMode = "wol"
+	r.Cfg.WolAgent = config.WolAgentConfig{Enabled: true, Namespace: "cba", PodLabel: "app=wol-agent"}
+	r.Cfg.RequireAgentOnAlwaysOnNode = true
+
+	require.True(t, r.MaybeResolveStaleCordons(ctx))
+	require.Empty(t, sim.ShutDown, "the only WOL agent runs on a managed node")
+	requireUncordoned(t, client, "cordoned")
+}
@@ -0,0 +1,84 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/docent-net/cluster-bare-autoscaler/internal/bootstrap/metrics"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
+)
+
+// EvaluatePoweredOffAlert tracks how much of the managed fleet is powered off. When the fraction
+// stays above alertPoweredOffPerc for alertPoweredOffDuration (a demand collapse or a stuck
+// scale-up), it sets cba_powered_off_alert and emits one Warning event per streak.
+func (r *Reconciler) EvaluatePoweredOffAlert(ctx context.Context) {
+	if r.Cfg.AlertPoweredOffPerc <= 0 {
+		return
+	}
+	allNodes, err := r.listAllNodes(ctx)
+	if err != nil || len(allNodes.Items) == 0 {
+		return
+	}
+
+	off := 0
+	for _, n := range allNodes.Items {
+		if r.isPoweredOff(n) {
+			off++
+		}
+	}
+	fraction := float64(off) / float64(len(allNodes.Items))
+	metrics.PoweredOffFraction.Set(fraction)
+
+	now := time.Now()
+	if fraction*100 <= float64(r.Cfg.AlertPoweredOffPerc) {
+		if r.State.ClearPoweredOffExcess() {
+			slog.Info("Powered-off fleet alert resolved", "poweredOff", off, "total", len(allNodes.Items))
+			r.recordFleetEvent(ctx, v1.EventTypeNormal, "PoweredOffFleetRecovered",
+				fmt.Sprintf("%d of %d managed nodes powered off, back within %d%%", off, len(allNodes.Items), r.Cfg.AlertPoweredOffPerc))
+		}
+		metrics.PoweredOffAlert.Set(0)
+		return
+	}
+
+	since := r.State.ObservePoweredOffExcess(now)
+	if now.Sub(since) < r.Cfg.AlertPoweredOffDuration {
+		metrics.PoweredOffAlert.Set(0)
+		return
+	}
+
+	metrics.PoweredOffAlert.Set(1)
+	if r.State.MarkPoweredOffAlerted() {
+		msg := fmt.Sprintf("%d of %d managed nodes powered off (above %d%%) for %s",
+			off, len(allNodes.Items), r.Cfg.AlertPoweredOffPerc, now.Sub(since).Round(time.Second))
+		slog.Warn("Powered-off fleet alert", "poweredOff", off, "total", len(allNodes.Items),
+			"threshold", r.Cfg.AlertPoweredOffPerc, "since", since.UTC().Format(time.RFC3339))
+		r.recordFleetEvent(ctx, v1.EventTypeWarning, "PoweredOffFleetHigh", msg)
+	}
+}
+
+// ObservePoweredOffDurations exports how long each managed node has been powered off, so alerts can
+// complement rotation.maxPoweredOffDuration. Series of nodes that came back are dropped.
+func (r *Reconciler) ObservePoweredOffDurations(ctx context.Context) {
+	allNodes, err := r.listAllNodes(ctx)
+	if err != nil {
+		return
+	}
+	now := time.Now()
+	metrics.NodePoweredOffSeconds.Reset()
+	for _, n := range allNodes.Items {
+		if since, ok := nodeops.PoweredOffSince(n); ok {
+			metrics.NodePoweredOffSeconds.WithLabelValues(n.Name).Set(now.Sub(since).Seconds())
+		}
+	}
+}
+
+func (r *Reconciler) isPoweredOff(n v1.Node) bool {
+	if _, ok := n.Annotations[nodeops.AnnotationPoweredOff]; ok {
+		return true
+	}
+	return r.State.IsPoweredOff(n.Name)
+}
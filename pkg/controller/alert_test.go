@@ -0,0 +1,133 @@
+package controller_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/docent-net/cluster-bare-autoscaler/internal/bootstrap/metrics"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/controller"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func poweredOffNode(name string) *v1.Node {
+	return &v1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name:        name,
+		Labels:      map[string]string{"cba.dev/is-managed": "true"},
+		Annotations: map[string]string{nodeops.AnnotationPoweredOff: time.Now().UTC().Format(time.RFC3339)},
+	}}
+}
+
+func fleetEvents(t *testing.T, client *fake.Clientset, reason string) int {
+	t.Helper()
+	events, err := client.CoreV1().Events("default").List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err)
+	n := 0
+	for _, e := range events.Items {
+		if e.Reason == reason {
+			n++
+		}
+	}
+	return n
+}
+
+func TestEvaluatePoweredOffAlert_FiresOnlyAfterSustainedDuration(t *testing.T) {
+	ctx := context.Background()
+	objs := []runtime.Object{
+		runningNode("on-1", time.Now(), nil),
+		poweredOffNode("off-1"),
+		poweredOffNode("off-2"),
+		poweredOffNode("off-3"),
+	}
+	client := fake.NewSimpleClientset(objs...)
+	state := nodeops.NewNodeStateTracker()
+	r := &controller.Reconciler{
+		Client: client,
+		Cfg: &config.Config{
+			NodeLabels:              config.NodeLabelConfig{Managed: "cba.dev/is-managed"},
+			AlertPoweredOffPerc:     50,
+			AlertPoweredOffDuration: time.Hour,
+		},
+		State: state,
+	}
+
+	// 75% off, but the streak has only just started.
+	r.EvaluatePoweredOffAlert(ctx)
+	require.Equal(t, 0.75, testutil.ToFloat64(metrics.PoweredOffFraction))
+	require.Equal(t, 0.0, testutil.ToFloat64(metrics.PoweredOffAlert))
+	require.Zero(t, fleetEvents(t, client, "PoweredOffFleetHigh"))
+
+	// Still above threshold after the sustained duration: alert fires once.
+	state.SetPoweredOffExcessSince(time.Now().Add(-2 * time.Hour))
+	r.EvaluatePoweredOffAlert(ctx)
+	r.EvaluatePoweredOffAlert(ctx)
+	require.Equal(t, 1.0, testutil.ToFloat64(metrics.PoweredOffAlert))
+	require.Equal(t, 1, fleetEvents(t, client, "PoweredOffFleetHigh"), "one event per streak")
+
+	// Nodes come back: the streak resets and the alert clears.
+	for _, name := range []string{"off-1", "off-2"} {
+		require.NoError(t, nodeops.ClearPoweredOffAnnotation(ctx, client, name))
+	}
+	r.EvaluatePoweredOffAlert(ctx)
+	require.Equal(t, 0.0, testutil.ToFloat64(metrics.PoweredOffAlert))
+	require.Equal(t, 1, fleetEvents(t, client, "PoweredOffFleetRecovered"))
+}
+
+func TestEvaluatePoweredOffAlert_StreakResetsWhenFractionDrops(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset(runningNode("on-1", time.Now(), nil), poweredOffNode("off-1"), poweredOffNode("off-2"))
+	state := nodeops.NewNodeStateTracker()
+	r := &controller.Reconciler{
+		Client: client,
+		Cfg: &config.Config{
+			NodeLabels:              config.NodeLabelConfig{Managed: "cba.dev/is-managed"},
+			AlertPoweredOffPerc:     50,
+			AlertPoweredOffDuration: time.Hour,
+		},
+		State: state,
+	}
+
+	state.SetPoweredOffExcessSince(time.Now().Add(-30 * time.Minute))
+	r.EvaluatePoweredOffAlert(ctx)
+	require.Equal(t, 0.0, testutil.ToFloat64(metrics.PoweredOffAlert))
+
+	// A dip below the threshold interrupts the streak...
+	require.NoError(t, nodeops.ClearPoweredOffAnnotation(ctx, client, "off-1"))
+	r.EvaluatePoweredOffAlert(ctx)
+
+	// ...so going back above starts a fresh one instead of firing.
+	_, err := client.CoreV1().Nodes().Update(ctx, poweredOffNode("off-1"), metav1.UpdateOptions{})
+	require.NoError(t, err)
+	r.EvaluatePoweredOffAlert(ctx)
+	require.Equal(t, 0.0, testutil.ToFloat64(metrics.PoweredOffAlert))
+	require.Zero(t, fleetEvents(t, client, "PoweredOffFleetHigh"))
+}
+
+func TestObservePoweredOffDurations(t *testing.T) {
+	ctx := context.Background()
+	twoHoursAgo := time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339)
+	off := poweredOffNode("off-1")
+	off.Annotations[nodeops.AnnotationPoweredOff] = twoHoursAgo
+	client := fake.NewSimpleClientset(runningNode("on-1", time.Now(), nil), off, poweredOffNode("off-2"))
+	r := &controller.Reconciler{
+		Client: client,
+		Cfg:    &config.Config{NodeLabels: config.NodeLabelConfig{Managed: "cba.dev/is-managed"}},
+		State:  nodeops.NewNodeStateTracker(),
+	}
+
+	r.ObservePoweredOffDurations(ctx)
+	require.Equal(t, 2, testutil.CollectAndCount(metrics.NodePoweredOffSeconds))
+	require.InDelta(t, 7200, testutil.ToFloat64(metrics.NodePoweredOffSeconds.WithLabelValues("off-1")), 60)
+
+	// A node that is back online loses its series.
+	require.NoError(t, nodeops.ClearPoweredOffAnnotation(ctx, client, "off-1"))
+	r.ObservePoweredOffDurations(ctx)
+	require.Equal(t, 1, testutil.CollectAndCount(metrics.NodePoweredOffSeconds))
+}
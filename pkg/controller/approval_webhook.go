@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+const defaultApprovalWebhookTimeout = 10 * time.Second
+
+// approvalRequest is the body POSTed to approvalWebhook.url.
+type approvalRequest struct {
+	Node            string   `json:"node"`
+	ActiveNodes     []string `json:"activeNodes"`
+	PoweredOffNodes []string `json:"poweredOffNodes"`
+	MinNodes        int      `json:"minNodes"`
+}
+
+type approvalResponse struct {
+	Approved bool   `json:"approved"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// retirementApproved asks the configured approval webhook whether node may be retired.
+// It returns true when no webhook is configured. Any failure to get an explicit approval
+// is treated as a veto.
+func (r *Reconciler) retirementApproved(ctx context.Context, node string) bool {
+	hook := r.Cfg.ApprovalWebhook
+	if hook.URL == "" {
+		return true
+	}
+
+	active, err := r.listActiveNodes(ctx)
+	if err != nil {
+		slog.Warn("Approval webhook: listing active nodes failed; treating as veto", "node", node, "err", err)
+		return false
+	}
+	body := approvalRequest{
+		Node:            node,
+		ActiveNodes:     make([]string, 0, len(active)),
+		PoweredOffNodes: r.shutdownNodeNames(ctx),
+		MinNodes:        r.MinNodes(),
+	}
+	for _, n := range active {
+		body.ActiveNodes = append(body.ActiveNodes, n.Name)
+	}
+
+	resp, err := callApprovalWebhook(ctx, hook.URL, hook.TimeoutSeconds, body)
+	if err != nil {
+		slog.Warn("Approval webhook call failed; treating as veto", "node", node, "err", err)
+		return false
+	}
+	if !resp.Approved {
+		slog.Info("Approval webhook vetoed scale-down", "node", node, "reason", resp.Reason)
+		return false
+	}
+	slog.Info("Approval webhook approved scale-down", "node", node, "reason", resp.Reason)
+	return true
+}
+
+func callApprovalWebhook(ctx context.Context, url string, timeoutSeconds int, body approvalRequest) (approvalResponse, error) {
+	timeout := defaultApprovalWebhookTimeout
+	if timeoutSeconds > 0 {
+		timeout = time.Duration(timeoutSeconds) * time.Second
+	}
+	reqCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return approvalResponse{}, fmt.Errorf("encoding approval request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return approvalResponse{}, fmt.Errorf("creating approval request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return approvalResponse{}, fmt.Errorf("calling approval webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return approvalResponse{}, fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+	var out approvalResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&out); err != nil {
+		return approvalResponse{}, fmt.Errorf("decoding approval response: %w", err)
+	}
+	return out, nil
+}
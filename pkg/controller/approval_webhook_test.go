@@ -0,0 +1,72 @@
+package controller_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/controller"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestReconcile_ApprovalWebhook(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		body         string
+		wantShutdown bool
+	}{
+		{"approve", http.StatusOK, `{"approved": true}`, true},
+		{"veto", http.StatusOK, `{"approved": false, "reason": "capacity reserved"}`, false},
+		{"server error counts as veto", http.StatusInternalServerError, ``, false},
+		{"malformed response counts as veto", http.StatusOK, `not json`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got map[string]any
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			ctx := context.Background()
+			client := fake.NewSimpleClientset(runningNode("idle", time.Now().Add(-time.Hour), nil))
+			sim := &bootSimulator{client: client}
+			r := &controller.Reconciler{
+				Client: client,
+				Cfg: &config.Config{
+					NodeLabels:      config.NodeLabelConfig{Managed: "cba.dev/is-managed"},
+					ApprovalWebhook: config.ApprovalWebhookConfig{URL: srv.URL},
+				},
+				State:             nodeops.NewNodeStateTracker(),
+				Shutdowner:        sim,
+				PowerOner:         sim,
+				ScaleDownStrategy: approveAllStrategy{},
+				ScaleUpStrategy:   &mockScaleUpStrategy{},
+			}
+
+			require.NoError(t, r.Reconcile(ctx))
+
+			require.Equal(t, "idle", got["node"])
+			require.Equal(t, []any{"idle"}, got["activeNodes"])
+			if tt.wantShutdown {
+				require.Equal(t, []string{"idle"}, sim.ShutDown)
+				return
+			}
+			require.Empty(t, sim.ShutDown)
+			node, err := client.CoreV1().Nodes().Get(ctx, "idle", metav1.GetOptions{})
+			require.NoError(t, err)
+			require.False(t, node.Spec.Unschedulable, "vetoed node must not be cordoned")
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/strategy"
+)
+
+// auditRecord is one line of the audit log.
+type auditRecord struct {
+	Time           time.Time       `json:"time"`
+	Stream         string          `json:"stream"` // always "audit", to split the log from stdout
+	Action         string          `json:"action"` // power-off or power-on
+	Category       string          `json:"category"`
+	Node           string          `json:"node"`
+	DryRun         bool            `json:"dryRun"`
+	Before         auditNodeState  `json:"before"` // as seen when the action was decided
+	After          *auditNodeState `json:"after,omitempty"`
+	StrategyChain  []string        `json:"strategyChain,omitempty"` // the chain that approved the action
+	ConfigRevision string          `json:"configRevision,omitempty"`
+	Error          string          `json:"error,omitempty"`
+}
+
+type auditNodeState struct {
+	Ready      bool `json:"ready"`
+	Cordoned   bool `json:"cordoned"`
+	PoweredOff bool `json:"poweredOff"`
+}
+
+// OpenAuditLog returns the sink configured by cfg, or nil when the audit log is disabled. A file
+// that cannot be opened disables the audit log with an error, rather than stopping CBA.
+func OpenAuditLog(cfg config.AuditLogConfig) io.Writer {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.Path == "" || cfg.Path == "stdout" {
+		return os.Stdout
+	}
+	f, err := os.OpenFile(cfg.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		slog.Error("Cannot open audit log; audit records are disabled", "path", cfg.Path, "err", err)
+		return nil
+	}
+	return f
+}
+
+type approversKey struct{}
+
+// withApprovers records the strategy chain that approved the action about to run in ctx.
+func withApprovers(ctx context.Context, names []string) context.Context {
+	return context.WithValue(ctx, approversKey{}, names)
+}
+
+// chainNames lists the strategies of a scale-down or scale-up chain, or the single strategy's name.
+func chainNames(s any) []string {
+	var names []string
+	switch c := s.(type) {
+	case *strategy.MultiStrategy:
+		for _, sub := range c.Strategies {
+			names = append(names, sub.Name())
+		}
+	case *strategy.MultiUpStrategy:
+		for _, sub := range c.Strategies {
+			names = append(names, sub.Name())
+		}
+	case interface{ Name() string }:
+		names = append(names, c.Name())
+	}
+	return names
+}
+
+// audit writes one record for a power action on node; before is the node as the decision saw it
+// (see nodeStateBefore). Failures to write are logged and otherwise ignored.
+func (r *Reconciler) audit(ctx context.Context, action string, node *nodeops.NodeWrapper, before auditNodeState, actionErr error) {
+	if r.AuditLog == nil {
+		return
+	}
+	rec := auditRecord{
+		Time:           time.Now().UTC(),
+		Stream:         "audit",
+		Action:         action,
+		Category:       actionFrom(ctx),
+		Node:           node.Name,
+		DryRun:         r.Cfg.IsPowerDryRun(),
+		Before:         before,
+		ConfigRevision: r.Cfg.Revision,
+	}
+	if after, err := r.Client.CoreV1().Nodes().Get(ctx, node.Name, metav1.GetOptions{}); err == nil {
+		state := r.auditNodeState(*after)
+		rec.After = &state
+	}
+	if names, ok := ctx.Value(approversKey{}).([]string); ok {
+		rec.StrategyChain = names
+	}
+	if actionErr != nil {
+		rec.Error = actionErr.Error()
+	}
+
+	line, err := json.Marshal(rec)
+	if err != nil {
+		slog.Warn("Encoding audit record failed", "node", node.Name, "err", err)
+		return
+	}
+	if _, err := r.AuditLog.Write(append(line, '\n')); err != nil {
+		slog.Warn("Writing audit record failed", "node", node.Name, "err", err)
+	}
+}
+
+// nodeStateBefore snapshots node for the audit log before a power action changes it.
+func (r *Reconciler) nodeStateBefore(node *nodeops.NodeWrapper) auditNodeState {
+	if r.AuditLog == nil || node.Node == nil {
+		return auditNodeState{}
+	}
+	return r.auditNodeState(*node.Node)
+}
+
+func (r *Reconciler) auditNodeState(n v1.Node) auditNodeState {
+	return auditNodeState{
+		Ready:      nodeops.IsNodeReady(&n),
+		Cordoned:   nodeops.IsCordoned(&n),
+		PoweredOff: r.isPoweredOff(n),
+	}
+}
@@ -0,0 +1,92 @@
+package controller_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docent-net/cluster-bare-autoscaler/internal/bootstrap/metrics"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/controller"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestMaybeScaleDown_WritesAuditRecord(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	keep := runningNode("keep", now.Add(-time.Hour), nil)
+	idle := runningNode("idle", now.Add(-time.Hour), nil)
+	client := fake.NewSimpleClientset(keep, idle)
+	state := nodeops.NewNodeStateTracker()
+	var audit bytes.Buffer
+	r := &controller.Reconciler{
+		Client: client,
+		Cfg: &config.Config{
+			Revision:   "abc123",
+			NodeLabels: config.NodeLabelConfig{Managed: "cba.dev/is-managed"},
+		},
+		State:             state,
+		Shutdowner:        &shutdownMock{},
+		ScaleDownStrategy: &alwaysAllowStrategy{candidate: "idle"},
+		AuditLog:          &audit,
+	}
+
+	eligible := nodeops.WrapNodes([]v1.Node{*keep, *idle}, state, now, nodeops.NodeAnnotationConfig{}, nil)
+	require.True(t, r.MaybeScaleDown(ctx, eligible))
+
+	lines := strings.Split(strings.TrimSpace(audit.String()), "\n")
+	require.Len(t, lines, 1)
+	var rec map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &rec))
+	require.Equal(t, "audit", rec["stream"])
+	require.Equal(t, "power-off", rec["action"])
+	require.Equal(t, controller.ActionScaleDown, rec["category"])
+	require.Equal(t, "idle", rec["node"])
+	require.Equal(t, "abc123", rec["configRevision"])
+	require.Equal(t, []any{"allow-all"}, rec["strategyChain"])
+	require.Equal(t, map[string]any{"ready": true, "cordoned": false, "poweredOff": false}, rec["before"])
+	require.Equal(t, map[string]any{"ready": true, "cordoned": true, "poweredOff": true}, rec["after"])
+	require.NotContains(t, rec, "error")
+}
+
+func TestReconcile_ForcePowerOnAuditsEachNode(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset(poweredOffNode("off-1"), poweredOffNode("off-2"))
+	var audit bytes.Buffer
+	r := &controller.Reconciler{
+		Client: client,
+		Cfg: &config.Config{
+			NodeLabels:           config.NodeLabelConfig{Managed: "cba.dev/is-managed"},
+			ForcePowerOnAllNodes: true,
+			CircuitBreaker:       config.CircuitBreakerConfig{FailureThreshold: 2, Window: time.Hour, Cooldown: time.Hour},
+		},
+		State:     nodeops.NewNodeStateTracker(),
+		PowerOner: &failingPowerOn{},
+		AuditLog:  &audit,
+	}
+	metrics.CircuitBreakerOpen.Set(0)
+	t.Cleanup(func() { metrics.CircuitBreakerOpen.Set(0) })
+
+	require.NoError(t, r.Reconcile(ctx))
+
+	lines := strings.Split(strings.TrimSpace(audit.String()), "\n")
+	require.Len(t, lines, 2, "one audit record per force-powered node")
+	var nodes []string
+	for _, line := range lines {
+		var rec map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &rec))
+		require.Equal(t, "power-on", rec["action"])
+		require.Equal(t, controller.ActionForce, rec["category"])
+		require.Contains(t, rec["error"], "wol agent unreachable")
+		nodes = append(nodes, rec["node"].(string))
+	}
+	require.ElementsMatch(t, []string{"off-1", "off-2"}, nodes)
+	require.Equal(t, 1.0, testutil.ToFloat64(metrics.CircuitBreakerOpen), "both failures count towards the breaker")
+}
@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/docent-net/cluster-bare-autoscaler/internal/bootstrap/metrics"
+)
+
+// recordPowerOutcome feeds the circuit breaker with the result of a shutdown or power-on. A success
+// resets the failure count; circuitBreaker.failureThreshold consecutive failures within
+// circuitBreaker.window open the breaker for circuitBreaker.cooldown.
+func (r *Reconciler) recordPowerOutcome(err error) {
+	cb := r.Cfg.CircuitBreaker
+	if cb.FailureThreshold <= 0 {
+		return
+	}
+	if err == nil {
+		r.breakerFailures = nil
+		return
+	}
+	now := time.Now()
+	kept := r.breakerFailures[:0]
+	for _, t := range r.breakerFailures {
+		if now.Sub(t) < cb.Window {
+			kept = append(kept, t)
+		}
+	}
+	r.breakerFailures = append(kept, now)
+	if len(r.breakerFailures) < cb.FailureThreshold {
+		return
+	}
+
+	r.breakerFailures = nil
+	r.breakerOpenUntil = now.Add(cb.Cooldown)
+	metrics.CircuitBreakerOpen.Set(1)
+	slog.Error("CIRCUIT BREAKER OPEN — repeated power-action failures; pausing all scaling",
+		"failures", cb.FailureThreshold, "window", cb.Window.String(), "cooldown", cb.Cooldown.String(),
+		"until", r.breakerOpenUntil.UTC().Format(time.RFC3339), "lastErr", err)
+}
+
+// circuitOpen reports whether the circuit breaker pauses scaling at now, closing it once the
+// cooldown has passed.
+func (r *Reconciler) circuitOpen(now time.Time) bool {
+	if r.breakerOpenUntil.IsZero() {
+		return false
+	}
+	if now.Before(r.breakerOpenUntil) {
+		slog.Warn("Circuit breaker open — skipping reconcile loop",
+			"remaining", r.breakerOpenUntil.Sub(now).Round(time.Second).String())
+		return true
+	}
+	r.breakerOpenUntil = time.Time{}
+	metrics.CircuitBreakerOpen.Set(0)
+	slog.Warn("Circuit breaker closed — resuming scaling")
+	return false
+}
@@ -0,0 +1,70 @@
+package controller_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/controller"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// failingPowerOn fails every power-on, like an unreachable WOL agent.
+type failingPowerOn struct{ calls int }
+
+func (f *failingPowerOn) PowerOn(_ context.Context, _, _ string) error {
+	f.calls++
+	return errors.New("wol agent unreachable")
+}
+
+func TestReconcile_CircuitBreakerPausesScalingAfterFailures(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset(
+		runningNode("on", time.Now().Add(-time.Hour), nil),
+		&v1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "off",
+				Labels: map[string]string{"cba.dev/is-managed": "true"},
+				Annotations: map[string]string{
+					nodeops.AnnotationPoweredOff: time.Now().Add(-time.Hour).UTC().Format(time.RFC3339),
+					nodeops.AnnotationMACAuto:    "00:11:22:33:44:55",
+				},
+			},
+			Spec: v1.NodeSpec{Unschedulable: true},
+		},
+	)
+	powerOn := &failingPowerOn{}
+	r := &controller.Reconciler{
+		Client: client,
+		Cfg: &config.Config{
+			NodeLabels: config.NodeLabelConfig{Managed: "cba.dev/is-managed"},
+			CircuitBreaker: config.CircuitBreakerConfig{
+				FailureThreshold: 2, Window: time.Hour, Cooldown: 50 * time.Millisecond,
+			},
+		},
+		State:             nodeops.NewNodeStateTracker(),
+		Shutdowner:        &shutdownMock{},
+		PowerOner:         powerOn,
+		ScaleDownStrategy: &MockScaleDownStrategy{},
+		ScaleUpStrategy:   &fixedScaleUpStrategy{node: "off"},
+	}
+
+	require.NoError(t, r.Reconcile(ctx))
+	require.NoError(t, r.Reconcile(ctx))
+	require.Equal(t, 2, powerOn.calls)
+
+	// Open: the next loop takes no power action.
+	require.NoError(t, r.Reconcile(ctx))
+	require.Equal(t, 2, powerOn.calls, "scaling must pause while the breaker is open")
+
+	// After the cooldown the breaker closes and scale-up is tried again.
+	time.Sleep(60 * time.Millisecond)
+	require.NoError(t, r.Reconcile(ctx))
+	require.Equal(t, 3, powerOn.calls)
+}
@@ -0,0 +1,33 @@
+package controller
+
+import (
+	"log/slog"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/notifier"
+)
+
+// ApplyConfig swaps in cfg, e.g. after the config file was reloaded, and rebuilds the strategy
+// chains and the notifier from it. It waits for a running reconcile loop or admin action to finish, so a loop
+// never sees two configs. Clients, power controllers and the load source keep the settings they
+// were created with until restart.
+func (r *Reconciler) ApplyConfig(cfg *config.Config) {
+	r.loopMu.Lock()
+	defer r.loopMu.Unlock()
+
+	r.cfgMu.Lock()
+	r.Cfg = cfg
+	r.cfgMu.Unlock()
+
+	r.ScaleDownStrategy = buildScaleDownStrategy(cfg, r.Client, r.metricsClient, r)
+	r.ScaleUpStrategy = buildScaleUpStrategy(cfg, r)
+	r.Notifier = notifier.New(cfg.Notifications)
+	slog.Info("Applied reloaded config")
+}
+
+// config returns the current config for goroutines that run outside loopMu (reporter, admin API).
+func (r *Reconciler) config() *config.Config {
+	r.cfgMu.RLock()
+	defer r.cfgMu.RUnlock()
+	return r.Cfg
+}
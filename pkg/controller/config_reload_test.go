@@ -0,0 +1,54 @@
+package controller_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/controller"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestApplyConfig_SwapsConfigAndRebuildsStrategies(t *testing.T) {
+	r := controller.NewReconciler(&config.Config{}, fake.NewSimpleClientset(), nil)
+	down, up := chainNames(t, r)
+	require.Equal(t, []string{"ResourceAware"}, down)
+	require.Equal(t, []string{"MinNodeCount"}, up)
+
+	reloaded := &config.Config{LoadAverageStrategy: config.LoadAverageStrategyConfig{Enabled: true}}
+	r.ApplyConfig(reloaded)
+
+	require.Same(t, reloaded, r.Cfg)
+	down, up = chainNames(t, r)
+	require.Equal(t, []string{"ResourceAware", "LoadAverage"}, down)
+	require.Equal(t, []string{"MinNodeCount", "LoadAverageScaleUp"}, up)
+}
+
+type constantPowerDraw struct{}
+
+func (constantPowerDraw) PowerDraw(context.Context, string) (float64, error) { return 100, nil }
+
+// Run with -race: the reporter and power-draw poller read the config outside loopMu.
+func TestApplyConfig_NoRaceWithBackgroundPollers(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset(runningNode("node1", time.Now().Add(-time.Hour), nil))
+	cfg := &config.Config{NodeLabels: config.NodeLabelConfig{Managed: "cba.dev/is-managed"}}
+	r := controller.NewReconciler(cfg, client, nil)
+	r.PowerDraw = constantPowerDraw{}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 50; i++ {
+			r.ApplyConfig(&config.Config{NodeLabels: config.NodeLabelConfig{Managed: "cba.dev/is-managed"}})
+		}
+	}()
+	for i := 0; i < 50; i++ {
+		r.ObserveReport(ctx, time.Now())
+		r.PollPowerDraw(ctx)
+	}
+	<-done
+	r.BuildReport(ctx, time.Now())
+}
@@ -0,0 +1,83 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// criticalDaemonSetsSafe reports whether node can be retired without leaving any of the configured
+// criticalDaemonSets under-replicated: every pod the DaemonSet should still run on the remaining
+// powered-on nodes must be Ready. Pods on nodes CBA powered off are not expected to run. A missing
+// or unreadable DaemonSet blocks the scale-down.
+func (r *Reconciler) criticalDaemonSetsSafe(ctx context.Context, node string) bool {
+	if len(r.Cfg.CriticalDaemonSets) == 0 {
+		return true
+	}
+	nodes, err := r.Client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		slog.Warn("Critical DaemonSets: listing nodes failed; blocking scale-down", "node", node, "err", err)
+		return false
+	}
+	poweredOff := make(map[string]bool, len(nodes.Items))
+	for _, n := range nodes.Items {
+		poweredOff[n.Name] = r.isPoweredOff(n)
+	}
+
+	for _, ref := range r.Cfg.CriticalDaemonSets {
+		ready, expected, err := r.criticalDaemonSetReplicas(ctx, ref, node, poweredOff)
+		if err != nil {
+			slog.Warn("Critical DaemonSets: cannot evaluate; blocking scale-down", "node", node, "daemonSet", ref, "err", err)
+			return false
+		}
+		if ready < expected {
+			slog.Info("Critical DaemonSet would be under-replicated; blocking scale-down",
+				"node", node, "daemonSet", ref, "readyElsewhere", ready, "expectedElsewhere", expected)
+			return false
+		}
+	}
+	return true
+}
+
+// criticalDaemonSetReplicas counts Ready pods of the DaemonSet ref ("namespace/name") outside node, and
+// how many it is expected to run there: desired minus the pods on node and on powered-off nodes.
+func (r *Reconciler) criticalDaemonSetReplicas(ctx context.Context, ref, node string, poweredOff map[string]bool) (int, int, error) {
+	ns, name, _ := strings.Cut(ref, "/")
+	ds, err := r.Client.AppsV1().DaemonSets(ns).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return 0, 0, fmt.Errorf("getting DaemonSet: %w", err)
+	}
+	selector, err := metav1.LabelSelectorAsSelector(ds.Spec.Selector)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid selector: %w", err)
+	}
+	pods, err := r.Client.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return 0, 0, fmt.Errorf("listing pods: %w", err)
+	}
+
+	expected := int(ds.Status.DesiredNumberScheduled)
+	ready := 0
+	for _, p := range pods.Items {
+		switch {
+		case p.Spec.NodeName == node || poweredOff[p.Spec.NodeName]:
+			expected--
+		case podReady(&p):
+			ready++
+		}
+	}
+	return ready, max(expected, 0), nil
+}
+
+func podReady(p *v1.Pod) bool {
+	for _, c := range p.Status.Conditions {
+		if c.Type == v1.PodReady && c.Status == v1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+package controller_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/controller"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func storageDaemonSet(desired int32) *appsv1.DaemonSet {
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "csi-node", Namespace: "storage"},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "csi-node"}},
+		},
+		Status: appsv1.DaemonSetStatus{DesiredNumberScheduled: desired},
+	}
+}
+
+func storagePod(name, node string, ready bool) *v1.Pod {
+	status := v1.ConditionFalse
+	if ready {
+		status = v1.ConditionTrue
+	}
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name, Namespace: "storage", Labels: map[string]string{"app": "csi-node"},
+			OwnerReferences: controlledBy("DaemonSet", "csi-node"),
+		},
+		Spec:   v1.PodSpec{NodeName: node},
+		Status: v1.PodStatus{Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: status}}},
+	}
+}
+
+func TestReconcile_CriticalDaemonSets(t *testing.T) {
+	hourAgo := time.Now().Add(-time.Hour)
+	nodes := []runtime.Object{runningNode("a", hourAgo, nil), runningNode("b", hourAgo, nil)}
+	tests := []struct {
+		name         string
+		objs         []runtime.Object
+		wantShutdown bool
+	}{
+		{
+			name:         "all replicas ready",
+			objs:         []runtime.Object{storageDaemonSet(2), storagePod("csi-a", "a", true), storagePod("csi-b", "b", true)},
+			wantShutdown: true,
+		},
+		{
+			name:         "replicas elsewhere not ready",
+			objs:         []runtime.Object{storageDaemonSet(2), storagePod("csi-a", "a", false), storagePod("csi-b", "b", false)},
+			wantShutdown: false,
+		},
+		{
+			name: "replica missing on another node",
+			objs: []runtime.Object{
+				runningNode("c", time.Now(), nil), // booted just now: not eligible, but should run the DaemonSet
+				storageDaemonSet(3), storagePod("csi-a", "a", true), storagePod("csi-b", "b", true),
+			},
+			wantShutdown: false,
+		},
+		{
+			name: "pods on powered-off nodes are not expected",
+			objs: []runtime.Object{
+				poweredOffNode("off"),
+				storageDaemonSet(3), storagePod("csi-a", "a", true), storagePod("csi-b", "b", true), storagePod("csi-off", "off", false),
+			},
+			wantShutdown: true,
+		},
+		{
+			name:         "missing DaemonSet blocks",
+			objs:         nil,
+			wantShutdown: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			client := fake.NewSimpleClientset(append(append([]runtime.Object{}, nodes...), tt.objs...)...)
+			sim := &bootSimulator{client: client}
+			r := &controller.Reconciler{
+				Client: client,
+				Cfg: &config.Config{
+					BootCooldown:       time.Hour,
+					NodeLabels:         config.NodeLabelConfig{Managed: "cba.dev/is-managed"},
+					CriticalDaemonSets: []string{"storage/csi-node"},
+				},
+				State:             nodeops.NewNodeStateTracker(),
+				Shutdowner:        sim,
+				PowerOner:         sim,
+				ScaleDownStrategy: approveAllStrategy{},
+				ScaleUpStrategy:   &mockScaleUpStrategy{},
+			}
+			r.State.MarkBooted("c")
+
+			require.NoError(t, r.Reconcile(ctx))
+			if tt.wantShutdown {
+				require.Len(t, sim.ShutDown, 1)
+				return
+			}
+			require.Empty(t, sim.ShutDown)
+			for _, name := range []string{"a", "b"} {
+				n, err := client.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+				require.NoError(t, err)
+				require.False(t, n.Spec.Unschedulable, "blocked candidate must not be cordoned")
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/apis/v1alpha1"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/strategy"
+)
+
+// lastAction is the most recent power action taken by this controller, reported in CR status.
+type lastAction struct {
+	action string
+	node   string
+	at     time.Time
+}
+
+func (r *Reconciler) recordAction(action, node string) {
+	r.lastAction = &lastAction{action: action, node: node, at: time.Now()}
+	switch action {
+	case "scale-up":
+		r.lastScaleUp = r.lastAction.at
+	case "scale-down":
+		r.lastScaleDown = r.lastAction.at
+	}
+}
+
+// customResourceEnabled reports whether the ClusterBareAutoscaler resource should be read and updated.
+func (r *Reconciler) customResourceEnabled() bool {
+	return r.Cfg.CustomResource.Enabled && r.Dynamic != nil
+}
+
+// RefreshCustomResourceSpec loads the spec of the configured ClusterBareAutoscaler. When the
+// resource is missing or unreadable the previous spec is dropped and config values apply.
+func (r *Reconciler) RefreshCustomResourceSpec(ctx context.Context) {
+	if !r.customResourceEnabled() {
+		r.crSpec = nil
+		return
+	}
+	defer r.applyThresholdOverrides()
+	name := r.Cfg.CustomResource.Name
+	obj, err := r.Dynamic.Resource(v1alpha1.Resource).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			slog.Debug("ClusterBareAutoscaler resource not found — using config values", "name", name)
+		} else {
+			slog.Warn("Failed to read ClusterBareAutoscaler spec — using config values", "name", name, "err", err)
+		}
+		r.crSpec = nil
+		return
+	}
+	var cr v1alpha1.ClusterBareAutoscaler
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &cr); err != nil {
+		slog.Warn("Invalid ClusterBareAutoscaler spec — using config values", "name", name, "err", err)
+		r.crSpec = nil
+		return
+	}
+	r.crSpec = &cr.Spec
+}
+
+// paused reports whether the custom resource asks CBA to stop taking actions.
+func (r *Reconciler) paused() bool {
+	return r.crSpec != nil && r.crSpec.Paused
+}
+
+// scaleDownBuffer returns scaleDownBuffer, overridden by the custom resource when set.
+func (r *Reconciler) scaleDownBuffer() int {
+	if r.crSpec != nil && r.crSpec.ScaleDownBuffer != nil {
+		return *r.crSpec.ScaleDownBuffer
+	}
+	return r.Cfg.ScaleDownBuffer
+}
+
+// applyThresholdOverrides points the load-average strategies at the spec's threshold overrides,
+// or back at loadAverageStrategy when they are unset or would leave scaleUpThreshold at or below
+// scaleDownThreshold.
+func (r *Reconciler) applyThresholdOverrides() {
+	la := r.Cfg.LoadAverageStrategy
+	node, down, up := la.NodeThreshold, la.ScaleDownThreshold, la.ScaleUpThreshold
+	if s := r.crSpec; s != nil {
+		if s.NodeThreshold != nil {
+			node = *s.NodeThreshold
+		}
+		if s.ScaleDownThreshold != nil {
+			down = *s.ScaleDownThreshold
+		}
+		if s.ScaleUpThreshold != nil {
+			up = *s.ScaleUpThreshold
+		}
+		if up <= down {
+			slog.Warn("ClusterBareAutoscaler thresholds ignored: scaleUpThreshold must be greater than scaleDownThreshold",
+				"scaleDownThreshold", down, "scaleUpThreshold", up)
+			node, down, up = la.NodeThreshold, la.ScaleDownThreshold, la.ScaleUpThreshold
+		}
+	}
+
+	if multi, ok := r.ScaleDownStrategy.(*strategy.MultiStrategy); ok {
+		for _, s := range multi.Strategies {
+			if l, ok := s.(*strategy.LoadAverageScaleDown); ok {
+				l.NodeThreshold, l.ClusterWideThreshold = node, down
+			}
+		}
+	}
+	if multi, ok := r.ScaleUpStrategy.(*strategy.MultiUpStrategy); ok {
+		for _, s := range multi.Strategies {
+			if l, ok := s.(*strategy.LoadAverageScaleUp); ok {
+				l.ClusterWideThreshold = up
+			}
+		}
+	}
+}
+
+// WriteCustomResourceStatus publishes the current fleet state to the ClusterBareAutoscaler status.
+// A missing resource is not an error: users opt in by creating it.
+func (r *Reconciler) WriteCustomResourceStatus(ctx context.Context) {
+	if !r.customResourceEnabled() {
+		return
+	}
+	status, err := r.buildCustomResourceStatus(ctx)
+	if err != nil {
+		slog.Warn("Failed to build ClusterBareAutoscaler status", "err", err)
+		return
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&status)
+	if err != nil {
+		slog.Warn("Failed to encode ClusterBareAutoscaler status", "err", err)
+		return
+	}
+
+	name := r.Cfg.CustomResource.Name
+	client := r.Dynamic.Resource(v1alpha1.Resource)
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		obj, err := client.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		obj.Object["status"] = content
+		_, err = client.UpdateStatus(ctx, obj, metav1.UpdateOptions{})
+		return err
+	})
+	switch {
+	case apierrors.IsNotFound(err):
+		slog.Debug("ClusterBareAutoscaler resource not found — skipping status update", "name", name)
+	case err != nil:
+		slog.Warn("Failed to update ClusterBareAutoscaler status", "name", name, "err", err)
+	}
+}
+
+func (r *Reconciler) buildCustomResourceStatus(ctx context.Context) (v1alpha1.ClusterBareAutoscalerStatus, error) {
+	active, err := r.listActiveNodes(ctx)
+	if err != nil {
+		return v1alpha1.ClusterBareAutoscalerStatus{}, fmt.Errorf("listing active nodes: %w", err)
+	}
+	status := v1alpha1.ClusterBareAutoscalerStatus{
+		ActiveNodes:     len(active),
+		PoweredOffNodes: r.shutdownNodeNames(ctx),
+		MinNodes:        r.MinNodes(),
+		Paused:          r.paused(),
+		CircuitBreaker:  v1alpha1.CircuitClosed,
+		AgentsHealthy:   !r.agentsUnhealthy,
+		ObservedTime:    metav1.Now(),
+	}
+	if status.PoweredOffNodes == nil {
+		status.PoweredOffNodes = []string{}
+	}
+	if time.Now().Before(r.breakerOpenUntil) {
+		status.CircuitBreaker = v1alpha1.CircuitOpen
+	}
+	if a := r.lastAction; a != nil {
+		at := metav1.NewTime(a.at)
+		status.LastAction, status.LastActionNode, status.LastActionTime = a.action, a.node, &at
+	}
+	return status, nil
+}
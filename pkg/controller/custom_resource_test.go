@@ -0,0 +1,157 @@
+package controller_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/apis/v1alpha1"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/controller"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/strategy"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func cbaResource(spec map[string]any) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]any{"spec": spec}}
+	obj.SetAPIVersion(v1alpha1.Group + "/" + v1alpha1.Version)
+	obj.SetKind(v1alpha1.Kind)
+	obj.SetName("default")
+	return obj
+}
+
+func newCRReconciler(t *testing.T, spec map[string]any) (*controller.Reconciler, *bootSimulator, *dynamicfake.FakeDynamicClient) {
+	t.Helper()
+	hourAgo := time.Now().Add(-time.Hour)
+	client := fake.NewSimpleClientset(
+		runningNode("a", hourAgo, nil), runningNode("b", hourAgo, nil), poweredOffNode("off"),
+	)
+	dyn := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{v1alpha1.Resource: v1alpha1.Kind + "List"},
+		cbaResource(spec))
+	sim := &bootSimulator{client: client}
+	r := newSimReconciler(client, sim, &config.Config{
+		MinNodes:       1,
+		CustomResource: config.CustomResourceConfig{Enabled: true, Name: "default"},
+	})
+	r.Dynamic = dyn
+	r.State.MarkPoweredOff("off")
+	return r, sim, dyn
+}
+
+func getStatus(t *testing.T, dyn *dynamicfake.FakeDynamicClient) v1alpha1.ClusterBareAutoscalerStatus {
+	t.Helper()
+	obj, err := dyn.Resource(v1alpha1.Resource).Get(context.Background(), "default", metav1.GetOptions{})
+	require.NoError(t, err)
+	var cr v1alpha1.ClusterBareAutoscaler
+	require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &cr))
+	return cr.Status
+}
+
+func TestReconcile_WritesCustomResourceStatus(t *testing.T) {
+	r, sim, dyn := newCRReconciler(t, map[string]any{})
+
+	require.NoError(t, r.Reconcile(context.Background()))
+	require.Len(t, sim.ShutDown, 1)
+
+	status := getStatus(t, dyn)
+	require.Equal(t, 1, status.ActiveNodes)
+	require.ElementsMatch(t, []string{"off", sim.ShutDown[0]}, status.PoweredOffNodes)
+	require.Equal(t, 1, status.MinNodes)
+	require.False(t, status.Paused)
+	require.Equal(t, "scale-down", status.LastAction)
+	require.Equal(t, sim.ShutDown[0], status.LastActionNode)
+	require.NotNil(t, status.LastActionTime)
+	require.Equal(t, v1alpha1.CircuitClosed, status.CircuitBreaker)
+	require.True(t, status.AgentsHealthy)
+	require.False(t, status.ObservedTime.IsZero())
+}
+
+func TestReconcile_CustomResourceSpecOverrides(t *testing.T) {
+	t.Run("paused", func(t *testing.T) {
+		r, sim, dyn := newCRReconciler(t, map[string]any{"paused": true})
+
+		require.NoError(t, r.Reconcile(context.Background()))
+		require.Empty(t, sim.ShutDown)
+
+		status := getStatus(t, dyn)
+		require.True(t, status.Paused)
+		require.Equal(t, 2, status.ActiveNodes)
+		require.Empty(t, status.LastAction)
+	})
+
+	t.Run("minNodes", func(t *testing.T) {
+		r, sim, dyn := newCRReconciler(t, map[string]any{"minNodes": int64(2)})
+
+		require.NoError(t, r.Reconcile(context.Background()))
+		require.Empty(t, sim.ShutDown, "spec.minNodes=2 with two active nodes leaves nothing to retire")
+		require.Equal(t, 2, getStatus(t, dyn).MinNodes)
+	})
+
+	thresholdTests := []struct {
+		name                       string
+		spec                       map[string]any
+		wantNode, wantDown, wantUp float64
+	}{
+		{name: "load thresholds", spec: map[string]any{"nodeThreshold": 0.9, "scaleDownThreshold": 0.2, "scaleUpThreshold": 0.7},
+			wantNode: 0.9, wantDown: 0.2, wantUp: 0.7},
+		{name: "inconsistent load thresholds keep config", spec: map[string]any{"scaleUpThreshold": 0.1},
+			wantNode: 0.8, wantDown: 0.3, wantUp: 0.6},
+	}
+	for _, tt := range thresholdTests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _, _ := newCRReconciler(t, tt.spec)
+			r.Cfg.LoadAverageStrategy = config.LoadAverageStrategyConfig{NodeThreshold: 0.8, ScaleDownThreshold: 0.3, ScaleUpThreshold: 0.6}
+			down := &strategy.LoadAverageScaleDown{}
+			up := &strategy.LoadAverageScaleUp{}
+			r.ScaleDownStrategy = &strategy.MultiStrategy{Strategies: []strategy.ScaleDownStrategy{down}}
+			r.ScaleUpStrategy = &strategy.MultiUpStrategy{Strategies: []strategy.ScaleUpStrategy{up}}
+
+			r.RefreshCustomResourceSpec(context.Background())
+			require.Equal(t, tt.wantNode, down.NodeThreshold)
+			require.Equal(t, tt.wantDown, down.ClusterWideThreshold)
+			require.Equal(t, tt.wantUp, up.ClusterWideThreshold)
+		})
+	}
+}
+
+func TestReconcile_CustomResourceReportsAgentHealthSeparately(t *testing.T) {
+	r, sim, dyn := newCRReconciler(t, map[string]any{})
+	r.Cfg.ShutdownMode = "http"
+	r.Cfg.ShutdownManager = config.ShutdownManagerConfig{Namespace: "cba", PodLabel: "app=poweroff"}
+	r.Cfg.MinAgentHealthRatio = 0.8 // no poweroff DaemonSet exists, so the agents count as unhealthy
+
+	require.NoError(t, r.Reconcile(context.Background()))
+	require.Empty(t, sim.ShutDown)
+	status := getStatus(t, dyn)
+	require.False(t, status.AgentsHealthy)
+	require.Equal(t, v1alpha1.CircuitClosed, status.CircuitBreaker, "unhealthy agents do not open the circuit breaker")
+}
+
+func TestReconcile_CustomResourceMissing(t *testing.T) {
+	r, sim, _ := newCRReconciler(t, nil)
+	r.Cfg.CustomResource.Name = "absent"
+
+	require.NoError(t, r.Reconcile(context.Background()))
+	require.Len(t, sim.ShutDown, 1, "config values apply when the resource does not exist")
+}
+
+func TestReconcile_CustomResourceReportsOpenCircuitBreaker(t *testing.T) {
+	r, _, dyn := newCRReconciler(t, map[string]any{})
+	r.Cfg.CircuitBreaker = config.CircuitBreakerConfig{FailureThreshold: 1, Window: time.Hour, Cooldown: time.Hour}
+	r.PowerOner = &failingPowerOn{}
+	r.ScaleUpStrategy = &fixedScaleUpStrategy{node: "off"}
+
+	require.NoError(t, r.Reconcile(context.Background()))
+	require.Equal(t, v1alpha1.CircuitOpen, getStatus(t, dyn).CircuitBreaker,
+		"a failed power-on at failureThreshold=1 opens the breaker")
+
+	require.NoError(t, r.Reconcile(context.Background()))
+	require.Equal(t, v1alpha1.CircuitOpen, getStatus(t, dyn).CircuitBreaker, "still open during the cooldown")
+}
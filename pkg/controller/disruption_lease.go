@@ -0,0 +1,168 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type disruptionLeaseKey struct{}
+
+// holdDisruptionLease takes the disruption Lease for a drain or power-off and keeps renewing it
+// until release is called. A ctx that already holds the lease is returned as is with a no-op
+// release, so scaleDownNode and the powerOffDrained call nested in it take the lease only once.
+// ok is false when the lease is held elsewhere.
+func (r *Reconciler) holdDisruptionLease(ctx context.Context) (_ context.Context, release func(), ok bool) {
+	if ctx.Value(disruptionLeaseKey{}) != nil {
+		return ctx, func() {}, true
+	}
+	if !r.acquireDisruptionLease(ctx) {
+		return ctx, nil, false
+	}
+	cfg := r.Cfg.DisruptionLease
+	if !cfg.Enabled || r.Cfg.IsK8sDryRun() {
+		return context.WithValue(ctx, disruptionLeaseKey{}, true), func() {}, true
+	}
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		// Renew well before expiry so a long drain never lets another holder in.
+		ticker := time.NewTicker(time.Duration(cfg.DurationSeconds) * time.Second / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				r.renewDisruptionLease(ctx, cfg)
+			}
+		}
+	}()
+	release = func() {
+		close(stop)
+		<-done
+		r.releaseDisruptionLease(ctx)
+	}
+	return context.WithValue(ctx, disruptionLeaseKey{}, true), release, true
+}
+
+// acquireDisruptionLease takes the configured disruption Lease for a drain or power-off. It returns
+// false when another holder's lease is unexpired or the Lease cannot be read or written; a
+// conflicting update means someone else just took it. It always succeeds when the lease is disabled
+// or in Kubernetes dry-run.
+func (r *Reconciler) acquireDisruptionLease(ctx context.Context) bool {
+	cfg := r.Cfg.DisruptionLease
+	if !cfg.Enabled {
+		return true
+	}
+	if r.Cfg.IsK8sDryRun() {
+		slog.Info("Dry-run: would acquire disruption lease", "namespace", cfg.Namespace, "name", cfg.Name)
+		return true
+	}
+
+	leases := r.Client.CoordinationV1().Leases(cfg.Namespace)
+	now := metav1.NewMicroTime(time.Now())
+	duration := int32(cfg.DurationSeconds)
+	lease, err := leases.Get(ctx, cfg.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = leases.Create(ctx, &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{Name: cfg.Name, Namespace: cfg.Namespace},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       &cfg.Identity,
+				LeaseDurationSeconds: &duration,
+				AcquireTime:          &now,
+				RenewTime:            &now,
+			},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			slog.Warn("Creating disruption lease failed", "namespace", cfg.Namespace, "name", cfg.Name, "err", err)
+			return false
+		}
+		return true
+	}
+	if err != nil {
+		slog.Warn("Reading disruption lease failed", "namespace", cfg.Namespace, "name", cfg.Name, "err", err)
+		return false
+	}
+
+	if holder := leaseHolder(lease, now.Time); holder != "" && holder != cfg.Identity {
+		slog.Info("Disruption lease held by another holder — skipping scale-down", "holder", holder,
+			"namespace", cfg.Namespace, "name", cfg.Name)
+		return false
+	}
+	lease.Spec.HolderIdentity = &cfg.Identity
+	lease.Spec.LeaseDurationSeconds = &duration
+	lease.Spec.AcquireTime = &now
+	lease.Spec.RenewTime = &now
+	if _, err := leases.Update(ctx, lease, metav1.UpdateOptions{}); err != nil {
+		slog.Warn("Taking disruption lease failed", "namespace", cfg.Namespace, "name", cfg.Name, "err", err)
+		return false
+	}
+	return true
+}
+
+// renewDisruptionLease bumps the renew time of the disruption Lease while CBA holds it.
+func (r *Reconciler) renewDisruptionLease(ctx context.Context, cfg config.DisruptionLeaseConfig) {
+	leases := r.Client.CoordinationV1().Leases(cfg.Namespace)
+	lease, err := leases.Get(ctx, cfg.Name, metav1.GetOptions{})
+	if err != nil {
+		slog.Warn("Reading disruption lease for renewal failed", "namespace", cfg.Namespace, "name", cfg.Name, "err", err)
+		return
+	}
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != cfg.Identity {
+		slog.Warn("Disruption lease lost during drain", "namespace", cfg.Namespace, "name", cfg.Name)
+		return
+	}
+	now := metav1.NewMicroTime(time.Now())
+	lease.Spec.RenewTime = &now
+	if _, err := leases.Update(ctx, lease, metav1.UpdateOptions{}); err != nil {
+		slog.Warn("Renewing disruption lease failed", "namespace", cfg.Namespace, "name", cfg.Name, "err", err)
+	}
+}
+
+// releaseDisruptionLease clears the holder of the disruption Lease if CBA still holds it.
+func (r *Reconciler) releaseDisruptionLease(ctx context.Context) {
+	cfg := r.Cfg.DisruptionLease
+	if !cfg.Enabled || r.Cfg.IsK8sDryRun() {
+		return
+	}
+	leases := r.Client.CoordinationV1().Leases(cfg.Namespace)
+	lease, err := leases.Get(ctx, cfg.Name, metav1.GetOptions{})
+	if err != nil {
+		slog.Warn("Reading disruption lease for release failed", "namespace", cfg.Namespace, "name", cfg.Name, "err", err)
+		return
+	}
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != cfg.Identity {
+		return
+	}
+	lease.Spec.HolderIdentity = nil
+	lease.Spec.AcquireTime = nil
+	lease.Spec.RenewTime = nil
+	if _, err := leases.Update(ctx, lease, metav1.UpdateOptions{}); err != nil {
+		slog.Warn("Releasing disruption lease failed; it expires on its own", "namespace", cfg.Namespace, "name", cfg.Name, "err", err)
+	}
+}
+
+// leaseHolder returns the lease's holder, or "" when it has none or its lease has expired.
+func leaseHolder(lease *coordinationv1.Lease, now time.Time) string {
+	spec := lease.Spec
+	if spec.HolderIdentity == nil || *spec.HolderIdentity == "" {
+		return ""
+	}
+	if spec.RenewTime != nil && spec.LeaseDurationSeconds != nil {
+		expiry := spec.RenewTime.Add(time.Duration(*spec.LeaseDurationSeconds) * time.Second)
+		if now.After(expiry) {
+			return ""
+		}
+	}
+	return *spec.HolderIdentity
+}
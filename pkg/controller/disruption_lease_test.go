@@ -0,0 +1,156 @@
+package controller_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/controller"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
+	"github.com/stretchr/testify/require"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func disruptionLease(holder string, renewed time.Time) *coordinationv1.Lease {
+	duration := int32(600)
+	renew := metav1.NewMicroTime(renewed)
+	return &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-disruption", Namespace: "kube-system"},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       &holder,
+			LeaseDurationSeconds: &duration,
+			RenewTime:            &renew,
+		},
+	}
+}
+
+func TestReconcile_DisruptionLease(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing *coordinationv1.Lease
+		wantOff  bool
+	}{
+		{name: "no lease yet", wantOff: true},
+		{name: "held by another tool", existing: disruptionLease("node-upgrader", time.Now()), wantOff: false},
+		{name: "expired lease of another tool", existing: disruptionLease("node-upgrader", time.Now().Add(-time.Hour)), wantOff: true},
+		{name: "released lease", existing: disruptionLease("", time.Now()), wantOff: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			hourAgo := time.Now().Add(-time.Hour)
+			objects := []runtime.Object{runningNode("a", hourAgo, nil), runningNode("b", hourAgo, nil)}
+			if tt.existing != nil {
+				objects = append(objects, tt.existing)
+			}
+			client := fake.NewSimpleClientset(objects...)
+			sim := &bootSimulator{client: client}
+			r := &controller.Reconciler{
+				Client: client,
+				Cfg: &config.Config{
+					NodeLabels: config.NodeLabelConfig{Managed: "cba.dev/is-managed"},
+					DisruptionLease: config.DisruptionLeaseConfig{
+						Enabled: true, Namespace: "kube-system", Name: "cluster-disruption",
+						Identity: "cluster-bare-autoscaler", DurationSeconds: 900,
+					},
+				},
+				State:             nodeops.NewNodeStateTracker(),
+				Shutdowner:        sim,
+				PowerOner:         sim,
+				ScaleDownStrategy: approveAllStrategy{},
+				ScaleUpStrategy:   &mockScaleUpStrategy{},
+			}
+
+			require.NoError(t, r.Reconcile(ctx))
+
+			lease, err := client.CoordinationV1().Leases("kube-system").Get(ctx, "cluster-disruption", metav1.GetOptions{})
+			require.NoError(t, err)
+			if !tt.wantOff {
+				require.Empty(t, sim.ShutDown, "a lease held elsewhere must block the drain")
+				for _, name := range []string{"a", "b"} {
+					n, err := client.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+					require.NoError(t, err)
+					require.False(t, n.Spec.Unschedulable, "node %s must not be cordoned", name)
+				}
+				require.Equal(t, "node-upgrader", *lease.Spec.HolderIdentity, "the other holder's lease is untouched")
+				return
+			}
+			require.Len(t, sim.ShutDown, 1)
+			require.Nil(t, lease.Spec.HolderIdentity, "CBA releases the lease after the scale-down")
+		})
+	}
+}
+
+func leaseConfig(durationSeconds int) config.DisruptionLeaseConfig {
+	return config.DisruptionLeaseConfig{
+		Enabled: true, Namespace: "kube-system", Name: "cluster-disruption",
+		Identity: "cluster-bare-autoscaler", DurationSeconds: durationSeconds,
+	}
+}
+
+func TestMaybeResolveStaleCordons_HonorsDisruptionLease(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset(
+		cordonedNode("cordoned", time.Now().Add(-time.Hour)),
+		disruptionLease("node-upgrader", time.Now()),
+	)
+	sim := &bootSimulator{client: client}
+	r := newStaleCordonReconciler(client, sim, config.CordonedOnActionPowerOff)
+	r.Cfg.DisruptionLease = leaseConfig(900)
+
+	require.True(t, r.MaybeResolveStaleCordons(ctx))
+	require.Empty(t, sim.ShutDown, "a lease held elsewhere must block the stale-cordon power-off")
+	requireUncordoned(t, client, "cordoned")
+}
+
+func TestReconcile_DisruptionLeaseRenewedDuringDrain(t *testing.T) {
+	ctx := context.Background()
+	hourAgo := time.Now().Add(-time.Hour)
+	pod := func(node string) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-" + node, Namespace: "default", OwnerReferences: controlledBy("ReplicaSet", "web")},
+			Spec:       v1.PodSpec{NodeName: node},
+		}
+	}
+	client := fake.NewSimpleClientset(runningNode("a", hourAgo, nil), runningNode("b", hourAgo, nil), pod("a"), pod("b"))
+	client.Fake.PrependReactor("create", "pods/eviction", func(k8stesting.Action) (bool, runtime.Object, error) {
+		time.Sleep(900 * time.Millisecond) // a drain outlasting several renew periods
+		return true, nil, nil
+	})
+	renewals := 0
+	client.Fake.PrependReactor("update", "leases", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		lease := action.(k8stesting.UpdateAction).GetObject().(*coordinationv1.Lease)
+		if lease.Spec.HolderIdentity != nil {
+			renewals++
+		}
+		return false, nil, nil
+	})
+	sim := &bootSimulator{client: client}
+	r := &controller.Reconciler{
+		Client: client,
+		Cfg: &config.Config{
+			NodeLabels:      config.NodeLabelConfig{Managed: "cba.dev/is-managed"},
+			DisruptionLease: leaseConfig(1),
+		},
+		State:             nodeops.NewNodeStateTracker(),
+		Shutdowner:        sim,
+		PowerOner:         sim,
+		ScaleDownStrategy: approveAllStrategy{},
+		ScaleUpStrategy:   &mockScaleUpStrategy{},
+	}
+
+	require.NoError(t, r.Reconcile(ctx))
+	require.Len(t, sim.ShutDown, 1)
+	require.GreaterOrEqual(t, renewals, 2, "the lease must be renewed while the drain runs")
+
+	lease, err := client.CoordinationV1().Leases("kube-system").Get(ctx, "cluster-disruption", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Nil(t, lease.Spec.HolderIdentity, "released once the power-off is done")
+}
@@ -0,0 +1,243 @@
+package controller_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/docent-net/cluster-bare-autoscaler/internal/bootstrap/metrics"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/controller"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// pdbBlockedClient returns a client whose evictions of mypod on node1 fail with err for the first
+// failures attempts and succeed afterwards; failures < 0 fails forever.
+func pdbBlockedClient(failures int, err error) (*fake.Clientset, *int) {
+	client := fake.NewSimpleClientset(
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}},
+		&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "mypod", Namespace: "default", OwnerReferences: controlledBy("ReplicaSet", "web")},
+			Spec:       v1.PodSpec{NodeName: "node1"},
+		},
+	)
+	attempts := 0
+	client.Fake.PrependReactor("create", "pods/eviction", func(k8stesting.Action) (bool, runtime.Object, error) {
+		attempts++
+		if failures < 0 || attempts <= failures {
+			return true, nil, err
+		}
+		return true, nil, nil
+	})
+	return client, &attempts
+}
+
+func drainTarget() *nodeops.NodeWrapper {
+	return nodeops.NewNodeWrapper(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}},
+		nodeops.NewNodeStateTracker(), time.Now(), nodeops.NodeAnnotationConfig{}, map[string]string{})
+}
+
+func TestCordonAndDrain_RetriesPDBBlockedEviction(t *testing.T) {
+	pdbErr := apierrors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 0)
+	client, attempts := pdbBlockedClient(3, pdbErr)
+	var waits []time.Duration
+	r := &controller.Reconciler{
+		Client: client,
+		Cfg:    &config.Config{DrainTimeout: time.Hour, DrainRetryInterval: 20 * time.Second},
+		Sleep: func(_ context.Context, d time.Duration) error {
+			waits = append(waits, d)
+			return nil
+		},
+	}
+
+	require.NoError(t, r.CordonAndDrain(context.Background(), drainTarget()))
+	require.Equal(t, 4, *attempts)
+	require.Equal(t, []time.Duration{20 * time.Second, 40 * time.Second, time.Minute}, waits, "backoff doubles up to the cap")
+}
+
+func TestCordonAndDrain_PermanentEvictionFailureAbortsAtOnce(t *testing.T) {
+	client, attempts := pdbBlockedClient(-1, fmt.Errorf("eviction failed"))
+	r := &controller.Reconciler{
+		Client: client,
+		Cfg:    &config.Config{DrainTimeout: time.Hour, DrainRetryInterval: time.Second},
+		Sleep: func(context.Context, time.Duration) error {
+			t.Fatal("a non-PDB eviction failure must not be retried")
+			return nil
+		},
+	}
+
+	err := r.CordonAndDrain(context.Background(), drainTarget())
+	require.ErrorContains(t, err, "aborting drain due to eviction failure")
+	require.Equal(t, 1, *attempts)
+}
+
+func TestCordonAndDrain_PDBBlockedWithoutTimeoutAbortsAtOnce(t *testing.T) {
+	client, attempts := pdbBlockedClient(-1, apierrors.NewTooManyRequests("disruption budget", 0))
+	r := &controller.Reconciler{
+		Client: client,
+		Cfg:    &config.Config{DrainRetryInterval: time.Second},
+	}
+
+	require.Error(t, r.CordonAndDrain(context.Background(), drainTarget()))
+	require.Equal(t, 1, *attempts)
+}
+
+func TestCordonAndDrain_PDBBlockedUntilTimeout(t *testing.T) {
+	client, attempts := pdbBlockedClient(-1, apierrors.NewTooManyRequests("disruption budget", 0))
+	r := &controller.Reconciler{
+		Client: client,
+		Cfg:    &config.Config{DrainTimeout: 100 * time.Millisecond, DrainRetryInterval: 10 * time.Millisecond},
+	}
+	before := testutil.ToFloat64(metrics.DrainTimeouts)
+
+	err := r.CordonAndDrain(context.Background(), drainTarget())
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Greater(t, *attempts, 1, "a PDB-blocked eviction is retried until the deadline")
+	require.Equal(t, 1.0, testutil.ToFloat64(metrics.DrainTimeouts)-before)
+}
+
+func TestCordonAndDrain_ForceDeleteCountsTimeoutOnce(t *testing.T) {
+	for _, deleteFails := range []bool{false, true} {
+		client, _ := pdbBlockedClient(-1, apierrors.NewTooManyRequests("disruption budget", 0))
+		if deleteFails {
+			client.Fake.PrependReactor("delete", "pods", func(k8stesting.Action) (bool, runtime.Object, error) {
+				return true, nil, errors.New("apiserver unavailable")
+			})
+		}
+		r := &controller.Reconciler{
+			Client: client,
+			Cfg: &config.Config{
+				DrainTimeout: 50 * time.Millisecond, DrainRetryInterval: 10 * time.Millisecond,
+				ForceDeleteAfterDrainTimeout: true,
+			},
+		}
+		before := testutil.ToFloat64(metrics.DrainTimeouts)
+
+		err := r.CordonAndDrain(context.Background(), drainTarget())
+		require.Equal(t, deleteFails, err != nil, "deleteFails=%v", deleteFails)
+		require.Equal(t, 1.0, testutil.ToFloat64(metrics.DrainTimeouts)-before, "deleteFails=%v", deleteFails)
+	}
+}
+
+func TestCordonAndDrain_ForceDeleteAfterDrainTimeout(t *testing.T) {
+	for _, dryRun := range []bool{false, true} {
+		t.Run(fmt.Sprintf("dryRun=%v", dryRun), func(t *testing.T) {
+			ctx := context.Background()
+			client, _ := pdbBlockedClient(-1, apierrors.NewTooManyRequests("disruption budget", 0))
+			for _, pod := range []*v1.Pod{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "ds-pod", Namespace: "kube-system", OwnerReferences: controlledBy("DaemonSet", "agent")},
+					Spec:       v1.PodSpec{NodeName: "node1"},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "mirror-pod", Namespace: "kube-system", Annotations: map[string]string{"kubernetes.io/config.mirror": "x"}},
+					Spec:       v1.PodSpec{NodeName: "node1"},
+				},
+			} {
+				require.NoError(t, client.Tracker().Add(pod))
+			}
+			r := &controller.Reconciler{
+				Client: client,
+				Cfg: &config.Config{
+					DrainTimeout:                 50 * time.Millisecond,
+					DrainRetryInterval:           10 * time.Millisecond,
+					ForceDeleteAfterDrainTimeout: true,
+					DryRunK8s:                    dryRun,
+				},
+			}
+
+			require.NoError(t, r.CordonAndDrain(ctx, drainTarget()))
+
+			pods, err := client.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+			require.NoError(t, err)
+			var left []string
+			for _, p := range pods.Items {
+				left = append(left, p.Name)
+			}
+			if dryRun {
+				require.ElementsMatch(t, []string{"mypod", "ds-pod", "mirror-pod"}, left)
+				return
+			}
+			require.ElementsMatch(t, []string{"ds-pod", "mirror-pod"}, left, "only mirror and DaemonSet pods survive a forced drain")
+		})
+	}
+}
+
+func TestCordonAndDrain_GracePeriod(t *testing.T) {
+	podGrace := int64(120)
+	tests := []struct {
+		name       string
+		cfg        config.Config
+		blocked    bool // evictions are refused by a PDB until the drain times out
+		wantEvict  *int64
+		wantDelete *int64
+	}{
+		{name: "eviction keeps the pod's own grace", cfg: config.Config{ForceDeleteGracePeriod: 5 * time.Second}},
+		{name: "drainGracePeriod overrides eviction grace", cfg: config.Config{DrainGracePeriod: 45 * time.Second}, wantEvict: ptr(45)},
+		{
+			name:       "force-delete shortens grace",
+			cfg:        config.Config{ForceDeleteAfterDrainTimeout: true, ForceDeleteGracePeriod: 5 * time.Second},
+			blocked:    true,
+			wantDelete: ptr(5),
+		},
+		{
+			name:    "force-delete without a cap keeps the pod's own grace",
+			cfg:     config.Config{ForceDeleteAfterDrainTimeout: true},
+			blocked: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(
+				&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}},
+				&v1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: "mypod", Namespace: "default", OwnerReferences: controlledBy("ReplicaSet", "web")},
+					Spec:       v1.PodSpec{NodeName: "node1", TerminationGracePeriodSeconds: &podGrace},
+				},
+			)
+			var evictGrace, deleteGrace []*int64
+			client.Fake.PrependReactor("create", "pods/eviction", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				evictGrace = append(evictGrace, action.(k8stesting.CreateAction).GetObject().(*policyv1.Eviction).DeleteOptions.GracePeriodSeconds)
+				if tt.blocked {
+					return true, nil, apierrors.NewTooManyRequests("disruption budget", 0)
+				}
+				return true, nil, nil
+			})
+			client.Fake.PrependReactor("delete", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				deleteGrace = append(deleteGrace, action.(k8stesting.DeleteAction).GetDeleteOptions().GracePeriodSeconds)
+				return false, nil, nil
+			})
+			cfg := tt.cfg
+			if tt.blocked {
+				cfg.DrainTimeout, cfg.DrainRetryInterval = 50*time.Millisecond, 10*time.Millisecond
+			}
+			r := &controller.Reconciler{Client: client, Cfg: &cfg}
+
+			require.NoError(t, r.CordonAndDrain(context.Background(), drainTarget()))
+
+			require.NotEmpty(t, evictGrace)
+			for _, g := range evictGrace {
+				require.Equal(t, tt.wantEvict, g, "eviction grace")
+			}
+			if !tt.blocked {
+				require.Empty(t, deleteGrace, "a successful drain deletes nothing")
+				return
+			}
+			require.Equal(t, []*int64{tt.wantDelete}, deleteGrace, "force-delete grace")
+		})
+	}
+}
+
+func ptr(v int64) *int64 { return &v }
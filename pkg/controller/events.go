@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const eventComponent = "cluster-bare-autoscaler"
+
+// eventNamespace is where fleet-level events are recorded: the autoscaler's own namespace
+// (POD_NAMESPACE via the downward API), or "default".
+func eventNamespace() string {
+	if ns := os.Getenv("POD_NAMESPACE"); ns != "" {
+		return ns
+	}
+	return "default"
+}
+
+// recordFleetEvent emits a Kubernetes Event that concerns the managed fleet as a whole rather than
+// a single node. Failures are logged, never fatal.
+func (r *Reconciler) recordFleetEvent(ctx context.Context, eventType, reason, message string) {
+	ns := eventNamespace()
+	r.recordEvent(ctx, v1.ObjectReference{APIVersion: "v1", Kind: "Namespace", Name: ns}, eventType, reason, message)
+}
+
+// recordNodeEvent emits a Kubernetes Event about node, recorded in the autoscaler's namespace.
+func (r *Reconciler) recordNodeEvent(ctx context.Context, node *v1.Node, eventType, reason, message string) {
+	r.recordEvent(ctx, v1.ObjectReference{APIVersion: "v1", Kind: "Node", Name: node.Name, UID: node.UID}, eventType, reason, message)
+}
+
+func (r *Reconciler) recordEvent(ctx context.Context, involved v1.ObjectReference, eventType, reason, message string) {
+	ns := eventNamespace()
+	ts := time.Now()
+	now := metav1.NewTime(ts)
+	event := &v1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			// same naming scheme as client-go's event recorder: <object>.<unique hex>
+			Name:      fmt.Sprintf("%s.%x", involved.Name, ts.UnixNano()),
+			Namespace: ns,
+		},
+		InvolvedObject: involved,
+		Type:           eventType,
+		Reason:         reason,
+		Message:        message,
+		Source:         v1.EventSource{Component: eventComponent},
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+	}
+	if _, err := r.Client.CoreV1().Events(ns).Create(ctx, event, metav1.CreateOptions{}); err != nil {
+		slog.Warn("Failed to record event", "reason", reason, "err", fmt.Errorf("create event: %w", err))
+	}
+}
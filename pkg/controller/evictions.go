@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// podGonePollInterval is the wait between checks for an evicted pod to disappear.
+const podGonePollInterval = 2 * time.Second
+
+// evictBounded evicts pods with at most limit evictions in flight. An eviction holds its slot until
+// the pod is gone from the API (terminated and deleted), so rescheduling happens in bounded
+// waves rather than all at once. The first failure stops new evictions; in-flight ones finish.
+func (r *Reconciler) evictBounded(ctx context.Context, pods []v1.Pod, grace time.Duration, limit int) error {
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	slots := make(chan struct{}, limit)
+	failed := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return firstErr != nil
+	}
+
+	for i := range pods {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return ctx.Err()
+		}
+		if failed() {
+			<-slots
+			break
+		}
+		wg.Add(1)
+		go func(pod *v1.Pod) {
+			defer func() { <-slots; wg.Done() }()
+			err := r.evictPod(ctx, pod, grace)
+			if err == nil && !r.Cfg.IsK8sDryRun() {
+				err = r.waitPodGone(ctx, pod)
+			}
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+			}
+		}(&pods[i])
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		if errors.Is(firstErr, context.DeadlineExceeded) || errors.Is(firstErr, context.Canceled) {
+			return firstErr
+		}
+		return fmt.Errorf("aborting drain due to eviction failure: %w", firstErr)
+	}
+	return nil
+}
+
+// waitPodGone polls until pod no longer exists (or was replaced under the same name).
+func (r *Reconciler) waitPodGone(ctx context.Context, pod *v1.Pod) error {
+	for {
+		cur, err := r.Client.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) || (err == nil && cur.UID != pod.UID) {
+			return nil
+		}
+		if err != nil {
+			slog.Debug("Checking evicted pod failed; retrying", "pod", pod.Name, "err", err)
+		}
+		if err := r.sleep(ctx, podGonePollInterval); err != nil {
+			return err
+		}
+	}
+}
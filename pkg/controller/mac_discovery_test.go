@@ -0,0 +1,53 @@
+package controller_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/agenthttp"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/controller"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestReconcile_MissingMACTriggersTargetedDiscovery(t *testing.T) {
+	origFind, origFetch := nodeops.FindPodIPFunc, nodeops.FetchMACFunc
+	t.Cleanup(func() { nodeops.FindPodIPFunc, nodeops.FetchMACFunc = origFind, origFetch })
+
+	var discovered []string
+	nodeops.FindPodIPFunc = func(_ context.Context, _ kubernetes.Interface, _, _, node string) (string, error) {
+		discovered = append(discovered, node)
+		return "10.0.0.4", nil
+	}
+	nodeops.FetchMACFunc = func(context.Context, *agenthttp.Client, string, int) (nodeops.MACReport, error) {
+		return nodeops.MACReport{Interface: "eno1", MAC: "aa:bb:cc:dd:ee:04"}, nil
+	}
+
+	ctx := context.Background()
+	client := fake.NewSimpleClientset(poweredOffNode("node4"))
+	power := &mockPowerOnController{}
+	r := &controller.Reconciler{
+		Client: client,
+		Cfg: &config.Config{
+			NodeLabels:           config.NodeLabelConfig{Managed: "cba.dev/is-managed"},
+			MACDiscoveryOnDemand: true,
+			PowerOnMode:          "wol",
+		},
+		State:             nodeops.NewNodeStateTracker(),
+		Shutdowner:        &shutdownMock{},
+		PowerOner:         power,
+		ScaleDownStrategy: approveAllStrategy{},
+		ScaleUpStrategy:   &fixedScaleUpStrategy{node: "node4"},
+	}
+
+	require.NoError(t, r.Reconcile(ctx))
+	require.Equal(t, []string{"node4"}, discovered, "missing MAC must trigger an immediate discovery")
+	require.Empty(t, power.PoweredOn, "boot is retried on the next loop")
+
+	require.NoError(t, r.Reconcile(ctx))
+	require.Equal(t, []string{"node4"}, power.PoweredOn)
+	require.Len(t, discovered, 1)
+}
@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/docent-net/cluster-bare-autoscaler/internal/bootstrap/metrics"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
+)
+
+// macVerifiedKey marks a ctx whose candidate already passed macVerifiedForShutdown in scaleDownNode.
+type macVerifiedKey struct{}
+
+// macVerifiedForShutdown re-checks node's WOL MAC against its poweroff daemon before power-off, so a
+// swapped NIC is caught while the node can still report it. It returns false only when
+// macDriftAction is "block" and the annotation drifted. A failed check is logged and does not block:
+// the daemon may simply be unavailable, and the periodic MAC discovery still covers the node.
+func (r *Reconciler) macVerifiedForShutdown(ctx context.Context, node *nodeops.NodeWrapper) bool {
+	if !r.Cfg.MACVerifyBeforeShutdown {
+		return true
+	}
+	check, err := nodeops.VerifyNodeMAC(ctx, r.Client, nodeops.NewMACUpdaterConfig(r.Cfg, r.AgentHTTP), node)
+	if err != nil {
+		slog.Warn("Pre-shutdown MAC verification failed; proceeding", "node", node.Name, "err", err)
+		return true
+	}
+	if !check.Drift {
+		slog.Debug("Pre-shutdown MAC verified", "node", node.Name, "mac", check.Annotated)
+		return true
+	}
+
+	metrics.MACDrift.WithLabelValues(node.Name).Inc()
+	slog.Warn("WOL MAC annotation drifted from node's interface", "node", node.Name,
+		"annotated", check.Annotated, "manual", check.Manual, "current", check.Current, "interface", check.Interface)
+
+	if r.Cfg.MACDriftAction == config.MACDriftBlock {
+		return false
+	}
+	if check.Manual {
+		return true
+	}
+	if err := node.SetDiscoveredMAC(ctx, r.Client, check.Interface, check.Current, r.Cfg.IsK8sDryRun()); err != nil {
+		slog.Warn("Failed to refresh drifted MAC annotation", "node", node.Name, "err", err)
+		return true
+	}
+	slog.Info("Refreshed drifted MAC annotation", "node", node.Name, "mac", check.Current, "interface", check.Interface)
+	return true
+}
@@ -0,0 +1,147 @@
+package controller_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/agenthttp"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/controller"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestReconcile_MACVerifiedBeforeShutdown(t *testing.T) {
+	const annotated, swapped = "aa:bb:cc:dd:ee:01", "aa:bb:cc:dd:ee:02"
+	tests := []struct {
+		name         string
+		annotations  map[string]string
+		driftAction  string
+		wantShutdown bool
+		wantMAC      string // cba.dev/mac-address on the candidate afterwards
+	}{
+		{
+			name:         "matching MAC",
+			annotations:  map[string]string{nodeops.AnnotationMACAuto: swapped},
+			wantShutdown: true,
+			wantMAC:      swapped,
+		},
+		{
+			name:         "case differences are not drift",
+			annotations:  map[string]string{nodeops.AnnotationMACAuto: "AA:BB:CC:DD:EE:02"},
+			wantShutdown: true,
+			wantMAC:      "AA:BB:CC:DD:EE:02",
+		},
+		{
+			name:         "mismatch refreshes annotation",
+			annotations:  map[string]string{nodeops.AnnotationMACAuto: annotated},
+			driftAction:  config.MACDriftRefresh,
+			wantShutdown: true,
+			wantMAC:      swapped,
+		},
+		{
+			name:         "mismatch blocks",
+			annotations:  map[string]string{nodeops.AnnotationMACAuto: annotated},
+			driftAction:  config.MACDriftBlock,
+			wantShutdown: false,
+			wantMAC:      annotated,
+		},
+		{
+			name:         "manual override on a secondary NIC is not drift",
+			annotations:  map[string]string{nodeops.AnnotationMACManual: "aa:bb:cc:dd:ee:03"},
+			driftAction:  config.MACDriftBlock,
+			wantShutdown: true,
+		},
+		{
+			name:         "missing manual override MAC blocks",
+			annotations:  map[string]string{nodeops.AnnotationMACManual: annotated},
+			driftAction:  config.MACDriftBlock,
+			wantShutdown: false,
+		},
+	}
+
+	origFind, origFetch := nodeops.FindPodIPFunc, nodeops.FetchMACFunc
+	t.Cleanup(func() { nodeops.FindPodIPFunc, nodeops.FetchMACFunc = origFind, origFetch })
+	nodeops.FindPodIPFunc = func(context.Context, kubernetes.Interface, string, string, string) (string, error) {
+		return "10.0.0.9", nil
+	}
+	nodeops.FetchMACFunc = func(context.Context, *agenthttp.Client, string, int) (nodeops.MACReport, error) {
+		return nodeops.MACReport{
+			Interface: "eno1", MAC: swapped,
+			Interfaces: []nodeops.NICAddress{{Name: "eno1", MAC: swapped}, {Name: "eno2", MAC: "aa:bb:cc:dd:ee:03"}},
+		}, nil
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			hourAgo := time.Now().Add(-time.Hour)
+			client := fake.NewSimpleClientset(runningNode("a", hourAgo, tt.annotations), runningNode("b", hourAgo, tt.annotations))
+			sim := &bootSimulator{client: client}
+			cfg := &config.Config{
+				NodeLabels:              config.NodeLabelConfig{Managed: "cba.dev/is-managed"},
+				MACVerifyBeforeShutdown: true,
+				MACDriftAction:          tt.driftAction,
+			}
+			require.NoError(t, cfg.ApplyDefaultsAndValidate())
+			r := &controller.Reconciler{
+				Client:            client,
+				Cfg:               cfg,
+				State:             nodeops.NewNodeStateTracker(),
+				Shutdowner:        sim,
+				PowerOner:         sim,
+				ScaleDownStrategy: approveAllStrategy{},
+				ScaleUpStrategy:   &mockScaleUpStrategy{},
+			}
+
+			require.NoError(t, r.Reconcile(ctx))
+			if !tt.wantShutdown {
+				require.Empty(t, sim.ShutDown)
+				for _, name := range []string{"a", "b"} {
+					n, err := client.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+					require.NoError(t, err)
+					if tt.wantMAC != "" {
+						require.Equal(t, tt.wantMAC, n.Annotations[nodeops.AnnotationMACAuto])
+					}
+					require.NotContains(t, n.Annotations, nodeops.AnnotationPoweredOff, "blocked node must not be marked powered off")
+					require.False(t, n.Spec.Unschedulable, "drift is caught before %s is cordoned and drained", name)
+				}
+				return
+			}
+			require.Len(t, sim.ShutDown, 1)
+			if tt.wantMAC != "" {
+				n, err := client.CoreV1().Nodes().Get(ctx, sim.ShutDown[0], metav1.GetOptions{})
+				require.NoError(t, err)
+				require.Equal(t, tt.wantMAC, n.Annotations[nodeops.AnnotationMACAuto])
+			}
+		})
+	}
+}
+
+func TestMaybeResolveStaleCordons_MACDriftUncordons(t *testing.T) {
+	origFind, origFetch := nodeops.FindPodIPFunc, nodeops.FetchMACFunc
+	t.Cleanup(func() { nodeops.FindPodIPFunc, nodeops.FetchMACFunc = origFind, origFetch })
+	nodeops.FindPodIPFunc = func(context.Context, kubernetes.Interface, string, string, string) (string, error) {
+		return "10.0.0.9", nil
+	}
+	nodeops.FetchMACFunc = func(context.Context, *agenthttp.Client, string, int) (nodeops.MACReport, error) {
+		return nodeops.MACReport{Interface: "eno1", MAC: "aa:bb:cc:dd:ee:02"}, nil
+	}
+
+	ctx := context.Background()
+	node := cordonedNode("cordoned", time.Now().Add(-time.Hour))
+	node.Annotations[nodeops.AnnotationMACAuto] = "aa:bb:cc:dd:ee:01"
+	client := fake.NewSimpleClientset(node, runningNode("a", time.Now().Add(-time.Hour), nil))
+	sim := &bootSimulator{client: client}
+	r := newStaleCordonReconciler(client, sim, config.CordonedOnActionPowerOff)
+	r.Cfg.MACVerifyBeforeShutdown = true
+	r.Cfg.MACDriftAction = config.MACDriftBlock
+
+	require.True(t, r.MaybeResolveStaleCordons(ctx))
+	require.Empty(t, sim.ShutDown)
+	requireUncordoned(t, client, "cordoned")
+}
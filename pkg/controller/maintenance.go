@@ -0,0 +1,131 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/strategy"
+)
+
+// maintenanceFloor is the fewest active managed nodes maintenance may leave running;
+// absoluteMinNodes additionally requires more than that many to remain.
+const maintenanceFloor = 1
+
+// MaybeMaintenance drains and powers off one running node annotated cba.dev/maintenance: "true",
+// regardless of load, minNodes, cooldowns and maxPoweredOff. Capacity safety still applies: at
+// least maintenanceFloor active nodes and more than absoluteMinNodes stay up, and a resourceAware
+// strategy in the scale-down chain must agree that the node's pods fit elsewhere. Scale-up,
+// rotation, recycle and force power-on leave maintenance nodes off until the annotation is
+// removed. Returns true if an action was taken.
+func (r *Reconciler) MaybeMaintenance(ctx context.Context) bool {
+	node := r.maintenanceNode(ctx)
+	if node == nil {
+		r.maintenanceBlocked = nil
+		return false
+	}
+
+	ctx, span := r.startSpan(withAction(ctx, ActionMaintenance), "MaybeMaintenance",
+		attrAction.String("maintenance"), attrNode.String(node.Name))
+	defer span.End()
+
+	wrapped := nodeops.NewNodeWrapper(node, r.State, time.Now(), nodeops.NodeAnnotationConfig{
+		MAC: r.Cfg.NodeAnnotations.MAC,
+	}, r.Cfg.IgnoreLabels)
+	if wrapped.IsObserveOnly() {
+		slog.Info("Observe-only: node requested maintenance but is not acted upon", "node", node.Name)
+		setReason(ctx, "observe-only")
+		return false
+	}
+
+	if reason := r.maintenanceBlockReason(ctx, node.Name); reason != "" {
+		slog.Info("Maintenance power-off blocked", "node", node.Name, "reason", reason)
+		setReason(ctx, "maintenance blocked: "+reason)
+		if r.maintenanceBlocked[node.Name] != reason {
+			r.maintenanceBlocked = map[string]string{node.Name: reason}
+			r.recordNodeEvent(ctx, node, v1.EventTypeWarning, "MaintenanceBlocked",
+				fmt.Sprintf("Maintenance power-off of %s blocked: %s", node.Name, reason))
+		}
+		return false
+	}
+	r.maintenanceBlocked = nil
+
+	var msg string
+	switch k8s, pwr := r.Cfg.IsK8sDryRun(), r.Cfg.IsPowerDryRun(); {
+	case k8s && pwr:
+		msg = fmt.Sprintf("Dry-run: would drain and power off %s for maintenance", node.Name)
+	case k8s:
+		msg = fmt.Sprintf("Dry-run: would drain %s; powering it off for maintenance", node.Name)
+	case pwr:
+		msg = fmt.Sprintf("Draining %s for maintenance; dry-run: would power it off", node.Name)
+	default:
+		msg = fmt.Sprintf("Draining and powering off %s for maintenance", node.Name)
+	}
+	slog.Info("Maintenance requested — draining and powering off node", "node", node.Name,
+		"dryRunK8s", r.Cfg.IsK8sDryRun(), "dryRunPower", r.Cfg.IsPowerDryRun())
+	r.recordNodeEvent(ctx, node, v1.EventTypeNormal, "MaintenancePowerOff", msg)
+
+	if !r.scaleDownNode(ctx, wrapped) {
+		r.recordNodeEvent(ctx, node, v1.EventTypeWarning, "MaintenancePowerOffFailed",
+			fmt.Sprintf("Maintenance power-off of %s did not complete; retrying next loop", node.Name))
+		return false
+	}
+	return true
+}
+
+// maintenanceNode returns the running node MaybeMaintenance would take down next, or nil.
+func (r *Reconciler) maintenanceNode(ctx context.Context) *v1.Node {
+	allNodes, err := r.listAllNodes(ctx)
+	if err != nil {
+		return nil
+	}
+	for i := range allNodes.Items {
+		n := &allNodes.Items[i]
+		if nodeops.IsInMaintenance(n) && !r.isPoweredOff(*n) {
+			return n
+		}
+	}
+	return nil
+}
+
+// maintenanceBlockReason returns why nodeName cannot be taken down for maintenance right now,
+// or "" when it can.
+func (r *Reconciler) maintenanceBlockReason(ctx context.Context, nodeName string) string {
+	active, err := r.listActiveNodes(ctx)
+	if err != nil {
+		return "listing active nodes failed"
+	}
+	remaining := len(active)
+	if slices.ContainsFunc(active, func(n v1.Node) bool { return n.Name == nodeName }) {
+		remaining--
+	}
+	if remaining < maintenanceFloor {
+		return fmt.Sprintf("would leave fewer than %d active node(s)", maintenanceFloor)
+	}
+	if floor := r.Cfg.AbsoluteMinNodes; floor > 0 && remaining <= floor {
+		return fmt.Sprintf("would leave %d active node(s), at or below absoluteMinNodes %d", remaining, floor)
+	}
+
+	multi, ok := r.ScaleDownStrategy.(*strategy.MultiStrategy)
+	if !ok {
+		return ""
+	}
+	for _, s := range multi.Strategies {
+		if _, capacity := s.(*strategy.ResourceAwareScaleDown); !capacity {
+			continue
+		}
+		fits, err := s.ShouldScaleDown(ctx, nodeName)
+		if err != nil {
+			return "capacity check failed: " + err.Error()
+		}
+		if !fits {
+			return "remaining nodes lack capacity for its pods"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,134 @@
+package controller_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/controller"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newMaintenanceReconciler(client *fake.Clientset, sim *bootSimulator) *controller.Reconciler {
+	return newSimReconciler(client, sim, &config.Config{
+		MinNodes: 5, // maintenance ignores minNodes
+	})
+}
+
+func eventReasons(t *testing.T, client *fake.Clientset) []string {
+	t.Helper()
+	events, err := client.CoreV1().Events("").List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err)
+	var reasons []string
+	for _, e := range events.Items {
+		reasons = append(reasons, e.Reason)
+	}
+	return reasons
+}
+
+func TestMaybeMaintenance_PowersOffAnnotatedNode(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	client := fake.NewSimpleClientset(
+		runningNode("busy", now.Add(-time.Hour), nil),
+		runningNode("service-me", now.Add(-time.Hour), map[string]string{nodeops.AnnotationMaintenance: "true"}),
+	)
+	sim := &bootSimulator{client: client}
+	r := newMaintenanceReconciler(client, sim)
+
+	require.True(t, r.MaybeMaintenance(ctx))
+	require.Equal(t, []string{"service-me"}, sim.ShutDown)
+	require.Contains(t, eventReasons(t, client), "MaintenancePowerOff")
+
+	// Once powered off the node is left alone and is not offered for scale-up.
+	require.False(t, r.MaybeMaintenance(ctx))
+	require.Len(t, sim.ShutDown, 1)
+	require.Empty(t, r.ScaleUpCandidates(ctx))
+}
+
+func TestMaybeMaintenance_EventReflectsSplitDryRun(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	client := fake.NewSimpleClientset(
+		runningNode("busy", now.Add(-time.Hour), nil),
+		runningNode("service-me", now.Add(-time.Hour), map[string]string{nodeops.AnnotationMaintenance: "true"}),
+	)
+	sim := &bootSimulator{client: client}
+	r := newMaintenanceReconciler(client, sim)
+	r.Cfg.DryRunPower = true
+
+	require.True(t, r.MaybeMaintenance(ctx))
+	require.Empty(t, sim.ShutDown, "dryRunPower skips the physical power-off")
+	events, err := client.CoreV1().Events("").List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	var msgs []string
+	for _, e := range events.Items {
+		if e.Reason == "MaintenancePowerOff" {
+			msgs = append(msgs, e.Message)
+		}
+	}
+	require.Equal(t, []string{"Draining service-me for maintenance; dry-run: would power it off"}, msgs)
+}
+
+// failingShutdown fails every shutdown, like an unreachable BMC.
+type failingShutdown struct{}
+
+func (failingShutdown) Shutdown(context.Context, string) error {
+	return errors.New("bmc unreachable")
+}
+
+func TestMaybeMaintenance_ReportsFailedPowerOff(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	client := fake.NewSimpleClientset(
+		runningNode("busy", now.Add(-time.Hour), nil),
+		runningNode("service-me", now.Add(-time.Hour), map[string]string{nodeops.AnnotationMaintenance: "true"}),
+	)
+	r := newMaintenanceReconciler(client, &bootSimulator{client: client})
+	r.Shutdowner = failingShutdown{}
+
+	require.False(t, r.MaybeMaintenance(ctx))
+	require.Contains(t, eventReasons(t, client), "MaintenancePowerOffFailed")
+}
+
+func TestMaybeMaintenance_KeepsHardFloor(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset(
+		runningNode("only", time.Now().Add(-time.Hour), map[string]string{nodeops.AnnotationMaintenance: "true"}),
+	)
+	sim := &bootSimulator{client: client}
+	r := newMaintenanceReconciler(client, sim)
+
+	require.False(t, r.MaybeMaintenance(ctx))
+	require.False(t, r.MaybeMaintenance(ctx))
+	require.Empty(t, sim.ShutDown)
+	require.Equal(t, []string{"MaintenanceBlocked"}, eventReasons(t, client), "blocked event is recorded once per reason")
+}
+
+func TestScaleUpCandidates_SkipsMaintenanceNodes(t *testing.T) {
+	ctx := context.Background()
+	off := func(name string, annotations map[string]string) *v1.Node {
+		n := &v1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Labels:      map[string]string{"cba.dev/is-managed": "true"},
+			Annotations: map[string]string{nodeops.AnnotationPoweredOff: time.Now().UTC().Format(time.RFC3339)},
+		}}
+		for k, v := range annotations {
+			n.Annotations[k] = v
+		}
+		return n
+	}
+	client := fake.NewSimpleClientset(
+		off("spare", nil),
+		off("in-service", map[string]string{nodeops.AnnotationMaintenance: "true"}),
+	)
+	r := newMaintenanceReconciler(client, &bootSimulator{client: client})
+
+	require.Equal(t, []string{"spare"}, r.ScaleUpCandidates(ctx))
+}
@@ -0,0 +1,70 @@
+package controller_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/controller"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/power"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestReconcile_NilPowerControllers(t *testing.T) {
+	ctx := context.Background()
+	hourAgo := time.Now().Add(-time.Hour)
+
+	t.Run("scale-up without PowerOner", func(t *testing.T) {
+		off := poweredOffNode("off")
+		off.Annotations[nodeops.AnnotationMACAuto] = "aa:bb:cc:dd:ee:ff"
+		client := fake.NewSimpleClientset(runningNode("a", hourAgo, nil), off)
+		r := &controller.Reconciler{
+			Client:            client,
+			Cfg:               &config.Config{NodeLabels: config.NodeLabelConfig{Managed: "cba.dev/is-managed"}},
+			State:             nodeops.NewNodeStateTracker(),
+			ScaleDownStrategy: &MockScaleDownStrategy{},
+			ScaleUpStrategy:   &fixedScaleUpStrategy{node: "off"},
+		}
+
+		require.NotPanics(t, func() { require.NoError(t, r.Reconcile(ctx)) })
+		n, err := client.CoreV1().Nodes().Get(ctx, "off", metav1.GetOptions{})
+		require.NoError(t, err)
+		require.Contains(t, n.Annotations, nodeops.AnnotationPoweredOff, "node must stay marked powered off")
+	})
+
+	t.Run("scale-down without Shutdowner", func(t *testing.T) {
+		client := fake.NewSimpleClientset(runningNode("a", hourAgo, nil), runningNode("b", hourAgo, nil))
+		r := &controller.Reconciler{
+			Client:            client,
+			Cfg:               &config.Config{NodeLabels: config.NodeLabelConfig{Managed: "cba.dev/is-managed"}},
+			State:             nodeops.NewNodeStateTracker(),
+			ScaleDownStrategy: approveAllStrategy{},
+			ScaleUpStrategy:   &mockScaleUpStrategy{},
+		}
+
+		require.NotPanics(t, func() { require.NoError(t, r.Reconcile(ctx)) })
+		for _, name := range []string{"a", "b"} {
+			n, err := client.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+			require.NoError(t, err)
+			require.NotContains(t, n.Annotations, nodeops.AnnotationPoweredOff, "failed shutdown must not leave the node marked powered off")
+		}
+	})
+
+	t.Run("power on helper reports missing controller", func(t *testing.T) {
+		off := poweredOffNode("off")
+		off.Annotations[nodeops.AnnotationMACAuto] = "aa:bb:cc:dd:ee:ff"
+		node := nodeops.NewNodeWrapper(off, nil, time.Now(), nodeops.NodeAnnotationConfig{}, nil)
+		err := nodeops.PowerOnAndMarkBooted(ctx, node, &config.Config{}, fake.NewSimpleClientset(off), nil, nil, false)
+		require.ErrorIs(t, err, power.ErrNotConfigured)
+	})
+
+	t.Run("constructor defaults to noop controllers", func(t *testing.T) {
+		r := controller.NewReconciler(&config.Config{ShutdownMode: "bogus", PowerOnMode: "bogus"}, fake.NewSimpleClientset(), nil)
+		require.NotNil(t, r.Shutdowner)
+		require.NotNil(t, r.PowerOner)
+	})
+}
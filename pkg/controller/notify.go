@@ -0,0 +1,31 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/notifier"
+)
+
+// notify sends a power-action notification in the background. Delivery never blocks or fails the
+// loop; errors are only logged.
+func (r *Reconciler) notify(ctx context.Context, action, node string, dryRun bool) {
+	n := r.Notifier
+	if n == nil {
+		return
+	}
+	ev := notifier.Event{
+		Action:    action,
+		Node:      node,
+		Reason:    actionFrom(ctx),
+		Timestamp: time.Now().UTC(),
+		DryRun:    dryRun,
+	}
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		if err := n.Notify(ctx, ev); err != nil {
+			slog.Warn("Power-action notification failed", "node", node, "action", action, "err", err)
+		}
+	}()
+}
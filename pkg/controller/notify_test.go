@@ -0,0 +1,59 @@
+package controller_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/controller"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/notifier"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// chanNotifier hands every event to the test and then fails, which must not affect the loop.
+type chanNotifier chan notifier.Event
+
+func (c chanNotifier) Notify(_ context.Context, ev notifier.Event) error {
+	c <- ev
+	return errors.New("webhook down")
+}
+
+func TestMaybeScaleDown_NotifiesPowerOff(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset(
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "keep"}},
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "idle"}},
+	)
+	state := nodeops.NewNodeStateTracker()
+	events := make(chanNotifier, 1)
+	r := &controller.Reconciler{
+		Client:            client,
+		Cfg:               &config.Config{DryRunPower: true},
+		State:             state,
+		Shutdowner:        &shutdownMock{},
+		ScaleDownStrategy: &alwaysAllowStrategy{candidate: "idle"},
+		Notifier:          events,
+	}
+
+	eligible := nodeops.WrapNodes([]v1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "keep"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "idle"}},
+	}, state, time.Now(), nodeops.NodeAnnotationConfig{}, nil)
+	require.True(t, r.MaybeScaleDown(ctx, eligible), "a failing notifier must not fail the scale-down")
+
+	select {
+	case ev := <-events:
+		require.Equal(t, notifier.ActionPowerOff, ev.Action)
+		require.Equal(t, "idle", ev.Node)
+		require.Equal(t, controller.ActionScaleDown, ev.Reason)
+		require.True(t, ev.DryRun)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no notification sent")
+	}
+}
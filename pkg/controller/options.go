@@ -1,5 +1,12 @@
 package controller
 
+import (
+	"k8s.io/client-go/dynamic"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/power"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/strategy"
+)
+
 func WithDryRunNodeLoad(val float64) ReconcilerOption {
 	return func(r *Reconciler) {
 		r.DryRunNodeLoad = &val
@@ -17,3 +24,34 @@ func WithDryRunClusterLoadUp(val float64) ReconcilerOption {
 		r.DryRunClusterLoadUp = &val
 	}
 }
+
+func WithPowerDrawProbe(p power.PowerDrawProbe) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.PowerDraw = p
+	}
+}
+
+func WithSleeper(s Sleeper) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.Sleep = s
+	}
+}
+
+func WithDynamicClient(d dynamic.Interface) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.Dynamic = d
+	}
+}
+
+func WithLoadSource(s strategy.NodeLoadSource) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.LoadSource = s
+	}
+}
+
+// WithoutLoadCache makes every load lookup hit the metrics agent, even within one loop.
+func WithoutLoadCache() ReconcilerOption {
+	return func(r *Reconciler) {
+		r.LoadCache = nil
+	}
+}
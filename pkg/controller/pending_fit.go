@@ -0,0 +1,66 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/strategy"
+)
+
+// unschedulablePods lists pods the scheduler has marked Unschedulable.
+func (r *Reconciler) unschedulablePods(ctx context.Context) ([]v1.Pod, error) {
+	pods, err := r.Client.CoreV1().Pods("").List(ctx, metav1.ListOptions{FieldSelector: "status.phase=Pending"})
+	if err != nil {
+		return nil, err
+	}
+	var out []v1.Pod
+	for _, p := range pods.Items {
+		if p.Status.Phase != v1.PodPending || p.Spec.NodeName != "" {
+			continue
+		}
+		for _, c := range p.Status.Conditions {
+			if c.Type == v1.PodScheduled && c.Status == v1.ConditionFalse && c.Reason == v1.PodReasonUnschedulable {
+				out = append(out, p)
+				break
+			}
+		}
+	}
+	return out, nil
+}
+
+// sortByPendingFit stably moves the powered-off nodes that can host the most unschedulable pods to
+// the front of names. A node's capacity comes from nodeCapacityByType or its last known allocatable
+// (see strategy.NodeCapacity), so booting a node too small for the waiting pods is avoided. Without
+// unschedulable pods the order is left as is.
+func (r *Reconciler) sortByPendingFit(ctx context.Context, names []string, nodes []v1.Node) {
+	pending, err := r.unschedulablePods(ctx)
+	if err != nil {
+		slog.Warn("Listing unschedulable pods failed; keeping scale-up order", "err", err)
+		return
+	}
+	if len(pending) == 0 {
+		return
+	}
+	byName := make(map[string]v1.Node, len(nodes))
+	for _, n := range nodes {
+		byName[n.Name] = n
+	}
+	fits := make(map[string]int, len(names))
+	for _, name := range names {
+		node, ok := byName[name]
+		if !ok {
+			continue
+		}
+		for _, p := range pending {
+			if strategy.FitsOnNode(node, r.Cfg, strategy.PodRequests(p)) {
+				fits[name]++
+			}
+		}
+	}
+	sort.SliceStable(names, func(i, j int) bool { return fits[names[i]] > fits[names[j]] })
+	slog.Debug("Scale-up candidates ordered by unschedulable pod fit", "pending", len(pending), "candidates", names)
+}
@@ -0,0 +1,278 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
+)
+
+// Plan is what one reconcile loop would do against the current cluster, printed by -plan.
+type Plan struct {
+	GeneratedAt  time.Time    `json:"generatedAt"`
+	MinNodes     int          `json:"minNodes"`
+	DesiredNodes *int         `json:"desiredNodes,omitempty"`
+	StaleCordon  PlanDecision `json:"staleCordon"`
+	Maintenance  PlanDecision `json:"maintenance"`
+	ScaleUp      PlanDecision `json:"scaleUp"`
+	ScaleDown    PlanDecision `json:"scaleDown"`
+	Recycle      PlanDecision `json:"recycle"`
+	Rotation     PlanDecision `json:"rotation"`
+}
+
+// PlanDecision is the outcome of one step. Node may be set without Act, e.g. a scale-down
+// candidate the strategies denied or a rotation target deferred by an earlier action.
+type PlanDecision struct {
+	Act    bool   `json:"act"`
+	Node   string `json:"node,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// decide sets the decision to act for reason, unless block names why it cannot.
+func (d *PlanDecision) decide(block, reason string) {
+	if block != "" {
+		d.Reason = block
+		return
+	}
+	d.Act, d.Reason = true, reason
+}
+
+// skipRest gives every step not evaluated yet the reason the loop would stop before reaching it.
+func (p *Plan) skipRest(reason string) *Plan {
+	for _, d := range []*PlanDecision{&p.StaleCordon, &p.Maintenance, &p.ScaleUp, &p.ScaleDown, &p.Recycle, &p.Rotation} {
+		if d.Reason == "" {
+			d.Reason = reason
+		}
+	}
+	return p
+}
+
+// Plan evaluates one loop the way Reconcile would, in the same order and through the same gates,
+// strategy chains and candidate selection, but never cordons, drains, powers or annotates nodes.
+// Checks that call out or take a lock (MAC verification, approval webhook, disruption lease) are
+// not evaluated.
+func (r *Reconciler) Plan(ctx context.Context) (*Plan, error) {
+	r.loopMu.Lock()
+	defer r.loopMu.Unlock()
+	ctx = beginLoop(ctx)
+	r.LoadCache.Reset()
+	now := time.Now()
+
+	r.RefreshCustomResourceSpec(ctx)
+	r.RefreshMinNodes(ctx)
+	p := &Plan{GeneratedAt: now, MinNodes: r.MinNodes()}
+
+	if r.Cfg.ForcePowerOnAllNodes {
+		return p.skipRest("forcePowerOnAllNodes set; every powered-off node would be powered on"), nil
+	}
+	if reason := r.loopBlockReason(now); reason != "" {
+		return p.skipRest(reason), nil
+	}
+
+	if r.planStaleCordon(ctx, p, now) {
+		return p.skipRest("deferred: stale cordon planned"), nil
+	}
+	if !r.agentsHealthy(ctx) {
+		return p.skipRest("agents unhealthy"), nil
+	}
+	if r.planMaintenance(ctx, p) {
+		return p.skipRest("deferred: maintenance planned"), nil
+	}
+	if desired, ok := r.resolveDesiredNodeCount(ctx); ok {
+		p.DesiredNodes = &desired
+		r.planConverge(ctx, p, desired)
+		return p.skipRest("desired node count set"), nil
+	}
+
+	if !r.scaleUpScheduled(ctx, now) {
+		p.ScaleUp.Reason = "outside schedule"
+	} else if err := r.planScaleUp(ctx, p); err != nil {
+		return nil, err
+	}
+	if p.ScaleUp.Act {
+		return r.planDeferred(ctx, p, now, "deferred: scale-up planned")
+	}
+	if r.upgradeInProgress(ctx) {
+		return p.skipRest("upgrade in progress"), nil
+	}
+
+	allNodes, err := r.listAllNodes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing nodes: %w", err)
+	}
+	eligible := r.filterEligibleNodes(allNodes.Items)
+	if !r.scaleDownScheduled(ctx, now) {
+		p.ScaleDown.Reason = "outside schedule"
+	} else if err := r.planScaleDown(ctx, p, eligible); err != nil {
+		return nil, err
+	}
+	if p.ScaleDown.Act {
+		return r.planDeferred(ctx, p, now, "deferred: scale-down planned")
+	}
+
+	if err := r.planRecycle(ctx, p, eligible, now); err != nil {
+		return nil, err
+	}
+	if p.Recycle.Act {
+		return r.planDeferred(ctx, p, now, "deferred: recycle planned")
+	}
+	if err := r.planRotation(ctx, p, now); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// planDeferred names the rotation target an earlier action defers, then skips the remaining steps.
+func (r *Reconciler) planDeferred(ctx context.Context, p *Plan, now time.Time, reason string) (*Plan, error) {
+	if err := r.planRotation(ctx, p, now); err != nil {
+		return nil, err
+	}
+	if p.Rotation.Act {
+		p.Rotation.Act, p.Rotation.Reason = false, reason
+	}
+	return p.skipRest(reason), nil
+}
+
+// planStaleCordon reports whether MaybeResolveStaleCordons would act, which ends the loop.
+func (r *Reconciler) planStaleCordon(ctx context.Context, p *Plan, now time.Time) bool {
+	stale := r.staleCordons(ctx, now)
+	if len(stale) == 0 {
+		p.StaleCordon.Reason = "no stale cordon"
+		return false
+	}
+	p.StaleCordon = PlanDecision{Act: true, Node: stale[0].node.Name, Reason: fmt.Sprintf(
+		"cordoned for %s, past maxCordonedOnDuration", stale[0].cordonedFor.Round(time.Minute))}
+	return true
+}
+
+// planMaintenance reports whether MaybeMaintenance would power off a node, which ends the loop.
+func (r *Reconciler) planMaintenance(ctx context.Context, p *Plan) bool {
+	d := &p.Maintenance
+	node := r.maintenanceNode(ctx)
+	if node == nil {
+		d.Reason = "no node requested"
+		return false
+	}
+	d.Node = node.Name
+
+	ctx = withAction(ctx, ActionMaintenance)
+	wrapped := nodeops.NewNodeWrapper(node, r.State, time.Now(), nodeops.NodeAnnotationConfig{
+		MAC: r.Cfg.NodeAnnotations.MAC,
+	}, r.Cfg.IgnoreLabels)
+	block := r.maintenanceBlockReason(ctx, node.Name)
+	if block == "" {
+		block = r.powerOffBlockReason(ctx, wrapped)
+	}
+	if block != "" {
+		block = "blocked: " + block
+	}
+	d.decide(block, "maintenance requested")
+	return d.Act
+}
+
+func (r *Reconciler) planConverge(ctx context.Context, p *Plan, desired int) {
+	step := r.nextConvergeStep(ctx, desired)
+	switch {
+	case step.up != "":
+		p.ScaleUp = PlanDecision{Act: true, Node: step.up,
+			Reason: fmt.Sprintf("%d active, below desired node count", step.active)}
+	case step.down != nil:
+		p.ScaleDown.Node = step.down.Name
+		p.ScaleDown.decide(r.powerOffBlockReason(withAction(ctx, ActionScaleDown), step.down),
+			fmt.Sprintf("%d active, above desired node count", step.active))
+	default:
+		p.ScaleUp.Reason, p.ScaleDown.Reason = step.reason, step.reason
+	}
+}
+
+func (r *Reconciler) planScaleUp(ctx context.Context, p *Plan) error {
+	node, ok, err := r.ScaleUpStrategy.ShouldScaleUp(ctx)
+	switch {
+	case err != nil:
+		return fmt.Errorf("scale-up strategy: %w", err)
+	case ok:
+		p.ScaleUp = PlanDecision{Act: true, Node: node, Reason: "strategies approved"}
+	default:
+		p.ScaleUp.Reason = "all strategies denied"
+	}
+	return nil
+}
+
+func (r *Reconciler) planScaleDown(ctx context.Context, p *Plan, eligible []*nodeops.NodeWrapper) error {
+	d := &p.ScaleDown
+	ctx = withAction(ctx, ActionScaleDown)
+	if reason := r.scaleDownPhaseBlockReason(ctx); reason != "" {
+		d.Reason = reason
+		return nil
+	}
+	candidate := r.PickScaleDownCandidate(eligible)
+	if candidate == nil {
+		d.Reason = "no candidate"
+		return nil
+	}
+	d.Node = candidate.Name
+
+	ok, err := r.ScaleDownStrategy.ShouldScaleDown(ctx, candidate.Name)
+	switch {
+	case err != nil:
+		return fmt.Errorf("scale-down strategy: %w", err)
+	case !ok:
+		d.Reason = "strategy denied"
+	default:
+		d.decide(r.powerOffBlockReason(ctx, candidate), "strategies approved")
+	}
+	return nil
+}
+
+func (r *Reconciler) planRecycle(ctx context.Context, p *Plan, eligible []*nodeops.NodeWrapper, now time.Time) error {
+	d := &p.Recycle
+	if r.Cfg.Recycle.MaxOnDuration <= 0 {
+		d.Reason = "recycle disabled"
+		return nil
+	}
+	ctx = withAction(ctx, ActionRecycle)
+	overdue, _, pending := r.recycleTarget(eligible, now)
+	if overdue == nil {
+		d.Reason = "no overdue node"
+		return nil
+	}
+	d.Node = overdue.Name
+
+	switch {
+	case pending && !r.recycleReplacementUp(ctx, overdue):
+		d.Reason = "waiting for replacement"
+	case pending:
+		d.decide(r.powerOffBlockReason(ctx, overdue), "replacement up; retiring")
+	case overdue.IsObserveOnly():
+		d.Reason = "observe-only"
+	case len(r.ScaleUpCandidates(ctx)) > 0:
+		d.Act, d.Reason = true, "overdue; booting a replacement"
+	default:
+		block := r.recycleRetireBlockReason(ctx, eligible, overdue)
+		if block == "" {
+			block = r.powerOffBlockReason(ctx, overdue)
+		}
+		d.decide(block, "overdue; retiring without a replacement")
+	}
+	return nil
+}
+
+func (r *Reconciler) planRotation(ctx context.Context, p *Plan, now time.Time) error {
+	d := &p.Rotation
+	if !r.Cfg.Rotation.Enabled || r.Cfg.Rotation.MaxPoweredOffDuration <= 0 {
+		d.Reason = "rotation disabled"
+		return nil
+	}
+	managed, err := nodeops.ListManagedNodes(ctx, r.Client, nodeops.NewManagedNodeFilter(r.Cfg))
+	if err != nil {
+		return fmt.Errorf("listing managed nodes: %w", err)
+	}
+	tied, _ := r.overdueRotationNodes(managed, now.UTC())
+	if len(tied) == 0 {
+		d.Reason = "no overdue node"
+		return nil
+	}
+	d.Act, d.Node, d.Reason = true, r.breakRotationTie(tied).Name, "overdue"
+	return nil
+}
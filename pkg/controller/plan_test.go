@@ -0,0 +1,203 @@
+package controller_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/controller"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestPlan(t *testing.T) {
+	hourAgo := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name         string
+		scaleUp      *mockScaleUpStrategy
+		wantUp       controller.PlanDecision
+		wantDown     controller.PlanDecision
+		wantRotation controller.PlanDecision
+		inCooldown   bool
+	}{
+		{
+			name:         "scale-down and deferred rotation",
+			scaleUp:      &mockScaleUpStrategy{},
+			wantUp:       controller.PlanDecision{Reason: "all strategies denied"},
+			wantDown:     controller.PlanDecision{Act: true, Node: "a", Reason: "strategies approved"},
+			wantRotation: controller.PlanDecision{Node: "off", Reason: "deferred: scale-down planned"},
+		},
+		{
+			name:         "scale-up takes precedence",
+			scaleUp:      &mockScaleUpStrategy{node: "off", ok: true},
+			wantUp:       controller.PlanDecision{Act: true, Node: "off", Reason: "strategies approved"},
+			wantDown:     controller.PlanDecision{Reason: "deferred: scale-up planned"},
+			wantRotation: controller.PlanDecision{Node: "off", Reason: "deferred: scale-up planned"},
+		},
+		{
+			name:         "global cooldown",
+			scaleUp:      &mockScaleUpStrategy{node: "off", ok: true},
+			inCooldown:   true,
+			wantUp:       controller.PlanDecision{Reason: "global cooldown"},
+			wantDown:     controller.PlanDecision{Reason: "global cooldown"},
+			wantRotation: controller.PlanDecision{Reason: "global cooldown"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			client := fake.NewSimpleClientset(
+				runningNode("a", hourAgo, nil),
+				poweredOffSince(managedNode("off", false), time.Now().Add(-48*time.Hour)),
+			)
+			sim := &bootSimulator{client: client}
+			state := nodeops.NewNodeStateTracker()
+			if tt.inCooldown {
+				state.MarkGlobalShutdown()
+			}
+			r := &controller.Reconciler{
+				Client: client,
+				Cfg: &config.Config{
+					NodeLabels: config.NodeLabelConfig{Managed: "cba.dev/is-managed"},
+					Cooldown:   time.Hour,
+					Rotation:   config.RotationConfig{Enabled: true, MaxPoweredOffDuration: 24 * time.Hour},
+				},
+				State:             state,
+				Shutdowner:        sim,
+				PowerOner:         sim,
+				ScaleDownStrategy: approveAllStrategy{},
+				ScaleUpStrategy:   tt.scaleUp,
+			}
+
+			plan, err := r.Plan(ctx)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantUp, plan.ScaleUp)
+			require.Equal(t, tt.wantDown, plan.ScaleDown)
+			require.Equal(t, tt.wantRotation, plan.Rotation)
+
+			require.Empty(t, sim.ShutDown)
+			require.Empty(t, sim.PoweredOn)
+			a, err := client.CoreV1().Nodes().Get(ctx, "a", metav1.GetOptions{})
+			require.NoError(t, err)
+			require.False(t, a.Spec.Unschedulable, "plan must not cordon")
+		})
+	}
+}
+
+func TestPlan_SharesReconcileGates(t *testing.T) {
+	hourAgo := time.Now().Add(-time.Hour)
+	desiredSource := config.ValueSourceConfig{Type: config.ValueSourceConfigMap, Namespace: "cba", Name: "targets", Key: "desiredNodeCount"}
+	targets := func(desired string) *v1.ConfigMap {
+		return &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "targets", Namespace: "cba"},
+			Data:       map[string]string{"desiredNodeCount": desired},
+		}
+	}
+	inMaintenance := runningNode("a", hourAgo, map[string]string{nodeops.AnnotationMaintenance: "true"})
+	two, zero := 2, 0
+
+	tests := []struct {
+		name        string
+		objects     []runtime.Object
+		cfg         func(*config.Config)
+		wantDesired *int
+		wantStale   controller.PlanDecision
+		wantMaint   controller.PlanDecision
+		wantUp      controller.PlanDecision
+		wantDown    controller.PlanDecision
+	}{
+		{
+			name:        "desired count above active",
+			objects:     []runtime.Object{runningNode("a", hourAgo, nil), targets("2")},
+			cfg:         func(c *config.Config) { c.DesiredNodeCountSource = desiredSource },
+			wantDesired: &two,
+			wantStale:   controller.PlanDecision{Reason: "no stale cordon"},
+			wantMaint:   controller.PlanDecision{Reason: "no node requested"},
+			wantUp:      controller.PlanDecision{Act: true, Node: "off", Reason: "1 active, below desired node count"},
+			wantDown:    controller.PlanDecision{Reason: "desired node count set"},
+		},
+		{
+			name:        "desired count below active",
+			objects:     []runtime.Object{runningNode("a", hourAgo, nil), targets("0")},
+			cfg:         func(c *config.Config) { c.DesiredNodeCountSource = desiredSource },
+			wantDesired: &zero,
+			wantStale:   controller.PlanDecision{Reason: "no stale cordon"},
+			wantMaint:   controller.PlanDecision{Reason: "no node requested"},
+			wantUp:      controller.PlanDecision{Reason: "desired node count set"},
+			wantDown:    controller.PlanDecision{Act: true, Node: "a", Reason: "1 active, above desired node count"},
+		},
+		{
+			name:        "desired count blocked by absoluteMinNodes",
+			objects:     []runtime.Object{runningNode("a", hourAgo, nil), targets("0")},
+			cfg:         func(c *config.Config) { c.DesiredNodeCountSource, c.AbsoluteMinNodes = desiredSource, 1 },
+			wantDesired: &zero,
+			wantStale:   controller.PlanDecision{Reason: "no stale cordon"},
+			wantMaint:   controller.PlanDecision{Reason: "no node requested"},
+			wantUp:      controller.PlanDecision{Reason: "desired node count set"},
+			wantDown:    controller.PlanDecision{Node: "a", Reason: "absoluteMinNodes floor"},
+		},
+		{
+			name:      "stale cordon ends the loop",
+			objects:   []runtime.Object{runningNode("a", hourAgo, nil), cordonedNode("c", time.Now().Add(-2*time.Hour))},
+			cfg:       func(c *config.Config) { c.MaxCordonedOnDuration = time.Hour },
+			wantStale: controller.PlanDecision{Act: true, Node: "c", Reason: "cordoned for 2h0m0s, past maxCordonedOnDuration"},
+			wantMaint: controller.PlanDecision{Reason: "deferred: stale cordon planned"},
+			wantUp:    controller.PlanDecision{Reason: "deferred: stale cordon planned"},
+			wantDown:  controller.PlanDecision{Reason: "deferred: stale cordon planned"},
+		},
+		{
+			name:      "maintenance ends the loop",
+			objects:   []runtime.Object{inMaintenance, runningNode("b", hourAgo, nil)},
+			wantStale: controller.PlanDecision{Reason: "no stale cordon"},
+			wantMaint: controller.PlanDecision{Act: true, Node: "a", Reason: "maintenance requested"},
+			wantUp:    controller.PlanDecision{Reason: "deferred: maintenance planned"},
+			wantDown:  controller.PlanDecision{Reason: "deferred: maintenance planned"},
+		},
+		{
+			name:      "scale-down blocked by absoluteMinNodes",
+			objects:   []runtime.Object{runningNode("a", hourAgo, nil)},
+			cfg:       func(c *config.Config) { c.AbsoluteMinNodes = 1 },
+			wantStale: controller.PlanDecision{Reason: "no stale cordon"},
+			wantMaint: controller.PlanDecision{Reason: "no node requested"},
+			wantUp:    controller.PlanDecision{Reason: "all strategies denied"},
+			wantDown:  controller.PlanDecision{Reason: "absoluteMinNodes floor"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(append(tt.objects, poweredOffNode("off"))...)
+			sim := &bootSimulator{client: client}
+			cfg := &config.Config{NodeLabels: config.NodeLabelConfig{Managed: "cba.dev/is-managed"}}
+			if tt.cfg != nil {
+				tt.cfg(cfg)
+			}
+			r := &controller.Reconciler{
+				Client:            client,
+				Cfg:               cfg,
+				State:             nodeops.NewNodeStateTracker(),
+				Shutdowner:        sim,
+				PowerOner:         sim,
+				ScaleDownStrategy: approveAllStrategy{},
+				ScaleUpStrategy:   &mockScaleUpStrategy{},
+			}
+
+			plan, err := r.Plan(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, tt.wantDesired, plan.DesiredNodes)
+			require.Equal(t, tt.wantStale, plan.StaleCordon)
+			require.Equal(t, tt.wantMaint, plan.Maintenance)
+			require.Equal(t, tt.wantUp, plan.ScaleUp)
+			require.Equal(t, tt.wantDown, plan.ScaleDown)
+			require.Empty(t, sim.ShutDown)
+			require.Empty(t, sim.PoweredOn)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package controller
+
+import (
+	"strings"
+	"time"
+)
+
+// AdaptivePoll decides the wait before the next reconcile loop. Active loops, and the first
+// IdleLoops-1 idle ones after them, wait Base; every further idle loop doubles the wait up to Max.
+// With Max <= Base the interval stays fixed.
+type AdaptivePoll struct {
+	Base      time.Duration
+	Max       time.Duration
+	IdleLoops int
+
+	idle    int
+	current time.Duration
+}
+
+// Next records whether the loop that just finished was active and returns the wait before the next.
+func (p *AdaptivePoll) Next(active bool) time.Duration {
+	if active || p.Max <= p.Base {
+		p.idle, p.current = 0, p.Base
+		return p.Base
+	}
+	p.idle++
+	if p.idle < p.IdleLoops || p.current < p.Base {
+		p.current = p.Base
+		return p.current
+	}
+	p.current = min(2*p.current, p.Max)
+	return p.current
+}
+
+// LoopActive reports whether the last Reconcile took a power action or saw the set of active,
+// eligible or powered-off nodes change; either resets adaptive polling to pollInterval.
+func (r *Reconciler) LoopActive() bool {
+	return r.loopActive
+}
+
+// noteLoopActivity sets loopActive from the status snapshot built at the end of a loop.
+func (r *Reconciler) noteLoopActivity(st *Status) {
+	off := make([]string, 0, len(st.PoweredOffNodes))
+	for _, n := range st.PoweredOffNodes {
+		off = append(off, n.Name)
+	}
+	sig := strings.Join([]string{
+		strings.Join(st.ActiveNodes, ","),
+		strings.Join(st.EligibleCandidates, ","),
+		strings.Join(off, ","),
+	}, "|")
+	acted := r.lastAction != nil && !r.lastAction.at.Before(r.loopStarted)
+	r.loopActive = acted || sig != r.nodeSignature
+	r.nodeSignature = sig
+}
@@ -0,0 +1,55 @@
+package controller_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/controller"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestAdaptivePoll(t *testing.T) {
+	p := &controller.AdaptivePoll{Base: time.Minute, Max: 5 * time.Minute, IdleLoops: 2}
+
+	var got []time.Duration
+	for range 5 {
+		got = append(got, p.Next(false))
+	}
+	require.Equal(t, []time.Duration{
+		time.Minute, 2 * time.Minute, 4 * time.Minute, 5 * time.Minute, 5 * time.Minute,
+	}, got, "grows while idle and is capped at Max")
+
+	require.Equal(t, time.Minute, p.Next(true), "activity resets to Base")
+	require.Equal(t, time.Minute, p.Next(false), "backoff restarts after IdleLoops")
+}
+
+func TestAdaptivePoll_FixedWithoutMax(t *testing.T) {
+	p := &controller.AdaptivePoll{Base: time.Minute, IdleLoops: 1}
+	for range 3 {
+		require.Equal(t, time.Minute, p.Next(false))
+	}
+}
+
+func TestReconcile_LoopActive(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset(runningNode("a", time.Now().Add(-time.Hour), nil))
+	sim := &bootSimulator{client: client}
+	r := &controller.Reconciler{
+		Client:            client,
+		Cfg:               &config.Config{NodeLabels: config.NodeLabelConfig{Managed: "cba.dev/is-managed"}},
+		State:             nodeops.NewNodeStateTracker(),
+		Shutdowner:        sim,
+		PowerOner:         sim,
+		ScaleDownStrategy: &MockScaleDownStrategy{},
+		ScaleUpStrategy:   &mockScaleUpStrategy{},
+	}
+
+	require.NoError(t, r.Reconcile(ctx))
+	require.True(t, r.LoopActive(), "first loop sees a new node set")
+	require.NoError(t, r.Reconcile(ctx))
+	require.False(t, r.LoopActive(), "unchanged cluster with no action is idle")
+}
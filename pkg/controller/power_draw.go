@@ -0,0 +1,68 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/docent-net/cluster-bare-autoscaler/internal/bootstrap/metrics"
+)
+
+// StartPowerDrawPoller runs PollPowerDraw every interval until ctx is done.
+// It is a no-op when no PowerDrawProbe is available or interval is not positive.
+func (r *Reconciler) StartPowerDrawPoller(ctx context.Context, interval time.Duration) {
+	if r.PowerDraw == nil || interval <= 0 {
+		return
+	}
+	go func() {
+		slog.Info("Power-draw poller started", "interval", interval.String())
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			r.PollPowerDraw(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// PollPowerDraw reads the power draw of every active managed node and exports it as
+// cluster_bare_autoscaler_node_power_watts. Series for nodes that are no longer running
+// (or whose probe fails) are removed so dashboards only sum real readings.
+func (r *Reconciler) PollPowerDraw(ctx context.Context) {
+	if r.PowerDraw == nil {
+		return
+	}
+	cfg := r.config() // runs outside loopMu
+	allNodes, err := r.listAllNodesWith(ctx, cfg)
+	if err != nil {
+		return
+	}
+	active, err := r.listActiveNodesWith(ctx, cfg)
+	if err != nil {
+		slog.Warn("Power-draw poll: listing active nodes failed", "err", err)
+		return
+	}
+
+	running := make(map[string]struct{}, len(active))
+	for _, n := range active {
+		running[n.Name] = struct{}{}
+		watts, err := r.PowerDraw.PowerDraw(ctx, n.Name)
+		if err != nil {
+			slog.Debug("Power-draw poll: probe failed", "node", n.Name, "err", err)
+			metrics.NodePowerWatts.DeleteLabelValues(n.Name)
+			continue
+		}
+		metrics.NodePowerWatts.WithLabelValues(n.Name).Set(watts)
+		r.report.recordWatts(n.Name, watts)
+	}
+
+	for _, n := range allNodes.Items {
+		if _, ok := running[n.Name]; !ok {
+			metrics.NodePowerWatts.DeleteLabelValues(n.Name)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package controller_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/docent-net/cluster-bare-autoscaler/internal/bootstrap/metrics"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/controller"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+type stubPowerProbe struct {
+	watts map[string]float64
+}
+
+func (s *stubPowerProbe) PowerDraw(_ context.Context, nodeName string) (float64, error) {
+	w, ok := s.watts[nodeName]
+	if !ok {
+		return 0, errors.New("no reading")
+	}
+	return w, nil
+}
+
+func TestPollPowerDraw_ExportsRunningNodesOnly(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	client := fake.NewSimpleClientset(
+		runningNode("pd-a", now, nil),
+		runningNode("pd-b", now, nil),
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name:   "pd-off",
+			Labels: map[string]string{"cba.dev/is-managed": "true"},
+			Annotations: map[string]string{
+				nodeops.AnnotationPoweredOff: now.UTC().Format(time.RFC3339),
+			},
+		}},
+	)
+
+	// A stale series from before pd-off was shut down must be dropped.
+	metrics.NodePowerWatts.WithLabelValues("pd-off").Set(200)
+
+	probe := &stubPowerProbe{watts: map[string]float64{"pd-a": 142.5, "pd-b": 98, "pd-off": 5}}
+	r := &controller.Reconciler{
+		Client: client,
+		Cfg: &config.Config{
+			NodeLabels: config.NodeLabelConfig{Managed: "cba.dev/is-managed"},
+		},
+		State:     nodeops.NewNodeStateTracker(),
+		PowerDraw: probe,
+	}
+
+	r.PollPowerDraw(ctx)
+
+	require.Equal(t, 142.5, testutil.ToFloat64(metrics.NodePowerWatts.WithLabelValues("pd-a")))
+	require.Equal(t, 98.0, testutil.ToFloat64(metrics.NodePowerWatts.WithLabelValues("pd-b")))
+	require.False(t, metrics.NodePowerWatts.DeleteLabelValues("pd-off"), "powered-off node must not be exported")
+
+	// A failing probe removes the node's series instead of leaving a stale value.
+	delete(probe.watts, "pd-b")
+	r.PollPowerDraw(ctx)
+	require.False(t, metrics.NodePowerWatts.DeleteLabelValues("pd-b"))
+	require.Equal(t, 142.5, testutil.ToFloat64(metrics.NodePowerWatts.WithLabelValues("pd-a")))
+}
@@ -6,9 +6,14 @@ import (
 	"fmt"
 	"github.com/docent-net/cluster-bare-autoscaler/internal/bootstrap/metrics"
 	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/notifier"
+	"io"
 	"k8s.io/client-go/util/retry"
 	metricsclient "k8s.io/metrics/pkg/client/clientset/versioned"
 	"maps"
+	"slices"
+	"sort"
+	"sync"
 
 	policyv1 "k8s.io/api/policy/v1"
 	"log/slog"
@@ -17,14 +22,46 @@ import (
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/agenthttp"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/apis/v1alpha1"
 	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
 	"github.com/docent-net/cluster-bare-autoscaler/pkg/power"
 	"github.com/docent-net/cluster-bare-autoscaler/pkg/strategy"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// ErrBarePods is returned by CordonAndDrain when the node runs a pod without a controller and
+// drainAllowBarePods is off.
+var ErrBarePods = errors.New("node runs a pod without a controller")
+
+// errShutdownUnverified means a shutdown call succeeded but the node stayed Ready for shutdownVerifyTimeout.
+var errShutdownUnverified = errors.New("node did not go NotReady after shutdown")
+
+// errPowerOffBlocked wraps the reasons powerOffDrained refuses to act before touching the node.
+var errPowerOffBlocked = errors.New("power-off blocked")
+
+// errAbsoluteFloor means powering off the node would leave absoluteMinNodes or fewer Ready nodes.
+var errAbsoluteFloor = fmt.Errorf("%w: absoluteMinNodes floor", errPowerOffBlocked)
+
+// errPoweredOffCap means maxPoweredOff managed nodes are already powered off.
+var errPoweredOffCap = fmt.Errorf("%w: maxPoweredOff reached", errPowerOffBlocked)
+
+// errNoAlwaysOnAgent means a placement agent has no Ready pod on an always-on node.
+var errNoAlwaysOnAgent = fmt.Errorf("%w: no agent on an always-on node", errPowerOffBlocked)
+
+// errDisruptionLeaseHeld means another holder has the disruption Lease.
+var errDisruptionLeaseHeld = fmt.Errorf("%w: disruption lease held elsewhere", errPowerOffBlocked)
+
+// errMACDrift means the node's WOL MAC no longer matched its annotation right before power-off.
+var errMACDrift = fmt.Errorf("%w: WOL MAC drift", errPowerOffBlocked)
+
+// shutdownVerifyPollInterval is the wait between readiness checks while verifying a shutdown.
+const shutdownVerifyPollInterval = 5 * time.Second
+
 type Reconciler struct {
 	Cfg                   *config.Config
 	Client                kubernetes.Interface
@@ -34,21 +71,100 @@ type Reconciler struct {
 	Metrics               metrics.Interface
 	ScaleDownStrategy     strategy.ScaleDownStrategy
 	ScaleUpStrategy       strategy.ScaleUpStrategy
-	DryRunNodeLoad        *float64 // optional CLI override
-	DryRunClusterLoadDown *float64 // CLI override for scale-down
-	DryRunClusterLoadUp   *float64 // CLI override for scale-up
+	DryRunNodeLoad        *float64                // optional CLI override
+	DryRunClusterLoadDown *float64                // CLI override for scale-down
+	DryRunClusterLoadUp   *float64                // CLI override for scale-up
+	Sleep                 Sleeper                 // optional; defaults to a context-aware timer
+	PowerDraw             power.PowerDrawProbe    // optional; set when the power backend can read BMC power draw
+	AgentHTTP             *agenthttp.Client       // shared client for metrics/WOL/shutdown agent calls
+	Dynamic               dynamic.Interface       // optional; reads and updates the ClusterBareAutoscaler resource
+	LoadSource            strategy.NodeLoadSource // optional; replaces the metrics DaemonSet (loadSource: synthetic)
+	LoadCache             *strategy.LoadCache     // per-loop /load replies; nil fetches every time
+	Notifier              notifier.Notifier       // optional; told about every node powered on or off
+	AuditLog              io.Writer               // optional; receives one JSON line per power action
+
+	metricsClient      metricsclient.Interface             // kept to rebuild strategies on config reload
+	cfgMu              sync.RWMutex                        // guards Cfg for readers outside loopMu (see config)
+	effectiveMinNodes  *int                                // resolved from minNodesSource; nil means use Cfg.MinNodes
+	crSpec             *v1alpha1.ClusterBareAutoscalerSpec // from the ClusterBareAutoscaler resource; nil when absent
+	agentsUnhealthy    bool                                // agent health gate paused the last loop
+	maintenanceBlocked map[string]string                   // node -> reason its maintenance power-off is blocked (event dedup)
+	lastAction         *lastAction
+	report             reportTally // data for the periodic summary report
+	statePending       bool        // state changes not yet written to the state ConfigMap
+	lastStateWrite     time.Time
+	loopMu             sync.Mutex // serializes Reconcile with admin API power actions
+	loopEligible       []string   // scale-down eligible nodes found by the current loop
+	lastScaleUp        time.Time
+	lastScaleDown      time.Time
+	statusMu           sync.Mutex
+	status             *Status     // published at the end of each loop for StatusHandler
+	loopStarted        time.Time   // start of the current loop
+	loopActive         bool        // the last loop acted or saw the node picture change (adaptive polling)
+	nodeSignature      string      // active/eligible/powered-off nodes seen by the previous loop
+	breakerFailures    []time.Time // consecutive failed power actions, for the circuit breaker
+	breakerOpenUntil   time.Time   // zero while the circuit breaker is closed
 }
 
 type ReconcilerOption func(r *Reconciler)
 
+// Sleeper waits for d or until ctx is done, returning ctx.Err() in the latter case.
+type Sleeper func(ctx context.Context, d time.Duration) error
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+func (r *Reconciler) sleep(ctx context.Context, d time.Duration) error {
+	if r.Sleep != nil {
+		return r.Sleep(ctx, d)
+	}
+	return sleepContext(ctx, d)
+}
+
 func NewReconciler(cfg *config.Config, client kubernetes.Interface, metricsClient metricsclient.Interface, opts ...ReconcilerOption) *Reconciler {
-	shutdowner, powerOner := power.NewControllersFromConfig(cfg, client)
+	agent, err := agenthttp.NewClient(cfg.AgentHTTP, client)
+	if err != nil {
+		slog.Error("Invalid agentHTTP settings; agent calls will be sent without auth", "err", err)
+	}
+	// Load rejects unknown modes, so this only fires for configs that skipped validation.
+	shutdowner, powerOner, err := power.NewControllersFromConfig(cfg, client, agent)
+	if err != nil {
+		slog.Error("Invalid power settings; power actions are disabled", "err", err)
+	}
+	if shutdowner == nil {
+		shutdowner = &power.NoopShutdownController{}
+	}
+	if powerOner == nil {
+		powerOner = &power.NoopPowerOnController{}
+	}
 	r := &Reconciler{
 		Cfg:        cfg,
 		Client:     client,
 		State:      nodeops.NewNodeStateTracker(),
 		Shutdowner: shutdowner,
 		PowerOner:  powerOner,
+		AgentHTTP:  agent,
+		LoadCache:  strategy.NewLoadCache(),
+		Notifier:   notifier.New(cfg.Notifications),
+		AuditLog:   OpenAuditLog(cfg.AuditLog),
+
+		metricsClient: metricsClient,
+	}
+
+	if probe, ok := powerOner.(power.PowerDrawProbe); ok {
+		r.PowerDraw = probe
+	}
+	if cfg.LoadAverageStrategy.LoadSource == config.LoadSourceSynthetic {
+		slog.Warn("Using synthetic load profile instead of the metrics DaemonSet")
+		r.LoadSource = strategy.NewSyntheticLoadSource(cfg.LoadAverageStrategy.SyntheticProfile, nil)
 	}
 
 	// Apply options
@@ -59,53 +175,80 @@ func NewReconciler(cfg *config.Config, client kubernetes.Interface, metricsClien
 	r.ScaleDownStrategy = buildScaleDownStrategy(cfg, client, metricsClient, r)
 	r.ScaleUpStrategy = buildScaleUpStrategy(cfg, r)
 
+	r.LoadPersistedState(context.Background())
 	r.RestorePoweredOffState(context.Background())
 	return r
 }
 
-// buildScaleDownStrategy constructs a composite scale-down strategy based on the current config.
-// It includes the ResourceAwareScaleDown strategy by default to ensure pods can fit elsewhere.
-// If LoadAverageStrategy is enabled, it adds LoadAverageScaleDown, which shuts down nodes
-// based on normalized per-node and cluster-wide load averages.
-// Supports dry-run overrides and evaluates multiple strategies using a MultiStrategy chain.
+// buildScaleDownStrategy constructs the scale-down chain from cfg.ScaleDownChain(), in order:
+//   - resourceAware: ResourceAwareScaleDown, which ensures the candidate's pods fit elsewhere.
+//   - loadAverage: LoadAverageScaleDown, which shuts down nodes based on normalized per-node and
+//     cluster-wide load averages.
+//   - prometheus: PrometheusLoadStrategy, the same thresholds applied to PromQL query results.
+//   - gpuAware: GPUAwareScaleDown, which keeps nodes running pods that request a GPU.
+//
+// Without an explicit scaleDownStrategies list the chain is resourceAware, plus loadAverage,
+// prometheus and gpuAware when enabled. Supports dry-run overrides; all strategies must approve (MultiStrategy).
 func buildScaleDownStrategy(cfg *config.Config, client kubernetes.Interface, metricsClient metricsclient.Interface, r *Reconciler) strategy.ScaleDownStrategy {
 	var strategies []strategy.ScaleDownStrategy
 
-	strategies = append(strategies, &strategy.ResourceAwareScaleDown{
-		Client:        client,
-		MetricsClient: metricsClient,
-		Cfg:           cfg,
-		NodeLister: func(ctx context.Context) ([]v1.Node, error) {
-			list, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
-			if err != nil {
-				return nil, err
-			}
-			return list.Items, nil
-		},
-		PodLister: func(ctx context.Context) ([]v1.Pod, error) {
-			list, err := client.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
-			if err != nil {
-				return nil, err
-			}
-			return list.Items, nil
-		},
-	})
-
-	if cfg.LoadAverageStrategy.Enabled {
-		strategies = append(strategies, &strategy.LoadAverageScaleDown{
-			Client:                    client,
-			Cfg:                       cfg,
-			PodLabel:                  cfg.LoadAverageStrategy.PodLabel,
-			Namespace:                 cfg.LoadAverageStrategy.Namespace,
-			HTTPPort:                  cfg.LoadAverageStrategy.Port,
-			HTTPTimeout:               time.Duration(cfg.LoadAverageStrategy.TimeoutSeconds) * time.Second,
-			NodeThreshold:             cfg.LoadAverageStrategy.NodeThreshold,
-			ClusterWideThreshold:      cfg.LoadAverageStrategy.ScaleDownThreshold,
-			DryRunNodeLoadOverride:    r.DryRunNodeLoad,
-			DryRunClusterLoadOverride: r.DryRunClusterLoadDown,
-			IgnoreLabels:              BuildAggregateExclusions(cfg),
-			ClusterEvalMode:           strategy.ParseClusterEvalMode(cfg.LoadAverageStrategy.ClusterEval),
-		})
+	for _, name := range cfg.ScaleDownChain() {
+		switch name {
+		case config.StrategyResourceAware:
+			strategies = append(strategies, &strategy.ResourceAwareScaleDown{
+				Client:        client,
+				MetricsClient: metricsClient,
+				Cfg:           cfg,
+				NodeLister: func(ctx context.Context) ([]v1.Node, error) {
+					list, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+					if err != nil {
+						return nil, err
+					}
+					return list.Items, nil
+				},
+				PodLister: func(ctx context.Context) ([]v1.Pod, error) {
+					list, err := client.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+					if err != nil {
+						return nil, err
+					}
+					return list.Items, nil
+				},
+			})
+		case config.StrategyLoadAverage:
+			strategies = append(strategies, &strategy.LoadAverageScaleDown{
+				Client:                    client,
+				Cfg:                       cfg,
+				PodLabel:                  cfg.LoadAverageStrategy.PodLabel,
+				Namespace:                 cfg.LoadAverageStrategy.Namespace,
+				HTTPPort:                  cfg.LoadAverageStrategy.Port,
+				HTTPTimeout:               time.Duration(cfg.LoadAverageStrategy.TimeoutSeconds) * time.Second,
+				NodeThreshold:             cfg.LoadAverageStrategy.NodeThreshold,
+				ClusterWideThreshold:      cfg.LoadAverageStrategy.ScaleDownThreshold,
+				DryRunNodeLoadOverride:    r.DryRunNodeLoad,
+				DryRunClusterLoadOverride: r.DryRunClusterLoadDown,
+				IgnoreLabels:              BuildAggregateExclusions(cfg),
+				ClusterEvalMode:           strategy.ParseClusterEvalMode(cfg.LoadAverageStrategy.ClusterEval),
+				UnavailablePolicy:         strategy.ParseLoadUnavailablePolicy(cfg.LoadAverageStrategy.LoadUnavailablePolicy.ScaleDown),
+				AllowLoadOverrides:        cfg.LoadAverageStrategy.AllowLoadOverrides,
+				MinLoadSamples:            cfg.LoadAverageStrategy.MinLoadSamples,
+				LoadNormalization:         strategy.ParseLoadNormalization(cfg.LoadAverageStrategy.LoadNormalization),
+				AggregateDenominator:      strategy.ParseAggregateDenominator(cfg.LoadAverageStrategy.AggregateDenominator),
+				MissingMetricsBehavior:    strategy.ParseMissingMetricsBehavior(cfg.LoadAverageStrategy.MissingMetricsBehavior),
+				AgentHTTP:                 r.AgentHTTP,
+				LoadSource:                r.LoadSource,
+				LoadCache:                 r.LoadCache,
+				LoadFetchConcurrency:      cfg.LoadAverageStrategy.LoadFetchConcurrency,
+			})
+		case config.StrategyPrometheus:
+			strategies = append(strategies, newPrometheusStrategy(cfg, r))
+		case config.StrategyGPUAware:
+			strategies = append(strategies, &strategy.GPUAwareScaleDown{
+				Client:        client,
+				ResourceNames: cfg.GPUAwareStrategy.ResourceNames,
+			})
+		default:
+			slog.Warn("Unknown scale-down strategy ignored", "strategy", name)
+		}
 	}
 
 	names := []string{}
@@ -117,33 +260,54 @@ func buildScaleDownStrategy(cfg *config.Config, client kubernetes.Interface, met
 	return &strategy.MultiStrategy{Strategies: strategies}
 }
 
-// buildScaleUpStrategy constructs a composite scale-up strategy based on the current config.
-// It always includes MinNodeCountScaleUp to maintain the minimum required nodes,
-// and optionally includes LoadAverageScaleUp if enabled, which powers on nodes based on
-// cluster-wide load average. Dry-run overrides for cluster-wide load are respected.
+// buildScaleUpStrategy constructs the scale-up chain from cfg.ScaleUpChain(), in order:
+//   - minNodeCount: MinNodeCountScaleUp, which maintains the minimum required nodes.
+//   - loadAverage: LoadAverageScaleUp, which powers on nodes based on cluster-wide load average.
+//   - prometheus: PrometheusLoadStrategy, which powers on nodes when the cluster query crosses scaleUpThreshold.
+//
+// Without an explicit scaleUpStrategies list the chain is minNodeCount, plus loadAverage and
+// prometheus when enabled. Dry-run overrides for cluster-wide load are respected.
 // The resulting strategy is a MultiUpStrategy that evaluates all sub-strategies in order.
 func buildScaleUpStrategy(cfg *config.Config, r *Reconciler) strategy.ScaleUpStrategy {
-	upStrategies := []strategy.ScaleUpStrategy{
-		&strategy.MinNodeCountScaleUp{
-			Cfg:          r.Cfg,
-			ActiveNodes:  r.listActiveNodes,
-			ShutdownList: r.shutdownNodeNames,
-		},
-	}
-
-	if cfg.LoadAverageStrategy.Enabled {
-		upStrategies = append(upStrategies, &strategy.LoadAverageScaleUp{
-			Client:               r.Client,
-			Namespace:            cfg.LoadAverageStrategy.Namespace,
-			PodLabel:             cfg.LoadAverageStrategy.PodLabel,
-			HTTPPort:             cfg.LoadAverageStrategy.Port,
-			HTTPTimeout:          time.Duration(cfg.LoadAverageStrategy.TimeoutSeconds) * time.Second,
-			ClusterEvalMode:      strategy.ParseClusterEvalMode(cfg.LoadAverageStrategy.ClusterEval),
-			ClusterWideThreshold: cfg.LoadAverageStrategy.ScaleUpThreshold,
-			DryRunOverride:       r.DryRunClusterLoadUp,
-			IgnoreLabels:         BuildAggregateExclusions(cfg),
-			ShutdownCandidates:   r.shutdownNodeNames,
-		})
+	var upStrategies []strategy.ScaleUpStrategy
+
+	for _, name := range cfg.ScaleUpChain() {
+		switch name {
+		case config.StrategyMinNodeCount:
+			upStrategies = append(upStrategies, &strategy.MinNodeCountScaleUp{
+				Cfg:          r.Cfg,
+				MinNodes:     r.MinNodes,
+				ActiveNodes:  r.listActiveNodes,
+				ShutdownList: r.ScaleUpCandidates,
+			})
+		case config.StrategyLoadAverage:
+			upStrategies = append(upStrategies, &strategy.LoadAverageScaleUp{
+				Client:               r.Client,
+				Namespace:            cfg.LoadAverageStrategy.Namespace,
+				PodLabel:             cfg.LoadAverageStrategy.PodLabel,
+				HTTPPort:             cfg.LoadAverageStrategy.Port,
+				HTTPTimeout:          time.Duration(cfg.LoadAverageStrategy.TimeoutSeconds) * time.Second,
+				ClusterEvalMode:      strategy.ParseClusterEvalMode(cfg.LoadAverageStrategy.ClusterEval),
+				ClusterWideThreshold: cfg.LoadAverageStrategy.ScaleUpThreshold,
+				DryRunOverride:       r.DryRunClusterLoadUp,
+				IgnoreLabels:         BuildAggregateExclusions(cfg),
+				ShutdownCandidates:   r.ScaleUpCandidates,
+				UnavailablePolicy:    strategy.ParseLoadUnavailablePolicy(cfg.LoadAverageStrategy.LoadUnavailablePolicy.ScaleUp),
+				AllowLoadOverrides:   cfg.LoadAverageStrategy.AllowLoadOverrides,
+				MinLoadSamples:       cfg.LoadAverageStrategy.MinLoadSamples,
+				LoadNormalization:    strategy.ParseLoadNormalization(cfg.LoadAverageStrategy.LoadNormalization),
+				AggregateDenominator: strategy.ParseAggregateDenominator(cfg.LoadAverageStrategy.AggregateDenominator),
+				MissingMetrics:       strategy.ParseMissingMetricsBehavior(cfg.LoadAverageStrategy.MissingMetricsBehavior),
+				AgentHTTP:            r.AgentHTTP,
+				LoadSource:           r.LoadSource,
+				LoadCache:            r.LoadCache,
+				LoadFetchConcurrency: cfg.LoadAverageStrategy.LoadFetchConcurrency,
+			})
+		case config.StrategyPrometheus:
+			upStrategies = append(upStrategies, newPrometheusStrategy(cfg, r))
+		default:
+			slog.Warn("Unknown scale-up strategy ignored", "strategy", name)
+		}
 	}
 
 	names := []string{}
@@ -155,53 +319,281 @@ func buildScaleUpStrategy(cfg *config.Config, r *Reconciler) strategy.ScaleUpStr
 	return &strategy.MultiUpStrategy{Strategies: upStrategies}
 }
 
+func newPrometheusStrategy(cfg *config.Config, r *Reconciler) *strategy.PrometheusLoadStrategy {
+	p := cfg.PrometheusStrategy
+	return &strategy.PrometheusLoadStrategy{
+		URL:                p.URL,
+		NodeQuery:          p.NodeQuery,
+		ClusterQuery:       p.ClusterQuery,
+		NodeThreshold:      p.NodeThreshold,
+		ScaleDownThreshold: p.ScaleDownThreshold,
+		ScaleUpThreshold:   p.ScaleUpThreshold,
+		Timeout:            time.Duration(p.TimeoutSeconds) * time.Second,
+		ShutdownCandidates: r.ScaleUpCandidates,
+	}
+}
+
 func (r *Reconciler) Reconcile(ctx context.Context) error {
+	r.loopMu.Lock()
+	defer r.loopMu.Unlock()
 	now := time.Now()
-
-	if err := nodeops.RecoverUnexpectedlyBootedNodes(ctx, r.Client, r.Cfg, r.Cfg.DryRun); err != nil {
-		slog.Warn("Failed to recover unexpectedly booted nodes", "err", err)
-		return nil
+	ctx, span := r.startSpan(ctx, "Reconcile")
+	defer span.End()
+	ctx = beginLoop(ctx)
+	r.LoadCache.Reset()
+	defer r.PersistState(ctx)
+	r.loopEligible = nil
+	r.loopStarted, r.loopActive = now, true
+	defer r.publishStatus(ctx)
+
+	// With simulated power-offs every "powered-off" node is still Ready; recovering it would undo
+	// the simulated scale-down on the next loop.
+	if !r.Cfg.IsPowerDryRun() {
+		if err := nodeops.RecoverUnexpectedlyBootedNodes(ctx, r.Client, r.Cfg, r.Cfg.IsK8sDryRun()); err != nil {
+			slog.Warn("Failed to recover unexpectedly booted nodes", "err", err)
+			return nil
+		}
 	}
 
 	if r.Cfg.ForcePowerOnAllNodes {
 		slog.Info("Force power-on of all managed nodes enabled")
-		err := nodeops.ForcePowerOnAllNodes(ctx, r.Client, r.Cfg, r.State, r.PowerOner, r.Cfg.DryRun)
+		ctx := withAction(ctx, ActionForce)
+		if err := r.claimAction(ctx, "*"); err != nil {
+			return nil
+		}
+		results, err := nodeops.ForcePowerOnAllNodes(ctx, r.Client, r.Cfg, r.State, r.PowerOner, r.Cfg.DryRun)
 		if err != nil {
 			slog.Warn("Failed to force power on all nodes", "err", err)
 		}
+		for _, res := range results {
+			// Same exclusions as powerOn: nothing was attempted for these.
+			if errors.Is(res.Err, nodeops.ErrMissingMAC) || errors.Is(res.Err, nodeops.ErrPowerOnSuppressed) {
+				continue
+			}
+			r.audit(ctx, notifier.ActionPowerOn, res.Node, r.nodeStateBefore(res.Node), res.Err)
+			r.recordPowerOutcome(res.Err)
+		}
 
 		return nil
 	}
 
-	if r.State.IsGlobalCooldownActive(now, r.Cfg.Cooldown) {
-		remaining := r.Cfg.Cooldown - now.Sub(r.State.LastShutdownTime)
-		slog.Info("Global cooldown active — skipping reconcile loop", "remaining", remaining.Round(time.Second).String())
+	r.RefreshCustomResourceSpec(ctx)
+	defer r.WriteCustomResourceStatus(ctx)
+
+	r.EvaluatePoweredOffAlert(ctx) // observability only; runs even during cooldown
+	r.ObservePoweredOffDurations(ctx)
+	if r.Cfg.Report.Interval > 0 {
+		r.ObserveReport(ctx, now)
+	}
+	r.PruneNodeState(ctx)
+
+	if reason := r.loopBlockReason(now); reason != "" {
+		setReason(ctx, reason)
 		return nil
 	}
 
 	slog.Info("Running reconcile loop")
 	metrics.Evaluations.Inc()
 
-	if r.MaybeScaleUp(ctx) {
+	r.RefreshMinNodes(ctx)
+
+	if r.MaybeResolveStaleCordons(ctx) {
+		return nil
+	}
+
+	r.agentsUnhealthy = !r.agentsHealthy(ctx)
+	if r.agentsUnhealthy {
+		setReason(ctx, "agents unhealthy")
+		return nil // load and shutdown decisions rely on the agents; recovery above still ran
+	}
+
+	if r.MaybeMaintenance(ctx) {
+		return nil // operator-requested power-off takes precedence over autoscaling
+	}
+
+	if desired, ok := r.resolveDesiredNodeCount(ctx); ok {
+		r.ConvergeToDesired(ctx, desired)
+		return nil // desired count replaces load-based decisions and rotation
+	}
+
+	if r.scaleUpScheduled(ctx, now) && r.MaybeScaleUp(ctx) {
 		return nil // stop here to avoid scaling up in the same loop
 	}
 
+	if r.upgradeInProgress(ctx) {
+		setReason(ctx, "upgrade in progress")
+		return nil // NotReady nodes during an upgrade are not idle capacity
+	}
+
 	allNodes, err := r.listAllNodes(ctx)
 	if err != nil {
 		return err
 	}
 
 	eligible := r.filterEligibleNodes(allNodes.Items)
-	if r.MaybeScaleDown(ctx, eligible) {
+	if r.scaleDownScheduled(ctx, now) && r.MaybeScaleDown(ctx, eligible) {
 		return nil
 	}
 
-	// maintenance: rotate overdue powered-off nodes
+	// maintenance: power-cycle long-running nodes, then rotate overdue powered-off nodes
+	if r.MaybeRecycle(ctx) {
+		return nil
+	}
 	r.MaybeRotate(ctx)
 
 	return nil
 }
 
+// loopBlockReason returns why the loop must not act at all at now (pause, circuit breaker or
+// global cooldown), or "" when it may.
+func (r *Reconciler) loopBlockReason(now time.Time) string {
+	switch {
+	case r.paused():
+		slog.Info("Paused via ClusterBareAutoscaler spec — skipping reconcile loop")
+		return "paused"
+	case r.circuitOpen(now):
+		return "circuit breaker open"
+	case r.State.IsGlobalCooldownActive(now, r.Cfg.Cooldown):
+		remaining := r.Cfg.Cooldown - now.Sub(r.State.LastShutdownTime)
+		slog.Info("Global cooldown active — skipping reconcile loop", "remaining", remaining.Round(time.Second).String())
+		return "global cooldown"
+	}
+	return ""
+}
+
+// MinNodes returns the effective minimum node count: the value resolved from
+// minNodesSource during this loop, then the ClusterBareAutoscaler spec, then the static minNodes from config.
+func (r *Reconciler) MinNodes() int {
+	if r.effectiveMinNodes != nil {
+		return *r.effectiveMinNodes
+	}
+	if r.crSpec != nil && r.crSpec.MinNodes != nil {
+		return *r.crSpec.MinNodes
+	}
+	return r.Cfg.MinNodes
+}
+
+// RefreshMinNodes resolves minNodes from the configured external source.
+// On any error the static config value is used for this loop.
+func (r *Reconciler) RefreshMinNodes(ctx context.Context) {
+	if r.Cfg.MinNodesSource.Type == "" {
+		r.effectiveMinNodes = nil
+		return
+	}
+	val, err := ResolveIntSource(ctx, r.Client, r.Cfg.MinNodesSource)
+	if err != nil {
+		slog.Warn("Failed to resolve minNodes from source — using static value",
+			"source", r.Cfg.MinNodesSource.Type, "minNodes", r.Cfg.MinNodes, "err", err)
+		r.effectiveMinNodes = nil
+		return
+	}
+	if r.effectiveMinNodes == nil || *r.effectiveMinNodes != val {
+		slog.Info("Effective minNodes resolved from source", "source", r.Cfg.MinNodesSource.Type, "minNodes", val)
+	}
+	r.effectiveMinNodes = &val
+}
+
+// resolveDesiredNodeCount resolves desiredNodeCountSource. ok is false when no source is
+// configured or it cannot be resolved, in which case normal strategies apply.
+func (r *Reconciler) resolveDesiredNodeCount(ctx context.Context) (int, bool) {
+	if r.Cfg.DesiredNodeCountSource.Type == "" {
+		return 0, false
+	}
+	val, err := ResolveIntSource(ctx, r.Client, r.Cfg.DesiredNodeCountSource)
+	if err != nil {
+		slog.Warn("Failed to resolve desired node count — falling back to strategies",
+			"source", r.Cfg.DesiredNodeCountSource.Type, "err", err)
+		return 0, false
+	}
+	if minNodes := r.MinNodes(); val < minNodes {
+		slog.Info("Desired node count below minNodes — clamping", "desired", val, "minNodes", minNodes)
+		val = minNodes
+	}
+	return val, true
+}
+
+// ConvergeToDesired moves the number of active nodes one step toward desired:
+// it powers on one node when below, or drains and powers off one eligible node when above.
+// Each direction only runs inside its schedule windows. Returns true if an action was taken.
+func (r *Reconciler) ConvergeToDesired(ctx context.Context, desired int) bool {
+	ctx, span := r.startSpan(ctx, "ConvergeToDesired", attrAction.String("converge"), attribute.Int("cba.desired_nodes", desired))
+	defer span.End()
+
+	step := r.nextConvergeStep(ctx, desired)
+	switch {
+	case step.up != "":
+		slog.Info("Scaling up toward desired node count", "active", step.active, "desired", desired)
+		return r.scaleUpNode(withAction(ctx, ActionScaleUp), step.up)
+	case step.down != nil:
+		slog.Info("Scaling down toward desired node count", "active", step.active, "desired", desired)
+		return r.scaleDownNode(withAction(ctx, ActionScaleDown), step.down)
+	default:
+		setReason(ctx, step.reason)
+		return false
+	}
+}
+
+// convergeStep is the next move toward a desired node count: a node to power on (up) or a
+// candidate to power off (down), or the reason there is none.
+type convergeStep struct {
+	active int
+	up     string
+	down   *nodeops.NodeWrapper
+	reason string
+}
+
+// nextConvergeStep picks the node ConvergeToDesired would act on next without acting on it.
+func (r *Reconciler) nextConvergeStep(ctx context.Context, desired int) convergeStep {
+	active, err := r.listActiveNodes(ctx)
+	if err != nil {
+		slog.Error("Failed to list active nodes for desired-count convergence", "err", err)
+		return convergeStep{reason: "listing nodes failed"}
+	}
+	step := convergeStep{active: len(active)}
+
+	now := time.Now()
+	switch {
+	case len(active) < desired:
+		if !r.scaleUpScheduled(ctx, now) {
+			step.reason = "outside schedule"
+			return step
+		}
+		candidates := r.ScaleUpCandidates(ctx)
+		if len(candidates) == 0 {
+			slog.Info("Below desired node count but no powered-off nodes available", "active", len(active), "desired", desired)
+			step.reason = "no powered-off node"
+			return step
+		}
+		step.up = candidates[0]
+	case len(active) > desired:
+		switch {
+		case !r.scaleDownScheduled(ctx, now):
+			step.reason = "outside schedule"
+			return step
+		case r.scaleDownHeld():
+			step.reason = "post-scale-up hold"
+			return step
+		case r.upgradeInProgress(ctx):
+			step.reason = "upgrade in progress"
+			return step
+		}
+		allNodes, err := r.listAllNodes(ctx)
+		if err != nil {
+			step.reason = "listing nodes failed"
+			return step
+		}
+		step.down = r.PickScaleDownCandidate(r.filterEligibleNodes(allNodes.Items))
+		if step.down == nil {
+			slog.Info("Above desired node count but no eligible nodes to power off", "active", len(active), "desired", desired)
+			step.reason = "no candidate"
+		}
+	default:
+		slog.Info("Active node count matches desired", "desired", desired)
+		step.reason = "at desired node count"
+	}
+	return step
+}
+
 func (r *Reconciler) RestorePoweredOffState(ctx context.Context) {
 	nodeList, err := r.Client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
 	if err != nil {
@@ -215,11 +607,7 @@ func (r *Reconciler) RestorePoweredOffState(ctx context.Context) {
 		active[node.Name] = struct{}{}
 	}
 
-	managed, err := nodeops.ListManagedNodes(ctx, r.Client, nodeops.ManagedNodeFilter{
-		ManagedLabel:  r.Cfg.NodeLabels.Managed,
-		DisabledLabel: r.Cfg.NodeLabels.Disabled,
-		IgnoreLabels:  r.Cfg.IgnoreLabels,
-	})
+	managed, err := nodeops.ListManagedNodes(ctx, r.Client, nodeops.NewManagedNodeFilter(r.Cfg))
 	if err != nil {
 		slog.Warn("Failed to list managed nodes during restore", "err", err)
 		return
@@ -234,20 +622,45 @@ func (r *Reconciler) RestorePoweredOffState(ctx context.Context) {
 
 func (r *Reconciler) filterEligibleNodes(nodes []v1.Node) []*nodeops.NodeWrapper {
 	eligible := nodeops.FilterShutdownEligibleNodes(nodes, r.State, time.Now(), nodeops.EligibilityConfig{
-		Cooldown:     r.Cfg.Cooldown,
-		BootCooldown: r.Cfg.BootCooldown,
-		IgnoreLabels: r.Cfg.IgnoreLabels,
+		Cooldown:                    r.Cfg.Cooldown,
+		BootCooldown:                r.Cfg.BootCooldown,
+		IgnoreLabels:                r.Cfg.IgnoreLabels,
+		ScaleDownDisabledAnnotation: r.Cfg.NodeAnnotations.ScaleDownDisabled,
+		ExcludeTaints:               excludeTaints(r.Cfg.ExcludeTaints),
 	})
 	slog.Info("Filtered nodes", "eligible", len(eligible), "total", len(nodes))
+	r.loopEligible = r.loopEligible[:0]
+	for _, n := range eligible {
+		r.loopEligible = append(r.loopEligible, n.Name)
+	}
+
+	// Reset drops series for nodes that left the managed set since the last loop.
+	metrics.ScaleDownEligible.Reset()
+	for _, n := range nodes {
+		metrics.ScaleDownEligible.WithLabelValues(n.Name).Set(0)
+	}
+	for _, n := range eligible {
+		metrics.ScaleDownEligible.WithLabelValues(n.Name).Set(1)
+	}
 	return eligible
 }
 
+func excludeTaints(matches []config.TaintMatch) []v1.Taint {
+	taints := make([]v1.Taint, 0, len(matches))
+	for _, m := range matches {
+		taints = append(taints, v1.Taint{Key: m.Key, Value: m.Value, Effect: v1.TaintEffect(m.Effect)})
+	}
+	return taints
+}
+
 func (r *Reconciler) listAllNodes(ctx context.Context) (*v1.NodeList, error) {
-	nodes, err := nodeops.ListManagedNodes(ctx, r.Client, nodeops.ManagedNodeFilter{
-		ManagedLabel:  r.Cfg.NodeLabels.Managed,
-		DisabledLabel: r.Cfg.NodeLabels.Disabled,
-		IgnoreLabels:  r.Cfg.IgnoreLabels,
-	})
+	return r.listAllNodesWith(ctx, r.Cfg)
+}
+
+// listAllNodesWith lists managed nodes as cfg defines them. Goroutines outside loopMu pass
+// r.config() so a concurrent ApplyConfig cannot race with them.
+func (r *Reconciler) listAllNodesWith(ctx context.Context, cfg *config.Config) (*v1.NodeList, error) {
+	nodes, err := nodeops.ListManagedNodes(ctx, r.Client, nodeops.NewManagedNodeFilter(cfg))
 	if err != nil {
 		slog.Error("failed to list managed nodes", "err", err)
 		return nil, err
@@ -256,21 +669,18 @@ func (r *Reconciler) listAllNodes(ctx context.Context) (*v1.NodeList, error) {
 }
 
 func (r *Reconciler) listActiveNodes(ctx context.Context) ([]v1.Node, error) {
-	return nodeops.ListActiveNodes(ctx, r.Client, r.State, nodeops.ManagedNodeFilter{
-		ManagedLabel:  r.Cfg.NodeLabels.Managed,
-		DisabledLabel: r.Cfg.NodeLabels.Disabled,
-		IgnoreLabels:  r.Cfg.IgnoreLabels,
-	}, nodeops.ActiveNodeFilter{
-		IgnoreLabels: r.Cfg.IgnoreLabels,
+	return r.listActiveNodesWith(ctx, r.Cfg)
+}
+
+// listActiveNodesWith is listActiveNodes for cfg; see listAllNodesWith.
+func (r *Reconciler) listActiveNodesWith(ctx context.Context, cfg *config.Config) ([]v1.Node, error) {
+	return nodeops.ListActiveNodes(ctx, r.Client, r.State, nodeops.NewManagedNodeFilter(cfg), nodeops.ActiveNodeFilter{
+		IgnoreLabels: cfg.IgnoreLabels,
 	})
 }
 
 func (r *Reconciler) shutdownNodeNames(ctx context.Context) []string {
-	nodes, err := nodeops.ListShutdownNodeNames(ctx, r.Client, nodeops.ManagedNodeFilter{
-		ManagedLabel:  r.Cfg.NodeLabels.Managed,
-		DisabledLabel: r.Cfg.NodeLabels.Disabled,
-		IgnoreLabels:  r.Cfg.IgnoreLabels,
-	}, r.State)
+	nodes, err := nodeops.ListShutdownNodeNames(ctx, r.Client, nodeops.NewManagedNodeFilter(r.Cfg), r.State)
 
 	if err != nil {
 		slog.Warn("Failed to list shutdown nodes", "err", err)
@@ -279,18 +689,72 @@ func (r *Reconciler) shutdownNodeNames(ctx context.Context) []string {
 	return nodes
 }
 
+// ScaleUpCandidates returns powered-off nodes in the order scaleUpPreference and scaleUpPolicy
+// prefer to boot them, with nodes that fit more unschedulable pods ahead of those that fit fewer.
+// Nodes in maintenance are left out.
+func (r *Reconciler) ScaleUpCandidates(ctx context.Context) []string {
+	names := r.shutdownNodeNames(ctx)
+	if len(names) == 0 {
+		return names
+	}
+	nodes, err := r.listAllNodes(ctx)
+	if err != nil {
+		return nil // can't tell which nodes are in maintenance
+	}
+	bootTimes := make(map[string]time.Duration, len(nodes.Items))
+	for _, n := range nodes.Items {
+		if nodeops.IsInMaintenance(&n) {
+			names = slices.DeleteFunc(names, func(name string) bool { return name == n.Name })
+		}
+		if d, ok := nodeops.BootDuration(n); ok {
+			bootTimes[n.Name] = d
+		}
+	}
+	if r.Cfg.ScaleUpPreference == config.ScaleUpPreferenceNewest {
+		slices.Reverse(names)
+	}
+	if len(names) < 2 {
+		return names
+	}
+	if r.Cfg.ScaleUpPolicy == config.ScaleUpPolicyFastestBoot {
+		// Stable sort keeps off-age order among equal and unrecorded boot times.
+		sort.SliceStable(names, func(i, j int) bool {
+			di, iok := bootTimes[names[i]]
+			dj, jok := bootTimes[names[j]]
+			if iok != jok {
+				return iok
+			}
+			return di < dj
+		})
+	}
+	r.sortByPendingFit(ctx, names, nodes.Items)
+	return names
+}
+
 func (r *Reconciler) MaybeScaleUp(ctx context.Context) bool {
+	ctx, span := r.startSpan(withAction(ctx, ActionScaleUp), "MaybeScaleUp", attrAction.String("scale-up"))
+	defer span.End()
+
 	nodeName, shouldScale, err := r.ScaleUpStrategy.ShouldScaleUp(ctx)
 	if err != nil {
 		slog.Error("Scale-up strategy error", "err", err)
+		span.RecordError(err)
+		setReason(ctx, "strategy error")
 		return false
 	}
 	if !shouldScale {
-		slog.Info("No scale-up possible", "reason", "all strategies denied", "minNodes", r.Cfg.MinNodes)
+		slog.Info("No scale-up possible", "reason", "all strategies denied", "minNodes", r.MinNodes())
+		setReason(ctx, "all strategies denied")
 		return false
 	}
 
+	return r.scaleUpNode(withApprovers(ctx, chainNames(r.ScaleUpStrategy)), nodeName)
+}
+
+// scaleUpNode powers on a powered-off node selected by a strategy or by desired-count convergence.
+func (r *Reconciler) scaleUpNode(ctx context.Context, nodeName string) bool {
 	slog.Info("Attempting scale-up", "node", nodeName)
+	trace.SpanFromContext(ctx).SetAttributes(attrNode.String(nodeName))
 
 	node, err := r.Client.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
 	if err != nil {
@@ -302,95 +766,389 @@ func (r *Reconciler) MaybeScaleUp(ctx context.Context) bool {
 		MAC: r.Cfg.NodeAnnotations.MAC,
 	}, r.Cfg.IgnoreLabels)
 
-	if err := nodeops.PowerOnAndMarkBooted(ctx, wrapped, r.Cfg, r.Client, r.PowerOner, r.State, r.Cfg.DryRun); err != nil {
+	if wrapped.IsObserveOnly() {
+		slog.Info("Observe-only: node selected for scale-up but not acted upon", "node", nodeName)
+		setReason(ctx, "observe-only")
+		return false
+	}
+
+	if err := r.powerOn(ctx, wrapped); err != nil {
 		slog.Error("PowerOnAndMarkBooted failed", "node", nodeName, "err", err)
+		setReason(ctx, "power-on failed")
 		return false
 	}
+	setReason(ctx, "powered on")
 
 	// Manual: Clear shutdown state and metrics here
 	r.State.ClearPoweredOff(nodeName)
 	metrics.PoweredOffNodes.WithLabelValues(nodeName).Set(0)
+	r.recordAction("scale-up", nodeName)
 
 	slog.Info("Scale-up complete", "node", nodeName)
 	return true
 }
 
 func (r *Reconciler) MaybeScaleDown(ctx context.Context, eligible []*nodeops.NodeWrapper) bool {
+	ctx, span := r.startSpan(withAction(ctx, ActionScaleDown), "MaybeScaleDown", attrAction.String("scale-down"))
+	defer span.End()
+
+	if reason := r.scaleDownPhaseBlockReason(ctx); reason != "" {
+		setReason(ctx, reason)
+		return false
+	}
+
 	candidate := r.PickScaleDownCandidate(eligible)
 	if candidate == nil {
-		slog.Info("No scale-down possible", "eligible", len(eligible), "minNodes", r.Cfg.MinNodes)
+		slog.Info("No scale-down possible", "eligible", len(eligible), "minNodes", r.MinNodes(), "buffer", r.scaleDownBuffer())
+		setReason(ctx, "no candidate")
 		return false
 	}
+	span.SetAttributes(attrNode.String(candidate.Name))
 
 	ok, err := r.ScaleDownStrategy.
 		ShouldScaleDown(ctx, candidate.Name)
 	if err != nil {
 		slog.Error("Scale-down strategy failed", "err", err)
+		span.RecordError(err)
+		setReason(ctx, "strategy error")
 		return false
 	}
 	if !ok {
 		slog.Info("Scale-down strategy: node not eligible", "node", candidate.Name)
+		setReason(ctx, "strategy denied")
 		return false
 	}
 
+	return r.scaleDownNode(withApprovers(ctx, chainNames(r.ScaleDownStrategy)), candidate)
+}
+
+// scaleDownPhaseBlockReason returns why load-driven scale-down cannot pick a candidate this loop,
+// or "" when it can.
+func (r *Reconciler) scaleDownPhaseBlockReason(ctx context.Context) string {
+	switch {
+	case r.scaleDownHeld():
+		return "post-scale-up hold"
+	case r.poweredOffCapReached(ctx):
+		return "maxPoweredOff reached"
+	case r.atAbsoluteFloor(ctx, ""):
+		return "absoluteMinNodes floor"
+	}
+	return ""
+}
+
+// scaleDownHeld reports whether scale-down is suppressed by a recent power-on.
+func (r *Reconciler) scaleDownHeld() bool {
+	now := time.Now()
+	if !r.State.IsPostScaleUpHoldActive(now, r.Cfg.PostScaleUpScaleDownHold) {
+		return false
+	}
+	remaining := r.Cfg.PostScaleUpScaleDownHold - now.Sub(r.State.LastPowerOnTime)
+	slog.Info("Scale-down held after recent scale-up", "remaining", remaining.Round(time.Second).String())
+	return true
+}
+
+// poweredOffCapReached reports whether maxPoweredOff managed nodes are already powered off. The
+// count is exported as a gauge on every check. Maintenance power-offs, which an operator requests
+// for a specific node, are not capped.
+func (r *Reconciler) poweredOffCapReached(ctx context.Context) bool {
+	if actionFrom(ctx) == ActionMaintenance {
+		return false
+	}
+	off := len(r.shutdownNodeNames(ctx))
+	metrics.PoweredOffCount.Set(float64(off))
+	if r.Cfg.MaxPoweredOff <= 0 || off < r.Cfg.MaxPoweredOff {
+		return false
+	}
+	slog.Info("Scale-down skipped: maxPoweredOff reached", "poweredOff", off, "maxPoweredOff", r.Cfg.MaxPoweredOff)
+	return true
+}
+
+// atAbsoluteFloor reports whether powering off candidate would leave absoluteMinNodes Ready managed
+// nodes or fewer. An empty candidate stands for any active node. The count comes straight from
+// ListActiveNodes, independent of scale-down eligibility, and a candidate CBA already cordoned is
+// not in it; a failed listing counts as being at the floor.
+func (r *Reconciler) atAbsoluteFloor(ctx context.Context, candidate string) bool {
+	if r.Cfg.AbsoluteMinNodes <= 0 {
+		return false
+	}
+	active, err := r.listActiveNodes(ctx)
+	if err != nil {
+		slog.Warn("Scale-down blocked: cannot count Ready nodes for absoluteMinNodes", "err", err)
+		return true
+	}
+	remaining := len(active)
+	if candidate == "" || slices.ContainsFunc(active, func(n v1.Node) bool { return n.Name == candidate }) {
+		remaining--
+	}
+	if remaining > r.Cfg.AbsoluteMinNodes {
+		return false
+	}
+	slog.Info("Scale-down blocked: absoluteMinNodes floor reached", "ready", len(active),
+		"remaining", remaining, "absoluteMinNodes", r.Cfg.AbsoluteMinNodes)
+	return true
+}
+
+// scaleDownNode cordons, drains and powers off an approved candidate. It reports whether the node
+// was powered off.
+func (r *Reconciler) scaleDownNode(ctx context.Context, candidate *nodeops.NodeWrapper) bool {
+	trace.SpanFromContext(ctx).SetAttributes(attrNode.String(candidate.Name))
+	if reason := r.powerOffBlockReason(ctx, candidate); reason != "" {
+		setReason(ctx, reason)
+		return false
+	}
+
+	// Checked before cordoning: a node blocked by MAC drift would otherwise be left drained.
+	if !r.macVerifiedForShutdown(ctx, candidate) {
+		setReason(ctx, "WOL MAC drift blocks power-off")
+		return false
+	}
+	ctx = context.WithValue(ctx, macVerifiedKey{}, true)
+
+	// Ask the external approver before cordoning, so a veto leaves the node untouched.
+	if !r.retirementApproved(ctx, candidate.Name) {
+		setReason(ctx, "vetoed by approval webhook")
+		return false
+	}
+
+	ctx, release, ok := r.holdDisruptionLease(ctx)
+	if !ok {
+		setReason(ctx, "disruption lease held elsewhere")
+		return false
+	}
+	defer release()
+
 	slog.Info("Candidate for scale-down", "node", candidate.Name)
 	metrics.ScaleDowns.Inc()
 
 	if err := r.CordonAndDrain(ctx, candidate); err != nil {
 		slog.Warn("CordonAndDrain failed", "node", candidate.Name, "err", err)
+		if errors.Is(err, ErrBarePods) {
+			setReason(ctx, "pod without controller blocks drain")
+		} else {
+			setReason(ctx, "drain failed")
+		}
 		if err := nodeops.ClearPoweredOffAnnotation(ctx, r.Client, candidate.Name); err != nil {
 			slog.Warn("Failed to clear annotation from powered-off node", "node", candidate.Name, "err", err)
 		}
 		return false
 	}
 
-	if err := r.AnnotatePoweredOffNode(ctx, candidate); err != nil {
-		slog.Warn("Failed to annotate powered-off node", "node", candidate.Name, "err", err)
+	return r.powerOffDrained(ctx, candidate) == nil
+}
+
+// powerOffBlockReason returns why candidate may not be drained and powered off right now, or ""
+// when the cluster-side safety gates allow it. The MAC check, approval webhook and disruption lease
+// are left to scaleDownNode since they call out or take a lock.
+func (r *Reconciler) powerOffBlockReason(ctx context.Context, candidate *nodeops.NodeWrapper) string {
+	switch {
+	case candidate.IsObserveOnly():
+		slog.Info("Observe-only: node approved for scale-down but not acted upon", "node", candidate.Name)
+		return "observe-only"
+	case r.atAbsoluteFloor(ctx, candidate.Name):
+		return "absoluteMinNodes floor"
+	case r.poweredOffCapReached(ctx):
+		return "maxPoweredOff reached"
+	case !r.agentsOnAlwaysOnNodes(ctx):
+		return "no agent on an always-on node"
+	case !r.criticalDaemonSetsSafe(ctx, candidate.Name):
+		return "critical DaemonSet under-replicated"
+	}
+	return ""
+}
+
+// powerOffDrained annotates and powers off a node that has already been cordoned and drained.
+// It returns the reason the node was not powered off, if any; refusals that leave the node
+// untouched wrap errPowerOffBlocked.
+func (r *Reconciler) powerOffDrained(ctx context.Context, candidate *nodeops.NodeWrapper) error {
+	ctx, release, ok := r.holdDisruptionLease(ctx)
+	if !ok {
+		setReason(ctx, "disruption lease held elsewhere")
+		return errDisruptionLeaseHeld
+	}
+	defer release()
+
+	// Checked again here for callers that skip scaleDownNode (stale cordons, admin power-off).
+	if r.atAbsoluteFloor(ctx, candidate.Name) {
+		slog.Warn("absoluteMinNodes floor blocks power-off; node stays cordoned", "node", candidate.Name)
+		setReason(ctx, "absoluteMinNodes floor")
+		return errAbsoluteFloor
+	}
+	if r.poweredOffCapReached(ctx) {
+		slog.Warn("maxPoweredOff blocks power-off; node stays cordoned", "node", candidate.Name)
+		setReason(ctx, "maxPoweredOff reached")
+		return errPoweredOffCap
+	}
+	if !r.agentsOnAlwaysOnNodes(ctx) {
+		slog.Warn("Agent placement blocks power-off; node stays cordoned", "node", candidate.Name)
+		setReason(ctx, "no agent on an always-on node")
+		return errNoAlwaysOnAgent
+	}
+	if ctx.Value(macVerifiedKey{}) == nil && !r.macVerifiedForShutdown(ctx, candidate) {
+		slog.Warn("MAC drift blocks power-off; node stays cordoned", "node", candidate.Name)
+		setReason(ctx, "WOL MAC drift blocks power-off")
+		return errMACDrift
+	}
+	before := r.nodeStateBefore(candidate)
+	// With verification the node is only annotated once it has actually gone NotReady.
+	verify := r.Cfg.ShutdownVerifyTimeout > 0 && !r.Cfg.IsPowerDryRun()
+	if !verify {
+		if err := r.AnnotatePoweredOffNode(ctx, candidate); err != nil {
+			slog.Warn("Failed to annotate powered-off node", "node", candidate.Name, "err", err)
+		}
 	}
 
 	metrics.ShutdownAttempts.Inc()
-	if err := r.Shutdowner.Shutdown(ctx, candidate.Name); err != nil {
+	err := r.shutdown(ctx, candidate.Name)
+	if err == nil && verify {
+		if err = r.verifyPoweredOff(ctx, candidate.Name); err == nil {
+			if err := r.AnnotatePoweredOffNode(ctx, candidate); err != nil {
+				slog.Warn("Failed to annotate powered-off node", "node", candidate.Name, "err", err)
+			}
+		}
+	}
+	if err != nil {
 		slog.Error("Shutdown failed", "node", candidate.Name, "err", err)
+		if errors.Is(err, errShutdownUnverified) {
+			setReason(ctx, "node still Ready after shutdown")
+		} else {
+			setReason(ctx, "shutdown failed")
+		}
 		if err := nodeops.ClearPoweredOffAnnotation(ctx, r.Client, candidate.Name); err != nil {
 			slog.Warn("Failed to clear annotation from powered-off node", "node", candidate.Name, "err", err)
 		}
 	} else {
 		slog.Info("Shutdown initiated", "node", candidate.Name)
+		setReason(ctx, "powered off")
 		metrics.ShutdownSuccesses.Inc()
 		metrics.PoweredOffNodes.WithLabelValues(candidate.Name).Set(1)
 		r.State.MarkGlobalShutdown()
+		r.recordAction("scale-down", candidate.Name)
 	}
 
-	if !r.Cfg.DryRun {
+	if !r.Cfg.IsPowerDryRun() {
 		r.State.MarkShutdown(candidate.Name)
-		r.State.MarkPoweredOff(candidate.Name)
+		if err == nil || !verify {
+			r.State.MarkPoweredOff(candidate.Name)
+		}
 	}
+	r.audit(ctx, notifier.ActionPowerOff, candidate, before, err)
+	r.recordPowerOutcome(err)
+	return err
+}
 
-	return true
+// verifyPoweredOff polls nodeName until it reports NotReady (or is gone), for up to shutdownVerifyTimeout.
+func (r *Reconciler) verifyPoweredOff(ctx context.Context, nodeName string) error {
+	timeout := r.Cfg.ShutdownVerifyTimeout
+	for waited := time.Duration(0); ; waited += shutdownVerifyPollInterval {
+		n, err := r.Client.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err):
+			return nil
+		case err != nil:
+			slog.Debug("Verifying shutdown: reading node failed", "node", nodeName, "err", err)
+		case !nodeops.IsNodeReady(n):
+			slog.Info("Shutdown verified: node is NotReady", "node", nodeName, "after", waited.String())
+			return nil
+		}
+		if waited >= timeout {
+			metrics.ShutdownVerificationFailures.Inc()
+			return fmt.Errorf("%w: %s still Ready after %s", errShutdownUnverified, nodeName, timeout)
+		}
+		if err := r.sleep(ctx, shutdownVerifyPollInterval); err != nil {
+			return err
+		}
+	}
+}
+
+// shutdown powers a node off through the configured controller inside its own span.
+// With dryRunPower the physical call is skipped.
+func (r *Reconciler) shutdown(ctx context.Context, nodeName string) error {
+	ctx, span := r.startSpan(ctx, "Shutdown", attrNode.String(nodeName))
+	if err := r.claimAction(ctx, nodeName); err != nil {
+		endSpan(span, err)
+		return err
+	}
+	var err error
+	if r.Cfg.IsPowerDryRun() {
+		slog.Info("Dry-run: would power off node", "node", nodeName)
+	} else if r.Shutdowner == nil {
+		slog.Error("Cannot power off node: power controller not configured", "node", nodeName)
+		err = power.ErrNotConfigured
+	} else {
+		err = r.Shutdowner.Shutdown(ctx, nodeName)
+	}
+	if err == nil {
+		r.report.recordPowerAction(actionFrom(ctx), nodeName, false)
+		r.notify(ctx, notifier.ActionPowerOff, nodeName, r.Cfg.IsPowerDryRun())
+	}
+	endSpan(span, err)
+	return err
 }
 
 func (r *Reconciler) AnnotatePoweredOffNode(ctx context.Context, node *nodeops.NodeWrapper) error {
-	if r.Cfg.DryRun {
+	if r.Cfg.IsK8sDryRun() {
 		slog.Debug("Dry-run: would annotate node as powered-off", "node", node.Name)
 		return nil
 	}
 	slog.Debug("Annotating node as powered-off", "node", node.Name)
 	timestamp := time.Now().UTC().Format(time.RFC3339)
-	patch := fmt.Appendf(nil, `{"metadata":{"annotations":{"%s":"%s"}}}`, nodeops.AnnotationPoweredOff, timestamp)
-	_, err := r.Client.CoreV1().Nodes().Patch(ctx, node.Name, types.MergePatchType, patch, metav1.PatchOptions{})
-	return err
+	return nodeops.PatchAnnotations(ctx, r.Client, node.Name, map[string]*string{
+		nodeops.AnnotationPoweredOff: &timestamp,
+	})
 }
 
 func (r *Reconciler) PickScaleDownCandidate(eligible []*nodeops.NodeWrapper) *nodeops.NodeWrapper {
-	if len(eligible) <= r.Cfg.MinNodes {
+	// Keep scaleDownBuffer nodes of headroom above minNodes to avoid an immediate scale-up flap.
+	if len(eligible)-1 < r.MinNodes()+r.scaleDownBuffer() {
 		return nil
 	}
-	return eligible[len(eligible)-1]
+	if len(r.Cfg.NodeShutdownPriority) == 0 {
+		return eligible[len(eligible)-1]
+	}
+	// Stable ascending sort: the highest priority ends up last, ties keep the shuffled order.
+	ordered := append([]*nodeops.NodeWrapper(nil), eligible...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return nodeops.ShutdownPriority(ordered[i].Node, r.Cfg.NodeShutdownPriority) <
+			nodeops.ShutdownPriority(ordered[j].Node, r.Cfg.NodeShutdownPriority)
+	})
+	return ordered[len(ordered)-1]
 }
 
-func (r *Reconciler) CordonAndDrain(ctx context.Context, node *nodeops.NodeWrapper) error {
+func (r *Reconciler) CordonAndDrain(ctx context.Context, node *nodeops.NodeWrapper) (err error) {
+	ctx, span := r.startSpan(ctx, "CordonAndDrain", attrNode.String(node.Name))
+	defer func() { endSpan(span, err) }()
+
+	if node.IsObserveOnly() {
+		slog.Info("Observe-only: would cordon and drain node", "node", node.Name)
+		return nodeops.ErrObserveOnly
+	}
+	if !r.Cfg.DrainAllowBarePods {
+		if err := r.checkBarePods(ctx, node.Name); err != nil {
+			return err
+		}
+	}
+	if err := r.claimAction(ctx, node.Name); err != nil {
+		return err
+	}
+
+	timeout, grace := r.drainSettings(node.Node)
+	parent := ctx
+	forced := false // the drain timed out and the remaining pods were force-deleted
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+		// Count each timed-out drain once, whether force-delete then succeeded or failed.
+		defer func() {
+			if forced || err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				slog.Warn("Drain timed out", "node", node.Name, "timeout", timeout.String())
+				metrics.DrainTimeouts.Inc()
+			}
+		}()
+	}
+
 	// Step 1: Cordon
-	if r.Cfg.DryRun {
+	if r.Cfg.IsK8sDryRun() {
 		slog.Info("Dry-run: would cordon node", "node", node.Name)
 	} else {
 		err := retry.OnError(retry.DefaultBackoff, apierrors.IsConflict, func() error {
@@ -399,7 +1157,11 @@ func (r *Reconciler) CordonAndDrain(ctx context.Context, node *nodeops.NodeWrapp
 				return err
 			}
 			latestCopy := latest.DeepCopy()
-			latestCopy.Spec.Unschedulable = true
+			nodeops.Cordon(latestCopy, r.Cfg.CordonWithTaint())
+			if latestCopy.Annotations == nil {
+				latestCopy.Annotations = map[string]string{}
+			}
+			latestCopy.Annotations[nodeops.AnnotationCordonedAt] = time.Now().UTC().Format(time.RFC3339)
 			_, err = r.Client.CoreV1().Nodes().Update(ctx, latestCopy, metav1.UpdateOptions{})
 			return err
 		})
@@ -408,6 +1170,23 @@ func (r *Reconciler) CordonAndDrain(ctx context.Context, node *nodeops.NodeWrapp
 			return err
 		}
 		slog.Info("Node cordoned", "node", node.Name)
+
+		if r.Cfg.PostCordonDelaySeconds > 0 {
+			pending, err := r.podsPendingDrain(ctx, node.Name)
+			if err != nil {
+				return err
+			}
+			if pending == 0 {
+				// Only DaemonSet/mirror pods (or none) remain: nothing to reschedule, so don't wait.
+				slog.Info("Only unmovable pods remain — skipping post-cordon delay", "node", node.Name)
+			} else {
+				delay := time.Duration(r.Cfg.PostCordonDelaySeconds) * time.Second
+				slog.Info("Waiting before eviction to let schedulers react", "node", node.Name, "delay", delay.String())
+				if err := r.sleep(ctx, delay); err != nil {
+					return fmt.Errorf("post-cordon delay: %w", err)
+				}
+			}
+		}
 	}
 
 	// Step 2: List pods on node
@@ -418,36 +1197,32 @@ func (r *Reconciler) CordonAndDrain(ctx context.Context, node *nodeops.NodeWrapp
 		return err
 	}
 
+	var toEvict []v1.Pod
 	for _, pod := range pods.Items {
-		// Skip mirror pods
-		if _, ok := pod.Annotations["kubernetes.io/config.mirror"]; ok {
-			slog.Info("Skipping mirror pod", "pod", pod.Name)
+		if reason := drainExemptReason(&pod); reason != "" {
+			slog.Info("Skipping "+reason+" pod", "pod", pod.Name)
 			continue
 		}
-		// Skip DaemonSet pods
-		if ref := metav1.GetControllerOf(&pod); ref != nil && ref.Kind == "DaemonSet" {
-			slog.Info("Skipping DaemonSet pod", "pod", pod.Name)
+
+		if isBarePod(&pod) {
+			// Only reached with drainAllowBarePods; nothing would recreate an evicted bare pod either.
+			if err := r.deleteBarePod(ctx, &pod, grace); err != nil {
+				slog.Warn("Deleting bare pod failed", "pod", pod.Name, "err", err)
+				return errors.New("aborting drain due to pod deletion failure")
+			}
 			continue
 		}
+		toEvict = append(toEvict, pod)
+	}
 
-		// Try eviction
-		eviction := &policyv1.Eviction{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      pod.Name,
-				Namespace: pod.Namespace,
-			},
-			DeleteOptions: &metav1.DeleteOptions{},
+	if err := r.evictAll(ctx, toEvict, grace); err != nil {
+		if !r.Cfg.ForceDeleteAfterDrainTimeout || !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return err
 		}
-
-		if r.Cfg.DryRun {
-			slog.Info("Dry-run: would evict pod", "pod", pod.Name, "ns", pod.Namespace)
-		} else {
-			err := r.Client.PolicyV1().Evictions(pod.Namespace).Evict(ctx, eviction)
-			if err != nil {
-				slog.Warn("Eviction failed", "pod", pod.Name, "err", err)
-				return errors.New("aborting drain due to eviction failure")
-			}
-			slog.Info("Evicted pod", "pod", pod.Name, "ns", pod.Namespace)
+		slog.Warn("Drain timed out; force-deleting remaining pods", "node", node.Name, "timeout", timeout.String())
+		forced = true
+		if err := r.forceDeleteRemaining(parent, node.Name, grace); err != nil {
+			return err
 		}
 	}
 
@@ -455,6 +1230,194 @@ func (r *Reconciler) CordonAndDrain(ctx context.Context, node *nodeops.NodeWrapp
 	return nil
 }
 
+func (r *Reconciler) evictAll(ctx context.Context, pods []v1.Pod, grace time.Duration) error {
+	if limit := r.Cfg.MaxConcurrentEvictionsPerNode; limit > 0 {
+		return r.evictBounded(ctx, pods, grace, limit)
+	}
+	for i := range pods {
+		if err := r.evictPod(ctx, &pods[i], grace); err != nil {
+			return fmt.Errorf("aborting drain due to eviction failure: %w", err)
+		}
+	}
+	return nil
+}
+
+// forceDeleteRemaining deletes the pods still on nodeName after a timed-out drain, skipping mirror
+// and DaemonSet pods like the eviction path. It bypasses PodDisruptionBudgets, hence opt-in only.
+func (r *Reconciler) forceDeleteRemaining(ctx context.Context, nodeName string, grace time.Duration) error {
+	pods, err := r.Client.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+		FieldSelector: "spec.nodeName=" + nodeName,
+	})
+	if err != nil {
+		return err
+	}
+	for _, pod := range pods.Items {
+		if drainExemptReason(&pod) != "" {
+			continue
+		}
+		if r.Cfg.IsK8sDryRun() {
+			slog.Info("Dry-run: would force-delete pod", "node", nodeName, "pod", pod.Name, "ns", pod.Namespace)
+			continue
+		}
+		if err := r.Client.CoreV1().Pods(pod.Namespace).Delete(ctx, pod.Name, deleteOptions(r.forceDeleteGrace(&pod, grace))); err != nil && !apierrors.IsNotFound(err) {
+			slog.Warn("Force-deleting pod failed", "pod", pod.Name, "ns", pod.Namespace, "err", err)
+			return fmt.Errorf("aborting drain due to force-delete failure: %w", err)
+		}
+		slog.Warn("Force-deleted pod after drain timeout", "node", nodeName, "pod", pod.Name, "ns", pod.Namespace)
+	}
+	return nil
+}
+
+// maxDrainRetryInterval caps the backoff between retries of a PDB-blocked eviction.
+const maxDrainRetryInterval = time.Minute
+
+// evictPod evicts one pod through the eviction API, honoring PodDisruptionBudgets. An eviction
+// refused with 429 (a PDB allows no disruption right now) is retried with backoff, starting at
+// drainRetryInterval, until the drain's deadline; without a drain timeout it fails at once.
+func (r *Reconciler) evictPod(ctx context.Context, pod *v1.Pod, grace time.Duration) error {
+	opts := deleteOptions(grace)
+	eviction := &policyv1.Eviction{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pod.Name,
+			Namespace: pod.Namespace,
+		},
+		DeleteOptions: &opts,
+	}
+
+	if r.Cfg.IsK8sDryRun() {
+		slog.Info("Dry-run: would evict pod", "pod", pod.Name, "ns", pod.Namespace, "grace", effectiveGrace(pod, grace).String())
+		return nil
+	}
+	_, bounded := ctx.Deadline()
+	interval := r.Cfg.DrainRetryInterval
+	for {
+		err := r.Client.PolicyV1().Evictions(pod.Namespace).Evict(ctx, eviction)
+		if err == nil {
+			break
+		}
+		if !bounded || interval <= 0 || !apierrors.IsTooManyRequests(err) {
+			slog.Warn("Eviction failed", "pod", pod.Name, "err", err)
+			metrics.EvictionFailures.Inc()
+			return err
+		}
+		slog.Info("Eviction blocked by disruption budget; retrying", "pod", pod.Name, "ns", pod.Namespace,
+			"retryIn", interval.String(), "err", err)
+		if err := r.sleep(ctx, interval); err != nil {
+			return fmt.Errorf("evicting pod %s/%s: %w", pod.Namespace, pod.Name, err)
+		}
+		interval = min(2*interval, maxDrainRetryInterval)
+	}
+	slog.Info("Evicted pod", "pod", pod.Name, "ns", pod.Namespace)
+	return nil
+}
+
+// drainSettings returns the drain timeout and pod grace period for node: its cba.dev/drain-timeout
+// and cba.dev/drain-grace annotations when set and valid, otherwise the configured defaults.
+func (r *Reconciler) drainSettings(node *v1.Node) (timeout, grace time.Duration) {
+	timeout, grace = r.Cfg.DrainTimeout, r.Cfg.DrainGracePeriod
+	for key, dst := range map[string]*time.Duration{
+		nodeops.AnnotationDrainTimeout: &timeout,
+		nodeops.AnnotationDrainGrace:   &grace,
+	} {
+		raw, ok := node.Annotations[key]
+		if !ok || raw == "" {
+			continue
+		}
+		d, err := time.ParseDuration(raw)
+		if err != nil || d < 0 {
+			slog.Warn("Ignoring invalid drain annotation; using config default", "node", node.Name, "annotation", key, "value", raw)
+			continue
+		}
+		*dst = d
+	}
+	return timeout, grace
+}
+
+// drainExemptReason returns why a pod is left in place during drain ("mirror", "DaemonSet"),
+// or "" if it must be evicted.
+func drainExemptReason(pod *v1.Pod) string {
+	if _, ok := pod.Annotations["kubernetes.io/config.mirror"]; ok {
+		return "mirror"
+	}
+	if ref := metav1.GetControllerOf(pod); ref != nil && ref.Kind == "DaemonSet" {
+		return "DaemonSet"
+	}
+	return ""
+}
+
+// isBarePod reports whether pod has no controller and is still running, so deleting it loses it
+// for good. Finished pods are safe to remove either way.
+func isBarePod(pod *v1.Pod) bool {
+	if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+		return false
+	}
+	return metav1.GetControllerOf(pod) == nil
+}
+
+// checkBarePods returns ErrBarePods if nodeName runs a pod without a controller that drain would
+// have to delete. It runs before cordoning, so a blocked node is left untouched.
+func (r *Reconciler) checkBarePods(ctx context.Context, nodeName string) error {
+	pods, err := r.Client.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+		FieldSelector: "spec.nodeName=" + nodeName,
+	})
+	if err != nil {
+		return err
+	}
+	for _, pod := range pods.Items {
+		if drainExemptReason(&pod) == "" && isBarePod(&pod) {
+			slog.Info("Pod without a controller blocks drain; set drainAllowBarePods to delete it",
+				"node", nodeName, "pod", pod.Name, "ns", pod.Namespace)
+			return fmt.Errorf("%w: %s/%s", ErrBarePods, pod.Namespace, pod.Name)
+		}
+	}
+	return nil
+}
+
+func (r *Reconciler) deleteBarePod(ctx context.Context, pod *v1.Pod, grace time.Duration) error {
+	if r.Cfg.IsK8sDryRun() {
+		slog.Info("Dry-run: would delete bare pod", "pod", pod.Name, "ns", pod.Namespace)
+		return nil
+	}
+	if err := r.Client.CoreV1().Pods(pod.Namespace).Delete(ctx, pod.Name, deleteOptions(grace)); err != nil {
+		return err
+	}
+	slog.Info("Deleted bare pod", "pod", pod.Name, "ns", pod.Namespace)
+	return nil
+}
+
+// effectiveGrace returns the termination grace pod gets when deleted with the override grace:
+// grace when positive, else the pod's own terminationGracePeriodSeconds (30s when unset).
+func effectiveGrace(pod *v1.Pod, grace time.Duration) time.Duration {
+	if grace > 0 {
+		return grace
+	}
+	if s := pod.Spec.TerminationGracePeriodSeconds; s != nil {
+		return time.Duration(*s) * time.Second
+	}
+	return v1.DefaultTerminationGracePeriodSeconds * time.Second
+}
+
+// forceDeleteGrace returns the grace period for force-deleting pod: forceDeleteGracePeriod when set
+// and shorter than what the pod would otherwise get (the drain override, else its own
+// terminationGracePeriodSeconds). Only the forced path shortens grace; evictions never do on their own.
+func (r *Reconciler) forceDeleteGrace(pod *v1.Pod, grace time.Duration) time.Duration {
+	limit := r.Cfg.ForceDeleteGracePeriod
+	if limit <= 0 {
+		return grace
+	}
+	return min(effectiveGrace(pod, grace), limit)
+}
+
+// deleteOptions sets the termination grace to grace when positive; otherwise the pod's own applies.
+func deleteOptions(grace time.Duration) metav1.DeleteOptions {
+	opts := metav1.DeleteOptions{}
+	if grace > 0 {
+		seconds := int64(grace.Seconds())
+		opts.GracePeriodSeconds = &seconds
+	}
+	return opts
+}
+
 // MaybeRotate performs a maintenance rotation in two phases.
 // Phase in this loop:
 //   - Find an overdue powered-off node (age >= rotation.maxPoweredOffDuration), honoring exempt & ignore labels.
@@ -467,6 +1430,8 @@ func (r *Reconciler) MaybeRotate(ctx context.Context) {
 	if r.Cfg == nil || !r.Cfg.Rotation.Enabled || r.Cfg.Rotation.MaxPoweredOffDuration <= 0 {
 		return
 	}
+	ctx, span := r.startSpan(withAction(ctx, ActionRotate), "MaybeRotate", attrAction.String("rotate"))
+	defer span.End()
 
 	slog.Debug("MaybeRotate: start",
 		"enabled", r.Cfg.Rotation.Enabled,
@@ -476,11 +1441,7 @@ func (r *Reconciler) MaybeRotate(ctx context.Context) {
 	now := time.Now().UTC()
 
 	// 1) Discover the oldest overdue powered-off node.
-	managed, err := nodeops.ListManagedNodes(ctx, r.Client, nodeops.ManagedNodeFilter{
-		ManagedLabel:  r.Cfg.NodeLabels.Managed,
-		DisabledLabel: r.Cfg.NodeLabels.Disabled,
-		IgnoreLabels:  r.Cfg.IgnoreLabels,
-	})
+	managed, err := nodeops.ListManagedNodes(ctx, r.Client, nodeops.NewManagedNodeFilter(r.Cfg))
 	if err != nil || len(managed) == 0 {
 		if err != nil {
 			slog.Warn("MaybeRotate: listing managed nodes failed", "err", err)
@@ -489,62 +1450,23 @@ func (r *Reconciler) MaybeRotate(ctx context.Context) {
 	}
 	slog.Debug("MaybeRotate: managed nodes fetched", "count", len(managed))
 
-	var (
-		overdue *v1.Node
-		since   time.Time
-	)
-	poweredOffCount := 0
-	overdueCount := 0
-	maxOffAge := time.Duration(0)
-	maxOffNode := ""
-
-	for i := range managed {
-		n := managed[i]
-
-		// Per-node exemption.
-		if key := r.Cfg.Rotation.ExemptLabel; key != "" {
-			if val, ok := n.Labels[key]; ok && val != "" {
-				slog.Debug("MaybeRotate: skip node due to exemptLabel", "node", n.Name, "label", key)
-				continue
-			}
-		}
-		// Honor global ignore labels.
-		if nodeops.ShouldIgnoreNodeDueToLabels(n, r.Cfg.IgnoreLabels) {
-			slog.Debug("MaybeRotate: skip node due to ignoreLabels", "node", n.Name)
-			continue
-		}
-
-		if t, ok := nodeops.PoweredOffSince(n); ok {
-			poweredOffCount++
-			age := now.Sub(t)
-
-			if age > maxOffAge {
-				maxOffAge = age
-				maxOffNode = n.Name
-			}
-
-			if age >= r.Cfg.Rotation.MaxPoweredOffDuration {
-				overdueCount++
-				if overdue == nil || t.Before(since) {
-					overdue = &managed[i]
-					since = t
-				}
-			}
-		}
-	}
-
-	if overdue == nil {
-		timeLeft := r.Cfg.Rotation.MaxPoweredOffDuration - maxOffAge
+	tied, scan := r.overdueRotationNodes(managed, now)
+	if len(tied) == 0 {
+		timeLeft := r.Cfg.Rotation.MaxPoweredOffDuration - scan.longestOffAge
 		slog.Info("MaybeRotate: no overdue powered-off node found",
-			"poweredOff", poweredOffCount,
-			"overdue", overdueCount,
+			"poweredOff", scan.poweredOff,
+			"overdue", scan.overdue,
 			"minOffAge", r.Cfg.Rotation.MaxPoweredOffDuration.String(),
-			"longestOffNode", maxOffNode,
-			"longestOffAge", maxOffAge.Round(time.Second).String(),
+			"longestOffNode", scan.longestOffNode,
+			"longestOffAge", scan.longestOffAge.Round(time.Second).String(),
 			"nextRotationIn", timeLeft.Round(time.Second).String(),
 		)
+		setReason(ctx, "no overdue node")
 		return
 	}
+	overdue := r.breakRotationTie(tied)
+	since, _ := nodeops.PoweredOffSince(*overdue)
+	span.SetAttributes(attrNode.String(overdue.Name))
 
 	// 2) Capacity safety before we consider booting another node.
 	allNodes, err := r.listAllNodes(ctx)
@@ -553,12 +1475,13 @@ func (r *Reconciler) MaybeRotate(ctx context.Context) {
 		return
 	}
 	eligible := r.filterEligibleNodes(allNodes.Items)
-	slog.Debug("MaybeRotate: pre-power-on capacity check", "eligible", len(eligible), "minNodes", r.Cfg.MinNodes)
+	slog.Debug("MaybeRotate: pre-power-on capacity check", "eligible", len(eligible), "minNodes", r.MinNodes())
 
 	// Allow rotation if adding one node would put us strictly above minNodes.
-	if len(eligible)+1 <= r.Cfg.MinNodes {
+	if len(eligible)+1 <= r.MinNodes() {
 		slog.Info("MaybeRotate: skip — eligible+1 at/below minNodes",
-			"eligible", len(eligible), "minNodes", r.Cfg.MinNodes)
+			"eligible", len(eligible), "minNodes", r.MinNodes())
+		setReason(ctx, "capacity")
 		return
 	}
 
@@ -568,6 +1491,7 @@ func (r *Reconciler) MaybeRotate(ctx context.Context) {
 	cand := r.PickRotationPoweroffCandidate(ctx, eligible)
 	if cand == nil {
 		slog.Info("MaybeRotate: skip — no suitable tentative retire candidate (gates/eligibility)")
+		setReason(ctx, "no retire candidate")
 		return
 	}
 	slog.Debug("MaybeRotate: tentative retire candidate selected", "node", cand.Name)
@@ -579,15 +1503,25 @@ func (r *Reconciler) MaybeRotate(ctx context.Context) {
 	wrapped := nodeops.NewNodeWrapper(overdue, r.State, now, nodeops.NodeAnnotationConfig{
 		MAC: r.Cfg.NodeAnnotations.MAC,
 	}, r.Cfg.IgnoreLabels)
+	if wrapped.IsObserveOnly() {
+		slog.Info("MaybeRotate: observe-only node is overdue but not acted upon", "node", overdue.Name)
+		setReason(ctx, "observe-only")
+		return
+	}
 
-	if err := nodeops.PowerOnAndMarkBooted(ctx, wrapped, r.Cfg, r.Client, r.PowerOner, r.State, r.Cfg.DryRun); err != nil {
+	if err := r.powerOn(ctx, wrapped); err != nil {
 		slog.Warn("MaybeRotate: power-on failed; abort", "node", overdue.Name, "err", err)
+		setReason(ctx, "power-on failed")
 		return
 	}
+	setReason(ctx, "powered on")
 
 	// Clear powered-off state/metric like in scale-up.
 	r.State.ClearPoweredOff(overdue.Name)
 	metrics.PoweredOffNodes.WithLabelValues(overdue.Name).Set(0)
+	if !r.Cfg.IsPowerDryRun() {
+		r.State.MarkRotated(overdue.Name)
+	}
 
 	// Two-phase: do not retire in the same loop. Reconcile()'s global cooldown guard + per-node boot cooldown
 	// ensure stabilization before any shutdown is considered later.
@@ -595,6 +1529,94 @@ func (r *Reconciler) MaybeRotate(ctx context.Context) {
 	return
 }
 
+// powerOn boots a node through the configured power controller inside its own span.
+// rotationScan summarizes the powered-off managed nodes seen while looking for a rotation target.
+type rotationScan struct {
+	poweredOff     int
+	overdue        int
+	longestOffAge  time.Duration
+	longestOffNode string
+}
+
+// overdueRotationNodes returns the overdue powered-off nodes that were powered off at the oldest
+// timestamp, skipping nodes exempt from rotation or matching ignoreLabels.
+func (r *Reconciler) overdueRotationNodes(managed []v1.Node, now time.Time) ([]*v1.Node, rotationScan) {
+	var (
+		tied  []*v1.Node
+		since time.Time
+		scan  rotationScan
+	)
+	for i := range managed {
+		n := managed[i]
+
+		// Per-node exemption.
+		if key := r.Cfg.Rotation.ExemptLabel; key != "" {
+			if val, ok := n.Labels[key]; ok && val != "" {
+				slog.Debug("MaybeRotate: skip node due to exemptLabel", "node", n.Name, "label", key)
+				continue
+			}
+		}
+		// Honor global ignore labels.
+		if nodeops.ShouldIgnoreNodeDueToLabels(n, r.Cfg.IgnoreLabels) {
+			slog.Debug("MaybeRotate: skip node due to ignoreLabels", "node", n.Name)
+			continue
+		}
+		if nodeops.IsInMaintenance(&n) {
+			slog.Debug("MaybeRotate: skip node in maintenance", "node", n.Name)
+			continue
+		}
+
+		if t, ok := nodeops.PoweredOffSince(n); ok {
+			scan.poweredOff++
+			age := now.Sub(t)
+
+			if age > scan.longestOffAge {
+				scan.longestOffAge = age
+				scan.longestOffNode = n.Name
+			}
+
+			if age >= r.Cfg.Rotation.MaxPoweredOffDuration {
+				scan.overdue++
+				switch {
+				case len(tied) == 0 || t.Before(since):
+					tied = []*v1.Node{&managed[i]}
+					since = t
+				case t.Equal(since):
+					tied = append(tied, &managed[i])
+				}
+			}
+		}
+	}
+	return tied, scan
+}
+
+func (r *Reconciler) powerOn(ctx context.Context, node *nodeops.NodeWrapper) error {
+	ctx, span := r.startSpan(ctx, "PowerOn", attrNode.String(node.Name))
+	if err := r.claimAction(ctx, node.Name); err != nil {
+		endSpan(span, err)
+		return err
+	}
+	before := r.nodeStateBefore(node)
+	err := nodeops.PowerOnAndMarkBooted(ctx, node, r.Cfg, r.Client, r.PowerOner, r.State, r.Cfg.DryRun)
+	if errors.Is(err, nodeops.ErrMissingMAC) && r.Cfg.MACDiscoveryOnDemand {
+		// Don't wait for the next discovery cycle; the boot is retried on the next loop.
+		slog.Info("Scale-up target has no MAC yet — running targeted discovery", "node", node.Name)
+		if nodeops.DiscoverNodeMAC(ctx, r.Client, nodeops.NewMACUpdaterConfig(r.Cfg, r.AgentHTTP), node.Name) {
+			slog.Info("MAC discovered for scale-up target; will boot on next loop", "node", node.Name)
+		}
+	}
+	if err == nil {
+		r.report.recordPowerAction(actionFrom(ctx), node.Name, true)
+		r.notify(ctx, notifier.ActionPowerOn, node.Name, r.Cfg.IsPowerDryRun())
+	}
+	if !errors.Is(err, nodeops.ErrMissingMAC) && !errors.Is(err, nodeops.ErrPowerOnSuppressed) {
+		r.audit(ctx, notifier.ActionPowerOn, node, before, err)
+		r.recordPowerOutcome(err)
+	}
+	endSpan(span, err)
+	return err
+}
+
 // PickRotationPoweroffCandidate applies optional LoadAverage checks to find a safe node to power off.
 // If LoadAverage is disabled, it defers to the default scale-down candidate picker.
 // When enabled, a candidate is accepted only if:
@@ -632,6 +1654,15 @@ func (r *Reconciler) PickRotationPoweroffCandidate(ctx context.Context, eligible
 		r.Cfg.LoadAverageStrategy.Port,
 		time.Duration(r.Cfg.LoadAverageStrategy.TimeoutSeconds)*time.Second,
 	)
+	utils.AllowLoadOverrides = r.Cfg.LoadAverageStrategy.AllowLoadOverrides
+	utils.MinSamples = r.Cfg.LoadAverageStrategy.MinLoadSamples
+	utils.Normalization = strategy.ParseLoadNormalization(r.Cfg.LoadAverageStrategy.LoadNormalization)
+	utils.Denominator = strategy.ParseAggregateDenominator(r.Cfg.LoadAverageStrategy.AggregateDenominator)
+	utils.MissingMetrics = strategy.ParseMissingMetricsBehavior(r.Cfg.LoadAverageStrategy.MissingMetricsBehavior)
+	utils.HTTP = r.AgentHTTP
+	utils.Source = r.LoadSource
+	utils.Cache = r.LoadCache
+	utils.Concurrency = r.Cfg.LoadAverageStrategy.LoadFetchConcurrency
 	evalMode := strategy.ParseClusterEvalMode(r.Cfg.LoadAverageStrategy.ClusterEval)
 
 	// Try candidates until one passes both node and cluster checks.
@@ -694,6 +1725,8 @@ func (r *Reconciler) PickRotationPoweroffCandidate(ctx context.Context, eligible
 
 // BuildAggregateExclusions returns the label set excluded from cluster-wide load math:
 // union of disabled-label and loadAverageStrategy.excludeFromAggregateLabels.
+// Individual nodes can also opt out with the cba.dev/exclude-from-aggregate annotation,
+// which GetEligibleClusterLoads honors directly.
 func BuildAggregateExclusions(cfg *config.Config) map[string]string {
 	ex := make(map[string]string, len(cfg.LoadAverageStrategy.ExcludeFromAggregateLabels)+1)
 	if cfg.NodeLabels.Disabled != "" {
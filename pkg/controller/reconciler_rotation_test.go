@@ -633,3 +633,67 @@ func TestMaybeRotate_SkipsIgnoredAndDisabled_PowerOnOnly(t *testing.T) {
 	require.Empty(t, rec.calls, "no shutdown in same loop")
 	require.ElementsMatch(t, []string{"off-old"}, mockPower.PoweredOn, "only the overdue node should be powered on")
 }
+
+func TestMaybeRotate_TieBreak(t *testing.T) {
+	offAt := time.Now().Add(-2 * time.Hour).Truncate(time.Second)
+	rotate := func(t *testing.T, rotation config.RotationConfig, state *nodeops.NodeStateTracker) string {
+		t.Helper()
+		client := corefake.NewSimpleClientset(
+			poweredOffSince(managedNode("off-c", false), offAt),
+			poweredOffSince(managedNode("off-a", false), offAt),
+			poweredOffSince(managedNode("off-b", false), offAt),
+			managedNode("n1", true),
+			managedNode("n2", true),
+		)
+		rotation.Enabled = true
+		rotation.MaxPoweredOffDuration = 30 * time.Minute
+		power := &mockPowerOnController{}
+		r := &controller.Reconciler{
+			Cfg: &config.Config{
+				NodeLabels:      config.NodeLabelConfig{Managed: "cba.dev/is-managed", Disabled: "cba.dev/disabled"},
+				NodeAnnotations: config.NodeAnnotationConfig{MAC: nodeops.AnnotationMACAuto},
+				Rotation:        rotation,
+			},
+			Client:     client,
+			State:      state,
+			Shutdowner: &shutdownRecorder{},
+			PowerOner:  power,
+		}
+		r.MaybeRotate(context.Background())
+		require.Len(t, power.PoweredOn, 1)
+		return power.PoweredOn[0]
+	}
+
+	t.Run("name", func(t *testing.T) {
+		state := nodeops.NewNodeStateTracker()
+		require.Equal(t, "off-a", rotate(t, config.RotationConfig{TieBreak: config.RotationTieBreakName}, state))
+		_, rotated := state.LastRotated("off-a")
+		require.True(t, rotated, "rotation history records the power-on")
+	})
+
+	t.Run("leastRecentlyRotated prefers never rotated", func(t *testing.T) {
+		state := nodeops.NewNodeStateTracker()
+		state.SetRotatedTime("off-a", time.Now().Add(-time.Hour))
+		state.SetRotatedTime("off-b", time.Now().Add(-2*time.Hour))
+		require.Equal(t, "off-c", rotate(t, config.RotationConfig{TieBreak: config.RotationTieBreakLeastRecentlyRotated}, state))
+	})
+
+	t.Run("leastRecentlyRotated picks oldest rotation", func(t *testing.T) {
+		state := nodeops.NewNodeStateTracker()
+		state.SetRotatedTime("off-a", time.Now().Add(-time.Hour))
+		state.SetRotatedTime("off-b", time.Now().Add(-3*time.Hour))
+		state.SetRotatedTime("off-c", time.Now().Add(-2*time.Hour))
+		require.Equal(t, "off-b", rotate(t, config.RotationConfig{TieBreak: config.RotationTieBreakLeastRecentlyRotated}, state))
+	})
+
+	t.Run("random is stable per seed", func(t *testing.T) {
+		picks := map[string]bool{}
+		for seed := int64(0); seed < 16; seed++ {
+			cfg := config.RotationConfig{TieBreak: config.RotationTieBreakRandom, TieBreakSeed: seed}
+			first := rotate(t, cfg, nodeops.NewNodeStateTracker())
+			require.Equal(t, first, rotate(t, cfg, nodeops.NewNodeStateTracker()), "seed %d", seed)
+			picks[first] = true
+		}
+		require.Greater(t, len(picks), 1, "different seeds should not all pick the same node")
+	})
+}
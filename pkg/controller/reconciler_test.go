@@ -6,15 +6,21 @@ import (
 	policyv1 "k8s.io/api/policy/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	k8stesting "k8s.io/client-go/testing"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/docent-net/cluster-bare-autoscaler/internal/bootstrap/metrics"
 	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
 	"github.com/docent-net/cluster-bare-autoscaler/pkg/controller"
 	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/strategy"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/fake"
 	corefake "k8s.io/client-go/kubernetes/fake"
 )
@@ -77,12 +83,19 @@ func TestMaybeScaleDown_StrategyDenies(t *testing.T) {
 	}
 
 	nodes, _ := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
-	wrappers := nodeops.WrapNodes(nodes.Items, state, time.Now(), nodeops.NodeAnnotationConfig(cfg.NodeAnnotations), cfg.IgnoreLabels)
+	wrappers := nodeops.WrapNodes(nodes.Items, state, time.Now(), nodeops.NodeAnnotationConfig{MAC: cfg.NodeAnnotations.MAC}, cfg.IgnoreLabels)
 
 	ok := reconciler.MaybeScaleDown(ctx, wrappers)
 	require.False(t, ok)
 }
 
+// controlledBy returns owner references marking a pod as managed by a controller, so drain treats
+// it as a regular workload rather than a bare pod.
+func controlledBy(kind, name string) []metav1.OwnerReference {
+	isController := true
+	return []metav1.OwnerReference{{Kind: kind, Name: name, Controller: &isController}}
+}
+
 func TestCordonAndDrain_EvictionFails(t *testing.T) {
 	ctx := context.Background()
 
@@ -97,8 +110,9 @@ func TestCordonAndDrain_EvictionFails(t *testing.T) {
 		},
 		&v1.Pod{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:      "mypod",
-				Namespace: "default",
+				Name:            "mypod",
+				Namespace:       "default",
+				OwnerReferences: controlledBy("ReplicaSet", "web"),
 			},
 			Spec: v1.PodSpec{
 				NodeName: "node1",
@@ -164,12 +178,9 @@ func TestCordonAndDrain_SkipsMirrorAndDaemonSet(t *testing.T) {
 		},
 		&v1.Pod{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:      "ds-pod",
-				Namespace: "default",
-				OwnerReferences: []metav1.OwnerReference{{
-					Kind: "DaemonSet",
-					Name: "ds-owner",
-				}},
+				Name:            "ds-pod",
+				Namespace:       "default",
+				OwnerReferences: controlledBy("DaemonSet", "ds-owner"),
 			},
 			Spec: v1.PodSpec{
 				NodeName: "node1",
@@ -415,6 +426,7 @@ func TestReconcile_ForcePowerOnAllNodes(t *testing.T) {
 				Cfg: &config.Config{
 					ForcePowerOnAllNodes: true,
 					DryRun:               tt.dryRun,
+					PowerOnMode:          "wol",
 					NodeAnnotations: config.NodeAnnotationConfig{
 						MAC: "cba.dev/mac-address",
 					},
@@ -678,6 +690,7 @@ func TestPickScaleDownCandidate(t *testing.T) {
 		name         string
 		eligible     []string
 		minNodes     int
+		buffer       int
 		expectedNode string // empty string means expect nil
 	}
 	cases := []scenario{
@@ -705,6 +718,20 @@ func TestPickScaleDownCandidate(t *testing.T) {
 			minNodes:     0,
 			expectedNode: "nodeB",
 		},
+		{
+			name:         "buffer keeps headroom above minNodes — returns nil",
+			eligible:     []string{"node1", "node2", "node3"},
+			minNodes:     2,
+			buffer:       1,
+			expectedNode: "",
+		},
+		{
+			name:         "buffer satisfied — pick last",
+			eligible:     []string{"node1", "node2", "node3", "node4"},
+			minNodes:     2,
+			buffer:       1,
+			expectedNode: "node4",
+		},
 	}
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -713,7 +740,7 @@ func TestPickScaleDownCandidate(t *testing.T) {
 				eligible = append(eligible, &nodeops.NodeWrapper{Node: &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: n}}})
 			}
 			reconciler := &controller.Reconciler{
-				Cfg: &config.Config{MinNodes: tc.minNodes},
+				Cfg: &config.Config{MinNodes: tc.minNodes, ScaleDownBuffer: tc.buffer},
 			}
 			node := reconciler.PickScaleDownCandidate(eligible)
 			if tc.expectedNode == "" {
@@ -727,6 +754,41 @@ func TestPickScaleDownCandidate(t *testing.T) {
 
 }
 
+func TestPickScaleDownCandidate_Priority(t *testing.T) {
+	node := func(name string, labels, annotations map[string]string) *nodeops.NodeWrapper {
+		return &nodeops.NodeWrapper{Node: &v1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name: name, Labels: labels, Annotations: annotations,
+		}}}
+	}
+	eligible := []*nodeops.NodeWrapper{
+		node("old-gen", map[string]string{"hw-gen": "1"}, nil),
+		node("power-hog", nil, map[string]string{"example.com/power-hungry": "true"}),
+		node("new-gen", map[string]string{"hw-gen": "3"}, nil),
+		node("plain", nil, nil),
+	}
+
+	tests := []struct {
+		name     string
+		priority map[string]int
+		want     string
+	}{
+		{name: "no priorities keeps last", want: "plain"},
+		{name: "label value match", priority: map[string]int{"hw-gen=1": 10}, want: "old-gen"},
+		{name: "annotation presence match", priority: map[string]int{"example.com/power-hungry": 5, "hw-gen=1": 3}, want: "power-hog"},
+		{name: "negative weight keeps node", priority: map[string]int{"hw-gen": -1}, want: "plain"},
+		{name: "ties keep last of equal weight", priority: map[string]int{"hw-gen": 2}, want: "new-gen"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &controller.Reconciler{Cfg: &config.Config{NodeShutdownPriority: tt.priority}}
+			got := r.PickScaleDownCandidate(eligible)
+			require.NotNil(t, got)
+			require.Equal(t, tt.want, got.Name)
+			require.Equal(t, "plain", eligible[len(eligible)-1].Name, "input order must not change")
+		})
+	}
+}
+
 func TestCordonAndDrain_Success(t *testing.T) {
 	type testCase struct {
 		name        string
@@ -754,9 +816,10 @@ func TestCordonAndDrain_Success(t *testing.T) {
 			// Normal pod (should be evicted)
 			evictMe := &v1.Pod{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:      "evict-me",
-					Namespace: "default",
-					UID:       "evictme-uid",
+					Name:            "evict-me",
+					Namespace:       "default",
+					UID:             "evictme-uid",
+					OwnerReferences: controlledBy("ReplicaSet", "web"),
 				},
 				Spec: v1.PodSpec{
 					NodeName: nodeName,
@@ -985,6 +1048,12 @@ func (s *alwaysAllowStrategy) ShouldScaleDown(_ context.Context, node string) (b
 }
 func (s *alwaysAllowStrategy) Name() string { return "allow-all" }
 
+// approveAllStrategy approves scale-down for any candidate.
+type approveAllStrategy struct{}
+
+func (approveAllStrategy) ShouldScaleDown(context.Context, string) (bool, error) { return true, nil }
+func (approveAllStrategy) Name() string                                          { return "approve-all" }
+
 // --- the actual test ---
 
 func TestMaybeScaleDown_AnnotatePatchError_AllowsShutdownAndMarksState(t *testing.T) {
@@ -1225,3 +1294,926 @@ func TestMaybeRotate_Skips_WhenNoTentativeCandidateDueToLoad(t *testing.T) {
 		t.Fatalf("expected no shutdown, got %v", sh.calls)
 	}
 }
+
+func TestRefreshMinNodes_SourceDrivesScaleDecisions(t *testing.T) {
+	ctx := context.Background()
+	cm := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "targets", Namespace: "cba"},
+		Data:       map[string]string{"minNodes": "1"},
+	}
+	client := fake.NewSimpleClientset(cm)
+
+	r := &controller.Reconciler{
+		Client: client,
+		Cfg: &config.Config{
+			MinNodes: 2,
+			MinNodesSource: config.ValueSourceConfig{
+				Type: config.ValueSourceConfigMap, Namespace: "cba", Name: "targets", Key: "minNodes",
+			},
+		},
+		State: nodeops.NewNodeStateTracker(),
+	}
+
+	now := time.Now()
+	eligible := nodeops.WrapNodes([]v1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "n1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "n2"}},
+	}, r.State, now, nodeops.NodeAnnotationConfig{}, nil)
+
+	// Source says 1: two eligible nodes leave room for a scale-down.
+	r.RefreshMinNodes(ctx)
+	require.Equal(t, 1, r.MinNodes())
+	require.NotNil(t, r.PickScaleDownCandidate(eligible))
+
+	// Source raised to 3: the same set no longer allows scale-down, and scale-up kicks in.
+	cm.Data["minNodes"] = "3"
+	_, err := client.CoreV1().ConfigMaps("cba").Update(ctx, cm, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	r.RefreshMinNodes(ctx)
+	require.Equal(t, 3, r.MinNodes())
+	require.Nil(t, r.PickScaleDownCandidate(eligible))
+
+	up := &strategy.MinNodeCountScaleUp{
+		Cfg:          r.Cfg,
+		MinNodes:     r.MinNodes,
+		ActiveNodes:  func(context.Context) ([]v1.Node, error) { return []v1.Node{{}, {}}, nil },
+		ShutdownList: func(context.Context) []string { return []string{"off-1"} },
+	}
+	node, ok, err := up.ShouldScaleUp(ctx)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "off-1", node)
+
+	// Broken source falls back to the static minNodes.
+	cm.Data["minNodes"] = "not-a-number"
+	_, err = client.CoreV1().ConfigMaps("cba").Update(ctx, cm, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	r.RefreshMinNodes(ctx)
+	require.Equal(t, 2, r.MinNodes())
+}
+
+func TestObserveOnlyNode_EvaluatedButNeverActedUpon(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	observed := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "observed",
+			Labels: map[string]string{
+				"cba.dev/is-managed":     "true",
+				nodeops.LabelObserveOnly: "true",
+			},
+		},
+	}
+	observedOff := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "observed-off",
+			Labels: map[string]string{
+				"cba.dev/is-managed":     "true",
+				nodeops.LabelObserveOnly: "true",
+			},
+			Annotations: map[string]string{
+				nodeops.AnnotationPoweredOff: now.Add(-time.Hour).Format(time.RFC3339),
+				nodeops.AnnotationMACAuto:    "00:11:22:33:44:55",
+			},
+		},
+		Spec: v1.NodeSpec{Unschedulable: true},
+	}
+	client := fake.NewSimpleClientset(observed, observedOff)
+
+	sm := &shutdownMock{}
+	power := &mockPowerOnController{}
+	state := nodeops.NewNodeStateTracker()
+	r := &controller.Reconciler{
+		Client:            client,
+		Cfg:               &config.Config{NodeLabels: config.NodeLabelConfig{Managed: "cba.dev/is-managed"}},
+		State:             state,
+		Shutdowner:        sm,
+		PowerOner:         power,
+		ScaleDownStrategy: &alwaysAllowStrategy{candidate: "observed"},
+		ScaleUpStrategy:   &fixedScaleUpStrategy{node: "observed-off"},
+	}
+
+	wrapped := nodeops.NewNodeWrapper(observed, state, now, nodeops.NodeAnnotationConfig{}, nil)
+	require.False(t, r.MaybeScaleDown(ctx, []*nodeops.NodeWrapper{wrapped}))
+	require.Equal(t, 0, sm.calls, "observe-only node must never be shut down")
+
+	got, err := client.CoreV1().Nodes().Get(ctx, "observed", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.False(t, got.Spec.Unschedulable, "observe-only node must never be cordoned")
+	require.False(t, state.IsPoweredOff("observed"))
+
+	require.False(t, r.MaybeScaleUp(ctx))
+	require.Empty(t, power.PoweredOn, "observe-only node must never be powered on")
+}
+
+// fixedScaleUpStrategy always asks to power on the given node.
+type fixedScaleUpStrategy struct{ node string }
+
+func (f *fixedScaleUpStrategy) ShouldScaleUp(context.Context) (string, bool, error) {
+	return f.node, true, nil
+}
+func (f *fixedScaleUpStrategy) Name() string { return "fixed" }
+
+// bootSimulator flips a node's Ready condition on power-on and shutdown.
+type bootSimulator struct {
+	client    *fake.Clientset
+	PoweredOn []string
+	ShutDown  []string
+}
+
+func (b *bootSimulator) PowerOn(ctx context.Context, nodeName string, _ string) error {
+	b.PoweredOn = append(b.PoweredOn, nodeName)
+	return b.setReady(ctx, nodeName, v1.ConditionTrue)
+}
+
+func (b *bootSimulator) Shutdown(ctx context.Context, nodeName string) error {
+	b.ShutDown = append(b.ShutDown, nodeName)
+	return b.setReady(ctx, nodeName, v1.ConditionFalse)
+}
+
+func (b *bootSimulator) setReady(ctx context.Context, nodeName string, status v1.ConditionStatus) error {
+	node, err := b.client.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	node.Status.Conditions = []v1.NodeCondition{{Type: v1.NodeReady, Status: status}}
+	_, err = b.client.CoreV1().Nodes().UpdateStatus(ctx, node, metav1.UpdateOptions{})
+	return err
+}
+
+// newSimReconciler builds a Reconciler over client that powers nodes through sim. cfg manages
+// nodes labelled cba.dev/is-managed unless it says otherwise; every scale-down candidate is
+// approved and no scale-up is requested. Tests override fields on the result as needed.
+func newSimReconciler(client *fake.Clientset, sim *bootSimulator, cfg *config.Config) *controller.Reconciler {
+	if cfg.NodeLabels.Managed == "" {
+		cfg.NodeLabels.Managed = "cba.dev/is-managed"
+	}
+	return &controller.Reconciler{
+		Client:            client,
+		Cfg:               cfg,
+		State:             nodeops.NewNodeStateTracker(),
+		Shutdowner:        sim,
+		PowerOner:         sim,
+		ScaleDownStrategy: approveAllStrategy{},
+		ScaleUpStrategy:   &mockScaleUpStrategy{},
+	}
+}
+
+func TestReconcile_DesiredNodeCount_ConvergesAcrossLoops(t *testing.T) {
+	ctx := context.Background()
+	managed := map[string]string{"cba.dev/is-managed": "true"}
+	ready := []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionTrue}}
+
+	var objs []runtime.Object
+	for _, name := range []string{"on-1", "on-2"} {
+		objs = append(objs, &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: managed},
+			Status:     v1.NodeStatus{Conditions: ready},
+		})
+	}
+	for i, name := range []string{"off-1", "off-2"} {
+		objs = append(objs, &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: managed,
+				Annotations: map[string]string{
+					nodeops.AnnotationPoweredOff: time.Now().Add(-time.Duration(i+1) * time.Hour).UTC().Format(time.RFC3339),
+					nodeops.AnnotationMACAuto:    "00:11:22:33:44:55",
+				},
+			},
+			Spec: v1.NodeSpec{Unschedulable: true},
+		})
+	}
+	cm := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "targets", Namespace: "cba"},
+		Data:       map[string]string{"desiredNodeCount": "4"},
+	}
+	objs = append(objs, cm)
+	client := fake.NewSimpleClientset(objs...)
+
+	sim := &bootSimulator{client: client}
+	r := &controller.Reconciler{
+		Client: client,
+		Cfg: &config.Config{
+			NodeLabels: config.NodeLabelConfig{Managed: "cba.dev/is-managed"},
+			DesiredNodeCountSource: config.ValueSourceConfig{
+				Type: config.ValueSourceConfigMap, Namespace: "cba", Name: "targets", Key: "desiredNodeCount",
+			},
+		},
+		State:      nodeops.NewNodeStateTracker(),
+		Shutdowner: sim,
+		PowerOner:  sim,
+		// Load-based strategies must not be consulted while a desired count is set.
+		ScaleDownStrategy: &MockScaleDownStrategy{},
+		ScaleUpStrategy:   &failingScaleUpStrategy{},
+	}
+
+	activeCount := func() int {
+		list, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+		require.NoError(t, err)
+		n := 0
+		for _, node := range list.Items {
+			if _, off := node.Annotations[nodeops.AnnotationPoweredOff]; !off && !node.Spec.Unschedulable && nodeops.IsNodeReady(&node) {
+				n++
+			}
+		}
+		return n
+	}
+
+	// Converge up from 2 to 4: one node per loop, oldest powered-off first.
+	for i := 0; i < 3; i++ {
+		require.NoError(t, r.Reconcile(ctx))
+	}
+	require.Equal(t, []string{"off-2", "off-1"}, sim.PoweredOn)
+	require.Equal(t, 4, activeCount())
+
+	// Converge down from 4 to 1: one drain and power-off per loop.
+	cm.Data["desiredNodeCount"] = "1"
+	_, err := client.CoreV1().ConfigMaps("cba").Update(ctx, cm, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	for i := 0; i < 4; i++ {
+		require.NoError(t, r.Reconcile(ctx))
+	}
+	require.Len(t, sim.ShutDown, 3)
+	require.Equal(t, 1, activeCount())
+	require.Len(t, sim.PoweredOn, 2, "no extra power-ons while converging down")
+}
+
+func TestMaybeScaleDown_HeldAfterRecentScaleUp(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset(
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name:        "off",
+			Annotations: map[string]string{nodeops.AnnotationMACAuto: "00:11:22:33:44:55"},
+		}},
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "busy"}},
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "idle"}},
+	)
+	state := nodeops.NewNodeStateTracker()
+	sm := &shutdownMock{}
+	r := &controller.Reconciler{
+		Client:            client,
+		Cfg:               &config.Config{PostScaleUpScaleDownHold: time.Hour},
+		State:             state,
+		Shutdowner:        sm,
+		PowerOner:         &mockPowerOnController{},
+		ScaleUpStrategy:   &fixedScaleUpStrategy{node: "off"},
+		ScaleDownStrategy: &alwaysAllowStrategy{candidate: "idle"},
+	}
+	eligible := nodeops.WrapNodes([]v1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "busy"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "idle"}},
+	}, state, time.Now(), nodeops.NodeAnnotationConfig{}, nil)
+
+	require.True(t, r.MaybeScaleUp(ctx))
+	require.False(t, r.MaybeScaleDown(ctx, eligible), "scale-down must be held right after a power-on")
+	require.Equal(t, 0, sm.calls)
+
+	// Once the window has passed, the same candidate is retired.
+	state.LastPowerOnTime = time.Now().Add(-2 * time.Hour)
+	require.True(t, r.MaybeScaleDown(ctx, eligible))
+	require.Equal(t, 1, sm.calls)
+}
+
+func TestCordonAndDrain_PostCordonDelayBeforeFirstEviction(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset(
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}},
+		&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "mypod", Namespace: "default", OwnerReferences: controlledBy("ReplicaSet", "web")},
+			Spec:       v1.PodSpec{NodeName: "node1"},
+		},
+	)
+
+	var events []string
+	client.Fake.PrependReactor("create", "pods/eviction", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		events = append(events, "evict")
+		return true, nil, nil
+	})
+
+	r := controller.NewReconciler(&config.Config{PostCordonDelaySeconds: 7}, client, nil,
+		controller.WithSleeper(func(_ context.Context, d time.Duration) error {
+			events = append(events, "sleep:"+d.String())
+			return nil
+		}),
+	)
+	wrapped := nodeops.NewNodeWrapper(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}},
+		r.State, time.Now(), nodeops.NodeAnnotationConfig{}, nil)
+
+	require.NoError(t, r.CordonAndDrain(ctx, wrapped))
+	require.Equal(t, []string{"sleep:7s", "evict"}, events)
+}
+
+func TestCordonAndDrain_PostCordonDelayHonorsContext(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}},
+		&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "mypod", Namespace: "default", OwnerReferences: controlledBy("ReplicaSet", "web")},
+			Spec:       v1.PodSpec{NodeName: "node1"},
+		},
+	)
+	r := &controller.Reconciler{
+		Client: client,
+		Cfg:    &config.Config{PostCordonDelaySeconds: 3600},
+	}
+	wrapped := nodeops.NewNodeWrapper(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}},
+		nodeops.NewNodeStateTracker(), time.Now(), nodeops.NodeAnnotationConfig{}, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := r.CordonAndDrain(ctx, wrapped)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestCordonAndDrain_OnlyDaemonSetPodsSkipsDelay(t *testing.T) {
+	ctx := context.Background()
+	isController := true
+	client := fake.NewSimpleClientset(
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}},
+		&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "ds-pod", Namespace: "kube-system",
+				OwnerReferences: []metav1.OwnerReference{{Kind: "DaemonSet", Name: "agent", Controller: &isController}},
+			},
+			Spec: v1.PodSpec{NodeName: "node1"},
+		},
+		&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "static-pod", Namespace: "kube-system",
+				Annotations: map[string]string{"kubernetes.io/config.mirror": "hash"},
+			},
+			Spec: v1.PodSpec{NodeName: "node1"},
+		},
+	)
+
+	slept := 0
+	r := controller.NewReconciler(&config.Config{PostCordonDelaySeconds: 3600}, client, nil,
+		controller.WithSleeper(func(context.Context, time.Duration) error {
+			slept++
+			return nil
+		}),
+	)
+	wrapped := nodeops.NewNodeWrapper(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}},
+		r.State, time.Now(), nodeops.NodeAnnotationConfig{}, nil)
+
+	require.NoError(t, r.CordonAndDrain(ctx, wrapped))
+	require.Zero(t, slept, "a node with only DaemonSet/mirror pods must not wait")
+}
+
+func TestCordonAndDrain_BarePods(t *testing.T) {
+	tests := []struct {
+		name        string
+		allowBare   bool
+		wantErr     error
+		wantDeleted bool
+	}{
+		{name: "bare pod blocks the node by default", allowBare: false, wantErr: controller.ErrBarePods},
+		{name: "drainAllowBarePods deletes it", allowBare: true, wantDeleted: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}}
+			client := fake.NewSimpleClientset(node,
+				&v1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: "bare", Namespace: "default"},
+					Spec:       v1.PodSpec{NodeName: "node1"},
+				},
+				&v1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default", OwnerReferences: controlledBy("ReplicaSet", "web")},
+					Spec:       v1.PodSpec{NodeName: "node1"},
+				},
+			)
+			var evicted []string
+			client.Fake.PrependReactor("create", "pods/eviction", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				evicted = append(evicted, action.(k8stesting.CreateAction).GetObject().(*policyv1.Eviction).Name)
+				return true, nil, nil
+			})
+
+			r := &controller.Reconciler{Client: client, Cfg: &config.Config{DrainAllowBarePods: tt.allowBare}}
+			wrapped := nodeops.NewNodeWrapper(node, nodeops.NewNodeStateTracker(), time.Now(), nodeops.NodeAnnotationConfig{}, nil)
+
+			err := r.CordonAndDrain(ctx, wrapped)
+			updated, getErr := client.CoreV1().Nodes().Get(ctx, "node1", metav1.GetOptions{})
+			require.NoError(t, getErr)
+			_, podErr := client.CoreV1().Pods("default").Get(ctx, "bare", metav1.GetOptions{})
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				require.False(t, updated.Spec.Unschedulable, "a blocked node must not be cordoned")
+				require.Empty(t, evicted)
+				require.NoError(t, podErr, "the bare pod must be left alone")
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, updated.Spec.Unschedulable)
+			require.Equal(t, []string{"web-1"}, evicted, "controller-owned pods are still evicted")
+			require.True(t, apierrors.IsNotFound(podErr), "the bare pod must be deleted, got %v", podErr)
+		})
+	}
+}
+
+func TestCordonAndDrain_MaxConcurrentEvictionsPerNode(t *testing.T) {
+	const limit = 2
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}}
+	objs := []runtime.Object{node}
+	for i := 0; i < 7; i++ {
+		objs = append(objs, &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: fmt.Sprintf("web-%d", i), Namespace: "default",
+				UID: types.UID(fmt.Sprintf("uid-%d", i)), OwnerReferences: controlledBy("ReplicaSet", "web"),
+			},
+			Spec: v1.PodSpec{NodeName: "node1"},
+		})
+	}
+	client := fake.NewSimpleClientset(objs...)
+
+	// Evicted pods linger until a simulated kubelet removes them one at a time, so an eviction
+	// stays in flight until its pod is gone.
+	var (
+		mu                 sync.Mutex
+		pending            []*policyv1.Eviction
+		maxFlight, removed int
+	)
+	client.Fake.PrependReactor("create", "pods/eviction", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		pending = append(pending, action.(k8stesting.CreateAction).GetObject().(*policyv1.Eviction))
+		maxFlight = max(maxFlight, len(pending))
+		return true, nil, nil
+	})
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-time.After(5 * time.Millisecond):
+			}
+			mu.Lock()
+			if len(pending) > 0 {
+				ev := pending[0]
+				pending = pending[1:]
+				removed++
+				_ = client.Tracker().Delete(v1.SchemeGroupVersion.WithResource("pods"), ev.Namespace, ev.Name)
+			}
+			mu.Unlock()
+		}
+	}()
+
+	r := controller.NewReconciler(&config.Config{MaxConcurrentEvictionsPerNode: limit}, client, nil,
+		controller.WithSleeper(func(ctx context.Context, _ time.Duration) error {
+			time.Sleep(time.Millisecond)
+			return ctx.Err()
+		}),
+	)
+	wrapped := nodeops.NewNodeWrapper(node, r.State, time.Now(), nodeops.NodeAnnotationConfig{}, nil)
+
+	require.NoError(t, r.CordonAndDrain(context.Background(), wrapped))
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(t, 7, removed, "drain returns only once every evicted pod is gone")
+	require.Equal(t, limit, maxFlight, "never more than the configured number of evictions in flight")
+}
+
+func TestCordonAndDrain_DrainGraceAnnotationOverridesConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		wantGrace   int64
+	}{
+		{"config default", nil, 30},
+		{"annotation overrides", map[string]string{nodeops.AnnotationDrainGrace: "5s"}, 5},
+		{"invalid annotation falls back", map[string]string{nodeops.AnnotationDrainGrace: "soon"}, 30},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1", Annotations: tt.annotations}}
+			client := fake.NewSimpleClientset(node, &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "mypod", Namespace: "default", OwnerReferences: controlledBy("ReplicaSet", "web")},
+				Spec:       v1.PodSpec{NodeName: "node1"},
+			})
+			var grace *int64
+			client.Fake.PrependReactor("create", "pods/eviction", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				grace = action.(k8stesting.CreateAction).GetObject().(*policyv1.Eviction).DeleteOptions.GracePeriodSeconds
+				return true, nil, nil
+			})
+
+			r := &controller.Reconciler{Client: client, Cfg: &config.Config{DrainGracePeriod: 30 * time.Second}}
+			wrapped := nodeops.NewNodeWrapper(node, nodeops.NewNodeStateTracker(), time.Now(), nodeops.NodeAnnotationConfig{}, nil)
+
+			require.NoError(t, r.CordonAndDrain(context.Background(), wrapped))
+			require.NotNil(t, grace)
+			require.Equal(t, tt.wantGrace, *grace)
+		})
+	}
+}
+
+func TestCordonAndDrain_DrainTimeoutAnnotationOverridesConfig(t *testing.T) {
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name:        "node1",
+		Annotations: map[string]string{nodeops.AnnotationDrainTimeout: "50ms"},
+	}}
+	client := fake.NewSimpleClientset(node, &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "mypod", Namespace: "default", OwnerReferences: controlledBy("ReplicaSet", "web")},
+		Spec:       v1.PodSpec{NodeName: "node1"},
+	})
+	r := &controller.Reconciler{
+		Client: client,
+		Cfg:    &config.Config{DrainTimeout: time.Hour, PostCordonDelaySeconds: 3600},
+	}
+	wrapped := nodeops.NewNodeWrapper(node, nodeops.NewNodeStateTracker(), time.Now(), nodeops.NodeAnnotationConfig{}, nil)
+
+	start := time.Now()
+	err := r.CordonAndDrain(context.Background(), wrapped)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Less(t, time.Since(start), 5*time.Second, "the node's 50ms drain timeout must win over the 1h default")
+}
+
+func TestDryRunPower_KubernetesActionsHappenButPowerCallsAreSkipped(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset(
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "keep"}},
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "idle"}},
+		&v1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "off",
+				Annotations: map[string]string{
+					nodeops.AnnotationPoweredOff: time.Now().UTC().Format(time.RFC3339),
+					nodeops.AnnotationMACAuto:    "00:11:22:33:44:55",
+				},
+			},
+			Spec: v1.NodeSpec{Unschedulable: true},
+		},
+	)
+	state := nodeops.NewNodeStateTracker()
+	sm := &shutdownMock{}
+	powerOn := &mockPowerOnController{}
+	r := &controller.Reconciler{
+		Client:            client,
+		Cfg:               &config.Config{DryRunPower: true},
+		State:             state,
+		Shutdowner:        sm,
+		PowerOner:         powerOn,
+		ScaleDownStrategy: &alwaysAllowStrategy{candidate: "idle"},
+		ScaleUpStrategy:   &fixedScaleUpStrategy{node: "off"},
+	}
+
+	eligible := nodeops.WrapNodes([]v1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "keep"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "idle"}},
+	}, state, time.Now(), nodeops.NodeAnnotationConfig{}, nil)
+	require.True(t, r.MaybeScaleDown(ctx, eligible))
+	require.Equal(t, 0, sm.calls, "physical shutdown must be simulated")
+
+	idle, err := client.CoreV1().Nodes().Get(ctx, "idle", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.True(t, idle.Spec.Unschedulable, "cordon must be real")
+	require.Contains(t, idle.Annotations, nodeops.AnnotationPoweredOff)
+
+	require.True(t, r.MaybeScaleUp(ctx))
+	require.Empty(t, powerOn.PoweredOn, "physical power-on must be simulated")
+
+	off, err := client.CoreV1().Nodes().Get(ctx, "off", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.False(t, off.Spec.Unschedulable, "uncordon must be real")
+	require.NotContains(t, off.Annotations, nodeops.AnnotationPoweredOff)
+}
+
+func TestDryRunPower_SimulatedPowerOffSurvivesNextLoop(t *testing.T) {
+	ctx := context.Background()
+	hourAgo := time.Now().Add(-time.Hour)
+	client := fake.NewSimpleClientset(runningNode("a", hourAgo, nil), runningNode("b", hourAgo, nil))
+	sim := &bootSimulator{client: client}
+	r := &controller.Reconciler{
+		Client: client,
+		Cfg: &config.Config{
+			MinNodes:    1,
+			NodeLabels:  config.NodeLabelConfig{Managed: "cba.dev/is-managed"},
+			DryRunPower: true,
+		},
+		State:             nodeops.NewNodeStateTracker(),
+		Shutdowner:        sim,
+		PowerOner:         sim,
+		ScaleDownStrategy: approveAllStrategy{},
+		ScaleUpStrategy:   &mockScaleUpStrategy{},
+	}
+
+	cordoned := func() []string {
+		list, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+		require.NoError(t, err)
+		var out []string
+		for _, n := range list.Items {
+			if _, off := n.Annotations[nodeops.AnnotationPoweredOff]; off && n.Spec.Unschedulable {
+				out = append(out, n.Name)
+			}
+		}
+		return out
+	}
+
+	require.NoError(t, r.Reconcile(ctx))
+	first := cordoned()
+	require.Len(t, first, 1)
+	require.Empty(t, sim.ShutDown, "physical shutdown must be simulated")
+
+	require.NoError(t, r.Reconcile(ctx))
+	require.Equal(t, first, cordoned(), "the still-Ready node must not be recovered as unexpectedly booted")
+}
+
+func TestDryRunK8s_PowerCallsHappenButKubernetesActionsAreSkipped(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset(
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "keep"}},
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "idle"}},
+	)
+	state := nodeops.NewNodeStateTracker()
+	sm := &shutdownMock{}
+	r := &controller.Reconciler{
+		Client:            client,
+		Cfg:               &config.Config{DryRunK8s: true},
+		State:             state,
+		Shutdowner:        sm,
+		ScaleDownStrategy: &alwaysAllowStrategy{candidate: "idle"},
+	}
+
+	eligible := nodeops.WrapNodes([]v1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "keep"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "idle"}},
+	}, state, time.Now(), nodeops.NodeAnnotationConfig{}, nil)
+	require.True(t, r.MaybeScaleDown(ctx, eligible))
+	require.Equal(t, 1, sm.calls)
+
+	idle, err := client.CoreV1().Nodes().Get(ctx, "idle", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.False(t, idle.Spec.Unschedulable)
+	require.NotContains(t, idle.Annotations, nodeops.AnnotationPoweredOff)
+}
+
+func TestReconcile_ScaleDownBufferStopsAboveMinNodes(t *testing.T) {
+	ctx := context.Background()
+	hourAgo := time.Now().Add(-time.Hour)
+	client := fake.NewSimpleClientset(
+		runningNode("n1", hourAgo, nil), runningNode("n2", hourAgo, nil),
+		runningNode("n3", hourAgo, nil), runningNode("n4", hourAgo, nil),
+	)
+	sim := &bootSimulator{client: client}
+	r := &controller.Reconciler{
+		Client: client,
+		Cfg: &config.Config{
+			MinNodes:        1,
+			ScaleDownBuffer: 1,
+			NodeLabels:      config.NodeLabelConfig{Managed: "cba.dev/is-managed"},
+		},
+		State:             nodeops.NewNodeStateTracker(),
+		Shutdowner:        sim,
+		PowerOner:         sim,
+		ScaleDownStrategy: approveAllStrategy{},
+		ScaleUpStrategy:   &mockScaleUpStrategy{},
+	}
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, r.Reconcile(ctx))
+	}
+	require.Len(t, sim.ShutDown, 2, "with minNodes=1 and buffer=1, scale-down must stop at 2 running nodes")
+}
+
+func TestReconcile_ScaleDownEligibleGauge(t *testing.T) {
+	ctx := context.Background()
+	hourAgo := time.Now().Add(-time.Hour)
+	client := fake.NewSimpleClientset(
+		runningNode("elig-a", hourAgo, nil), runningNode("elig-b", hourAgo, nil), runningNode("elig-booted", hourAgo, nil),
+	)
+	r := &controller.Reconciler{
+		Client: client,
+		Cfg: &config.Config{
+			BootCooldown: time.Hour,
+			NodeLabels:   config.NodeLabelConfig{Managed: "cba.dev/is-managed"},
+		},
+		State:             nodeops.NewNodeStateTracker(),
+		Shutdowner:        &shutdownMock{},
+		PowerOner:         &mockPowerOnController{},
+		ScaleDownStrategy: &MockScaleDownStrategy{}, // denies, so the eligible set stays intact
+		ScaleUpStrategy:   &mockScaleUpStrategy{},
+	}
+	r.State.MarkBooted("elig-booted")
+	metrics.ScaleDownEligible.WithLabelValues("elig-deleted").Set(1) // left over from an earlier loop
+
+	require.NoError(t, r.Reconcile(ctx))
+
+	require.Equal(t, 1.0, testutil.ToFloat64(metrics.ScaleDownEligible.WithLabelValues("elig-a")))
+	require.Equal(t, 1.0, testutil.ToFloat64(metrics.ScaleDownEligible.WithLabelValues("elig-b")))
+	require.Equal(t, 0.0, testutil.ToFloat64(metrics.ScaleDownEligible.WithLabelValues("elig-booted")))
+	require.Equal(t, 3, testutil.CollectAndCount(metrics.ScaleDownEligible), "stale series must be dropped")
+}
+
+func TestMaybeScaleDown_MaxPoweredOffCap(t *testing.T) {
+	tests := []struct {
+		name          string
+		maxPoweredOff int
+		wantShutdowns int
+	}{
+		{name: "no cap", maxPoweredOff: 0, wantShutdowns: 1},
+		{name: "below cap", maxPoweredOff: 2, wantShutdowns: 1},
+		{name: "at cap", maxPoweredOff: 1, wantShutdowns: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			hourAgo := time.Now().Add(-time.Hour)
+			client := fake.NewSimpleClientset(
+				runningNode("a", hourAgo, nil),
+				runningNode("b", hourAgo, nil),
+				poweredOffNode("off"),
+			)
+			sim := &bootSimulator{client: client}
+			r := &controller.Reconciler{
+				Client: client,
+				Cfg: &config.Config{
+					NodeLabels:    config.NodeLabelConfig{Managed: "cba.dev/is-managed"},
+					MaxPoweredOff: tt.maxPoweredOff,
+				},
+				State:             nodeops.NewNodeStateTracker(),
+				Shutdowner:        sim,
+				PowerOner:         sim,
+				ScaleDownStrategy: approveAllStrategy{},
+				ScaleUpStrategy:   &mockScaleUpStrategy{},
+			}
+
+			require.NoError(t, r.Reconcile(ctx))
+			require.Len(t, sim.ShutDown, tt.wantShutdowns)
+			require.Equal(t, 1.0, testutil.ToFloat64(metrics.PoweredOffCount))
+		})
+	}
+}
+
+func TestReconcile_DesiredNodeCountRespectsMaxPoweredOff(t *testing.T) {
+	ctx := context.Background()
+	hourAgo := time.Now().Add(-time.Hour)
+	client := fake.NewSimpleClientset(
+		runningNode("a", hourAgo, nil),
+		runningNode("b", hourAgo, nil),
+		poweredOffNode("off"),
+		&v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "targets", Namespace: "cba"},
+			Data:       map[string]string{"desiredNodeCount": "1"},
+		},
+	)
+	sim := &bootSimulator{client: client}
+	r := &controller.Reconciler{
+		Client: client,
+		Cfg: &config.Config{
+			NodeLabels:    config.NodeLabelConfig{Managed: "cba.dev/is-managed"},
+			MaxPoweredOff: 1,
+			DesiredNodeCountSource: config.ValueSourceConfig{
+				Type: config.ValueSourceConfigMap, Namespace: "cba", Name: "targets", Key: "desiredNodeCount",
+			},
+		},
+		State:             nodeops.NewNodeStateTracker(),
+		Shutdowner:        sim,
+		PowerOner:         sim,
+		ScaleDownStrategy: &MockScaleDownStrategy{},
+		ScaleUpStrategy:   &failingScaleUpStrategy{},
+	}
+
+	require.NoError(t, r.Reconcile(ctx))
+	require.Empty(t, sim.ShutDown, "converging down must not exceed maxPoweredOff")
+	for _, name := range []string{"a", "b"} {
+		n, err := client.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+		require.NoError(t, err)
+		require.False(t, n.Spec.Unschedulable, "node %s must not be drained for a power-off the cap forbids", name)
+	}
+}
+
+func TestReconcile_DesiredNodeCountPicksCandidateLikeScaleDown(t *testing.T) {
+	tests := []struct {
+		name     string
+		priority map[string]int
+		buffer   int
+		want     []string
+	}{
+		{name: "shutdown priority", priority: map[string]int{"hw-gen=1": 10}, want: []string{"old-gen"}},
+		{name: "buffer keeps headroom", buffer: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hourAgo := time.Now().Add(-time.Hour)
+			oldGen := runningNode("old-gen", hourAgo, nil)
+			oldGen.Labels["hw-gen"] = "1"
+			client := fake.NewSimpleClientset(
+				oldGen,
+				runningNode("b", hourAgo, nil),
+				runningNode("c", hourAgo, nil),
+				&v1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "targets", Namespace: "cba"},
+					Data:       map[string]string{"desiredNodeCount": "1"},
+				},
+			)
+			sim := &bootSimulator{client: client}
+			r := &controller.Reconciler{
+				Client: client,
+				Cfg: &config.Config{
+					NodeLabels:           config.NodeLabelConfig{Managed: "cba.dev/is-managed"},
+					NodeShutdownPriority: tt.priority,
+					ScaleDownBuffer:      tt.buffer,
+					DesiredNodeCountSource: config.ValueSourceConfig{
+						Type: config.ValueSourceConfigMap, Namespace: "cba", Name: "targets", Key: "desiredNodeCount",
+					},
+				},
+				State:             nodeops.NewNodeStateTracker(),
+				Shutdowner:        sim,
+				PowerOner:         sim,
+				ScaleDownStrategy: &MockScaleDownStrategy{},
+				ScaleUpStrategy:   &failingScaleUpStrategy{},
+			}
+
+			require.NoError(t, r.Reconcile(context.Background()))
+			require.Equal(t, tt.want, sim.ShutDown)
+		})
+	}
+}
+
+func TestMaybeScaleDown_SkipsScaleDownDisabledNode(t *testing.T) {
+	ctx := context.Background()
+	hourAgo := time.Now().Add(-time.Hour)
+	client := fake.NewSimpleClientset(
+		runningNode("a", hourAgo, map[string]string{config.DefaultScaleDownDisabledAnnotation: "true"}),
+		runningNode("b", hourAgo, nil),
+	)
+	sim := &bootSimulator{client: client}
+	cfg := &config.Config{NodeLabels: config.NodeLabelConfig{Managed: "cba.dev/is-managed"}}
+	require.NoError(t, cfg.ApplyDefaultsAndValidate())
+	r := &controller.Reconciler{
+		Client:            client,
+		Cfg:               cfg,
+		State:             nodeops.NewNodeStateTracker(),
+		Shutdowner:        sim,
+		PowerOner:         sim,
+		ScaleDownStrategy: approveAllStrategy{},
+		ScaleUpStrategy:   &mockScaleUpStrategy{},
+	}
+
+	require.NoError(t, r.Reconcile(ctx))
+	require.Equal(t, []string{"b"}, sim.ShutDown)
+
+	require.NoError(t, r.Reconcile(ctx))
+	require.Equal(t, []string{"b"}, sim.ShutDown, "annotated node must never be picked")
+}
+
+func TestMaybeScaleDown_AbsoluteMinNodesFloor(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	notReady := runningNode("not-ready", now.Add(-time.Hour), nil)
+	notReady.Status.Conditions[0].Status = v1.ConditionFalse
+	cordoned := runningNode("cordoned", now.Add(-time.Hour), nil)
+	cordoned.Spec.Unschedulable = true
+
+	cases := []struct {
+		name  string
+		nodes []*v1.Node
+		floor int
+		want  bool
+	}{
+		{"disabled", []*v1.Node{runningNode("a", now, nil), runningNode("idle", now, nil)}, 0, true},
+		{"ready equals floor", []*v1.Node{runningNode("a", now, nil), runningNode("idle", now, nil)}, 2, false},
+		{"ready one above floor", []*v1.Node{runningNode("a", now, nil), runningNode("b", now, nil), runningNode("idle", now, nil)}, 2, false},
+		{"ready two above floor", []*v1.Node{runningNode("a", now, nil), runningNode("b", now, nil), runningNode("c", now, nil), runningNode("idle", now, nil)}, 2, true},
+		{"not-ready and cordoned nodes do not count", []*v1.Node{runningNode("a", now, nil), runningNode("idle", now, nil), notReady, cordoned}, 2, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var objs []runtime.Object
+			var listed []v1.Node
+			for _, n := range tc.nodes {
+				objs = append(objs, n.DeepCopy())
+				listed = append(listed, *n)
+			}
+			client := fake.NewSimpleClientset(objs...)
+			state := nodeops.NewNodeStateTracker()
+			sm := &shutdownMock{}
+			r := &controller.Reconciler{
+				Client: client,
+				Cfg: &config.Config{
+					AbsoluteMinNodes: tc.floor,
+					NodeLabels:       config.NodeLabelConfig{Managed: "cba.dev/is-managed"},
+				},
+				State:             state,
+				Shutdowner:        sm,
+				ScaleDownStrategy: &alwaysAllowStrategy{candidate: "idle"},
+			}
+			// Every node is offered as eligible, so only the floor can hold scale-down back.
+			eligible := nodeops.WrapNodes(listed, state, now, nodeops.NodeAnnotationConfig{}, nil)
+			require.Equal(t, tc.want, r.MaybeScaleDown(ctx, eligible))
+			if !tc.want {
+				require.Zero(t, sm.calls)
+			}
+		})
+	}
+}
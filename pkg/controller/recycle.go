@@ -0,0 +1,143 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
+)
+
+// MaybeRecycle power-cycles nodes that have been running longer than recycle.maxOnDuration.
+// Like rotation it works in two phases across loops:
+//   - Find the longest-running overdue node. If a powered-off node is available, boot it as a
+//     replacement first, mark the overdue node with cba.dev/recycle-pending and RETURN.
+//   - In a later loop, once the replacement counts as active and minNodes still holds without
+//     the pending node, drain and power it off.
+//
+// Without a spare node the overdue node is retired directly, but only when capacity allows
+// (eligible > minNodes) and the scale-down strategy chain approves.
+// Returns true if an action was taken.
+func (r *Reconciler) MaybeRecycle(ctx context.Context) bool {
+	if r.Cfg == nil || r.Cfg.Recycle.MaxOnDuration <= 0 {
+		return false
+	}
+	ctx, span := r.startSpan(withAction(ctx, ActionRecycle), "MaybeRecycle", attrAction.String("recycle"))
+	defer span.End()
+
+	allNodes, err := r.listAllNodes(ctx)
+	if err != nil {
+		return false
+	}
+	eligible := r.filterEligibleNodes(allNodes.Items)
+	now := time.Now()
+	overdue, since, pending := r.recycleTarget(eligible, now)
+
+	// Phase 2: retire a node whose replacement was already booted.
+	if pending {
+		if !r.recycleReplacementUp(ctx, overdue) {
+			setReason(ctx, "waiting for replacement")
+			return false
+		}
+		slog.Info("MaybeRecycle: retiring node after replacement is up", "node", overdue.Name)
+		return r.scaleDownNode(ctx, overdue)
+	}
+
+	// Phase 1: the longest-running overdue node.
+	if overdue == nil {
+		return false
+	}
+	span.SetAttributes(attrNode.String(overdue.Name))
+
+	if overdue.IsObserveOnly() {
+		slog.Info("MaybeRecycle: observe-only node is overdue but not acted upon", "node", overdue.Name)
+		setReason(ctx, "observe-only")
+		return false
+	}
+
+	slog.Info("MaybeRecycle: node exceeded maxOnDuration",
+		"node", overdue.Name, "runningFor", now.Sub(since).Round(time.Second).String(),
+		"maxOnDuration", r.Cfg.Recycle.MaxOnDuration.String())
+
+	if spares := r.ScaleUpCandidates(ctx); len(spares) > 0 {
+		if !r.scaleUpNode(ctx, spares[0]) {
+			return false
+		}
+		if err := r.markRecyclePending(ctx, overdue); err != nil {
+			slog.Warn("MaybeRecycle: failed to mark node for recycle", "node", overdue.Name, "err", err)
+		}
+		setReason(ctx, "replacement booted")
+		return true
+	}
+
+	if reason := r.recycleRetireBlockReason(ctx, eligible, overdue); reason != "" {
+		setReason(ctx, reason)
+		return false
+	}
+	return r.scaleDownNode(ctx, overdue)
+}
+
+// recycleRetireBlockReason returns why overdue cannot be retired without a replacement, or "".
+func (r *Reconciler) recycleRetireBlockReason(ctx context.Context, eligible []*nodeops.NodeWrapper, overdue *nodeops.NodeWrapper) string {
+	if len(eligible) <= r.MinNodes() {
+		slog.Info("MaybeRecycle: skip — no spare node and no capacity headroom",
+			"node", overdue.Name, "eligible", len(eligible), "minNodes", r.MinNodes())
+		return "capacity"
+	}
+	ok, err := r.ScaleDownStrategy.ShouldScaleDown(ctx, overdue.Name)
+	if err != nil || !ok {
+		slog.Info("MaybeRecycle: skip — scale-down strategy denied retiring node", "node", overdue.Name, "err", err)
+		return "strategy denied"
+	}
+	return ""
+}
+
+// recycleTarget returns the node MaybeRecycle acts on next: a recycle-pending node (pending is
+// true), else the longest-running node past maxOnDuration and when it started, or nil.
+func (r *Reconciler) recycleTarget(eligible []*nodeops.NodeWrapper, now time.Time) (*nodeops.NodeWrapper, time.Time, bool) {
+	for _, n := range eligible {
+		if _, pending := n.Annotations[nodeops.AnnotationRecyclePending]; pending {
+			return n, time.Time{}, true
+		}
+	}
+	var (
+		overdue *nodeops.NodeWrapper
+		since   time.Time
+	)
+	for _, n := range eligible {
+		started, ok := nodeops.RunningSince(*n.Node)
+		if !ok || now.Sub(started) < r.Cfg.Recycle.MaxOnDuration {
+			continue
+		}
+		if overdue == nil || started.Before(since) {
+			overdue, since = n, started
+		}
+	}
+	return overdue, since, false
+}
+
+// recycleReplacementUp reports whether minNodes still holds once the recycle-pending node is retired.
+func (r *Reconciler) recycleReplacementUp(ctx context.Context, pending *nodeops.NodeWrapper) bool {
+	active, err := r.listActiveNodes(ctx)
+	if err != nil {
+		slog.Warn("MaybeRecycle: listing active nodes failed", "err", err)
+		return false
+	}
+	if len(active)-1 < r.MinNodes() {
+		slog.Info("MaybeRecycle: waiting for replacement before retiring node",
+			"node", pending.Name, "active", len(active), "minNodes", r.MinNodes())
+		return false
+	}
+	return true
+}
+
+func (r *Reconciler) markRecyclePending(ctx context.Context, node *nodeops.NodeWrapper) error {
+	if r.Cfg.IsK8sDryRun() {
+		slog.Info("Dry-run: would mark node for recycle", "node", node.Name)
+		return nil
+	}
+	timestamp := time.Now().UTC().Format(time.RFC3339)
+	return nodeops.PatchAnnotations(ctx, r.Client, node.Name, map[string]*string{
+		nodeops.AnnotationRecyclePending: &timestamp,
+	})
+}
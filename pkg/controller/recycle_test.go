@@ -0,0 +1,101 @@
+package controller_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/controller"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func runningNode(name string, readySince time.Time, annotations map[string]string) *v1.Node {
+	return &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Labels:      map[string]string{"cba.dev/is-managed": "true"},
+			Annotations: annotations,
+		},
+		Status: v1.NodeStatus{Conditions: []v1.NodeCondition{{
+			Type: v1.NodeReady, Status: v1.ConditionTrue, LastTransitionTime: metav1.NewTime(readySince),
+		}}},
+	}
+}
+
+func newRecycleReconciler(client *fake.Clientset, sim *bootSimulator, minNodes int) *controller.Reconciler {
+	return newSimReconciler(client, sim, &config.Config{
+		MinNodes: minNodes,
+		Recycle:  config.RecycleConfig{MaxOnDuration: 24 * time.Hour},
+	})
+}
+
+func TestMaybeRecycle_BootsReplacementThenRetiresOverdueNode(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	var objs []runtime.Object
+	objs = append(objs,
+		runningNode("old", now.Add(-48*time.Hour), nil),
+		runningNode("fresh", now.Add(-48*time.Hour), map[string]string{
+			nodeops.AnnotationBootedAt: now.Add(-time.Hour).UTC().Format(time.RFC3339),
+		}),
+		&v1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "spare",
+				Labels: map[string]string{"cba.dev/is-managed": "true"},
+				Annotations: map[string]string{
+					nodeops.AnnotationPoweredOff: now.Add(-time.Hour).UTC().Format(time.RFC3339),
+					nodeops.AnnotationMACAuto:    "00:11:22:33:44:55",
+				},
+			},
+			Spec: v1.NodeSpec{Unschedulable: true},
+		},
+	)
+	client := fake.NewSimpleClientset(objs...)
+	sim := &bootSimulator{client: client}
+	r := newRecycleReconciler(client, sim, 2)
+
+	// Loop 1: the replacement is booted first; "fresh" (booted-at 1h ago) is not overdue.
+	require.True(t, r.MaybeRecycle(ctx))
+	require.Equal(t, []string{"spare"}, sim.PoweredOn)
+	require.Empty(t, sim.ShutDown)
+
+	old, err := client.CoreV1().Nodes().Get(ctx, "old", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Contains(t, old.Annotations, nodeops.AnnotationRecyclePending)
+
+	spare, err := client.CoreV1().Nodes().Get(ctx, "spare", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Contains(t, spare.Annotations, nodeops.AnnotationBootedAt)
+	require.NotContains(t, spare.Annotations, nodeops.AnnotationPoweredOff)
+
+	// Loop 2: with the replacement active, the overdue node is drained and powered off.
+	require.True(t, r.MaybeRecycle(ctx))
+	require.Equal(t, []string{"old"}, sim.ShutDown)
+	require.Len(t, sim.PoweredOn, 1)
+}
+
+func TestMaybeRecycle_RespectsCapacityWithoutSpare(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	client := fake.NewSimpleClientset(
+		runningNode("old", now.Add(-48*time.Hour), nil),
+		runningNode("other", now.Add(-time.Hour), nil),
+	)
+	sim := &bootSimulator{client: client}
+
+	// Two nodes, minNodes 2: no headroom to retire the overdue node.
+	r := newRecycleReconciler(client, sim, 2)
+	require.False(t, r.MaybeRecycle(ctx))
+	require.Empty(t, sim.ShutDown)
+
+	// With headroom the overdue node is retired directly.
+	r = newRecycleReconciler(client, sim, 1)
+	require.True(t, r.MaybeRecycle(ctx))
+	require.Equal(t, []string{"old"}, sim.ShutDown)
+}
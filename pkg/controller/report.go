@@ -0,0 +1,245 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
+)
+
+// Report is the periodic digest of what CBA did over one reporting window.
+type Report struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+
+	PowerOns  int `json:"powerOns"`
+	PowerOffs int `json:"powerOffs"`
+	// NodesCycled lists every node that was powered on or off in the window.
+	NodesCycled []string `json:"nodesCycled"`
+	// Actions counts power actions by category (scale-up, scale-down, rotate, recycle, ...).
+	Actions         map[string]int `json:"actions"`
+	RotationActions int            `json:"rotationActions"`
+
+	// PoweredOffHours sums, over all nodes, the time spent powered off in the window.
+	PoweredOffHours float64 `json:"poweredOffHours"`
+	// EstimatedSavingsKWh uses each node's last power-draw reading, or report.nodeWatts.
+	EstimatedSavingsKWh float64 `json:"estimatedSavingsKWh"`
+
+	// Blocked lists nodes CBA cordoned but left powered on for longer than report.blockedAfter.
+	Blocked []string `json:"blocked"`
+}
+
+// reportTally accumulates report data between digests. The zero value starts a window on first use.
+type reportTally struct {
+	mu         sync.Mutex
+	from       time.Time
+	powerOns   int
+	powerOffs  int
+	cycled     map[string]struct{}
+	actions    map[string]int
+	offSeconds map[string]float64
+	lastSample time.Time
+	lastOff    map[string]struct{}
+	lastWatts  map[string]float64
+}
+
+func (t *reportTally) recordPowerAction(category, node string, on bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if on {
+		t.powerOns++
+	} else {
+		t.powerOffs++
+	}
+	if t.cycled == nil {
+		t.cycled = map[string]struct{}{}
+		t.actions = map[string]int{}
+	}
+	t.cycled[node] = struct{}{}
+	t.actions[category]++
+}
+
+func (t *reportTally) recordWatts(node string, watts float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.lastWatts == nil {
+		t.lastWatts = map[string]float64{}
+	}
+	t.lastWatts[node] = watts
+}
+
+// sample credits the nodes seen powered off at the previous sample with the time since then.
+func (t *reportTally) sample(now time.Time, off map[string]struct{}) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.from.IsZero() {
+		t.from = now
+	}
+	if !t.lastSample.IsZero() && now.After(t.lastSample) {
+		elapsed := now.Sub(t.lastSample).Seconds()
+		if t.offSeconds == nil {
+			t.offSeconds = map[string]float64{}
+		}
+		for node := range t.lastOff {
+			t.offSeconds[node] += elapsed
+		}
+	}
+	t.lastSample = now
+	t.lastOff = off
+}
+
+// ObserveReport samples which nodes are powered off, for the off-hours and savings totals. It runs
+// each reconcile loop while reporting is enabled, and from the reporter goroutine.
+func (r *Reconciler) ObserveReport(ctx context.Context, now time.Time) {
+	allNodes, err := r.listAllNodesWith(ctx, r.config())
+	if err != nil {
+		return
+	}
+	off := map[string]struct{}{}
+	for _, n := range allNodes.Items {
+		if r.isPoweredOff(n) {
+			off[n.Name] = struct{}{}
+		}
+	}
+	r.report.sample(now, off)
+}
+
+// BuildReport closes the current window at now, returns its digest and starts the next window.
+func (r *Reconciler) BuildReport(ctx context.Context, now time.Time) Report {
+	cfg := r.config() // runs outside loopMu
+	r.ObserveReport(ctx, now)
+
+	t := &r.report
+	t.mu.Lock()
+	rep := Report{
+		From:      t.from,
+		To:        now,
+		PowerOns:  t.powerOns,
+		PowerOffs: t.powerOffs,
+		Actions:   map[string]int{},
+	}
+	for node := range t.cycled {
+		rep.NodesCycled = append(rep.NodesCycled, node)
+	}
+	for category, n := range t.actions {
+		rep.Actions[category] = n
+	}
+	rep.RotationActions = t.actions[ActionRotate]
+	var offSeconds, wattSeconds float64
+	for node, secs := range t.offSeconds {
+		offSeconds += secs
+		watts, ok := t.lastWatts[node]
+		if !ok {
+			watts = cfg.Report.NodeWatts
+		}
+		wattSeconds += secs * watts
+	}
+	rep.PoweredOffHours = offSeconds / 3600
+	rep.EstimatedSavingsKWh = wattSeconds / 3600 / 1000
+
+	t.from = now
+	t.powerOns, t.powerOffs = 0, 0
+	t.cycled, t.actions, t.offSeconds = nil, nil, nil
+	t.mu.Unlock()
+
+	sort.Strings(rep.NodesCycled)
+	rep.Blocked = r.blockedNodes(ctx, cfg, now)
+	return rep
+}
+
+// blockedNodes returns nodes CBA cordoned at least report.blockedAfter ago that are still powered on.
+func (r *Reconciler) blockedNodes(ctx context.Context, cfg *config.Config, now time.Time) []string {
+	allNodes, err := r.listAllNodesWith(ctx, cfg)
+	if err != nil {
+		return nil
+	}
+	var out []string
+	for _, n := range allNodes.Items {
+		raw, ok := n.Annotations[nodeops.AnnotationCordonedAt]
+		if !ok || !nodeops.IsCordoned(&n) || r.isPoweredOff(n) {
+			continue
+		}
+		cordonedAt, err := time.Parse(time.RFC3339, raw)
+		if err != nil || now.Sub(cordonedAt) < cfg.Report.BlockedAfter {
+			continue
+		}
+		out = append(out, fmt.Sprintf("%s: cordoned for %s", n.Name, now.Sub(cordonedAt).Round(time.Minute)))
+	}
+	return out
+}
+
+// StartReporter emits a digest every interval until ctx is done. It is a no-op when interval is not positive.
+func (r *Reconciler) StartReporter(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	r.report.sample(time.Now(), nil)
+	go func() {
+		slog.Info("Summary reporter started", "interval", interval.String())
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				r.EmitReport(ctx, r.BuildReport(ctx, now))
+			}
+		}
+	}()
+}
+
+// EmitReport logs rep and, when report.webhookURL is set, POSTs it there as JSON.
+func (r *Reconciler) EmitReport(ctx context.Context, rep Report) {
+	slog.Info("Summary report",
+		"from", rep.From.UTC().Format(time.RFC3339), "to", rep.To.UTC().Format(time.RFC3339),
+		"powerOns", rep.PowerOns, "powerOffs", rep.PowerOffs, "nodesCycled", rep.NodesCycled,
+		"actions", rep.Actions, "rotationActions", rep.RotationActions,
+		"poweredOffHours", rep.PoweredOffHours, "estimatedSavingsKWh", rep.EstimatedSavingsKWh,
+		"blocked", rep.Blocked)
+
+	cfg := r.config()
+	if cfg.Report.WebhookURL == "" {
+		return
+	}
+	if err := postReport(ctx, cfg.Report.WebhookURL, cfg.Report.TimeoutSeconds, rep); err != nil {
+		slog.Warn("Summary report webhook failed", "err", err)
+	}
+}
+
+func postReport(ctx context.Context, url string, timeoutSeconds int, rep Report) error {
+	timeout := defaultApprovalWebhookTimeout
+	if timeoutSeconds > 0 {
+		timeout = time.Duration(timeoutSeconds) * time.Second
+	}
+	reqCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	payload, err := json.Marshal(rep)
+	if err != nil {
+		return fmt.Errorf("encoding report: %w", err)
+	}
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("creating report request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("calling report webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package controller_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/controller"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestBuildReport_SimulatedDay(t *testing.T) {
+	ctx := context.Background()
+	r, sim, client := newActionGuardFixture(t)
+	r.ScaleUpStrategy = &mockScaleUpStrategy{}
+	r.Cfg.Report.NodeWatts = 200
+	r.Cfg.Report.BlockedAfter = time.Hour
+	r.Cfg.BootCooldown = time.Hour // keep the rotated node from being retired right away
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	// 00:00 "off" is powered off; rotation boots it.
+	r.ObserveReport(ctx, day)
+	r.MaybeRotate(ctx)
+	require.Equal(t, []string{"off"}, sim.PoweredOn)
+
+	// 06:00 all nodes up; a normal loop retires one node.
+	r.ObserveReport(ctx, day.Add(6*time.Hour))
+	require.NoError(t, r.Reconcile(ctx))
+	require.Len(t, sim.ShutDown, 1)
+	retired := sim.ShutDown[0]
+	require.NotEqual(t, "off", retired)
+
+	// 12:00 the retired node is off for the rest of the day.
+	r.ObserveReport(ctx, day.Add(12*time.Hour))
+
+	// A node CBA cordoned at 20:00 and never powered off.
+	stuck := runningNode("stuck", day, map[string]string{
+		nodeops.AnnotationCordonedAt: day.Add(20 * time.Hour).Format(time.RFC3339),
+	})
+	stuck.Spec.Unschedulable = true
+	_, err := client.CoreV1().Nodes().Create(ctx, stuck, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	rep := r.BuildReport(ctx, day.Add(24*time.Hour))
+
+	require.Equal(t, day, rep.From)
+	require.Equal(t, day.Add(24*time.Hour), rep.To)
+	require.Equal(t, 1, rep.PowerOns)
+	require.Equal(t, 1, rep.PowerOffs)
+	require.ElementsMatch(t, []string{"off", retired}, rep.NodesCycled)
+	require.Equal(t, map[string]int{controller.ActionRotate: 1, controller.ActionScaleDown: 1}, rep.Actions)
+	require.Equal(t, 1, rep.RotationActions)
+	require.InDelta(t, 18, rep.PoweredOffHours, 1e-9, "6h for the rotated node + 12h for the retired one")
+	require.InDelta(t, 3.6, rep.EstimatedSavingsKWh, 1e-9, "18h at 200W")
+	require.Equal(t, []string{"stuck: cordoned for 4h0m0s"}, rep.Blocked)
+
+	// The next window starts empty.
+	next := r.BuildReport(ctx, day.Add(25*time.Hour))
+	require.Equal(t, day.Add(24*time.Hour), next.From)
+	require.Zero(t, next.PowerOns+next.PowerOffs)
+	require.Empty(t, next.NodesCycled)
+	require.InDelta(t, 1, next.PoweredOffHours, 1e-9)
+}
@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"slices"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+)
+
+// breakRotationTie picks one of the overdue nodes that were powered off at the same instant,
+// following rotation.tieBreak. tied must not be empty.
+func (r *Reconciler) breakRotationTie(tied []*v1.Node) *v1.Node {
+	if len(tied) == 1 {
+		return tied[0]
+	}
+	byName := func(a, b *v1.Node) int { return strings.Compare(a.Name, b.Name) }
+	order := byName
+
+	switch r.Cfg.Rotation.TieBreak {
+	case config.RotationTieBreakLeastRecentlyRotated:
+		order = func(a, b *v1.Node) int {
+			// Never-rotated nodes have a zero time and sort first.
+			ta, _ := r.State.LastRotated(a.Name)
+			tb, _ := r.State.LastRotated(b.Name)
+			if c := ta.Compare(tb); c != 0 {
+				return c
+			}
+			return byName(a, b)
+		}
+	case config.RotationTieBreakRandom:
+		seed := r.Cfg.Rotation.TieBreakSeed
+		order = func(a, b *v1.Node) int {
+			ka, kb := seededKey(seed, a.Name), seededKey(seed, b.Name)
+			switch {
+			case ka < kb:
+				return -1
+			case ka > kb:
+				return 1
+			}
+			return byName(a, b)
+		}
+	}
+
+	sorted := slices.Clone(tied)
+	slices.SortFunc(sorted, order)
+	return sorted[0]
+}
+
+// seededKey hashes name with seed, giving each seed its own stable shuffle of node names.
+func seededKey(seed int64, name string) uint64 {
+	h := fnv.New64a()
+	_ = binary.Write(h, binary.LittleEndian, seed)
+	h.Write([]byte(name))
+	return h.Sum64()
+}
@@ -0,0 +1,137 @@
+package controller_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/controller"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func offNodeWithBoot(name string, offFor time.Duration, boot string) *v1.Node {
+	ann := map[string]string{nodeops.AnnotationPoweredOff: time.Now().Add(-offFor).UTC().Format(time.RFC3339)}
+	if boot != "" {
+		ann[nodeops.AnnotationBootDuration] = boot
+	}
+	return &v1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name:        name,
+		Labels:      map[string]string{"cba.dev/is-managed": "true"},
+		Annotations: ann,
+	}}
+}
+
+func TestScaleUpCandidates_Policy(t *testing.T) {
+	tests := []struct {
+		policy     string
+		preference string
+		want       []string
+	}{
+		{config.ScaleUpPolicyLongestOff, config.ScaleUpPreferenceOldest, []string{"unrecorded", "slow", "mid", "fast"}},
+		{config.ScaleUpPolicyLongestOff, config.ScaleUpPreferenceNewest, []string{"fast", "mid", "slow", "unrecorded"}},
+		{config.ScaleUpPolicyFastestBoot, config.ScaleUpPreferenceOldest, []string{"fast", "mid", "slow", "unrecorded"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy+"/"+tt.preference, func(t *testing.T) {
+			client := fake.NewSimpleClientset(
+				offNodeWithBoot("unrecorded", 5*time.Hour, ""),
+				offNodeWithBoot("slow", 4*time.Hour, "5m0s"),
+				offNodeWithBoot("mid", 3*time.Hour, "2m0s"),
+				offNodeWithBoot("fast", time.Hour, "1m30s"),
+			)
+			r := &controller.Reconciler{
+				Client: client,
+				Cfg: &config.Config{
+					NodeLabels:        config.NodeLabelConfig{Managed: "cba.dev/is-managed"},
+					ScaleUpPolicy:     tt.policy,
+					ScaleUpPreference: tt.preference,
+				},
+				State: nodeops.NewNodeStateTracker(),
+			}
+
+			require.Equal(t, tt.want, r.ScaleUpCandidates(context.Background()))
+		})
+	}
+}
+
+func TestScaleUpCandidates_NewestKeepsOffAgeForEqualBootTimes(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		offNodeWithBoot("old", 5*time.Hour, "2m0s"),
+		offNodeWithBoot("recent", time.Hour, "2m0s"),
+		offNodeWithBoot("unrecorded", 3*time.Hour, ""),
+	)
+	r := &controller.Reconciler{
+		Client: client,
+		Cfg: &config.Config{
+			NodeLabels:        config.NodeLabelConfig{Managed: "cba.dev/is-managed"},
+			ScaleUpPolicy:     config.ScaleUpPolicyFastestBoot,
+			ScaleUpPreference: config.ScaleUpPreferenceNewest,
+		},
+		State: nodeops.NewNodeStateTracker(),
+	}
+
+	require.Equal(t, []string{"recent", "old", "unrecorded"}, r.ScaleUpCandidates(context.Background()))
+}
+
+func unschedulablePod(name, cpu string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: v1.PodSpec{Containers: []v1.Container{{
+			Resources: v1.ResourceRequirements{Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse(cpu)}},
+		}}},
+		Status: v1.PodStatus{
+			Phase: v1.PodPending,
+			Conditions: []v1.PodCondition{{
+				Type: v1.PodScheduled, Status: v1.ConditionFalse, Reason: v1.PodReasonUnschedulable,
+			}},
+		},
+	}
+}
+
+func TestScaleUpCandidates_PrefersNodesPendingPodsFit(t *testing.T) {
+	typed := func(name, nodeType string, offFor time.Duration) *v1.Node {
+		n := offNodeWithBoot(name, offFor, "")
+		n.Labels["node.kubernetes.io/instance-type"] = nodeType
+		return n
+	}
+	tests := []struct {
+		name string
+		pods []*v1.Pod
+		want []string
+	}{
+		{name: "no pending pods keeps off-age order", want: []string{"small", "large"}},
+		{name: "declared capacity justifies the large node", pods: []*v1.Pod{unschedulablePod("big", "8")}, want: []string{"large", "small"}},
+		{name: "fits both keeps off-age order", pods: []*v1.Pod{unschedulablePod("tiny", "1")}, want: []string{"small", "large"}},
+		{name: "fits neither keeps off-age order", pods: []*v1.Pod{unschedulablePod("huge", "64")}, want: []string{"small", "large"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(typed("small", "small", 5*time.Hour), typed("large", "large", time.Hour))
+			for _, p := range tt.pods {
+				_, err := client.CoreV1().Pods(p.Namespace).Create(context.Background(), p, metav1.CreateOptions{})
+				require.NoError(t, err)
+			}
+			r := &controller.Reconciler{
+				Client: client,
+				Cfg: &config.Config{
+					NodeLabels: config.NodeLabelConfig{Managed: "cba.dev/is-managed"},
+					NodeCapacityByType: config.NodeCapacityByTypeConfig{Types: map[string]config.NodeCapacity{
+						"small": {CPU: "4", Memory: "16Gi"},
+						"large": {CPU: "32", Memory: "128Gi"},
+					}},
+				},
+				State: nodeops.NewNodeStateTracker(),
+			}
+
+			require.Equal(t, tt.want, r.ScaleUpCandidates(context.Background()))
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// scaleDownScheduled reports whether schedule.scaleDown allows scale-down at now.
+func (r *Reconciler) scaleDownScheduled(ctx context.Context, now time.Time) bool {
+	window, ok := r.Cfg.Schedule.ScaleDownWindow(now)
+	return r.scheduleAllows(ctx, "scale-down", window, ok)
+}
+
+// scaleUpScheduled reports whether schedule.scaleUp allows scale-up at now.
+func (r *Reconciler) scaleUpScheduled(ctx context.Context, now time.Time) bool {
+	window, ok := r.Cfg.Schedule.ScaleUpWindow(now)
+	return r.scheduleAllows(ctx, "scale-up", window, ok)
+}
+
+func (r *Reconciler) scheduleAllows(ctx context.Context, phase, window string, ok bool) bool {
+	if !ok {
+		slog.Info("Outside allowed schedule windows — skipping "+phase, "timezone", r.Cfg.Schedule.Timezone)
+		setReason(ctx, "outside schedule")
+		return false
+	}
+	if window != "" {
+		slog.Debug("Schedule window open", "phase", phase, "window", window)
+	}
+	return true
+}
@@ -0,0 +1,127 @@
+package controller_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/controller"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// windowAround returns a window open from now+from to now+to, in UTC.
+func windowAround(name string, from, to time.Duration) config.TimeWindow {
+	now := time.Now().UTC()
+	return config.TimeWindow{Name: name, Start: now.Add(from).Format("15:04"), End: now.Add(to).Format("15:04")}
+}
+
+func TestReconcile_ScaleDownSchedule(t *testing.T) {
+	tests := []struct {
+		name       string
+		windows    []config.TimeWindow
+		wantOff    int
+		wantWindow string
+	}{
+		{name: "no schedule", wantOff: 1},
+		{name: "inside window", windows: []config.TimeWindow{windowAround("now", -time.Hour, time.Hour)}, wantOff: 1, wantWindow: "now"},
+		{name: "outside window", windows: []config.TimeWindow{windowAround("later", 6*time.Hour, 7*time.Hour)}, wantOff: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hourAgo := time.Now().Add(-time.Hour)
+			client := fake.NewSimpleClientset(runningNode("a", hourAgo, nil), runningNode("b", hourAgo, nil))
+			sim := &bootSimulator{client: client}
+			r := &controller.Reconciler{
+				Client: client,
+				Cfg: &config.Config{
+					NodeLabels: config.NodeLabelConfig{Managed: "cba.dev/is-managed"},
+					Schedule:   config.ScheduleConfig{ScaleDown: tt.windows},
+				},
+				State:             nodeops.NewNodeStateTracker(),
+				Shutdowner:        sim,
+				PowerOner:         sim,
+				ScaleDownStrategy: approveAllStrategy{},
+				ScaleUpStrategy:   &mockScaleUpStrategy{},
+			}
+
+			require.NoError(t, r.Reconcile(context.Background()))
+			require.Len(t, sim.ShutDown, tt.wantOff)
+
+			rec := httptest.NewRecorder()
+			r.StatusHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/status", nil))
+			var st controller.Status
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&st))
+			if tt.windows == nil {
+				require.Nil(t, st.Schedule)
+				return
+			}
+			require.NotNil(t, st.Schedule)
+			require.Equal(t, tt.wantOff > 0, st.Schedule.ScaleDownAllowed)
+			require.Equal(t, tt.wantWindow, st.Schedule.ScaleDownWindow)
+			require.True(t, st.Schedule.ScaleUpAllowed, "no scaleUp windows means always allowed")
+		})
+	}
+}
+
+func TestReconcile_DesiredNodeCountFollowsSchedule(t *testing.T) {
+	outside := []config.TimeWindow{windowAround("later", 6*time.Hour, 7*time.Hour)}
+	inside := []config.TimeWindow{windowAround("now", -time.Hour, time.Hour)}
+	tests := []struct {
+		name         string
+		desired      string
+		schedule     config.ScheduleConfig
+		wantShutDown int
+		wantPowerOn  int
+	}{
+		{name: "down inside window", desired: "1", schedule: config.ScheduleConfig{ScaleDown: inside}, wantShutDown: 1},
+		{name: "down outside window", desired: "1", schedule: config.ScheduleConfig{ScaleDown: outside}},
+		{name: "up inside window", desired: "3", schedule: config.ScheduleConfig{ScaleUp: inside}, wantPowerOn: 1},
+		{name: "up outside window", desired: "3", schedule: config.ScheduleConfig{ScaleUp: outside}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hourAgo := time.Now().Add(-time.Hour)
+			off := poweredOffNode("off")
+			off.Annotations[nodeops.AnnotationMACAuto] = "00:11:22:33:44:55"
+			client := fake.NewSimpleClientset(
+				runningNode("a", hourAgo, nil),
+				runningNode("b", hourAgo, nil),
+				off,
+				&v1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "targets", Namespace: "cba"},
+					Data:       map[string]string{"desiredNodeCount": tt.desired},
+				},
+			)
+			sim := &bootSimulator{client: client}
+			r := &controller.Reconciler{
+				Client: client,
+				Cfg: &config.Config{
+					NodeLabels: config.NodeLabelConfig{Managed: "cba.dev/is-managed"},
+					Schedule:   tt.schedule,
+					DesiredNodeCountSource: config.ValueSourceConfig{
+						Type: config.ValueSourceConfigMap, Namespace: "cba", Name: "targets", Key: "desiredNodeCount",
+					},
+				},
+				State:             nodeops.NewNodeStateTracker(),
+				Shutdowner:        sim,
+				PowerOner:         sim,
+				ScaleDownStrategy: &MockScaleDownStrategy{},
+				ScaleUpStrategy:   &failingScaleUpStrategy{},
+			}
+
+			require.NoError(t, r.Reconcile(context.Background()))
+			require.Len(t, sim.ShutDown, tt.wantShutDown)
+			require.Len(t, sim.PoweredOn, tt.wantPowerOn)
+		})
+	}
+}
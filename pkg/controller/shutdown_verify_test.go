@@ -0,0 +1,85 @@
+package controller_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/docent-net/cluster-bare-autoscaler/internal/bootstrap/metrics"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/controller"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/power"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestReconcile_ShutdownVerification(t *testing.T) {
+	tests := []struct {
+		name        string
+		hung        bool // the shutdown call succeeds but the node stays Ready
+		dryRunPower bool
+		wantOff     bool
+		wantFailure float64
+	}{
+		{name: "node goes NotReady", wantOff: true},
+		{name: "node stays Ready", hung: true, wantOff: false, wantFailure: 1},
+		{name: "dry-run power skips verification", hung: true, dryRunPower: true, wantOff: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			hourAgo := time.Now().Add(-time.Hour)
+			client := fake.NewSimpleClientset(runningNode("a", hourAgo, nil), runningNode("b", hourAgo, nil))
+			sim := &bootSimulator{client: client}
+			var shutdowner power.ShutdownController = sim
+			if tt.hung {
+				shutdowner = &shutdownMock{}
+			}
+			var slept time.Duration
+			r := &controller.Reconciler{
+				Client: client,
+				Cfg: &config.Config{
+					NodeLabels:            config.NodeLabelConfig{Managed: "cba.dev/is-managed"},
+					ShutdownVerifyTimeout: time.Minute,
+					DryRunPower:           tt.dryRunPower,
+				},
+				State:             nodeops.NewNodeStateTracker(),
+				Shutdowner:        shutdowner,
+				PowerOner:         sim,
+				ScaleDownStrategy: approveAllStrategy{},
+				ScaleUpStrategy:   &mockScaleUpStrategy{},
+				Sleep: func(_ context.Context, d time.Duration) error {
+					slept += d
+					return nil
+				},
+			}
+			before := testutil.ToFloat64(metrics.ShutdownVerificationFailures)
+
+			require.NoError(t, r.Reconcile(ctx))
+
+			require.Equal(t, tt.wantFailure, testutil.ToFloat64(metrics.ShutdownVerificationFailures)-before)
+			var annotated []string
+			for _, name := range []string{"a", "b"} {
+				n, err := client.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+				require.NoError(t, err)
+				if _, ok := n.Annotations[nodeops.AnnotationPoweredOff]; ok {
+					annotated = append(annotated, name)
+				}
+				// A simulated power-off is annotated but not recorded as off in State.
+				wantState := slices.Contains(annotated, name) && !tt.dryRunPower
+				require.Equal(t, wantState, r.State.IsPoweredOff(name), "state and annotation must agree for %s", name)
+			}
+			if !tt.wantOff {
+				require.Empty(t, annotated, "unverified shutdown must not leave a powered-off annotation")
+				require.Equal(t, time.Minute, slept, "verification must poll until the timeout")
+				return
+			}
+			require.Len(t, annotated, 1)
+		})
+	}
+}
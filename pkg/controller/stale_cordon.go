@@ -0,0 +1,137 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
+)
+
+// MaybeResolveStaleCordons enforces maxCordonedOnDuration on nodes CBA cordoned but never powered
+// off (e.g. a stalled drain or a failed shutdown), since they draw power without serving.
+// With maxCordonedOnAction=powerOff an expired node is powered off if its drain completed and
+// uncordoned otherwise, including when a safety gate refuses the power-off; with uncordon it is
+// always reverted.
+// Returns true if an action was taken.
+func (r *Reconciler) MaybeResolveStaleCordons(ctx context.Context) bool {
+	acted := false
+	for _, s := range r.staleCordons(ctx, time.Now()) {
+		if r.resolveStaleCordon(ctx, s.node, s.cordonedFor) {
+			acted = true
+		}
+	}
+	return acted
+}
+
+// staleCordon is a node CBA cordoned longer than maxCordonedOnDuration ago.
+type staleCordon struct {
+	node        *nodeops.NodeWrapper
+	cordonedFor time.Duration
+}
+
+// staleCordons lists the nodes MaybeResolveStaleCordons would resolve at now; observe-only nodes
+// are logged and left out.
+func (r *Reconciler) staleCordons(ctx context.Context, now time.Time) []staleCordon {
+	if r.Cfg.MaxCordonedOnDuration <= 0 {
+		return nil
+	}
+	allNodes, err := r.listAllNodes(ctx)
+	if err != nil {
+		return nil
+	}
+
+	var stale []staleCordon
+	for i := range allNodes.Items {
+		n := &allNodes.Items[i]
+		raw, ok := n.Annotations[nodeops.AnnotationCordonedAt]
+		if !ok || !nodeops.IsCordoned(n) {
+			continue
+		}
+		if _, off := n.Annotations[nodeops.AnnotationPoweredOff]; off || r.State.IsPoweredOff(n.Name) {
+			continue
+		}
+		cordonedAt, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			cordonedAt = time.Unix(0, 0) // unparseable: treat as very old
+		}
+		if now.Sub(cordonedAt) < r.Cfg.MaxCordonedOnDuration {
+			continue
+		}
+
+		node := nodeops.NewNodeWrapper(n, r.State, now, nodeops.NodeAnnotationConfig{
+			MAC: r.Cfg.NodeAnnotations.MAC,
+		}, r.Cfg.IgnoreLabels)
+		if node.IsObserveOnly() {
+			slog.Info("Observe-only: cordoned node exceeded maxCordonedOnDuration but not acted upon", "node", n.Name)
+			continue
+		}
+		stale = append(stale, staleCordon{node: node, cordonedFor: now.Sub(cordonedAt)})
+	}
+	return stale
+}
+
+func (r *Reconciler) resolveStaleCordon(ctx context.Context, node *nodeops.NodeWrapper, cordonedFor time.Duration) bool {
+	ctx, span := r.startSpan(withAction(ctx, ActionStaleCordon), "ResolveStaleCordon", attrAction.String("stale-cordon"), attrNode.String(node.Name))
+	defer span.End()
+
+	if r.Cfg.MaxCordonedOnAction != config.CordonedOnActionUncordon {
+		pending, err := r.podsPendingDrain(ctx, node.Name)
+		if err != nil {
+			slog.Warn("Stale cordon: failed to list pods", "node", node.Name, "err", err)
+			return false
+		}
+		if pending == 0 {
+			slog.Info("Stale cordon: drained node still on past maxCordonedOnDuration — powering off",
+				"node", node.Name, "cordonedFor", cordonedFor.Round(time.Second).String())
+			setReason(ctx, "drained; powering off")
+			err := r.powerOffDrained(ctx, node)
+			if !errors.Is(err, errPowerOffBlocked) {
+				return true
+			}
+			// Powering off is not allowed now; don't keep an idle node cordoned and running.
+			slog.Info("Stale cordon: power-off refused — reverting cordon", "node", node.Name, "err", err)
+		} else {
+			slog.Info("Stale cordon: drain stalled — reverting cordon", "node", node.Name, "pendingPods", pending)
+		}
+	}
+
+	setReason(ctx, "uncordon")
+	if r.Cfg.IsK8sDryRun() {
+		slog.Info("Dry-run: would uncordon stale cordoned node", "node", node.Name)
+		return true
+	}
+	if err := nodeops.UncordonNode(ctx, r.Client, node.Name, r.Cfg.CordonWithTaint()); err != nil {
+		slog.Warn("Stale cordon: failed to uncordon node", "node", node.Name, "err", err)
+		return false
+	}
+	slog.Info("Stale cordon: node uncordoned", "node", node.Name,
+		"cordonedFor", cordonedFor.Round(time.Second).String())
+	return true
+}
+
+// podsPendingDrain counts pods on the node that drain would still have to evict.
+func (r *Reconciler) podsPendingDrain(ctx context.Context, nodeName string) (int, error) {
+	pods, err := r.Client.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+		FieldSelector: "spec.nodeName=" + nodeName,
+	})
+	if err != nil {
+		return 0, err
+	}
+	pending := 0
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName != nodeName || drainExemptReason(&pod) != "" {
+			continue
+		}
+		if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+		pending++
+	}
+	return pending, nil
+}
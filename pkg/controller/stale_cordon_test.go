@@ -0,0 +1,165 @@
+package controller_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/controller"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func cordonedNode(name string, cordonedAt time.Time) *v1.Node {
+	n := runningNode(name, cordonedAt.Add(-time.Hour), map[string]string{
+		nodeops.AnnotationCordonedAt: cordonedAt.UTC().Format(time.RFC3339),
+	})
+	n.Spec.Unschedulable = true
+	return n
+}
+
+func podOn(name, nodeName string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       v1.PodSpec{NodeName: nodeName},
+		Status:     v1.PodStatus{Phase: v1.PodRunning},
+	}
+}
+
+func newStaleCordonReconciler(client *fake.Clientset, sim *bootSimulator, action string) *controller.Reconciler {
+	return newSimReconciler(client, sim, &config.Config{
+		MaxCordonedOnDuration: 30 * time.Minute,
+		MaxCordonedOnAction:   action,
+	})
+}
+
+func TestMaybeResolveStaleCordons(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name         string
+		action       string
+		cordonedAt   time.Time
+		pods         []runtime.Object
+		wantActed    bool
+		wantShutdown bool
+		wantCordoned bool
+	}{
+		{
+			name:         "powerOff: drained node past window is powered off",
+			action:       config.CordonedOnActionPowerOff,
+			cordonedAt:   now.Add(-time.Hour),
+			wantActed:    true,
+			wantShutdown: true,
+			wantCordoned: true,
+		},
+		{
+			name:         "powerOff: stalled drain past window is uncordoned",
+			action:       config.CordonedOnActionPowerOff,
+			cordonedAt:   now.Add(-time.Hour),
+			pods:         []runtime.Object{podOn("stuck", "cordoned")},
+			wantActed:    true,
+			wantCordoned: false,
+		},
+		{
+			name:         "uncordon: drained node past window is reverted",
+			action:       config.CordonedOnActionUncordon,
+			cordonedAt:   now.Add(-time.Hour),
+			wantActed:    true,
+			wantCordoned: false,
+		},
+		{
+			name:         "within window nothing happens",
+			action:       config.CordonedOnActionPowerOff,
+			cordonedAt:   now.Add(-10 * time.Minute),
+			wantCordoned: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			objs := append([]runtime.Object{cordonedNode("cordoned", tt.cordonedAt)}, tt.pods...)
+			client := fake.NewSimpleClientset(objs...)
+			sim := &bootSimulator{client: client}
+			r := newStaleCordonReconciler(client, sim, tt.action)
+
+			require.Equal(t, tt.wantActed, r.MaybeResolveStaleCordons(ctx))
+
+			if tt.wantShutdown {
+				require.Equal(t, []string{"cordoned"}, sim.ShutDown)
+			} else {
+				require.Empty(t, sim.ShutDown)
+			}
+
+			node, err := client.CoreV1().Nodes().Get(ctx, "cordoned", metav1.GetOptions{})
+			require.NoError(t, err)
+			require.Equal(t, tt.wantCordoned, node.Spec.Unschedulable)
+			if !tt.wantCordoned {
+				require.NotContains(t, node.Annotations, nodeops.AnnotationCordonedAt)
+			}
+		})
+	}
+}
+
+func TestMaybeResolveStaleCordons_RespectsAbsoluteMinNodes(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset(
+		cordonedNode("cordoned", time.Now().Add(-time.Hour)),
+		runningNode("keep", time.Now().Add(-time.Hour), nil),
+	)
+	sim := &bootSimulator{client: client}
+	r := newStaleCordonReconciler(client, sim, config.CordonedOnActionPowerOff)
+	r.Cfg.AbsoluteMinNodes = 1
+
+	require.True(t, r.MaybeResolveStaleCordons(ctx))
+	require.Empty(t, sim.ShutDown, "powering off would leave only absoluteMinNodes Ready nodes")
+	requireUncordoned(t, client, "cordoned")
+}
+
+func TestMaybeResolveStaleCordons_UncordonsWhenCapBlocksPowerOff(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset(
+		cordonedNode("cordoned", time.Now().Add(-time.Hour)),
+		poweredOffNode("off"),
+	)
+	sim := &bootSimulator{client: client}
+	r := newStaleCordonReconciler(client, sim, config.CordonedOnActionPowerOff)
+	r.Cfg.MaxPoweredOff = 1
+
+	require.True(t, r.MaybeResolveStaleCordons(ctx))
+	require.Empty(t, sim.ShutDown, "one node is already off and maxPoweredOff is 1")
+	requireUncordoned(t, client, "cordoned")
+
+	require.False(t, r.MaybeResolveStaleCordons(ctx), "the node is no longer cordoned on the next loop")
+}
+
+// requireUncordoned asserts that the stale-cordon handler reverted the cordon on name.
+func requireUncordoned(t *testing.T, client *fake.Clientset, name string) {
+	t.Helper()
+	node, err := client.CoreV1().Nodes().Get(context.Background(), name, metav1.GetOptions{})
+	require.NoError(t, err)
+	require.False(t, node.Spec.Unschedulable, "node %s must be uncordoned", name)
+	require.NotContains(t, node.Annotations, nodeops.AnnotationCordonedAt)
+}
+
+func TestCordonAndDrain_RecordsCordonTime(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset(runningNode("n1", time.Now(), nil))
+	r := newStaleCordonReconciler(client, &bootSimulator{client: client}, "")
+
+	node, err := client.CoreV1().Nodes().Get(ctx, "n1", metav1.GetOptions{})
+	require.NoError(t, err)
+	wrapped := nodeops.NewNodeWrapper(node, r.State, time.Now(), nodeops.NodeAnnotationConfig{}, nil)
+	require.NoError(t, r.CordonAndDrain(ctx, wrapped))
+
+	node, err = client.CoreV1().Nodes().Get(ctx, "n1", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.True(t, node.Spec.Unschedulable)
+	require.Contains(t, node.Annotations, nodeops.AnnotationCordonedAt)
+}
@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
+)
+
+// statePersistDebounce is the least time between two writes of the state ConfigMap; changes made
+// sooner are written by a later loop.
+const statePersistDebounce = 10 * time.Second
+
+// LoadPersistedState restores cooldown and powered-off state saved by a previous run.
+func (r *Reconciler) LoadPersistedState(ctx context.Context) {
+	ref := r.Cfg.StateConfigMap
+	if !r.Cfg.PersistState {
+		return
+	}
+	if err := nodeops.LoadState(ctx, r.Client, ref.Namespace, ref.Name, r.State); err != nil {
+		slog.Warn("Failed to load persisted state; starting from scratch", "err", err)
+		return
+	}
+	r.State.TakeDirty()
+	slog.Info("Loaded persisted node state", "namespace", ref.Namespace, "name", ref.Name)
+}
+
+// PersistState writes the tracker to the state ConfigMap if it changed, at most once per
+// statePersistDebounce. A failed write stays pending for the next loop.
+func (r *Reconciler) PersistState(ctx context.Context) {
+	if !r.Cfg.PersistState {
+		return
+	}
+	if r.State.TakeDirty() {
+		r.statePending = true
+	}
+	now := time.Now()
+	if !r.statePending || now.Sub(r.lastStateWrite) < statePersistDebounce {
+		return
+	}
+	ref := r.Cfg.StateConfigMap
+	if r.Cfg.IsK8sDryRun() {
+		slog.Info("Dry-run: would persist node state", "namespace", ref.Namespace, "name", ref.Name)
+		r.statePending = false
+		return
+	}
+	if err := nodeops.SaveState(ctx, r.Client, ref.Namespace, ref.Name, r.State.Snapshot()); err != nil {
+		slog.Warn("Failed to persist node state", "namespace", ref.Namespace, "name", ref.Name, "err", err)
+		return
+	}
+	r.statePending = false
+	r.lastStateWrite = now
+}
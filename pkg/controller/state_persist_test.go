@@ -0,0 +1,73 @@
+package controller_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/controller"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestPersistState_SurvivesRestart(t *testing.T) {
+	ctx := context.Background()
+	hourAgo := time.Now().Add(-time.Hour)
+	client := fake.NewSimpleClientset(runningNode("a", hourAgo, nil), runningNode("b", hourAgo, nil))
+	sim := &bootSimulator{client: client}
+	cfg := &config.Config{
+		NodeLabels:     config.NodeLabelConfig{Managed: "cba.dev/is-managed"},
+		Cooldown:       time.Hour,
+		PersistState:   true,
+		StateConfigMap: config.StateConfigMapConfig{Namespace: "cba", Name: "cba-state"},
+	}
+	newReconciler := func() *controller.Reconciler {
+		return &controller.Reconciler{
+			Client:            client,
+			Cfg:               cfg,
+			State:             nodeops.NewNodeStateTracker(),
+			Shutdowner:        sim,
+			PowerOner:         sim,
+			ScaleDownStrategy: approveAllStrategy{},
+			ScaleUpStrategy:   &mockScaleUpStrategy{},
+		}
+	}
+
+	first := newReconciler()
+	require.NoError(t, first.Reconcile(ctx))
+	require.Len(t, sim.ShutDown, 1)
+	cm, err := client.CoreV1().ConfigMaps("cba").Get(ctx, "cba-state", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Contains(t, cm.Data, nodeops.StateConfigMapKey)
+
+	// A restarted controller must still honor the global cooldown started by the first one.
+	second := newReconciler()
+	second.LoadPersistedState(ctx)
+	require.True(t, second.State.IsPoweredOff(sim.ShutDown[0]))
+	require.NoError(t, second.Reconcile(ctx))
+	require.Len(t, sim.ShutDown, 1, "cooldown must survive the restart")
+}
+
+func TestPersistState_DisabledWritesNothing(t *testing.T) {
+	ctx := context.Background()
+	hourAgo := time.Now().Add(-time.Hour)
+	client := fake.NewSimpleClientset(runningNode("a", hourAgo, nil), runningNode("b", hourAgo, nil))
+	sim := &bootSimulator{client: client}
+	r := &controller.Reconciler{
+		Client:            client,
+		Cfg:               &config.Config{NodeLabels: config.NodeLabelConfig{Managed: "cba.dev/is-managed"}},
+		State:             nodeops.NewNodeStateTracker(),
+		Shutdowner:        sim,
+		PowerOner:         sim,
+		ScaleDownStrategy: approveAllStrategy{},
+		ScaleUpStrategy:   &mockScaleUpStrategy{},
+	}
+
+	require.NoError(t, r.Reconcile(ctx))
+	cms, err := client.CoreV1().ConfigMaps("").List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Empty(t, cms.Items)
+}
@@ -0,0 +1,29 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PruneNodeState drops in-memory state for nodes that have been gone from the cluster for
+// longer than nodeStateRetention. A zero retention disables pruning.
+func (r *Reconciler) PruneNodeState(ctx context.Context) {
+	if r.Cfg.NodeStateRetention <= 0 {
+		return
+	}
+	nodes, err := r.Client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		slog.Warn("PruneNodeState: listing nodes failed", "err", err)
+		return
+	}
+	present := make(map[string]struct{}, len(nodes.Items))
+	for _, n := range nodes.Items {
+		present[n.Name] = struct{}{}
+	}
+	if pruned := r.State.PruneAbsent(present, time.Now(), r.Cfg.NodeStateRetention); len(pruned) > 0 {
+		slog.Info("Pruned state of nodes gone from the cluster", "nodes", pruned, "retention", r.Cfg.NodeStateRetention.String())
+	}
+}
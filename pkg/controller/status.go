@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
+)
+
+// Status is what the reconciler knew at the end of its last loop, served as JSON on GET /status.
+type Status struct {
+	ObservedAt           time.Time         `json:"observedAt"`
+	ManagedNodes         int               `json:"managedNodes"`
+	ActiveNodes          []string          `json:"activeNodes"`
+	PoweredOffNodes      []PoweredOffState `json:"poweredOffNodes"`
+	EligibleCandidates   []string          `json:"eligibleCandidates"` // empty when the loop stopped before evaluating scale-down
+	LastScaleUp          *time.Time        `json:"lastScaleUp,omitempty"`
+	LastScaleDown        *time.Time        `json:"lastScaleDown,omitempty"`
+	GlobalCooldownActive bool              `json:"globalCooldownActive"`
+	Schedule             *ScheduleState    `json:"schedule,omitempty"` // set when schedule windows are configured
+}
+
+// ScheduleState tells whether each phase may run now and which window allows it. The window is
+// empty when the phase is outside its windows or has none configured.
+type ScheduleState struct {
+	ScaleDownAllowed bool   `json:"scaleDownAllowed"`
+	ScaleDownWindow  string `json:"scaleDownWindow,omitempty"`
+	ScaleUpAllowed   bool   `json:"scaleUpAllowed"`
+	ScaleUpWindow    string `json:"scaleUpWindow,omitempty"`
+}
+
+// PoweredOffState describes one powered-off node. Since is unset for nodes only known as powered
+// off from in-memory state.
+type PoweredOffState struct {
+	Name  string     `json:"name"`
+	Since *time.Time `json:"since,omitempty"`
+	Age   string     `json:"age,omitempty"`
+}
+
+// publishStatus builds a Status at the end of a loop and stores it for StatusHandler.
+func (r *Reconciler) publishStatus(ctx context.Context) {
+	now := time.Now()
+	managed, err := nodeops.ListManagedNodes(ctx, r.Client, nodeops.NewManagedNodeFilter(r.Cfg))
+	if err != nil {
+		slog.Warn("Status: listing managed nodes failed; keeping previous status", "err", err)
+		return
+	}
+	active, err := r.listActiveNodes(ctx)
+	if err != nil {
+		slog.Warn("Status: listing active nodes failed; keeping previous status", "err", err)
+		return
+	}
+
+	st := &Status{
+		ObservedAt:           now,
+		ManagedNodes:         len(managed),
+		ActiveNodes:          []string{},
+		PoweredOffNodes:      []PoweredOffState{},
+		EligibleCandidates:   append([]string{}, r.loopEligible...),
+		GlobalCooldownActive: r.State.IsGlobalCooldownActive(now, r.Cfg.Cooldown),
+	}
+	for _, n := range active {
+		st.ActiveNodes = append(st.ActiveNodes, n.Name)
+	}
+	for _, n := range managed {
+		if since, ok := nodeops.PoweredOffSince(n); ok {
+			st.PoweredOffNodes = append(st.PoweredOffNodes, PoweredOffState{
+				Name: n.Name, Since: &since, Age: now.Sub(since).Round(time.Second).String(),
+			})
+		} else if r.State.IsPoweredOff(n.Name) {
+			st.PoweredOffNodes = append(st.PoweredOffNodes, PoweredOffState{Name: n.Name})
+		}
+	}
+	sort.Strings(st.ActiveNodes)
+	sort.Strings(st.EligibleCandidates)
+	if up := r.lastScaleUp; !up.IsZero() {
+		st.LastScaleUp = &up
+	}
+	if down := r.lastScaleDown; !down.IsZero() {
+		st.LastScaleDown = &down
+	}
+
+	if r.Cfg.Schedule.Enabled() {
+		sched := &ScheduleState{}
+		sched.ScaleDownWindow, sched.ScaleDownAllowed = r.Cfg.Schedule.ScaleDownWindow(now)
+		sched.ScaleUpWindow, sched.ScaleUpAllowed = r.Cfg.Schedule.ScaleUpWindow(now)
+		st.Schedule = sched
+	}
+
+	r.noteLoopActivity(st)
+
+	r.statusMu.Lock()
+	r.status = st
+	r.statusMu.Unlock()
+}
+
+// StatusHandler serves the Status published by the last reconcile loop, or 503 before the first.
+func (r *Reconciler) StatusHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		r.statusMu.Lock()
+		st := r.status
+		r.statusMu.Unlock()
+		if st == nil {
+			http.Error(w, "no reconcile loop has finished yet", http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(st)
+	})
+}
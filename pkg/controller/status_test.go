@@ -0,0 +1,78 @@
+package controller_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/controller"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestStatusHandler(t *testing.T) {
+	ctx := context.Background()
+	hourAgo := time.Now().Add(-time.Hour)
+	client := fake.NewSimpleClientset(
+		runningNode("a", hourAgo, nil),
+		runningNode("b", hourAgo, nil),
+		poweredOffNode("off"),
+	)
+	sim := &bootSimulator{client: client}
+	r := &controller.Reconciler{
+		Client: client,
+		Cfg: &config.Config{
+			NodeLabels: config.NodeLabelConfig{Managed: "cba.dev/is-managed"},
+			Cooldown:   time.Hour,
+		},
+		State:             nodeops.NewNodeStateTracker(),
+		Shutdowner:        sim,
+		PowerOner:         sim,
+		ScaleDownStrategy: approveAllStrategy{},
+		ScaleUpStrategy:   &mockScaleUpStrategy{},
+	}
+
+	get := func() (*httptest.ResponseRecorder, controller.Status) {
+		rec := httptest.NewRecorder()
+		r.StatusHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/status", nil))
+		var st controller.Status
+		if rec.Code == http.StatusOK {
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&st))
+		}
+		return rec, st
+	}
+
+	rec, _ := get()
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code, "no status before the first loop")
+
+	require.NoError(t, r.Reconcile(ctx))
+	require.Len(t, sim.ShutDown, 1)
+	retired := sim.ShutDown[0]
+
+	rec, st := get()
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, 3, st.ManagedNodes)
+	require.ElementsMatch(t, []string{"a", "b"}, st.EligibleCandidates)
+	require.Len(t, st.ActiveNodes, 1)
+	require.NotContains(t, st.ActiveNodes, retired)
+	var off []string
+	for _, n := range st.PoweredOffNodes {
+		off = append(off, n.Name)
+		require.NotNil(t, n.Since, "node %s", n.Name)
+	}
+	require.ElementsMatch(t, []string{"off", retired}, off)
+	require.NotNil(t, st.LastScaleDown)
+	require.Nil(t, st.LastScaleUp)
+	require.True(t, st.GlobalCooldownActive)
+
+	// A loop skipped by the cooldown publishes a fresh snapshot without candidates.
+	require.NoError(t, r.Reconcile(ctx))
+	_, st = get()
+	require.Empty(t, st.EligibleCandidates)
+	require.True(t, st.GlobalCooldownActive)
+}
@@ -0,0 +1,101 @@
+package controller_test
+
+import (
+	"testing"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/controller"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/strategy"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func chainNames(t *testing.T, r *controller.Reconciler) (down, up []string) {
+	t.Helper()
+	for _, s := range r.ScaleDownStrategy.(*strategy.MultiStrategy).Strategies {
+		down = append(down, s.Name())
+	}
+	for _, s := range r.ScaleUpStrategy.(*strategy.MultiUpStrategy).Strategies {
+		up = append(up, s.Name())
+	}
+	return down, up
+}
+
+func TestNewReconciler_StrategyChainsFromList(t *testing.T) {
+	disabled := false
+	loadAvg := func(enabled bool) config.LoadAverageStrategyConfig {
+		return config.LoadAverageStrategyConfig{
+			Enabled: enabled, ScaleDownThreshold: 0.3, ScaleUpThreshold: 0.8,
+			PodLabel: "app=metrics", Namespace: "cba", Port: 9100,
+		}
+	}
+	listLoadAvg := loadAvg(false)
+	tests := []struct {
+		name     string
+		cfg      config.Config
+		wantDown []string
+		wantUp   []string
+	}{
+		{
+			name:     "legacy fields",
+			cfg:      config.Config{},
+			wantDown: []string{"ResourceAware"},
+			wantUp:   []string{"MinNodeCount"},
+		},
+		{
+			name:     "legacy load average",
+			cfg:      config.Config{LoadAverageStrategy: loadAvg(true)},
+			wantDown: []string{"ResourceAware", "LoadAverage"},
+			wantUp:   []string{"MinNodeCount", "LoadAverageScaleUp"},
+		},
+		{
+			name: "list form, load average first and scale-down only",
+			cfg: config.Config{
+				ScaleDownStrategies: []config.StrategyEntry{
+					{Name: config.StrategyLoadAverage, LoadAverage: &listLoadAvg},
+					{Name: config.StrategyResourceAware},
+				},
+				ScaleUpStrategies: []config.StrategyEntry{{Name: config.StrategyMinNodeCount}},
+			},
+			wantDown: []string{"LoadAverage", "ResourceAware"},
+			wantUp:   []string{"MinNodeCount"},
+		},
+		{
+			name: "legacy prometheus",
+			cfg: config.Config{PrometheusStrategy: config.PrometheusStrategyConfig{
+				Enabled: true, URL: "http://prometheus:9090", NodeQuery: "q", ClusterQuery: "q",
+			}},
+			wantDown: []string{"ResourceAware", "Prometheus"},
+			wantUp:   []string{"MinNodeCount", "Prometheus"},
+		},
+		{
+			name:     "legacy gpu guard is scale-down only",
+			cfg:      config.Config{GPUAwareStrategy: config.GPUAwareStrategyConfig{Enabled: true}},
+			wantDown: []string{"ResourceAware", "GPUAware"},
+			wantUp:   []string{"MinNodeCount"},
+		},
+		{
+			name: "list form with a disabled entry",
+			cfg: config.Config{
+				ScaleDownStrategies: []config.StrategyEntry{
+					{Name: config.StrategyResourceAware},
+					{Name: config.StrategyLoadAverage, Enabled: &disabled},
+				},
+			},
+			wantDown: []string{"ResourceAware"},
+			wantUp:   []string{"MinNodeCount"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			require.NoError(t, cfg.ApplyDefaultsAndValidate())
+			r := controller.NewReconciler(&cfg, fake.NewSimpleClientset(), nil)
+
+			down, up := chainNames(t, r)
+			require.Equal(t, tt.wantDown, down)
+			require.Equal(t, tt.wantUp, up)
+		})
+	}
+}
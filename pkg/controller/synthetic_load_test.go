@@ -0,0 +1,71 @@
+package controller_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/controller"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/strategy"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+	clocktesting "k8s.io/utils/clock/testing"
+)
+
+func TestReconcile_SyntheticLoadCurve(t *testing.T) {
+	ctx := context.Background()
+	hourAgo := time.Now().Add(-time.Hour)
+	off := poweredOffNode("off")
+	off.Annotations[nodeops.AnnotationMACAuto] = "aa:bb:cc:dd:ee:ff"
+	client := fake.NewSimpleClientset(runningNode("a", hourAgo, nil), runningNode("b", hourAgo, nil), off)
+	sim := &bootSimulator{client: client}
+
+	// Busy for the first 10 minutes, then load drains away over the following 20.
+	profile := config.SyntheticLoadProfile{Cluster: []config.LoadPoint{
+		{At: 0, Load: 0.9},
+		{At: 10 * time.Minute, Load: 0.9},
+		{At: 30 * time.Minute, Load: 0.1},
+	}}
+	clk := clocktesting.NewFakePassiveClock(time.Now())
+	src := strategy.NewSyntheticLoadSource(profile, clk)
+
+	cfg := &config.Config{NodeLabels: config.NodeLabelConfig{Managed: "cba.dev/is-managed"}}
+	r := &controller.Reconciler{
+		Client:     client,
+		Cfg:        cfg,
+		State:      nodeops.NewNodeStateTracker(),
+		Shutdowner: sim,
+		PowerOner:  sim,
+		LoadSource: src,
+	}
+	r.ScaleUpStrategy = &strategy.LoadAverageScaleUp{
+		Client:               client,
+		ClusterEvalMode:      strategy.ClusterEvalAverage,
+		ClusterWideThreshold: 0.7,
+		ShutdownCandidates:   r.ScaleUpCandidates,
+		LoadSource:           src,
+	}
+	r.ScaleDownStrategy = &strategy.LoadAverageScaleDown{
+		Client:               client,
+		Cfg:                  cfg,
+		NodeThreshold:        0.5,
+		ClusterWideThreshold: 0.3,
+		ClusterEvalMode:      strategy.ClusterEvalAverage,
+		LoadSource:           src,
+	}
+
+	require.NoError(t, r.Reconcile(ctx))
+	require.Equal(t, []string{"off"}, sim.PoweredOn, "high synthetic load must boot the powered-off node")
+	require.Empty(t, sim.ShutDown)
+
+	clk.SetTime(clk.Now().Add(20 * time.Minute)) // load 0.5: between thresholds
+	require.NoError(t, r.Reconcile(ctx))
+	require.Len(t, sim.PoweredOn, 1)
+	require.Empty(t, sim.ShutDown)
+
+	clk.SetTime(clk.Now().Add(20 * time.Minute)) // past the curve: load holds at 0.1
+	require.NoError(t, r.Reconcile(ctx))
+	require.Len(t, sim.ShutDown, 1, "low synthetic load must power a node off")
+}
@@ -0,0 +1,42 @@
+package controller
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/docent-net/cluster-bare-autoscaler/pkg/controller")
+
+// Span attribute keys for reconcile decisions. Load values are attached by the
+// strategies themselves (see strategy.AttrNodeLoad / strategy.AttrClusterLoad).
+const (
+	attrNode   = attribute.Key("cba.node")
+	attrAction = attribute.Key("cba.action")
+	attrReason = attribute.Key("cba.reason")
+	attrDryRun = attribute.Key("cba.dry_run")
+)
+
+// startSpan starts a child span tagged with whether any dry-run mode is active.
+func (r *Reconciler) startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	dryRun := r.Cfg != nil && (r.Cfg.IsPowerDryRun() || r.Cfg.IsK8sDryRun())
+	attrs = append(attrs, attrDryRun.Bool(dryRun))
+	return tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// setReason records why the decision in the current span ended the way it did.
+func setReason(ctx context.Context, reason string) {
+	trace.SpanFromContext(ctx).SetAttributes(attrReason.String(reason))
+}
+
+// endSpan marks the span failed when err is non-nil and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
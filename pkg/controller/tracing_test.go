@@ -0,0 +1,66 @@
+package controller_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/controller"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestMaybeScaleDown_EmitsSpans(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	client := fake.NewSimpleClientset(
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "busy"}},
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "idle"}},
+	)
+	state := nodeops.NewNodeStateTracker()
+	r := &controller.Reconciler{
+		Client:            client,
+		Cfg:               &config.Config{},
+		State:             state,
+		Shutdowner:        &shutdownMock{},
+		ScaleDownStrategy: &alwaysAllowStrategy{candidate: "idle"},
+	}
+	eligible := nodeops.WrapNodes([]v1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "busy"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "idle"}},
+	}, state, time.Now(), nodeops.NodeAnnotationConfig{}, nil)
+
+	require.True(t, r.MaybeScaleDown(context.Background(), eligible))
+
+	spans := map[string]tracetest.SpanStub{}
+	for _, s := range exporter.GetSpans() {
+		spans[s.Name] = s
+	}
+	for _, name := range []string{"MaybeScaleDown", "CordonAndDrain", "Shutdown"} {
+		require.Contains(t, spans, name)
+	}
+
+	root := spans["MaybeScaleDown"]
+	require.Contains(t, root.Attributes, attribute.String("cba.node", "idle"))
+	require.Contains(t, root.Attributes, attribute.String("cba.action", "scale-down"))
+	require.Contains(t, root.Attributes, attribute.String("cba.reason", "powered off"))
+	require.Contains(t, root.Attributes, attribute.Bool("cba.dry_run", false))
+
+	// Child spans share the trace and hang off the decision span.
+	for _, name := range []string{"CordonAndDrain", "Shutdown"} {
+		require.Equal(t, root.SpanContext.TraceID(), spans[name].SpanContext.TraceID())
+		require.Equal(t, root.SpanContext.SpanID(), spans[name].Parent.SpanID())
+	}
+}
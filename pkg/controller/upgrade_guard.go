@@ -0,0 +1,91 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
+)
+
+// upgradeInProgress reports whether upgradeGuard detects a cluster-wide upgrade or drain,
+// in which case scale-down, recycle and rotation are paused for this loop.
+func (r *Reconciler) upgradeInProgress(ctx context.Context) bool {
+	guard := r.Cfg.UpgradeGuard
+	if !guard.Enabled {
+		return false
+	}
+
+	if guard.Sentinel.Name != "" && r.upgradeSentinelSet(ctx) {
+		slog.Info("Upgrade guard: sentinel present — pausing scale-down",
+			"namespace", guard.Sentinel.Namespace, "name", guard.Sentinel.Name, "key", guard.Sentinel.Key)
+		return true
+	}
+
+	if guard.NotReadyThreshold > 0 {
+		allNodes, err := r.listAllNodes(ctx)
+		if err != nil {
+			return true // can't tell; err on the side of not powering nodes off
+		}
+		now := time.Now()
+		notReady := 0
+		for i := range allNodes.Items {
+			n := &allNodes.Items[i]
+			if nodeops.IsNodeReady(n) {
+				continue
+			}
+			// NotReady because CBA powered it off or is still booting it — expected, not an upgrade.
+			if _, off := n.Annotations[nodeops.AnnotationPoweredOff]; off || r.State.IsPoweredOff(n.Name) {
+				continue
+			}
+			if r.State.IsBootCooldownActive(n.Name, now, r.Cfg.BootCooldown) {
+				continue
+			}
+			notReady++
+		}
+		if notReady >= guard.NotReadyThreshold {
+			slog.Info("Upgrade guard: too many managed nodes NotReady — pausing scale-down",
+				"notReady", notReady, "threshold", guard.NotReadyThreshold)
+			return true
+		}
+	}
+	return false
+}
+
+// upgradeSentinelSet checks the sentinel label/annotation. A missing object means no upgrade;
+// any other lookup error is treated as an upgrade in progress.
+func (r *Reconciler) upgradeSentinelSet(ctx context.Context) bool {
+	s := r.Cfg.UpgradeGuard.Sentinel
+	var meta metav1.ObjectMeta
+	var err error
+	if s.Namespace == "" {
+		node, getErr := r.Client.CoreV1().Nodes().Get(ctx, s.Name, metav1.GetOptions{})
+		if getErr == nil {
+			meta = node.ObjectMeta
+		}
+		err = getErr
+	} else {
+		cm, getErr := r.Client.CoreV1().ConfigMaps(s.Namespace).Get(ctx, s.Name, metav1.GetOptions{})
+		if getErr == nil {
+			meta = cm.ObjectMeta
+		}
+		err = getErr
+	}
+	if apierrors.IsNotFound(err) {
+		return false
+	}
+	if err != nil {
+		slog.Warn("Upgrade guard: failed to read sentinel object", "name", s.Name, "err", err)
+		return true
+	}
+
+	for _, m := range []map[string]string{meta.Labels, meta.Annotations} {
+		if val, ok := m[s.Key]; ok && val != "false" {
+			return true
+		}
+	}
+	return false
+}
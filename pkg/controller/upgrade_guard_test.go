@@ -0,0 +1,120 @@
+package controller_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/controller"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func notReadyNode(name string) *v1.Node {
+	return &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{"cba.dev/is-managed": "true"},
+		},
+		Status: v1.NodeStatus{Conditions: []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionFalse}}},
+	}
+}
+
+func newUpgradeGuardReconciler(client *fake.Clientset, sim *bootSimulator, guard config.UpgradeGuardConfig) *controller.Reconciler {
+	return newSimReconciler(client, sim, &config.Config{UpgradeGuard: guard})
+}
+
+func TestReconcile_UpgradeGuard_NotReadyThreshold(t *testing.T) {
+	guard := config.UpgradeGuardConfig{Enabled: true, NotReadyThreshold: 3}
+
+	tests := []struct {
+		name         string
+		notReady     int
+		poweredOff   int
+		wantShutdown bool
+	}{
+		{"many NotReady nodes suppress scale-down", 3, 0, false},
+		{"below threshold scales down", 2, 0, true},
+		{"nodes powered off by CBA do not count", 1, 3, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			objs := []runtime.Object{runningNode("idle", time.Now().Add(-time.Hour), nil)}
+			for i := 0; i < tt.notReady; i++ {
+				objs = append(objs, notReadyNode(fmt.Sprintf("upgrading-%d", i)))
+			}
+			for i := 0; i < tt.poweredOff; i++ {
+				n := notReadyNode(fmt.Sprintf("off-%d", i))
+				n.Annotations = map[string]string{nodeops.AnnotationPoweredOff: time.Now().UTC().Format(time.RFC3339)}
+				objs = append(objs, n)
+			}
+			client := fake.NewSimpleClientset(objs...)
+			sim := &bootSimulator{client: client}
+
+			r := newUpgradeGuardReconciler(client, sim, guard)
+			require.NoError(t, r.Reconcile(ctx))
+
+			if tt.wantShutdown {
+				require.Len(t, sim.ShutDown, 1)
+			} else {
+				require.Empty(t, sim.ShutDown, "scale-down must pause while an upgrade is in progress")
+			}
+		})
+	}
+}
+
+func TestReconcile_UpgradeGuard_Sentinel(t *testing.T) {
+	guard := config.UpgradeGuardConfig{
+		Enabled:  true,
+		Sentinel: config.UpgradeSentinelConfig{Namespace: "kube-system", Name: "cluster-upgrade", Key: "cba.dev/upgrade-in-progress"},
+	}
+
+	tests := []struct {
+		name         string
+		sentinel     *v1.ConfigMap
+		wantShutdown bool
+	}{
+		{"annotation set pauses scale-down", &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Namespace: "kube-system", Name: "cluster-upgrade",
+			Annotations: map[string]string{"cba.dev/upgrade-in-progress": "true"},
+		}}, false},
+		{"label set pauses scale-down", &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Namespace: "kube-system", Name: "cluster-upgrade",
+			Labels: map[string]string{"cba.dev/upgrade-in-progress": ""},
+		}}, false},
+		{"explicit false allows scale-down", &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Namespace: "kube-system", Name: "cluster-upgrade",
+			Annotations: map[string]string{"cba.dev/upgrade-in-progress": "false"},
+		}}, true},
+		{"missing sentinel object allows scale-down", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			objs := []runtime.Object{runningNode("idle", time.Now().Add(-time.Hour), nil)}
+			if tt.sentinel != nil {
+				objs = append(objs, tt.sentinel)
+			}
+			client := fake.NewSimpleClientset(objs...)
+			sim := &bootSimulator{client: client}
+
+			r := newUpgradeGuardReconciler(client, sim, guard)
+			require.NoError(t, r.Reconcile(ctx))
+
+			if tt.wantShutdown {
+				require.Equal(t, []string{"idle"}, sim.ShutDown)
+			} else {
+				require.Empty(t, sim.ShutDown)
+			}
+		})
+	}
+}
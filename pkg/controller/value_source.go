@@ -0,0 +1,109 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+)
+
+const defaultValueSourceTimeout = 5 * time.Second
+
+// ResolveIntSource reads an integer from the configured external source (ConfigMap key,
+// annotation on a reference object, or HTTP endpoint).
+func ResolveIntSource(ctx context.Context, client kubernetes.Interface, src config.ValueSourceConfig) (int, error) {
+	var raw string
+	switch src.Type {
+	case config.ValueSourceConfigMap:
+		cm, err := client.CoreV1().ConfigMaps(src.Namespace).Get(ctx, src.Name, metav1.GetOptions{})
+		if err != nil {
+			return 0, fmt.Errorf("get configmap %s/%s: %w", src.Namespace, src.Name, err)
+		}
+		val, ok := cm.Data[src.Key]
+		if !ok {
+			return 0, fmt.Errorf("configmap %s/%s has no key %q", src.Namespace, src.Name, src.Key)
+		}
+		raw = val
+	case config.ValueSourceAnnotation:
+		var annotations map[string]string
+		if src.Namespace == "" {
+			node, err := client.CoreV1().Nodes().Get(ctx, src.Name, metav1.GetOptions{})
+			if err != nil {
+				return 0, fmt.Errorf("get node %s: %w", src.Name, err)
+			}
+			annotations = node.Annotations
+		} else {
+			cm, err := client.CoreV1().ConfigMaps(src.Namespace).Get(ctx, src.Name, metav1.GetOptions{})
+			if err != nil {
+				return 0, fmt.Errorf("get configmap %s/%s: %w", src.Namespace, src.Name, err)
+			}
+			annotations = cm.Annotations
+		}
+		val, ok := annotations[src.Annotation]
+		if !ok {
+			return 0, fmt.Errorf("reference object %s has no annotation %q", src.Name, src.Annotation)
+		}
+		raw = val
+	case config.ValueSourceHTTP:
+		val, err := fetchHTTPValue(ctx, src)
+		if err != nil {
+			return 0, err
+		}
+		raw = val
+	default:
+		return 0, fmt.Errorf("unsupported value source type %q", src.Type)
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(raw))
+	if err != nil {
+		return 0, fmt.Errorf("parse value %q: %w", raw, err)
+	}
+	if n < 0 {
+		return 0, fmt.Errorf("negative value %d", n)
+	}
+	return n, nil
+}
+
+func fetchHTTPValue(ctx context.Context, src config.ValueSourceConfig) (string, error) {
+	timeout := defaultValueSourceTimeout
+	if src.TimeoutSeconds > 0 {
+		timeout = time.Duration(src.TimeoutSeconds) * time.Second
+	}
+	reqCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, src.URL, nil)
+	if err != nil {
+		return "", fmt.Errorf("creating value request: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("calling value endpoint: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err != nil {
+		return "", fmt.Errorf("reading value response: %w", err)
+	}
+
+	var payload struct {
+		Value *int `json:"value"`
+	}
+	if json.Unmarshal(body, &payload) == nil && payload.Value != nil {
+		return strconv.Itoa(*payload.Value), nil
+	}
+	return string(body), nil
+}
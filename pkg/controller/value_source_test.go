@@ -0,0 +1,75 @@
+package controller_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/controller"
+)
+
+func TestResolveIntSource_ConfigMap(t *testing.T) {
+	client := fake.NewSimpleClientset(&v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "cba-targets", Namespace: "cba"},
+		Data:       map[string]string{"minNodes": " 4 "},
+	})
+
+	got, err := controller.ResolveIntSource(context.Background(), client, config.ValueSourceConfig{
+		Type: config.ValueSourceConfigMap, Namespace: "cba", Name: "cba-targets", Key: "minNodes",
+	})
+	require.NoError(t, err)
+	require.Equal(t, 4, got)
+
+	_, err = controller.ResolveIntSource(context.Background(), client, config.ValueSourceConfig{
+		Type: config.ValueSourceConfigMap, Namespace: "cba", Name: "cba-targets", Key: "missing",
+	})
+	require.Error(t, err)
+}
+
+func TestResolveIntSource_AnnotationOnNode(t *testing.T) {
+	client := fake.NewSimpleClientset(&v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "anchor",
+			Annotations: map[string]string{"example.com/min-nodes": "2"},
+		},
+	})
+
+	got, err := controller.ResolveIntSource(context.Background(), client, config.ValueSourceConfig{
+		Type: config.ValueSourceAnnotation, Name: "anchor", Annotation: "example.com/min-nodes",
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, got)
+}
+
+func TestResolveIntSource_HTTP(t *testing.T) {
+	bodies := map[string]string{
+		"/plain": "5\n",
+		"/json":  `{"value": 6}`,
+		"/bad":   "lots",
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, bodies[r.URL.Path])
+	}))
+	defer srv.Close()
+
+	client := fake.NewSimpleClientset()
+
+	got, err := controller.ResolveIntSource(context.Background(), client, config.ValueSourceConfig{Type: config.ValueSourceHTTP, URL: srv.URL + "/plain"})
+	require.NoError(t, err)
+	require.Equal(t, 5, got)
+
+	got, err = controller.ResolveIntSource(context.Background(), client, config.ValueSourceConfig{Type: config.ValueSourceHTTP, URL: srv.URL + "/json"})
+	require.NoError(t, err)
+	require.Equal(t, 6, got)
+
+	_, err = controller.ResolveIntSource(context.Background(), client, config.ValueSourceConfig{Type: config.ValueSourceHTTP, URL: srv.URL + "/bad"})
+	require.Error(t, err)
+}
@@ -0,0 +1,39 @@
+package kubeclient
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/flowcontrol"
+
+	"github.com/docent-net/cluster-bare-autoscaler/internal/bootstrap/metrics"
+)
+
+// WithRateLimit returns a copy of cfg whose clients share one token bucket of qps requests per
+// second with the given burst. Time spent waiting for a token is recorded in
+// cba_kube_api_throttle_seconds, so a fast loop being held back shows up in metrics.
+func WithRateLimit(cfg *rest.Config, qps float32, burst int) *rest.Config {
+	out := rest.CopyConfig(cfg)
+	out.QPS, out.Burst = qps, burst
+	out.RateLimiter = &observedRateLimiter{RateLimiter: flowcontrol.NewTokenBucketRateLimiter(qps, burst)}
+	return out
+}
+
+// observedRateLimiter records how long each request waited for the underlying limiter.
+type observedRateLimiter struct {
+	flowcontrol.RateLimiter
+}
+
+func (l *observedRateLimiter) Wait(ctx context.Context) error {
+	start := time.Now()
+	err := l.RateLimiter.Wait(ctx)
+	metrics.KubeAPIThrottleSeconds.Observe(time.Since(start).Seconds())
+	return err
+}
+
+func (l *observedRateLimiter) Accept() {
+	start := time.Now()
+	l.RateLimiter.Accept()
+	metrics.KubeAPIThrottleSeconds.Observe(time.Since(start).Seconds())
+}
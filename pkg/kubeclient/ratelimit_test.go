@@ -0,0 +1,62 @@
+package kubeclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/docent-net/cluster-bare-autoscaler/internal/bootstrap/metrics"
+)
+
+func TestWithRateLimit_ThrottlesBurstToQPS(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"kind":"NodeList","apiVersion":"v1","items":[]}`))
+	}))
+	defer srv.Close()
+
+	const qps, burst, calls = 20, 2, 8
+	client, err := kubernetes.NewForConfig(WithRateLimit(&rest.Config{Host: srv.URL}, qps, burst))
+	if err != nil {
+		t.Fatalf("building client: %v", err)
+	}
+
+	before := throttleSamples(t)
+	start := time.Now()
+	for i := 0; i < calls; i++ {
+		if _, err := client.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{}); err != nil {
+			t.Fatalf("list nodes: %v", err)
+		}
+	}
+	elapsed := time.Since(start)
+
+	// The first `burst` calls are free; every further call waits 1/qps.
+	if want := time.Duration(calls-burst) * time.Second / qps; elapsed < want*9/10 {
+		t.Errorf("%d calls took %v, want at least ~%v at %d QPS", calls, elapsed, want, qps)
+	}
+	if got := requests.Load(); got != calls {
+		t.Errorf("server saw %d requests, want %d", got, calls)
+	}
+	if got := throttleSamples(t) - before; got != calls {
+		t.Errorf("throttle histogram recorded %d samples, want %d", got, calls)
+	}
+}
+
+func throttleSamples(t *testing.T) uint64 {
+	t.Helper()
+	var m dto.Metric
+	if err := metrics.KubeAPIThrottleSeconds.Write(&m); err != nil {
+		t.Fatalf("reading histogram: %v", err)
+	}
+	return m.GetHistogram().GetSampleCount()
+}
@@ -1,18 +1,54 @@
 package nodeops
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/power"
 )
 
 const (
 	// Rotation / power state
-	AnnotationPoweredOff = "cba.dev/was-powered-off"
+	AnnotationPoweredOff     = "cba.dev/was-powered-off"
+	AnnotationBootedAt       = "cba.dev/booted-at"       // RFC3339 time CBA last powered the node on
+	AnnotationRecyclePending = "cba.dev/recycle-pending" // replacement booted; node will be drained and powered off
+	AnnotationCordonedAt     = "cba.dev/cordoned-at"     // RFC3339 time CBA cordoned the node for scale-down
+	AnnotationBootDuration   = "cba.dev/boot-duration"   // smoothed time from power-on to Ready (Go duration)
 
 	// MAC addresses
-	AnnotationMACAuto   = "cba.dev/mac-address"          // default auto-discovered MAC
-	AnnotationMACManual = "cba.dev/mac-address-override" // manual override (takes precedence)
+	AnnotationMACAuto   = power.AnnotationMACAuto   // default auto-discovered MAC
+	AnnotationMACManual = power.AnnotationMACManual // manual override (takes precedence)
+	AnnotationMACIface  = "cba.dev/mac-interface"   // NIC the auto-discovered MAC belongs to (informational)
+
+	// Drain overrides (Go durations), taking precedence over drainTimeout / drainGracePeriod
+	AnnotationDrainTimeout = "cba.dev/drain-timeout" // e.g. "30m" for stateful nodes
+	AnnotationDrainGrace   = "cba.dev/drain-grace"   // pod termination grace passed with each eviction
+
+	// Cooldown overrides (Go durations), taking precedence over cooldown / bootCooldown
+	AnnotationCooldown     = "cba.dev/cooldown"      // e.g. "1h" for nodes that should stay off longer
+	AnnotationBootCooldown = "cba.dev/boot-cooldown" // e.g. "20m" for slow-booting hardware
+
+	// Testing / staged rollouts
+	AnnotationLoadOverride = "cba.dev/load-override" // forced normalized load (honored only with allowLoadOverrides)
+
+	// Load aggregation
+	AnnotationExcludeFromAggregate = "cba.dev/exclude-from-aggregate" // "true": keep out of cluster load math, still a scale-down target
+
+	// Operator requests
+	AnnotationMaintenance = "cba.dev/maintenance" // "true": drain and power off for hardware service, keep off until removed
+
+	// LabelObserveOnly keeps a node in all listings and decisions but blocks every action on it.
+	LabelObserveOnly = "cba.dev/observe-only"
+
+	// TaintPoweringOff is the NoSchedule taint CBA cordons with under cordonMode "taint".
+	TaintPoweringOff = "cba.dev/powering-off"
 )
 
 // PoweredOffSince returns the timestamp when the node was marked powered-off,
@@ -34,3 +70,64 @@ func PoweredOffSince(n v1.Node) (time.Time, bool) {
 	// Unknown value → force to "oldest"
 	return time.Unix(0, 0).UTC(), true
 }
+
+// RunningSince returns when the node was last started: the booted-at annotation written
+// by CBA, or else the time its Ready condition last turned true.
+func RunningSince(n v1.Node) (time.Time, bool) {
+	if raw := n.Annotations[AnnotationBootedAt]; raw != "" {
+		if t, err := time.Parse(time.RFC3339, raw); err == nil {
+			return t.UTC(), true
+		}
+	}
+	for _, cond := range n.Status.Conditions {
+		if cond.Type == v1.NodeReady && cond.Status == v1.ConditionTrue && !cond.LastTransitionTime.IsZero() {
+			return cond.LastTransitionTime.UTC(), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// bootDurationWeight is the share of the newest sample in the smoothed boot duration, so one slow
+// boot (e.g. a firmware update) doesn't immediately demote an otherwise fast node.
+const bootDurationWeight = 0.3
+
+// BootDuration returns the node's smoothed historical boot time, if one was recorded.
+func BootDuration(n v1.Node) (time.Duration, bool) {
+	raw := n.Annotations[AnnotationBootDuration]
+	if raw == "" {
+		return 0, false
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d < 0 {
+		return 0, false
+	}
+	return d, true
+}
+
+// SmoothBootDuration folds a new boot-time sample into the node's recorded history.
+func SmoothBootDuration(n v1.Node, sample time.Duration) time.Duration {
+	prev, ok := BootDuration(n)
+	if !ok {
+		return sample.Round(time.Second)
+	}
+	smoothed := float64(prev)*(1-bootDurationWeight) + float64(sample)*bootDurationWeight
+	return time.Duration(smoothed).Round(time.Second)
+}
+
+// PatchAnnotations applies all given annotation changes to a node in a single merge patch.
+// A nil value deletes the annotation; a non-nil value sets it.
+func PatchAnnotations(ctx context.Context, client kubernetes.Interface, nodeName string, changes map[string]*string) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			"annotations": changes,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("encode annotation patch: %w", err)
+	}
+	_, err = client.CoreV1().Nodes().Patch(ctx, nodeName, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
@@ -1,11 +1,14 @@
 package nodeops_test
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
 )
 
 func TestPoweredOffSince_NoAnnotation(t *testing.T) {
@@ -40,3 +43,86 @@ func TestPoweredOffSince_InvalidBecomesOldest(t *testing.T) {
 		t.Fatalf("got %v, want Unix(0)", got)
 	}
 }
+
+func TestPatchAnnotations_SinglePatchSetsAndDeletes(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset(&v1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name: "node1",
+		Annotations: map[string]string{
+			nodeops.AnnotationPoweredOff: "2024-01-01T00:00:00Z",
+			"keep":                       "me",
+		},
+	}})
+
+	mac := "aa:bb:cc:dd:ee:ff"
+	other := "value"
+	err := nodeops.PatchAnnotations(ctx, client, "node1", map[string]*string{
+		nodeops.AnnotationPoweredOff: nil,
+		nodeops.AnnotationMACAuto:    &mac,
+		"example.com/other":          &other,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	patches := 0
+	for _, a := range client.Actions() {
+		if a.GetVerb() == "patch" {
+			patches++
+		}
+	}
+	if patches != 1 {
+		t.Fatalf("expected exactly one patch call, got %d", patches)
+	}
+
+	got, _ := client.CoreV1().Nodes().Get(ctx, "node1", metav1.GetOptions{})
+	if _, ok := got.Annotations[nodeops.AnnotationPoweredOff]; ok {
+		t.Errorf("expected powered-off annotation to be deleted")
+	}
+	if got.Annotations[nodeops.AnnotationMACAuto] != mac || got.Annotations["example.com/other"] != other {
+		t.Errorf("expected new annotations to be set, got %v", got.Annotations)
+	}
+	if got.Annotations["keep"] != "me" {
+		t.Errorf("expected untouched annotation to remain, got %v", got.Annotations)
+	}
+}
+
+func TestPatchAnnotations_NoChangesIsNoop(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	if err := nodeops.PatchAnnotations(context.Background(), client, "missing", nil); err != nil {
+		t.Fatalf("expected no error for empty change set, got: %v", err)
+	}
+	if len(client.Actions()) != 0 {
+		t.Errorf("expected no API calls, got %d", len(client.Actions()))
+	}
+}
+
+func TestRunningSince(t *testing.T) {
+	booted := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	readySince := booted.Add(-time.Hour)
+	ready := v1.NodeStatus{Conditions: []v1.NodeCondition{{
+		Type: v1.NodeReady, Status: v1.ConditionTrue, LastTransitionTime: metav1.NewTime(readySince),
+	}}}
+
+	cases := []struct {
+		name   string
+		node   v1.Node
+		want   time.Time
+		wantOK bool
+	}{
+		{"booted-at annotation wins", v1.Node{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+			nodeops.AnnotationBootedAt: booted.Format(time.RFC3339)}}, Status: ready}, booted, true},
+		{"falls back to Ready transition", v1.Node{Status: ready}, readySince, true},
+		{"unparseable annotation falls back", v1.Node{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+			nodeops.AnnotationBootedAt: "yesterday"}}, Status: ready}, readySince, true},
+		{"unknown when not Ready", v1.Node{}, time.Time{}, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := nodeops.RunningSince(tc.node)
+			if ok != tc.wantOK || !got.Equal(tc.want) {
+				t.Errorf("RunningSince = %v, %v; want %v, %v", got, ok, tc.want, tc.wantOK)
+			}
+		})
+	}
+}
@@ -4,9 +4,16 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/agenthttp"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes"
 	"log/slog"
 	"net/http"
+	"path"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -15,15 +22,76 @@ var (
 	FetchMACFunc  = FetchMACFromDaemon
 )
 
+// macFetchTimeout bounds one /mac call, so an unresponsive daemon cannot stall discovery or a scale-down.
+const macFetchTimeout = 10 * time.Second
+
 type MACUpdaterConfig struct {
-	DryRun        bool
-	Interval      time.Duration
-	Port          int
-	Namespace     string
-	PodLabel      string
-	ManagedLabel  string
-	DisabledLabel string
-	IgnoreLabels  map[string]string
+	DryRun          bool
+	Interval        time.Duration
+	Port            int
+	Namespace       string
+	PodLabel        string
+	ManagedLabel    string
+	ManagedSelector labels.Selector
+	DisabledLabel   string
+	IgnoreLabels    map[string]string
+	// Concurrency bounds parallel MAC fetches per cycle; values < 1 mean sequential.
+	Concurrency int
+	// InterfacePreference lists NIC name patterns in order of preference (see MACReport.SelectMAC).
+	InterfacePreference []string
+	// HTTP carries the agentHTTP auth to the poweroff daemon; nil means plain http without auth.
+	HTTP *agenthttp.Client
+}
+
+// NICAddress is a single network interface reported by the poweroff daemon.
+type NICAddress struct {
+	Name string `json:"name"`
+	MAC  string `json:"mac"`
+}
+
+// MACReport is the /mac response of the poweroff daemon.
+type MACReport struct {
+	Interface  string       `json:"interface"` // default-route interface
+	MAC        string       `json:"mac"`
+	Interfaces []NICAddress `json:"interfaces,omitempty"` // all interfaces with a hardware address
+}
+
+// SelectMAC returns the interface and MAC to use for WOL. The first preference pattern
+// (path.Match glob, e.g. "enp*") matching a reported interface wins; patterns are tried
+// in order. Without a match it falls back to the default-route interface.
+func (r MACReport) SelectMAC(preference []string) (string, string) {
+	for _, pattern := range preference {
+		for _, nic := range r.Interfaces {
+			if nic.MAC == "" {
+				continue
+			}
+			if ok, _ := path.Match(pattern, nic.Name); ok {
+				return nic.Name, nic.MAC
+			}
+		}
+	}
+	return r.Interface, r.MAC
+}
+
+// NewMACUpdaterConfig builds the MAC updater settings from the controller config. agent is the
+// shared agent HTTP client.
+func NewMACUpdaterConfig(cfg *config.Config, agent *agenthttp.Client) MACUpdaterConfig {
+	filter := NewManagedNodeFilter(cfg)
+	return MACUpdaterConfig{
+		DryRun:          cfg.IsK8sDryRun(),
+		ManagedLabel:    filter.ManagedLabel,
+		ManagedSelector: filter.ManagedSelector,
+		DisabledLabel:   filter.DisabledLabel,
+		IgnoreLabels:    filter.IgnoreLabels,
+		Interval:        cfg.MACDiscoveryInterval,
+		Namespace:       cfg.ShutdownManager.Namespace,
+		PodLabel:        cfg.ShutdownManager.PodLabel,
+		Port:            cfg.ShutdownManager.Port,
+
+		Concurrency:         cfg.MACDiscoveryConcurrency,
+		InterfacePreference: cfg.WOLInterfacePreference,
+		HTTP:                agent,
+	}
 }
 
 func StartMACAnnotationUpdater(client kubernetes.Interface, cfg MACUpdaterConfig) {
@@ -34,7 +102,7 @@ func StartMACAnnotationUpdater(client kubernetes.Interface, cfg MACUpdaterConfig
 
 		for {
 			RunOnce(client, cfg)
-			time.Sleep(10 * time.Second)
+			<-ticker.C
 		}
 	}()
 }
@@ -43,19 +111,21 @@ func RunOnce(client kubernetes.Interface, cfg MACUpdaterConfig) {
 	ctx := context.Background()
 
 	nodes, err := ListManagedNodes(ctx, client, ManagedNodeFilter{
-		ManagedLabel:  cfg.ManagedLabel,
-		DisabledLabel: cfg.DisabledLabel,
-		IgnoreLabels:  cfg.IgnoreLabels,
+		ManagedLabel:    cfg.ManagedLabel,
+		ManagedSelector: cfg.ManagedSelector,
+		DisabledLabel:   cfg.DisabledLabel,
+		IgnoreLabels:    cfg.IgnoreLabels,
 	})
 	if err != nil {
 		slog.Warn("MAC updater: failed to list managed nodes", "err", err)
 		return
 	}
 
+	// Only nodes missing a MAC need a daemon round-trip; the rest are skipped up front.
 	now := time.Now()
-
-	for _, rawNode := range nodes {
-		node := NewNodeWrapper(&rawNode, nil, now, NodeAnnotationConfig{}, nil)
+	var pending []*NodeWrapper
+	for i := range nodes {
+		node := NewNodeWrapper(&nodes[i], nil, now, NodeAnnotationConfig{}, nil)
 
 		// Skip if manual override is set
 		if node.HasManualMACOverride() {
@@ -68,54 +138,148 @@ func RunOnce(client kubernetes.Interface, cfg MACUpdaterConfig) {
 			slog.Debug("Skipping MAC update for node with existing auto annotation", "node", node.Name)
 			continue
 		}
+		pending = append(pending, node)
+	}
 
-		ip, err := FindPodIPFunc(ctx, client, cfg.Namespace, cfg.PodLabel, node.Name)
-		if err != nil {
-			slog.Warn("MAC updater: failed to find Pod IP", "node", node.Name, "err", err)
-			continue
-		}
+	workers := max(cfg.Concurrency, 1)
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for _, node := range pending {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			updateNodeMAC(ctx, client, cfg, node)
+		}()
+	}
+	wg.Wait()
+}
 
-		mac, err := FetchMACFunc(ctx, ip, cfg.Port)
-		if err != nil {
-			slog.Warn("MAC updater: failed to fetch MAC from daemon", "node", node.Name, "err", err)
-			continue
-		}
+// DiscoverNodeMAC runs one MAC discovery for a single node outside the periodic cycle, for a node
+// that is needed now (e.g. a scale-up target). It returns whether a MAC annotation was applied.
+func DiscoverNodeMAC(ctx context.Context, client kubernetes.Interface, cfg MACUpdaterConfig, nodeName string) bool {
+	n, err := client.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		slog.Warn("MAC discovery: failed to get node", "node", nodeName, "err", err)
+		return false
+	}
+	node := NewNodeWrapper(n, nil, time.Now(), NodeAnnotationConfig{}, nil)
+	if node.HasManualMACOverride() || node.HasDiscoveredMACAddr() {
+		return true
+	}
+	return updateNodeMAC(ctx, client, cfg, node)
+}
 
-		slog.Debug("Discovered MAC address", "node", node.Name, "mac", mac)
+func updateNodeMAC(ctx context.Context, client kubernetes.Interface, cfg MACUpdaterConfig, node *NodeWrapper) bool {
+	ip, err := FindPodIPFunc(ctx, client, cfg.Namespace, cfg.PodLabel, node.Name)
+	if err != nil {
+		slog.Warn("MAC updater: failed to find Pod IP", "node", node.Name, "err", err)
+		return false
+	}
 
-		if err := node.SetDiscoveredMAC(ctx, client, mac, cfg.DryRun); err != nil {
-			continue
-		}
+	report, err := fetchMAC(ctx, cfg, ip)
+	if err != nil {
+		slog.Warn("MAC updater: failed to fetch MAC from daemon", "node", node.Name, "err", err)
+		return false
+	}
+
+	iface, mac := report.SelectMAC(cfg.InterfacePreference)
+	if mac == "" {
+		slog.Warn("MAC updater: daemon reported no usable MAC", "node", node.Name)
+		return false
+	}
+	slog.Debug("Discovered MAC address", "node", node.Name, "mac", mac, "interface", iface)
+
+	if err := node.SetDiscoveredMAC(ctx, client, iface, mac, cfg.DryRun); err != nil {
+		return false
+	}
+
+	slog.Info("MAC annotation applied", "node", node.Name, "mac", mac, "interface", iface)
+	return true
+}
+
+// MACCheck is the result of comparing a node's annotated WOL MAC with its poweroff daemon's report.
+type MACCheck struct {
+	Annotated string // effective annotation: manual override, else auto-discovered
+	Manual    bool   // Annotated is the manual override
+	Interface string // interface SelectMAC picks from the current report
+	Current   string // MAC SelectMAC picks from the current report
+	Drift     bool
+}
+
+// VerifyNodeMAC asks the node's poweroff daemon for its interfaces while the node is still up.
+// An auto-discovered MAC drifts when it differs from the currently selected one; a manual override
+// only when no reported interface carries it, since operators may pick a non-primary NIC on purpose.
+func VerifyNodeMAC(ctx context.Context, client kubernetes.Interface, cfg MACUpdaterConfig, node *NodeWrapper) (MACCheck, error) {
+	check := MACCheck{Annotated: node.Annotations[AnnotationMACAuto]}
+	if node.HasManualMACOverride() {
+		check.Annotated, check.Manual = node.Annotations[AnnotationMACManual], true
+	}
+
+	ip, err := FindPodIPFunc(ctx, client, cfg.Namespace, cfg.PodLabel, node.Name)
+	if err != nil {
+		return check, fmt.Errorf("finding poweroff daemon on %s: %w", node.Name, err)
+	}
+	report, err := fetchMAC(ctx, cfg, ip)
+	if err != nil {
+		return check, fmt.Errorf("fetching MAC of %s: %w", node.Name, err)
+	}
+	check.Interface, check.Current = report.SelectMAC(cfg.InterfacePreference)
+	if check.Current == "" {
+		return check, fmt.Errorf("daemon on %s reported no usable MAC", node.Name)
+	}
 
-		slog.Info("MAC annotation applied", "node", node.Name, "mac", mac)
+	if !check.Manual {
+		check.Drift = !strings.EqualFold(check.Annotated, check.Current)
+		return check, nil
 	}
+	check.Drift = true
+	for _, mac := range append([]string{report.MAC}, nicMACs(report.Interfaces)...) {
+		if strings.EqualFold(mac, check.Annotated) {
+			check.Drift = false
+			break
+		}
+	}
+	return check, nil
 }
 
-func FetchMACFromDaemon(ctx context.Context, ip string, port int) (string, error) {
-	var url string
-	if port == 0 {
-		url = fmt.Sprintf("http://%s/mac", ip)
-	} else {
-		url = fmt.Sprintf("http://%s:%d/mac", ip, port)
+func nicMACs(nics []NICAddress) []string {
+	out := make([]string, 0, len(nics))
+	for _, nic := range nics {
+		out = append(out, nic.MAC)
 	}
+	return out
+}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+// fetchMAC queries the poweroff daemon at ip with the configured auth, bounded by macFetchTimeout.
+func fetchMAC(ctx context.Context, cfg MACUpdaterConfig, ip string) (MACReport, error) {
+	ctx, cancel := context.WithTimeout(ctx, macFetchTimeout)
+	defer cancel()
+	return FetchMACFunc(ctx, cfg.HTTP, ip, cfg.Port)
+}
+
+// FetchMACFromDaemon calls the poweroff daemon's /mac endpoint at ip:port through client.
+func FetchMACFromDaemon(ctx context.Context, client *agenthttp.Client, ip string, port int) (MACReport, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, client.URL(ip, port, "/mac"), nil)
 	if err != nil {
-		return "", fmt.Errorf("creating MAC request: %w", err)
+		return MACReport{}, fmt.Errorf("creating MAC request: %w", err)
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("sending MAC request: %w", err)
+		return MACReport{}, fmt.Errorf("sending MAC request: %w", err)
 	}
 	defer resp.Body.Close()
 
-	var result struct {
-		MAC string `json:"mac"`
+	if resp.StatusCode != http.StatusOK {
+		return MACReport{}, fmt.Errorf("unexpected MAC response status: %s", resp.Status)
 	}
+
+	var result MACReport
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", fmt.Errorf("decoding MAC response: %w", err)
+		return MACReport{}, fmt.Errorf("decoding MAC response: %w", err)
 	}
 
-	return result.MAC, nil
+	return result, nil
 }
@@ -4,11 +4,17 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/agenthttp"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
 	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -23,7 +29,7 @@ func TestRunOnce_AnnotatesDiscoveredMAC(t *testing.T) {
 		_ = json.NewEncoder(w).Encode(map[string]string{"mac": "aa:bb:cc:dd:ee:ff"})
 	}))
 	defer macServer.Close()
-	macIP := strings.TrimPrefix(macServer.URL, "http://")
+	macIP, macPort := serverHostPort(t, macServer)
 
 	client := fake.NewSimpleClientset(&v1.Node{
 		ObjectMeta: metav1.ObjectMeta{
@@ -58,7 +64,7 @@ func TestRunOnce_AnnotatesDiscoveredMAC(t *testing.T) {
 		ManagedLabel:  "cba.dev/is-managed",
 		DisabledLabel: "cba.dev/disabled",
 		IgnoreLabels:  map[string]string{},
-		Port:          0,
+		Port:          macPort,
 	})
 
 	if !called {
@@ -80,8 +86,8 @@ func TestRunOnce_DryRunSkipsPatch(t *testing.T) {
 	nodeops.FindPodIPFunc = func(_ context.Context, _ kubernetes.Interface, _, _, node string) (string, error) {
 		return "dummy", nil
 	}
-	nodeops.FetchMACFunc = func(_ context.Context, _ string, _ int) (string, error) {
-		return "11:22:33:44:55:66", nil
+	nodeops.FetchMACFunc = func(_ context.Context, _ *agenthttp.Client, _ string, _ int) (nodeops.MACReport, error) {
+		return nodeops.MACReport{MAC: "11:22:33:44:55:66"}, nil
 	}
 
 	called := false
@@ -104,3 +110,180 @@ func TestRunOnce_DryRunSkipsPatch(t *testing.T) {
 		t.Error("expected no patch call in dry-run mode")
 	}
 }
+
+func TestMACReport_SelectMAC(t *testing.T) {
+	report := nodeops.MACReport{
+		Interface: "bond0",
+		MAC:       "00:00:00:00:00:01",
+		Interfaces: []nodeops.NICAddress{
+			{Name: "bond0", MAC: "00:00:00:00:00:01"},
+			{Name: "enp3s0f1", MAC: "00:00:00:00:00:02"},
+			{Name: "eno1", MAC: "00:00:00:00:00:03"},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		preference []string
+		wantIface  string
+		wantMAC    string
+	}{
+		{"no preference uses default route", nil, "bond0", "00:00:00:00:00:01"},
+		{"exact name wins", []string{"eno1", "enp*"}, "eno1", "00:00:00:00:00:03"},
+		{"glob matches when first pattern absent", []string{"eno9", "enp*"}, "enp3s0f1", "00:00:00:00:00:02"},
+		{"no match falls back", []string{"wlan*"}, "bond0", "00:00:00:00:00:01"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			iface, mac := report.SelectMAC(tt.preference)
+			if iface != tt.wantIface || mac != tt.wantMAC {
+				t.Errorf("SelectMAC(%v) = %s/%s, want %s/%s", tt.preference, iface, mac, tt.wantIface, tt.wantMAC)
+			}
+		})
+	}
+}
+
+func TestRunOnce_AnnotatesPreferredNIC(t *testing.T) {
+	macServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(nodeops.MACReport{
+			Interface: "bond0",
+			MAC:       "aa:aa:aa:aa:aa:aa",
+			Interfaces: []nodeops.NICAddress{
+				{Name: "bond0", MAC: "aa:aa:aa:aa:aa:aa"},
+				{Name: "eno1", MAC: "bb:bb:bb:bb:bb:bb"},
+			},
+		})
+	}))
+	defer macServer.Close()
+	macIP, macPort := serverHostPort(t, macServer)
+
+	client := fake.NewSimpleClientset(&v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "node1",
+			Labels: map[string]string{"cba.dev/is-managed": "true"},
+		},
+	})
+
+	nodeops.FindPodIPFunc = func(_ context.Context, _ kubernetes.Interface, _, _, _ string) (string, error) {
+		return macIP, nil
+	}
+	nodeops.FetchMACFunc = nodeops.FetchMACFromDaemon
+
+	nodeops.RunOnce(client, nodeops.MACUpdaterConfig{
+		Namespace:           "ns",
+		PodLabel:            "label",
+		ManagedLabel:        "cba.dev/is-managed",
+		Port:                macPort,
+		InterfacePreference: []string{"eno*"},
+	})
+
+	node, err := client.CoreV1().Nodes().Get(context.Background(), "node1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get node: %v", err)
+	}
+	if got := node.Annotations[nodeops.AnnotationMACAuto]; got != "bb:bb:bb:bb:bb:bb" {
+		t.Errorf("expected preferred NIC MAC, got %q", got)
+	}
+	if got := node.Annotations[nodeops.AnnotationMACIface]; got != "eno1" {
+		t.Errorf("expected interface annotation eno1, got %q", got)
+	}
+}
+
+func TestFetchMACFromDaemon_UsesAgentAuthAndChecksStatus(t *testing.T) {
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/mac" || r.Header.Get("X-Agent-Key") != "secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(nodeops.MACReport{Interface: "eno1", MAC: "aa:bb:cc:dd:ee:ff"})
+	}))
+	defer srv.Close()
+	host, port := serverHostPort(t, srv)
+
+	agent, err := agenthttp.NewClient(config.AgentHTTPConfig{Headers: map[string]string{"X-Agent-Key": "secret"}}, nil)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	report, err := nodeops.FetchMACFromDaemon(context.Background(), agent, host, port)
+	if err != nil || report.MAC != "aa:bb:cc:dd:ee:ff" {
+		t.Fatalf("FetchMACFromDaemon = %+v, %v; want the daemon's MAC", report, err)
+	}
+
+	if _, err := nodeops.FetchMACFromDaemon(context.Background(), nil, host, port); err == nil {
+		t.Error("expected an error without the agent headers")
+	}
+
+	status = http.StatusServiceUnavailable
+	if _, err := nodeops.FetchMACFromDaemon(context.Background(), agent, host, port); err == nil {
+		t.Error("expected an error for a non-200 response")
+	}
+}
+
+func serverHostPort(t *testing.T, srv *httptest.Server) (string, int) {
+	t.Helper()
+	host, portStr, err := net.SplitHostPort(strings.TrimPrefix(srv.URL, "http://"))
+	if err != nil {
+		t.Fatalf("parse server URL: %v", err)
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		t.Fatalf("parse server port: %v", err)
+	}
+	return host, port
+}
+
+func TestRunOnce_ProcessesMissingMACsConcurrentlyAndSkipsKnown(t *testing.T) {
+	managed := map[string]string{"cba.dev/is-managed": "true"}
+	var objs []runtime.Object
+	for i := 0; i < 6; i++ {
+		objs = append(objs, &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("missing-%d", i), Labels: managed}})
+	}
+	objs = append(objs,
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "known", Labels: managed,
+			Annotations: map[string]string{nodeops.AnnotationMACAuto: "aa:aa:aa:aa:aa:aa"}}},
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "manual", Labels: managed,
+			Annotations: map[string]string{nodeops.AnnotationMACManual: "bb:bb:bb:bb:bb:bb"}}},
+	)
+	client := fake.NewSimpleClientset(objs...)
+
+	nodeops.FindPodIPFunc = func(_ context.Context, _ kubernetes.Interface, _, _, node string) (string, error) {
+		return node, nil
+	}
+	var mu sync.Mutex
+	fetched := map[string]bool{}
+	nodeops.FetchMACFunc = func(_ context.Context, _ *agenthttp.Client, ip string, _ int) (nodeops.MACReport, error) {
+		time.Sleep(200 * time.Millisecond)
+		mu.Lock()
+		fetched[ip] = true
+		mu.Unlock()
+		return nodeops.MACReport{Interface: "eno1", MAC: "11:22:33:44:55:66"}, nil
+	}
+	defer func() { nodeops.FetchMACFunc = nodeops.FetchMACFromDaemon }()
+
+	start := time.Now()
+	nodeops.RunOnce(client, nodeops.MACUpdaterConfig{
+		ManagedLabel: "cba.dev/is-managed",
+		Concurrency:  3,
+	})
+	elapsed := time.Since(start)
+
+	// 6 fetches of 200ms with 3 workers take ~400ms; sequential would be 1.2s.
+	if elapsed >= time.Second {
+		t.Errorf("expected bounded concurrent fetches, took %s", elapsed)
+	}
+	if fetched["known"] || fetched["manual"] {
+		t.Errorf("nodes with a MAC must not be fetched, got %v", fetched)
+	}
+	for i := 0; i < 6; i++ {
+		name := fmt.Sprintf("missing-%d", i)
+		node, err := client.CoreV1().Nodes().Get(context.Background(), name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("get %s: %v", name, err)
+		}
+		if node.Annotations[nodeops.AnnotationMACAuto] != "11:22:33:44:55:66" {
+			t.Errorf("expected %s to be annotated in one cycle", name)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package nodeops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+)
+
+// stateAnnotations are the annotations CBA writes itself. They are cleared from nodes that are no
+// longer managed; operator-set annotations (MAC override, drain overrides, BMC settings) are kept.
+var stateAnnotations = []string{
+	AnnotationPoweredOff,
+	AnnotationBootedAt,
+	AnnotationRecyclePending,
+	AnnotationCordonedAt,
+	AnnotationBootDuration,
+	AnnotationMACAuto,
+	AnnotationMACIface,
+}
+
+// MigrateLabels runs the startup label migration: every node carrying an old label key from
+// migrateLabels.labels gets the new key with the same value and loses the old one. Afterwards, nodes
+// that don't match nodeLabels.managed (or nodeLabels.managedSelector) have CBA's state annotations
+// removed, so they no longer look powered off or recently booted. It returns the number of nodes changed.
+func MigrateLabels(ctx context.Context, client kubernetes.Interface, cfg *config.Config) (int, error) {
+	nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return 0, fmt.Errorf("listing nodes: %w", err)
+	}
+	dryRun := cfg.IsK8sDryRun()
+	filter := NewManagedNodeFilter(cfg)
+
+	changed := 0
+	for _, n := range nodes.Items {
+		labels := map[string]any{}
+		migrated := make(map[string]string, len(n.Labels))
+		maps.Copy(migrated, n.Labels)
+		for oldKey, newKey := range cfg.MigrateLabels.Labels {
+			val, ok := n.Labels[oldKey]
+			if !ok || oldKey == newKey {
+				continue
+			}
+			if _, exists := n.Labels[newKey]; !exists {
+				labels[newKey] = val
+				migrated[newKey] = val
+			}
+			labels[oldKey] = nil
+			delete(migrated, oldKey)
+		}
+		managed := filter.Manages(migrated)
+
+		annotations := map[string]any{}
+		if !managed {
+			for _, key := range stateAnnotations {
+				if _, ok := n.Annotations[key]; ok {
+					annotations[key] = nil
+				}
+			}
+		}
+		if len(labels) == 0 && len(annotations) == 0 {
+			continue
+		}
+
+		if dryRun {
+			slog.Info("Dry-run: would migrate node labels", "node", n.Name, "labels", labels, "clearAnnotations", len(annotations))
+			changed++
+			continue
+		}
+		meta := map[string]any{}
+		if len(labels) > 0 {
+			meta["labels"] = labels
+		}
+		if len(annotations) > 0 {
+			meta["annotations"] = annotations
+		}
+		patch, err := json.Marshal(map[string]any{"metadata": meta})
+		if err != nil {
+			return changed, fmt.Errorf("encode migration patch: %w", err)
+		}
+		if _, err := client.CoreV1().Nodes().Patch(ctx, n.Name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+			return changed, fmt.Errorf("migrating node %s: %w", n.Name, err)
+		}
+		slog.Info("Migrated node labels", "node", n.Name, "labels", labels, "clearedAnnotations", len(annotations))
+		changed++
+	}
+	return changed, nil
+}
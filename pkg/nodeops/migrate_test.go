@@ -0,0 +1,112 @@
+package nodeops_test
+
+import (
+	"context"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
+)
+
+func migrationNode(name string, labels, annotations map[string]string) *v1.Node {
+	return &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels, Annotations: annotations}}
+}
+
+func TestMigrateLabels(t *testing.T) {
+	state := map[string]string{
+		nodeops.AnnotationPoweredOff: "2025-01-01T00:00:00Z",
+		nodeops.AnnotationMACAuto:    "aa:bb:cc:dd:ee:ff",
+		nodeops.AnnotationMACManual:  "aa:bb:cc:dd:ee:01",
+	}
+	copyOf := func(m map[string]string) map[string]string {
+		out := map[string]string{}
+		for k, v := range m {
+			out[k] = v
+		}
+		return out
+	}
+	client := fake.NewSimpleClientset(
+		migrationNode("old-scheme", map[string]string{"scaling-managed-by-cba": "true"}, copyOf(state)),
+		migrationNode("current", map[string]string{"cba.dev/is-managed": "true"}, copyOf(state)),
+		migrationNode("unmanaged", map[string]string{"role": "db"}, copyOf(state)),
+		migrationNode("opted-out", map[string]string{"scaling-managed-by-cba": "false"}, copyOf(state)),
+	)
+	cfg := &config.Config{
+		NodeLabels: config.NodeLabelConfig{Managed: "cba.dev/is-managed"},
+		MigrateLabels: config.LabelMigrationConfig{
+			Enabled: true,
+			Labels:  map[string]string{"scaling-managed-by-cba": "cba.dev/is-managed"},
+		},
+	}
+
+	changed, err := nodeops.MigrateLabels(context.Background(), client, cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if changed != 3 {
+		t.Errorf("expected 3 nodes changed, got %d", changed)
+	}
+
+	get := func(name string) *v1.Node {
+		n, err := client.CoreV1().Nodes().Get(context.Background(), name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("get %s: %v", name, err)
+		}
+		return n
+	}
+
+	for _, name := range []string{"old-scheme", "current"} {
+		n := get(name)
+		if n.Labels["cba.dev/is-managed"] != "true" {
+			t.Errorf("%s: expected managed label, got %v", name, n.Labels)
+		}
+		if _, ok := n.Labels["scaling-managed-by-cba"]; ok {
+			t.Errorf("%s: old label must be removed", name)
+		}
+		if len(n.Annotations) != len(state) {
+			t.Errorf("%s: managed node must keep its annotations, got %v", name, n.Annotations)
+		}
+	}
+
+	for _, name := range []string{"unmanaged", "opted-out"} {
+		n := get(name)
+		if _, ok := n.Annotations[nodeops.AnnotationPoweredOff]; ok {
+			t.Errorf("%s: state annotation must be cleared", name)
+		}
+		if _, ok := n.Annotations[nodeops.AnnotationMACAuto]; ok {
+			t.Errorf("%s: discovered MAC must be cleared", name)
+		}
+		if n.Annotations[nodeops.AnnotationMACManual] == "" {
+			t.Errorf("%s: operator-set MAC override must be kept", name)
+		}
+	}
+	if get("opted-out").Labels["cba.dev/is-managed"] != "false" {
+		t.Error("label value must carry over to the new key")
+	}
+}
+
+func TestMigrateLabels_DryRun(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		migrationNode("old-scheme", map[string]string{"scaling-managed-by-cba": "true"}, nil),
+	)
+	cfg := &config.Config{
+		DryRun:     true,
+		NodeLabels: config.NodeLabelConfig{Managed: "cba.dev/is-managed"},
+		MigrateLabels: config.LabelMigrationConfig{
+			Enabled: true,
+			Labels:  map[string]string{"scaling-managed-by-cba": "cba.dev/is-managed"},
+		},
+	}
+
+	if _, err := nodeops.MigrateLabels(context.Background(), client, cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	n, _ := client.CoreV1().Nodes().Get(context.Background(), "old-scheme", metav1.GetOptions{})
+	if _, ok := n.Labels["cba.dev/is-managed"]; ok {
+		t.Error("dry run must not relabel nodes")
+	}
+}
@@ -2,9 +2,6 @@ package nodeops
 
 import (
 	"context"
-	"fmt"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/types"
 	"log/slog"
 	"time"
 
@@ -30,26 +27,66 @@ func NewNodeWrapper(n *v1.Node, state *NodeStateTracker, now time.Time, keys Nod
 	}
 }
 
-func (n *NodeWrapper) SetDiscoveredMAC(ctx context.Context, client kubernetes.Interface, mac string, dryRun bool) error {
+// SetDiscoveredMAC annotates the node with its auto-discovered MAC and, when known,
+// the name of the interface it was taken from.
+func (n *NodeWrapper) SetDiscoveredMAC(ctx context.Context, client kubernetes.Interface, iface, mac string, dryRun bool) error {
 	if dryRun {
-		slog.Debug("Dry-run: would annotate node with discovered MAC", "node", n.Name, "mac", mac)
+		slog.Debug("Dry-run: would annotate node with discovered MAC", "node", n.Name, "mac", mac, "interface", iface)
 		return nil
 	}
 
-	patch := fmt.Appendf(nil, `{"metadata":{"annotations":{"%s":"%s"}}}`, AnnotationMACAuto, mac)
-	_, err := client.CoreV1().Nodes().Patch(ctx, n.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	changes := map[string]*string{AnnotationMACAuto: &mac}
+	if iface != "" {
+		changes[AnnotationMACIface] = &iface
+	}
+	err := PatchAnnotations(ctx, client, n.Name, changes)
 	if err != nil {
 		slog.Warn("Failed to patch node with discovered MAC", "node", n.Name, "err", err)
 	}
 	return err
 }
 
+// IsInShutdownCooldown reports whether the node was powered off less than its cba.dev/cooldown
+// annotation (or duration, when unset) ago.
 func (n *NodeWrapper) IsInShutdownCooldown(duration time.Duration) bool {
+	duration = n.durationOverride(AnnotationCooldown, duration)
 	return n.State != nil && n.State.IsInCooldown(n.Name, n.Now, duration)
 }
 
+// IsInBootCooldown reports whether the node was powered on less than its cba.dev/boot-cooldown
+// annotation (or duration, when unset) ago. The in-memory tracker is authoritative; without an
+// entry (e.g. after a restart) the booted-at annotation is used.
 func (n *NodeWrapper) IsInBootCooldown(duration time.Duration) bool {
-	return n.State != nil && n.State.IsBootCooldownActive(n.Name, n.Now, duration)
+	duration = n.durationOverride(AnnotationBootCooldown, duration)
+	if n.State != nil {
+		if _, ok := n.State.LastBooted(n.Name); ok {
+			return n.State.IsBootCooldownActive(n.Name, n.Now, duration)
+		}
+	}
+	raw := n.Annotations[AnnotationBootedAt]
+	if raw == "" {
+		return false
+	}
+	bootedAt, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		slog.Debug("Ignoring unparsable booted-at annotation", "node", n.Name, "value", raw)
+		return false
+	}
+	return n.Now.Sub(bootedAt) < duration
+}
+
+// durationOverride returns the node's duration annotation key when set and valid, otherwise fallback.
+func (n *NodeWrapper) durationOverride(key string, fallback time.Duration) time.Duration {
+	raw, ok := n.Annotations[key]
+	if !ok || raw == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d < 0 {
+		slog.Warn("Ignoring invalid cooldown annotation; using config default", "node", n.Name, "annotation", key, "value", raw)
+		return fallback
+	}
+	return d
 }
 
 func (n *NodeWrapper) HasDiscoveredMACAddr() bool {
@@ -60,8 +97,9 @@ func (n *NodeWrapper) HasManualMACOverride() bool {
 	return n.Annotations[AnnotationMACManual] != ""
 }
 
+// IsCordoned reports whether the node is cordoned in either form (see IsCordoned).
 func (n *NodeWrapper) IsCordoned() bool {
-	return n.Spec.Unschedulable
+	return IsCordoned(n.Node)
 }
 
 func (n *NodeWrapper) IsReady() bool {
@@ -89,6 +127,26 @@ func (n *NodeWrapper) IsIgnored() bool {
 	return false
 }
 
+// HasExcludedTaint reports whether the node carries a taint matching one of taints by key, and by
+// value and effect where those are set.
+func (n *NodeWrapper) HasExcludedTaint(taints []v1.Taint) bool {
+	for _, want := range taints {
+		for _, t := range n.Spec.Taints {
+			if t.Key == want.Key &&
+				(want.Value == "" || t.Value == want.Value) &&
+				(want.Effect == "" || t.Effect == want.Effect) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsObserveOnly reports whether the node is labeled observe-only: CBA evaluates it but never acts on it.
+func (n *NodeWrapper) IsObserveOnly() bool {
+	return IsObserveOnly(n.Node)
+}
+
 func (n *NodeWrapper) GetEffectiveMACAddress() string {
 	manual := n.Annotations[AnnotationMACManual]
 	if manual != "" {
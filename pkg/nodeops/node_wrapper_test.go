@@ -113,7 +113,7 @@ func TestNodeWrapper_SetDiscoveredMAC_DryRun(t *testing.T) {
 	client := fake.NewSimpleClientset(n)
 
 	w := nodeops.NewNodeWrapper(n, nil, time.Now(), nodeops.NodeAnnotationConfig{}, nil)
-	err := w.SetDiscoveredMAC(context.Background(), client, "aa:bb:cc:dd:ee:ff", true)
+	err := w.SetDiscoveredMAC(context.Background(), client, "eno1", "aa:bb:cc:dd:ee:ff", true)
 	if err != nil {
 		t.Errorf("expected no error in dry-run, got: %v", err)
 	}
@@ -135,7 +135,7 @@ func TestNodeWrapper_SetDiscoveredMAC_ActualPatch(t *testing.T) {
 	})
 
 	w := nodeops.NewNodeWrapper(n, nil, time.Now(), nodeops.NodeAnnotationConfig{}, nil)
-	err := w.SetDiscoveredMAC(context.Background(), client, "aa:bb:cc:dd:ee:ff", false)
+	err := w.SetDiscoveredMAC(context.Background(), client, "eno1", "aa:bb:cc:dd:ee:ff", false)
 	if err != nil {
 		t.Errorf("expected no error when patching, got: %v", err)
 	}
@@ -180,6 +180,97 @@ func TestNodeWrapper_IsInBootCooldown(t *testing.T) {
 	}
 }
 
+func TestNodeWrapper_IsInBootCooldown_AnnotationFallback(t *testing.T) {
+	now := time.Now()
+	bootedAt := func(ago time.Duration) map[string]string {
+		return map[string]string{nodeops.AnnotationBootedAt: now.Add(-ago).UTC().Format(time.RFC3339)}
+	}
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		trackedAgo  time.Duration // 0: no in-memory entry, as after a restart
+		want        bool
+	}{
+		{name: "annotation within cooldown", annotations: bootedAt(time.Minute), want: true},
+		{name: "annotation past cooldown", annotations: bootedAt(time.Hour), want: false},
+		{name: "unparsable annotation", annotations: map[string]string{nodeops.AnnotationBootedAt: "yesterday"}, want: false},
+		{name: "no annotation", want: false},
+		{name: "tracker wins over recent annotation", annotations: bootedAt(time.Minute), trackedAgo: time.Hour, want: false},
+		{name: "tracker wins over old annotation", annotations: bootedAt(time.Hour), trackedAgo: time.Minute, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := nodeops.NewNodeStateTracker()
+			if tt.trackedAgo > 0 {
+				tracker.MarkBooted("boot-node")
+				tracker.SetBootTime("boot-node", now.Add(-tt.trackedAgo))
+			}
+			n := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "boot-node", Annotations: tt.annotations}}
+			wrapper := nodeops.NewNodeWrapper(n, tracker, now, nodeops.NodeAnnotationConfig{}, nil)
+
+			if got := wrapper.IsInBootCooldown(10 * time.Minute); got != tt.want {
+				t.Errorf("IsInBootCooldown() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNodeWrapper_CooldownAnnotationOverrides(t *testing.T) {
+	now := time.Now()
+	tracker := nodeops.NewNodeStateTracker()
+	tracker.MarkShutdown("node")
+	tracker.SetShutdownTime("node", now.Add(-10*time.Minute))
+	tracker.MarkBooted("node")
+	tracker.SetBootTime("node", now.Add(-10*time.Minute))
+
+	tests := []struct {
+		name         string
+		annotations  map[string]string
+		wantShutdown bool
+		wantBoot     bool
+	}{
+		{name: "global config", wantShutdown: false, wantBoot: false},
+		{
+			name: "node overrides extend cooldowns",
+			annotations: map[string]string{
+				nodeops.AnnotationCooldown:     "1h",
+				nodeops.AnnotationBootCooldown: "20m",
+			},
+			wantShutdown: true, wantBoot: true,
+		},
+		{
+			name: "node overrides shorten cooldowns",
+			annotations: map[string]string{
+				nodeops.AnnotationCooldown:     "1m",
+				nodeops.AnnotationBootCooldown: "0s",
+			},
+			wantShutdown: false, wantBoot: false,
+		},
+		{
+			name: "invalid values fall back",
+			annotations: map[string]string{
+				nodeops.AnnotationCooldown:     "soon",
+				nodeops.AnnotationBootCooldown: "-5m",
+			},
+			wantShutdown: false, wantBoot: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node", Annotations: tt.annotations}}
+			wrapper := nodeops.NewNodeWrapper(n, tracker, now, nodeops.NodeAnnotationConfig{}, nil)
+
+			if got := wrapper.IsInShutdownCooldown(5 * time.Minute); got != tt.wantShutdown {
+				t.Errorf("IsInShutdownCooldown() = %v, want %v", got, tt.wantShutdown)
+			}
+			if got := wrapper.IsInBootCooldown(5 * time.Minute); got != tt.wantBoot {
+				t.Errorf("IsInBootCooldown() = %v, want %v", got, tt.wantBoot)
+			}
+		})
+	}
+}
+
 func TestNodeWrapper_IsMarkedPoweredOff_ByAnnotation(t *testing.T) {
 	n := v1.Node{
 		ObjectMeta: mkObjMeta(map[string]string{nodeops.AnnotationPoweredOff: time.Now().UTC().Format(time.RFC3339)}),
@@ -209,3 +300,48 @@ func TestNodeWrapper_IsMarkedPoweredOff_FalseWhenNone(t *testing.T) {
 		t.Fatalf("expected false")
 	}
 }
+
+func TestNodeWrapper_IsObserveOnly(t *testing.T) {
+	observed := &v1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name:   "observed",
+		Labels: map[string]string{nodeops.LabelObserveOnly: "true"},
+	}}
+	regular := &v1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name:   "regular",
+		Labels: map[string]string{nodeops.LabelObserveOnly: "false"},
+	}}
+
+	if !nodeops.NewNodeWrapper(observed, nil, time.Now(), nodeops.NodeAnnotationConfig{}, nil).IsObserveOnly() {
+		t.Error("expected node labeled observe-only=true to be observe-only")
+	}
+	if nodeops.NewNodeWrapper(regular, nil, time.Now(), nodeops.NodeAnnotationConfig{}, nil).IsObserveOnly() {
+		t.Error("expected node labeled observe-only=false to be actionable")
+	}
+}
+
+func TestNodeWrapper_HasExcludedTaint(t *testing.T) {
+	n := &v1.Node{Spec: v1.NodeSpec{Taints: []v1.Taint{
+		{Key: "maintenance", Value: "true", Effect: v1.TaintEffectNoSchedule},
+	}}}
+	w := nodeops.NewNodeWrapper(n, nil, time.Now(), nodeops.NodeAnnotationConfig{}, nil)
+
+	tests := []struct {
+		name    string
+		exclude []v1.Taint
+		want    bool
+	}{
+		{"none configured", nil, false},
+		{"key only", []v1.Taint{{Key: "maintenance"}}, true},
+		{"key, value and effect", []v1.Taint{{Key: "maintenance", Value: "true", Effect: v1.TaintEffectNoSchedule}}, true},
+		{"value mismatch", []v1.Taint{{Key: "maintenance", Value: "false"}}, false},
+		{"effect mismatch", []v1.Taint{{Key: "maintenance", Effect: v1.TaintEffectNoExecute}}, false},
+		{"other key", []v1.Taint{{Key: "gpu"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := w.HasExcludedTaint(tt.exclude); got != tt.want {
+				t.Errorf("HasExcludedTaint() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
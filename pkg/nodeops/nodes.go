@@ -5,22 +5,60 @@ import (
 	"fmt"
 	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/retry"
 	"log/slog"
 	"math/rand"
 	"sort"
+	"strings"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes"
 )
 
 type ManagedNodeFilter struct {
-	ManagedLabel  string
-	DisabledLabel string
-	IgnoreLabels  map[string]string
+	ManagedLabel string
+	// ManagedSelector, when set, replaces the ManagedLabel == "true" match, e.g. "node-pool in (batch,ci)".
+	ManagedSelector labels.Selector
+	DisabledLabel   string
+	IgnoreLabels    map[string]string
+}
+
+// NewManagedNodeFilter builds the filter from nodeLabels and ignoreLabels in cfg.
+func NewManagedNodeFilter(cfg *config.Config) ManagedNodeFilter {
+	f := ManagedNodeFilter{
+		ManagedLabel:  cfg.NodeLabels.Managed,
+		DisabledLabel: cfg.NodeLabels.Disabled,
+		IgnoreLabels:  cfg.IgnoreLabels,
+	}
+	sel, err := cfg.NodeLabels.Selector()
+	if err != nil {
+		// ApplyDefaultsAndValidate rejects this; match nothing rather than everything.
+		slog.Error("Invalid nodeLabels.managedSelector; no node is managed", "err", err)
+		sel = labels.Nothing()
+	}
+	f.ManagedSelector = sel
+	return f
+}
+
+// selector returns the label selector matching managed nodes, or nil when nothing can match.
+func (f ManagedNodeFilter) selector() labels.Selector {
+	if f.ManagedSelector != nil {
+		return f.ManagedSelector
+	}
+	if f.ManagedLabel == "" {
+		return nil
+	}
+	return labels.SelectorFromSet(labels.Set{f.ManagedLabel: "true"})
+}
+
+// Manages reports whether a node with these labels is selected as managed. Disabled and ignore
+// labels are not considered.
+func (f ManagedNodeFilter) Manages(nodeLabels map[string]string) bool {
+	sel := f.selector()
+	return sel != nil && sel.Matches(labels.Set(nodeLabels))
 }
 
 // WrapNodes transforms a list of v1.Node objects into []*NodeWrapper.
@@ -39,10 +77,15 @@ func WrapNodes(nodes []v1.Node, state *NodeStateTracker, now time.Time, cfg Node
 	return result
 }
 
-// ListManagedNodes returns all nodes with the specified managed label = "true",
+// ListManagedNodes returns all nodes matching the managed selector (or managed label = "true"),
 // skips nodes with the disabled label = "true", and any node that matches any ignoreLabels.
+// The managed match is done server-side through a label selector.
 func ListManagedNodes(ctx context.Context, client kubernetes.Interface, filter ManagedNodeFilter) ([]v1.Node, error) {
-	allNodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	sel := filter.selector()
+	if sel == nil {
+		return nil, nil
+	}
+	allNodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: sel.String()})
 	if err != nil {
 		return nil, err
 	}
@@ -50,8 +93,8 @@ func ListManagedNodes(ctx context.Context, client kubernetes.Interface, filter M
 	var result []v1.Node
 outer:
 	for _, node := range allNodes.Items {
-		if node.Labels[filter.ManagedLabel] != "true" {
-			slog.Debug("Skipping node due to lack or incorrect ManagedLabel", "node", node.Name)
+		if !sel.Matches(labels.Set(node.Labels)) {
+			slog.Debug("Skipping node not matching the managed selector", "node", node.Name)
 			continue
 		}
 		if node.Labels[filter.DisabledLabel] == "true" {
@@ -146,6 +189,10 @@ type EligibilityConfig struct {
 	Cooldown     time.Duration
 	BootCooldown time.Duration
 	IgnoreLabels map[string]string
+	// ScaleDownDisabledAnnotation excludes nodes that carry it with value "true"; empty disables the check.
+	ScaleDownDisabledAnnotation string
+	// ExcludeTaints excludes nodes carrying a matching taint (see NodeWrapper.HasExcludedTaint).
+	ExcludeTaints []v1.Taint
 }
 
 // FilterEligibleNodes returns nodes that pass filtering criteria:
@@ -153,6 +200,8 @@ type EligibilityConfig struct {
 // - not marked powered-off
 // - not cordoned
 // - not in cooldown
+// - not annotated scale-down-disabled
+// - not carrying an excluded taint
 func FilterShutdownEligibleNodes(nodes []v1.Node, state *NodeStateTracker, now time.Time, cfg EligibilityConfig) []*NodeWrapper {
 	var eligible []*NodeWrapper
 	wrapped := WrapNodes(nodes, state, now, NodeAnnotationConfig{}, cfg.IgnoreLabels)
@@ -178,6 +227,14 @@ func FilterShutdownEligibleNodes(nodes []v1.Node, state *NodeStateTracker, now t
 			slog.Info("Skipping node due to boot cooldown", "node", node.Name)
 			continue
 		}
+		if key := cfg.ScaleDownDisabledAnnotation; key != "" && node.Annotations[key] == "true" {
+			slog.Info("Skipping node annotated scale-down-disabled", "node", node.Name, "annotation", key)
+			continue
+		}
+		if node.HasExcludedTaint(cfg.ExcludeTaints) {
+			slog.Info("Skipping node due to excludeTaints", "node", node.Name)
+			continue
+		}
 		eligible = append(eligible, node)
 	}
 
@@ -188,6 +245,23 @@ func FilterShutdownEligibleNodes(nodes []v1.Node, state *NodeStateTracker, now t
 	return eligible
 }
 
+// ShutdownPriority returns the highest weight whose key matches one of the node's labels or
+// annotations, or 0. A key "name" matches on presence, "name=value" on exact value.
+func ShutdownPriority(node *v1.Node, weights map[string]int) int {
+	best, matched := 0, false
+	for key, weight := range weights {
+		name, value, exact := strings.Cut(key, "=")
+		for _, m := range []map[string]string{node.Labels, node.Annotations} {
+			if v, ok := m[name]; ok && (!exact || v == value) {
+				if !matched || weight > best {
+					best, matched = weight, true
+				}
+			}
+		}
+	}
+	return best
+}
+
 func ShouldIgnoreNodeDueToLabels(node v1.Node, labels map[string]string) bool {
 	for k, v := range labels {
 		if val, ok := node.Labels[k]; ok {
@@ -201,16 +275,16 @@ func ShouldIgnoreNodeDueToLabels(node v1.Node, labels map[string]string) bool {
 }
 
 func RecoverUnexpectedlyBootedNodes(ctx context.Context, client kubernetes.Interface, cfg *config.Config, dryRun bool) error {
-	nodes, err := ListManagedNodes(ctx, client, ManagedNodeFilter{
-		ManagedLabel:  cfg.NodeLabels.Managed,
-		DisabledLabel: cfg.NodeLabels.Disabled,
-		IgnoreLabels:  cfg.IgnoreLabels,
-	})
+	nodes, err := ListManagedNodes(ctx, client, NewManagedNodeFilter(cfg))
 	if err != nil {
 		return fmt.Errorf("failed to list nodes for recovery: %w", err)
 	}
 
 	for _, node := range nodes {
+		if IsObserveOnly(&node) {
+			slog.Debug("Skipping observe-only node", "node", node.Name)
+			continue
+		}
 		if !IsNodeReady(&node) {
 			slog.Debug("Skipping node because it is not Ready", "node", node.Name)
 			continue
@@ -221,7 +295,7 @@ func RecoverUnexpectedlyBootedNodes(ctx context.Context, client kubernetes.Inter
 		if ShouldIgnoreNodeDueToLabels(node, cfg.IgnoreLabels) {
 			continue
 		}
-		if !node.Spec.Unschedulable {
+		if !IsCordoned(&node) {
 			slog.Debug("Skipping node that is not cordoned", "node", node.Name)
 			continue
 		}
@@ -241,7 +315,8 @@ func RecoverUnexpectedlyBootedNodes(ctx context.Context, client kubernetes.Inter
 			}
 
 			nodeCopy := nodeLatest.DeepCopy()
-			nodeCopy.Spec.Unschedulable = false
+			uncordon(nodeCopy, cfg.CordonWithTaint())
+			delete(nodeCopy.Annotations, AnnotationCordonedAt)
 
 			_, err = client.CoreV1().Nodes().Update(ctx, nodeCopy, metav1.UpdateOptions{})
 			if err != nil {
@@ -255,8 +330,7 @@ func RecoverUnexpectedlyBootedNodes(ctx context.Context, client kubernetes.Inter
 		}
 
 		// Step 2: Remove powered-off annotation
-		patch := fmt.Appendf(nil, `{"metadata":{"annotations":{"%s":null}}}`, AnnotationPoweredOff)
-		_, err = client.CoreV1().Nodes().Patch(ctx, node.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+		err = PatchAnnotations(ctx, client, node.Name, map[string]*string{AnnotationPoweredOff: nil})
 		if err != nil {
 			slog.Warn("Failed to clear powered-off annotation", "node", node.Name, "err", err)
 			continue
@@ -268,6 +342,16 @@ func RecoverUnexpectedlyBootedNodes(ctx context.Context, client kubernetes.Inter
 	return nil
 }
 
+// IsObserveOnly reports whether the node carries the observe-only label set to "true".
+func IsObserveOnly(node *v1.Node) bool {
+	return node.Labels[LabelObserveOnly] == "true"
+}
+
+// IsInMaintenance reports whether the node is annotated cba.dev/maintenance: "true".
+func IsInMaintenance(node *v1.Node) bool {
+	return node.Annotations[AnnotationMaintenance] == "true"
+}
+
 // IsNodeReady returns true if the node has a Ready condition with status True.
 func IsNodeReady(node *v1.Node) bool {
 	for _, cond := range node.Status.Conditions {
@@ -3,11 +3,13 @@ package nodeops_test
 import (
 	"context"
 	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	"sort"
 	"testing"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	corefake "k8s.io/client-go/kubernetes/fake"
 
 	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
@@ -144,6 +146,36 @@ func TestListManagedNodes(t *testing.T) {
 			t.Errorf("expected worker-node, got: %+v", nodes)
 		}
 	})
+
+	t.Run("ManagedSelector replaces the managed label", func(t *testing.T) {
+		sel, err := labels.Parse("node-pool in (batch,ci)")
+		if err != nil {
+			t.Fatal(err)
+		}
+		selFilter := filter
+		selFilter.ManagedSelector = sel
+		node := func(name string, lbls map[string]string) *v1.Node {
+			return &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: lbls}}
+		}
+		client := corefake.NewSimpleClientset(
+			node("batch-1", map[string]string{"node-pool": "batch"}),
+			node("ci-1", map[string]string{"node-pool": "ci"}),
+			node("web-1", map[string]string{"node-pool": "web", "cba.dev/is-managed": "true"}),
+			node("ci-disabled", map[string]string{"node-pool": "ci", "cba.dev/disabled": "true"}),
+			node("ci-cp", map[string]string{"node-pool": "ci", "node-role.kubernetes.io/control-plane": ""}),
+		)
+		nodes, err := nodeops.ListManagedNodes(ctx, client, selFilter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := map[string]bool{}
+		for _, n := range nodes {
+			got[n.Name] = true
+		}
+		if len(got) != 2 || !got["batch-1"] || !got["ci-1"] {
+			t.Errorf("expected batch-1 and ci-1, got: %v", got)
+		}
+	})
 }
 
 func TestListActiveNodes(t *testing.T) {
@@ -367,6 +399,33 @@ func TestRecoverUnexpectedlyBootedNodes(t *testing.T) {
 			},
 			shouldChange: false,
 		},
+		{
+			name: "ignores observe-only node",
+			node: &v1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "observe-only",
+					Labels: map[string]string{
+						"cba.dev/is-managed":     "true",
+						nodeops.LabelObserveOnly: "true",
+					},
+					Annotations: map[string]string{
+						"cba.dev/was-powered-off": "true",
+					},
+				},
+				Spec: v1.NodeSpec{
+					Unschedulable: true,
+				},
+				Status: v1.NodeStatus{
+					Conditions: []v1.NodeCondition{
+						{
+							Type:   v1.NodeReady,
+							Status: v1.ConditionTrue,
+						},
+					},
+				},
+			},
+			shouldChange: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -581,3 +640,100 @@ func TestRecoverUnexpectedlyBootedNodes_SkipsWhenIgnoredByLabels(t *testing.T) {
 		t.Fatalf("expected powered-off annotation to remain because node is ignored")
 	}
 }
+
+func TestScaleDownDisabledAnnotation(t *testing.T) {
+	const key = "cluster-autoscaler.kubernetes.io/scale-down-disabled"
+	tracker := nodeops.NewNodeStateTracker()
+	ready := v1.NodeStatus{Conditions: []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionTrue}}}
+	node := func(name, value string) *v1.Node {
+		n := &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"cba.dev/is-managed": "true"}},
+			Status:     ready,
+		}
+		if value != "" {
+			n.Annotations = map[string]string{key: value}
+		}
+		return n
+	}
+	nodes := []v1.Node{*node("pinned", "true"), *node("unpinned", "false"), *node("plain", "")}
+
+	eligible := nodeops.FilterShutdownEligibleNodes(nodes, tracker, time.Now(), nodeops.EligibilityConfig{
+		ScaleDownDisabledAnnotation: key,
+	})
+	var names []string
+	for _, n := range eligible {
+		names = append(names, n.Name)
+	}
+	sort.Strings(names)
+	if len(names) != 2 || names[0] != "plain" || names[1] != "unpinned" {
+		t.Errorf("eligible = %v, want [plain unpinned]", names)
+	}
+
+	client := corefake.NewSimpleClientset(&nodes[0], &nodes[1], &nodes[2])
+	active, err := nodeops.ListActiveNodes(context.Background(), client, tracker,
+		nodeops.ManagedNodeFilter{ManagedLabel: "cba.dev/is-managed"}, nodeops.ActiveNodeFilter{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(active) != 3 {
+		t.Errorf("expected the annotated node to still count as active, got %d active nodes", len(active))
+	}
+}
+
+func TestShutdownPriority(t *testing.T) {
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{
+		Labels:      map[string]string{"hw-gen": "1"},
+		Annotations: map[string]string{"example.com/power-hungry": "true"},
+	}}
+	tests := []struct {
+		name    string
+		weights map[string]int
+		want    int
+	}{
+		{"no weights", nil, 0},
+		{"label presence", map[string]int{"hw-gen": 4}, 4},
+		{"label value mismatch", map[string]int{"hw-gen=2": 4}, 0},
+		{"highest match wins", map[string]int{"hw-gen=1": 2, "example.com/power-hungry=true": 7}, 7},
+		{"negative match", map[string]int{"hw-gen": -3}, -3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nodeops.ShutdownPriority(node, tt.weights); got != tt.want {
+				t.Errorf("ShutdownPriority() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterShutdownEligibleNodes_ExcludeTaints(t *testing.T) {
+	tracker := nodeops.NewNodeStateTracker()
+	ready := v1.NodeStatus{Conditions: []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionTrue}}}
+	node := func(name string, taints ...v1.Taint) *v1.Node {
+		return &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"cba.dev/is-managed": "true"}},
+			Spec:       v1.NodeSpec{Taints: taints},
+			Status:     ready,
+		}
+	}
+	nodes := []v1.Node{
+		*node("maintenance", v1.Taint{Key: "maintenance", Value: "true", Effect: v1.TaintEffectNoSchedule}),
+		*node("plain"),
+	}
+
+	eligible := nodeops.FilterShutdownEligibleNodes(nodes, tracker, time.Now(), nodeops.EligibilityConfig{
+		ExcludeTaints: []v1.Taint{{Key: "maintenance", Effect: v1.TaintEffectNoSchedule}},
+	})
+	if len(eligible) != 1 || eligible[0].Name != "plain" {
+		t.Errorf("expected only the untainted node to be eligible, got %d nodes", len(eligible))
+	}
+
+	client := corefake.NewSimpleClientset(&nodes[0], &nodes[1])
+	active, err := nodeops.ListActiveNodes(context.Background(), client, tracker,
+		nodeops.ManagedNodeFilter{ManagedLabel: "cba.dev/is-managed"}, nodeops.ActiveNodeFilter{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(active) != 2 {
+		t.Errorf("expected the tainted node to still count as active, got %d active nodes", len(active))
+	}
+}
@@ -0,0 +1,90 @@
+package nodeops
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+)
+
+// PlanStep is a single intended action of a batch operation.
+type PlanStep struct {
+	Node   string
+	Action string // e.g. "power-on"
+}
+
+// BatchPlan is the full set of actions a multi-node operation intends to take.
+// It is logged in full before any step executes.
+type BatchPlan struct {
+	Operation string
+	Steps     []PlanStep
+}
+
+// ID is a short, stable fingerprint of the plan. Approvals reference it, so an approval
+// given for one plan never releases a different one.
+func (p BatchPlan) ID() string {
+	h := sha256.New()
+	h.Write([]byte(p.Operation))
+	for _, s := range p.Steps {
+		fmt.Fprintf(h, "\x00%s\x00%s", s.Node, s.Action)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:12]
+}
+
+// Log emits the plan header and every step.
+func (p BatchPlan) Log() {
+	slog.Info("Batch plan", "operation", p.Operation, "planID", p.ID(), "steps", len(p.Steps))
+	for i, s := range p.Steps {
+		slog.Info("Batch plan step", "planID", p.ID(), "step", i+1, "node", s.Node, "action", s.Action)
+	}
+}
+
+// PlanApproved reports whether a plan may execute. Without batchApproval.requireApproval, or in
+// dry-run, every plan is approved. Otherwise the configured annotation on the approval object must
+// equal the plan ID.
+func PlanApproved(ctx context.Context, client kubernetes.Interface, cfg *config.Config, plan BatchPlan, dryRun bool) bool {
+	approval := cfg.BatchApproval
+	if !approval.RequireApproval || dryRun {
+		return true
+	}
+
+	var annotations map[string]string
+	if approval.Namespace == "" {
+		node, err := client.CoreV1().Nodes().Get(ctx, approval.Name, metav1.GetOptions{})
+		if err == nil {
+			annotations = node.Annotations
+		} else {
+			slog.Warn("Batch approval: failed to read approval node", "name", approval.Name, "err", err)
+		}
+	} else {
+		cm, err := client.CoreV1().ConfigMaps(approval.Namespace).Get(ctx, approval.Name, metav1.GetOptions{})
+		if err == nil {
+			annotations = cm.Annotations
+		} else {
+			slog.Warn("Batch approval: failed to read approval configmap",
+				"namespace", approval.Namespace, "name", approval.Name, "err", err)
+		}
+	}
+
+	if annotations[approval.Annotation] == plan.ID() {
+		slog.Info("Batch plan approved", "operation", plan.Operation, "planID", plan.ID())
+		return true
+	}
+	slog.Warn("Batch plan awaiting approval — not executing",
+		"operation", plan.Operation, "planID", plan.ID(),
+		"approve", fmt.Sprintf("annotate %s with %s=%s", approvalObject(approval), approval.Annotation, plan.ID()))
+	return false
+}
+
+func approvalObject(a config.BatchApprovalConfig) string {
+	if a.Namespace == "" {
+		return "node/" + a.Name
+	}
+	return "configmap/" + a.Namespace + "/" + a.Name
+}
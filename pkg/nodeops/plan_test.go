@@ -0,0 +1,83 @@
+package nodeops_test
+
+import (
+	"context"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	corefake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
+)
+
+func TestBatchPlan_ID(t *testing.T) {
+	a := nodeops.BatchPlan{Operation: "force-power-on", Steps: []nodeops.PlanStep{{Node: "n1", Action: "power-on"}}}
+	b := nodeops.BatchPlan{Operation: "force-power-on", Steps: []nodeops.PlanStep{{Node: "n1", Action: "power-on"}}}
+	c := nodeops.BatchPlan{Operation: "force-power-on", Steps: []nodeops.PlanStep{{Node: "n2", Action: "power-on"}}}
+
+	if a.ID() != b.ID() {
+		t.Errorf("identical plans must share an ID: %s vs %s", a.ID(), b.ID())
+	}
+	if a.ID() == c.ID() {
+		t.Errorf("different plans must not share an ID: %s", a.ID())
+	}
+}
+
+func TestForcePowerOnAllNodes_RequireApproval(t *testing.T) {
+	plan := nodeops.BatchPlan{Operation: "force-power-on", Steps: []nodeops.PlanStep{{Node: "node1", Action: "power-on"}}}
+
+	tests := []struct {
+		name       string
+		approval   string // annotation value on the approval ConfigMap; "" = no ConfigMap
+		wantCalled bool
+	}{
+		{"without approval only the plan is logged", "", false},
+		{"approval for another plan does not release this one", "0123456789ab", false},
+		{"approved plan executes", plan.ID(), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objs := []runtime.Object{&v1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "node1",
+					Labels:      map[string]string{"scaling-managed-by-cba": "true"},
+					Annotations: map[string]string{"cba.dev/mac": "00:11:22:33:44:55"},
+				},
+				Status: v1.NodeStatus{
+					Conditions: []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionFalse}},
+				},
+			}}
+			if tt.approval != "" {
+				objs = append(objs, &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+					Namespace:   "kube-system",
+					Name:        "cba-approvals",
+					Annotations: map[string]string{"cba.dev/approve-batch": tt.approval},
+				}})
+			}
+			client := corefake.NewSimpleClientset(objs...)
+			cfg := &config.Config{
+				NodeLabels:      config.NodeLabelConfig{Managed: "scaling-managed-by-cba"},
+				NodeAnnotations: config.NodeAnnotationConfig{MAC: "cba.dev/mac"},
+				BatchApproval: config.BatchApprovalConfig{
+					RequireApproval: true,
+					Namespace:       "kube-system",
+					Name:            "cba-approvals",
+					Annotation:      "cba.dev/approve-batch",
+				},
+			}
+			powerMock := &mockPower{}
+
+			_, err := nodeops.ForcePowerOnAllNodes(context.Background(), client, cfg, nodeops.NewNodeStateTracker(), powerMock, false)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if powerMock.called != tt.wantCalled {
+				t.Errorf("power-on called = %v, want %v", powerMock.called, tt.wantCalled)
+			}
+		})
+	}
+}
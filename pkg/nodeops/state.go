@@ -2,7 +2,9 @@
 //
 // Overview:
 // The NodeStateTracker holds in-memory state to coordinate cooldowns and power transitions for nodes.
-// This state is *ephemeral* and does not persist across restarts of the autoscaler.
+// This state is *ephemeral* and does not persist across restarts of the autoscaler, unless
+// `persistState` is enabled: then cooldown timestamps, rotation history and the powered-off set are
+// saved to a ConfigMap after state changes and loaded back on startup (see StateSnapshot).
 // It includes timestamps and flags used to decide if a node can be shut down or powered on again.
 //
 // Cooldown flow explained:
@@ -18,12 +20,40 @@
 //    a. **Shutdown Cooldown**:
 //       - Prevents shutting down the same node again too soon.
 //       - Set via `MarkShutdown(node)` and checked with `IsInCooldown(...)`.
-//       - Controlled via the global `cooldown` config.
+//       - Controlled via the global `cooldown` config, overridable per node with `cba.dev/cooldown`.
 //
 //    b. **Boot Cooldown**:
 //       - Prevents newly powered-on nodes from being shut down immediately.
 //       - Set via `MarkBooted(node)` and checked with `IsBootCooldownActive(...)`.
-//       - Controlled via a separate `bootCooldown` config (e.g., `bootCooldownSeconds`).
+//       - Controlled via a separate `bootCooldown` config (e.g., `bootCooldownSeconds`),
+//         overridable per node with `cba.dev/boot-cooldown`.
+//
+// 3. **Post-scale-up Hold**:
+//    - Starts when any node is powered on.
+//    - Suppresses scale-down only, so a node booted for load isn't followed by retiring another
+//      node on a momentary dip.
+//    - Duration is configured via `postScaleUpScaleDownHold`; tracked using `LastPowerOnTime`.
+//
+// 4. **Power-on Deduplication**:
+//    - Suppresses re-sending a power-on (e.g. a WOL packet) to a node within `powerOnDedupWindow`
+//      of the previous attempt, independent of boot cooldown; failed attempts count too.
+//    - Set via `MarkPowerOnAttempt(node)` and checked with `IsPowerOnSuppressed(...)`.
+//
+// 5. **Powered-off Fleet Alert Streak**:
+//    - Records when the powered-off fraction of managed nodes first rose above `alertPoweredOffPerc`
+//      and whether the alert has fired for the current streak; cleared once the fraction drops.
+//    - Set via `ObservePoweredOffExcess(...)` and reset with `ClearPoweredOffExcess()`.
+//
+// 6. **Rotation History**:
+//    - Records when each node was last powered on by rotation, so ties between equally overdue
+//      nodes can go to the least recently rotated one (`rotation.tieBreak`). Persisted with
+//      `persistState`.
+//    - Set via `MarkRotated(node)` and read with `LastRotated(node)`.
+//
+// 7. **Retention of Gone Nodes**:
+//    - Nodes deleted from the cluster (e.g. replaced by Cluster API) would otherwise keep their
+//      entries forever. `PruneAbsent(...)` notes when a tracked node was first seen missing and drops
+//      all of its entries once it has been absent for `nodeStateRetention`.
 //
 // Additional tracking:
 // - `poweredOff` tracks which nodes are currently considered powered off.
@@ -43,7 +73,14 @@ type NodeStateTracker struct {
 	shutdownTimestamps map[string]time.Time
 	bootTimestamps     map[string]time.Time
 	poweredOff         map[string]struct{}
+	powerOnAttempts    map[string]time.Time
+	absentSince        map[string]time.Time // first time a tracked node was missing from the cluster
+	rotatedAt          map[string]time.Time // last power-on by rotation
+	poweredOffExcess   time.Time            // start of the current powered-off alert streak; zero when none
+	poweredOffAlerted  bool
+	dirty              bool // persisted state changed since the last TakeDirty
 	LastShutdownTime   time.Time
+	LastPowerOnTime    time.Time
 }
 
 // NewNodeStateTracker initializes all internal maps for tracking.
@@ -52,12 +89,16 @@ func NewNodeStateTracker() *NodeStateTracker {
 		shutdownTimestamps: make(map[string]time.Time),
 		bootTimestamps:     make(map[string]time.Time),
 		poweredOff:         make(map[string]struct{}),
+		powerOnAttempts:    make(map[string]time.Time),
+		absentSince:        make(map[string]time.Time),
+		rotatedAt:          make(map[string]time.Time),
 	}
 }
 
 // MarkShutdown stores the timestamp when the node was shut down.
 func (s *NodeStateTracker) MarkShutdown(node string) {
 	s.shutdownTimestamps[node] = time.Now()
+	s.dirty = true
 }
 
 // IsInCooldown returns true if the node is still within shutdown cooldown period.
@@ -72,11 +113,13 @@ func (s *NodeStateTracker) IsInCooldown(node string, now time.Time, cooldown tim
 // MarkPoweredOff registers the node as currently powered off.
 func (s *NodeStateTracker) MarkPoweredOff(node string) {
 	s.poweredOff[node] = struct{}{}
+	s.dirty = true
 }
 
 // ClearPoweredOff removes the powered-off state for a node.
 func (s *NodeStateTracker) ClearPoweredOff(node string) {
 	delete(s.poweredOff, node)
+	s.dirty = true
 }
 
 // IsPoweredOff returns true if the node is marked as powered off.
@@ -89,6 +132,7 @@ func (s *NodeStateTracker) IsPoweredOff(node string) bool {
 // This is used to enforce the global cooldown across all nodes.
 func (s *NodeStateTracker) MarkGlobalShutdown() {
 	s.LastShutdownTime = time.Now()
+	s.dirty = true
 }
 
 // IsGlobalCooldownActive returns true if the current time is still within global cooldown window.
@@ -99,6 +143,7 @@ func (s *NodeStateTracker) IsGlobalCooldownActive(now time.Time, cooldown time.D
 // MarkBooted stores the timestamp when the node was powered on.
 func (s *NodeStateTracker) MarkBooted(node string) {
 	s.bootTimestamps[node] = time.Now()
+	s.dirty = true
 }
 
 // IsBootCooldownActive returns true if the node was recently powered on and still within boot cooldown.
@@ -110,6 +155,141 @@ func (s *NodeStateTracker) IsBootCooldownActive(node string, now time.Time, cool
 	return now.Sub(last) < cooldown
 }
 
+// LastBooted returns when the node was last powered on by this process (or restored state), if known.
+func (s *NodeStateTracker) LastBooted(node string) (time.Time, bool) {
+	t, ok := s.bootTimestamps[node]
+	return t, ok
+}
+
+// MarkPowerOn sets the timestamp for the last power-on of any node.
+func (s *NodeStateTracker) MarkPowerOn() {
+	s.LastPowerOnTime = time.Now()
+	s.dirty = true
+}
+
+// IsPostScaleUpHoldActive returns true if a node was powered on within the hold window.
+func (s *NodeStateTracker) IsPostScaleUpHoldActive(now time.Time, hold time.Duration) bool {
+	return now.Sub(s.LastPowerOnTime) < hold
+}
+
+// MarkPowerOnAttempt records that a power-on was just sent to the node.
+func (s *NodeStateTracker) MarkPowerOnAttempt(node string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.powerOnAttempts[node] = time.Now()
+}
+
+// IsPowerOnSuppressed returns true if a power-on was sent to the node within window.
+func (s *NodeStateTracker) IsPowerOnSuppressed(node string, now time.Time, window time.Duration) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	last, ok := s.powerOnAttempts[node]
+	if !ok {
+		return false
+	}
+	return now.Sub(last) < window
+}
+
+// MarkRotated records that rotation just powered the node on.
+func (s *NodeStateTracker) MarkRotated(node string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rotatedAt[node] = time.Now()
+	s.dirty = true
+}
+
+// LastRotated returns when rotation last powered the node on, if ever.
+func (s *NodeStateTracker) LastRotated(node string) (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.rotatedAt[node]
+	return t, ok
+}
+
+// ObservePoweredOffExcess notes that the powered-off fraction is above the alert threshold and
+// returns when the current streak began.
+func (s *NodeStateTracker) ObservePoweredOffExcess(now time.Time) time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.poweredOffExcess.IsZero() {
+		s.poweredOffExcess = now
+	}
+	return s.poweredOffExcess
+}
+
+// ClearPoweredOffExcess ends the current streak. It returns true if the alert had fired during it.
+func (s *NodeStateTracker) ClearPoweredOffExcess() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	alerted := s.poweredOffAlerted
+	s.poweredOffExcess = time.Time{}
+	s.poweredOffAlerted = false
+	return alerted
+}
+
+// MarkPoweredOffAlerted records that the alert fired for the current streak and returns true
+// only the first time, so callers emit one event per streak.
+func (s *NodeStateTracker) MarkPoweredOffAlerted() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.poweredOffAlerted {
+		return false
+	}
+	s.poweredOffAlerted = true
+	return true
+}
+
+// PruneAbsent drops every entry for nodes that are not in present and have been missing for at
+// least retention, returning the pruned names. Nodes that reappear have their absence forgotten.
+func (s *NodeStateTracker) PruneAbsent(present map[string]struct{}, now time.Time, retention time.Duration) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tracked := make(map[string]struct{})
+	for _, m := range []map[string]time.Time{s.shutdownTimestamps, s.bootTimestamps, s.powerOnAttempts, s.rotatedAt, s.absentSince} {
+		for node := range m {
+			tracked[node] = struct{}{}
+		}
+	}
+	for node := range s.poweredOff {
+		tracked[node] = struct{}{}
+	}
+
+	var pruned []string
+	for node := range tracked {
+		if _, ok := present[node]; ok {
+			delete(s.absentSince, node)
+			continue
+		}
+		since, ok := s.absentSince[node]
+		if !ok {
+			s.absentSince[node] = now
+			continue
+		}
+		if now.Sub(since) < retention {
+			continue
+		}
+		delete(s.shutdownTimestamps, node)
+		delete(s.bootTimestamps, node)
+		delete(s.powerOnAttempts, node)
+		delete(s.rotatedAt, node)
+		delete(s.poweredOff, node)
+		delete(s.absentSince, node)
+		pruned = append(pruned, node)
+	}
+	if len(pruned) > 0 {
+		s.dirty = true
+	}
+	return pruned
+}
+
+// SetPoweredOffExcessSince sets the streak start manually (for testing only).
+func (s *NodeStateTracker) SetPoweredOffExcessSince(t time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.poweredOffExcess = t
+}
+
 // SetShutdownTime sets the shutdown timestamp manually (for testing only).
 func (s *NodeStateTracker) SetShutdownTime(node string, t time.Time) {
 	s.mu.Lock()
@@ -117,6 +297,13 @@ func (s *NodeStateTracker) SetShutdownTime(node string, t time.Time) {
 	s.shutdownTimestamps[node] = t
 }
 
+// SetRotatedTime sets the rotation timestamp manually (for testing only).
+func (s *NodeStateTracker) SetRotatedTime(node string, t time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rotatedAt[node] = t
+}
+
 // SetBootTime sets the boot timestamp manually (for testing only).
 func (s *NodeStateTracker) SetBootTime(node string, t time.Time) {
 	s.mu.Lock()
@@ -0,0 +1,142 @@
+package nodeops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// StateConfigMapKey is the ConfigMap data key holding the JSON-encoded StateSnapshot.
+const StateConfigMapKey = "state.json"
+
+// StateSnapshot is the part of NodeStateTracker kept across restarts when persistState is enabled:
+// cooldowns, the powered-off set and the rotation history. Power-on attempts, absence tracking and the alert streak stay in memory only.
+type StateSnapshot struct {
+	ShutdownTimestamps map[string]time.Time `json:"shutdownTimestamps,omitempty"`
+	BootTimestamps     map[string]time.Time `json:"bootTimestamps,omitempty"`
+	RotatedAt          map[string]time.Time `json:"rotatedAt,omitempty"`
+	PoweredOff         []string             `json:"poweredOff,omitempty"`
+	LastShutdownTime   time.Time            `json:"lastShutdownTime"`
+	LastPowerOnTime    time.Time            `json:"lastPowerOnTime"`
+}
+
+// Snapshot copies the persisted part of the tracker.
+func (s *NodeStateTracker) Snapshot() StateSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snap := StateSnapshot{
+		ShutdownTimestamps: make(map[string]time.Time, len(s.shutdownTimestamps)),
+		BootTimestamps:     make(map[string]time.Time, len(s.bootTimestamps)),
+		RotatedAt:          make(map[string]time.Time, len(s.rotatedAt)),
+		LastShutdownTime:   s.LastShutdownTime,
+		LastPowerOnTime:    s.LastPowerOnTime,
+	}
+	for node, t := range s.shutdownTimestamps {
+		snap.ShutdownTimestamps[node] = t
+	}
+	for node, t := range s.bootTimestamps {
+		snap.BootTimestamps[node] = t
+	}
+	for node, t := range s.rotatedAt {
+		snap.RotatedAt[node] = t
+	}
+	for node := range s.poweredOff {
+		snap.PoweredOff = append(snap.PoweredOff, node)
+	}
+	sort.Strings(snap.PoweredOff)
+	return snap
+}
+
+// Restore merges snap into the tracker; entries already tracked in memory are kept.
+func (s *NodeStateTracker) Restore(snap StateSnapshot) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for node, t := range snap.ShutdownTimestamps {
+		if _, ok := s.shutdownTimestamps[node]; !ok {
+			s.shutdownTimestamps[node] = t
+		}
+	}
+	for node, t := range snap.BootTimestamps {
+		if _, ok := s.bootTimestamps[node]; !ok {
+			s.bootTimestamps[node] = t
+		}
+	}
+	for node, t := range snap.RotatedAt {
+		if _, ok := s.rotatedAt[node]; !ok {
+			s.rotatedAt[node] = t
+		}
+	}
+	for _, node := range snap.PoweredOff {
+		s.poweredOff[node] = struct{}{}
+	}
+	if snap.LastShutdownTime.After(s.LastShutdownTime) {
+		s.LastShutdownTime = snap.LastShutdownTime
+	}
+	if snap.LastPowerOnTime.After(s.LastPowerOnTime) {
+		s.LastPowerOnTime = snap.LastPowerOnTime
+	}
+}
+
+// TakeDirty reports whether persisted state changed since the previous call, and resets the flag.
+func (s *NodeStateTracker) TakeDirty() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	dirty := s.dirty
+	s.dirty = false
+	return dirty
+}
+
+// LoadState restores tracker state from ConfigMap namespace/name. A missing ConfigMap or key is
+// not an error: there is simply nothing to restore yet.
+func LoadState(ctx context.Context, client kubernetes.Interface, namespace, name string, s *NodeStateTracker) error {
+	cm, err := client.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading state ConfigMap %s/%s: %w", namespace, name, err)
+	}
+	raw, ok := cm.Data[StateConfigMapKey]
+	if !ok {
+		return nil
+	}
+	var snap StateSnapshot
+	if err := json.Unmarshal([]byte(raw), &snap); err != nil {
+		return fmt.Errorf("decoding state ConfigMap %s/%s: %w", namespace, name, err)
+	}
+	s.Restore(snap)
+	return nil
+}
+
+// SaveState writes snap to ConfigMap namespace/name, creating it on first use.
+func SaveState(ctx context.Context, client kubernetes.Interface, namespace, name string, snap StateSnapshot) error {
+	raw, err := json.Marshal(snap)
+	if err != nil {
+		return fmt.Errorf("encoding state: %w", err)
+	}
+	cms := client.CoreV1().ConfigMaps(namespace)
+	cm, err := cms.Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = cms.Create(ctx, &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Data:       map[string]string{StateConfigMapKey: string(raw)},
+		}, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data[StateConfigMapKey] = string(raw)
+	_, err = cms.Update(ctx, cm, metav1.UpdateOptions{})
+	return err
+}
@@ -0,0 +1,68 @@
+package nodeops_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestSaveAndLoadState_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset()
+
+	s := nodeops.NewNodeStateTracker()
+	s.MarkShutdown("node1")
+	s.MarkBooted("node2")
+	s.MarkPoweredOff("node1")
+	s.MarkRotated("node2")
+	s.MarkGlobalShutdown()
+	if !s.TakeDirty() {
+		t.Fatal("expected mutations to mark the tracker dirty")
+	}
+	if s.TakeDirty() {
+		t.Fatal("TakeDirty must reset the flag")
+	}
+
+	for i := 0; i < 2; i++ { // second save updates the existing ConfigMap
+		if err := nodeops.SaveState(ctx, client, "cba", "cba-state", s.Snapshot()); err != nil {
+			t.Fatalf("SaveState: %v", err)
+		}
+	}
+
+	restored := nodeops.NewNodeStateTracker()
+	if err := nodeops.LoadState(ctx, client, "cba", "cba-state", restored); err != nil {
+		t.Fatalf("LoadState: %v", err)
+	}
+	now := time.Now()
+	if !restored.IsInCooldown("node1", now, time.Hour) {
+		t.Error("expected node1 shutdown cooldown to survive a restart")
+	}
+	if !restored.IsBootCooldownActive("node2", now, time.Hour) {
+		t.Error("expected node2 boot cooldown to survive a restart")
+	}
+	if !restored.IsPoweredOff("node1") || restored.IsPoweredOff("node2") {
+		t.Error("powered-off set not restored")
+	}
+	if _, ok := restored.LastRotated("node2"); !ok {
+		t.Error("expected rotation history to survive a restart")
+	}
+	if !restored.IsGlobalCooldownActive(now, time.Hour) {
+		t.Error("expected global cooldown to survive a restart")
+	}
+	if restored.TakeDirty() {
+		t.Error("restoring must not mark the tracker dirty")
+	}
+}
+
+func TestLoadState_MissingConfigMap(t *testing.T) {
+	s := nodeops.NewNodeStateTracker()
+	if err := nodeops.LoadState(context.Background(), fake.NewSimpleClientset(), "cba", "cba-state", s); err != nil {
+		t.Fatalf("expected no error for a missing ConfigMap, got %v", err)
+	}
+	if s.IsGlobalCooldownActive(time.Now(), time.Hour) {
+		t.Error("empty state must not start a cooldown")
+	}
+}
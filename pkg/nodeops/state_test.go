@@ -38,3 +38,80 @@ func TestNodeStateTracker_Cooldowns(t *testing.T) {
 		t.Errorf("expected global cooldown to be active")
 	}
 }
+
+func TestNodeStateTracker_PostScaleUpHold(t *testing.T) {
+	s := nodeops.NewNodeStateTracker()
+	if s.IsPostScaleUpHoldActive(time.Now(), time.Minute) {
+		t.Errorf("expected no hold before any power-on")
+	}
+
+	s.MarkPowerOn()
+	if !s.IsPostScaleUpHoldActive(time.Now(), time.Minute) {
+		t.Errorf("expected hold to be active right after power-on")
+	}
+	if s.IsPostScaleUpHoldActive(time.Now().Add(2*time.Minute), time.Minute) {
+		t.Errorf("expected hold to expire after the window")
+	}
+	if s.IsPostScaleUpHoldActive(time.Now(), 0) {
+		t.Errorf("expected zero window to disable the hold")
+	}
+}
+
+func TestNodeStateTracker_PowerOnDedup(t *testing.T) {
+	s := nodeops.NewNodeStateTracker()
+	if s.IsPowerOnSuppressed("n1", time.Now(), time.Minute) {
+		t.Errorf("expected no suppression before any attempt")
+	}
+
+	s.MarkPowerOnAttempt("n1")
+	if !s.IsPowerOnSuppressed("n1", time.Now(), time.Minute) {
+		t.Errorf("expected suppression right after an attempt")
+	}
+	if s.IsPowerOnSuppressed("n2", time.Now(), time.Minute) {
+		t.Errorf("expected suppression to be per node")
+	}
+	if s.IsPowerOnSuppressed("n1", time.Now().Add(2*time.Minute), time.Minute) {
+		t.Errorf("expected suppression to expire after the window")
+	}
+}
+
+func TestNodeStateTracker_PruneAbsent(t *testing.T) {
+	s := nodeops.NewNodeStateTracker()
+	now := time.Now()
+	s.SetShutdownTime("gone", now)
+	s.MarkPoweredOff("gone")
+	s.SetShutdownTime("kept", now)
+	present := map[string]struct{}{"kept": {}}
+
+	if pruned := s.PruneAbsent(present, now, time.Hour); len(pruned) != 0 {
+		t.Fatalf("first sighting of absence must not prune, got %v", pruned)
+	}
+	if pruned := s.PruneAbsent(present, now.Add(30*time.Minute), time.Hour); len(pruned) != 0 {
+		t.Fatalf("expected no pruning inside the retention window, got %v", pruned)
+	}
+
+	pruned := s.PruneAbsent(present, now.Add(time.Hour), time.Hour)
+	if len(pruned) != 1 || pruned[0] != "gone" {
+		t.Fatalf("expected [gone] to be pruned, got %v", pruned)
+	}
+	if s.IsInCooldown("gone", now, time.Hour) || s.IsPoweredOff("gone") {
+		t.Errorf("expected all state for gone to be dropped")
+	}
+	if !s.IsInCooldown("kept", now, time.Hour) {
+		t.Errorf("expected state for present node to be kept")
+	}
+}
+
+func TestNodeStateTracker_PruneAbsent_ReappearingNodeResetsWindow(t *testing.T) {
+	s := nodeops.NewNodeStateTracker()
+	now := time.Now()
+	s.SetBootTime("flaky", now)
+
+	s.PruneAbsent(map[string]struct{}{}, now, time.Hour)
+	s.PruneAbsent(map[string]struct{}{"flaky": {}}, now.Add(30*time.Minute), time.Hour)
+	s.PruneAbsent(map[string]struct{}{}, now.Add(45*time.Minute), time.Hour)
+
+	if pruned := s.PruneAbsent(map[string]struct{}{}, now.Add(90*time.Minute), time.Hour); len(pruned) != 0 {
+		t.Errorf("absence window should restart after the node reappeared, got %v", pruned)
+	}
+}
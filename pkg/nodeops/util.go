@@ -2,7 +2,11 @@ package nodeops
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+
+	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/client-go/util/retry"
 	"time"
@@ -11,24 +15,63 @@ import (
 	"github.com/docent-net/cluster-bare-autoscaler/pkg/power"
 	"golang.org/x/exp/slog"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 )
 
-// UncordonNode sets the Unschedulable field of a node to false.
-func UncordonNode(ctx context.Context, client kubernetes.Interface, nodeName string) error {
+// IsCordoned reports whether the node is unschedulable or carries CBA's powering-off taint.
+func IsCordoned(node *v1.Node) bool {
+	return node.Spec.Unschedulable || hasPoweringOffTaint(node)
+}
+
+func hasPoweringOffTaint(node *v1.Node) bool {
+	for _, t := range node.Spec.Taints {
+		if t.Key == TaintPoweringOff {
+			return true
+		}
+	}
+	return false
+}
+
+// Cordon marks node unschedulable in place, with the powering-off taint when useTaint is set and
+// through spec.unschedulable otherwise.
+func Cordon(node *v1.Node, useTaint bool) {
+	if !useTaint {
+		node.Spec.Unschedulable = true
+		return
+	}
+	if !hasPoweringOffTaint(node) {
+		node.Spec.Taints = append(node.Spec.Taints, v1.Taint{Key: TaintPoweringOff, Effect: v1.TaintEffectNoSchedule})
+	}
+}
+
+// uncordon reverts Cordon in place and reports whether anything changed. The powering-off taint
+// is always removed; spec.unschedulable is left alone under useTaint, where another controller owns it.
+func uncordon(node *v1.Node, useTaint bool) bool {
+	changed := false
+	if !useTaint && node.Spec.Unschedulable {
+		node.Spec.Unschedulable = false
+		changed = true
+	}
+	if hasPoweringOffTaint(node) {
+		node.Spec.Taints = slices.DeleteFunc(node.Spec.Taints, func(t v1.Taint) bool { return t.Key == TaintPoweringOff })
+		changed = true
+	}
+	return changed
+}
+
+// UncordonNode reverts CBA's cordon (see Cordon) and drops its cordon marker.
+func UncordonNode(ctx context.Context, client kubernetes.Interface, nodeName string, useTaint bool) error {
 	return retry.OnError(retry.DefaultBackoff, apierrors.IsConflict, func() error {
 		node, err := client.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
 		if err != nil {
 			return fmt.Errorf("fetch node: %w", err)
 		}
 
-		if !node.Spec.Unschedulable {
+		nodeCopy := node.DeepCopy()
+		if !uncordon(nodeCopy, useTaint) {
 			return nil
 		}
-
-		nodeCopy := node.DeepCopy()
-		nodeCopy.Spec.Unschedulable = false
+		delete(nodeCopy.Annotations, AnnotationCordonedAt)
 
 		_, err = client.CoreV1().Nodes().Update(ctx, nodeCopy, metav1.UpdateOptions{})
 		if err != nil {
@@ -41,16 +84,31 @@ func UncordonNode(ctx context.Context, client kubernetes.Interface, nodeName str
 
 // ClearPoweredOffAnnotation removes the powered-off annotation from the node.
 func ClearPoweredOffAnnotation(ctx context.Context, client kubernetes.Interface, nodeName string) error {
-	patch := fmt.Appendf(nil, `{"metadata":{"annotations":{"%s":null}}}`, AnnotationPoweredOff)
-	_, err := client.CoreV1().Nodes().Patch(ctx, nodeName, types.MergePatchType, patch, metav1.PatchOptions{})
+	err := PatchAnnotations(ctx, client, nodeName, map[string]*string{AnnotationPoweredOff: nil})
 	if err != nil {
 		return fmt.Errorf("remove annotation: %w", err)
 	}
 	return nil
 }
 
+// ErrObserveOnly is returned when an action is requested for a node labeled observe-only.
+var ErrObserveOnly = errors.New("node is observe-only")
+
+// ErrMissingMAC is returned when a node selected for Wake-on-LAN power-on has no MAC annotation yet.
+var ErrMissingMAC = errors.New("missing MAC address")
+
+// ErrPowerOnSuppressed is returned when a power-on was already sent within powerOnDedupWindow.
+var ErrPowerOnSuppressed = errors.New("power-on suppressed: recent attempt within dedup window")
+
 // PowerOnAndMarkBooted performs power-on logic and updates state and annotations.
+// dryRun simulates everything; cfg.DryRunPower / cfg.DryRunK8s simulate only the
+// physical power-on or only the Kubernetes mutations respectively.
 func PowerOnAndMarkBooted(ctx context.Context, node *NodeWrapper, cfg *config.Config, client kubernetes.Interface, powerOner power.PowerOnController, state *NodeStateTracker, dryRun bool) error {
+	if node.IsObserveOnly() {
+		slog.Info("Observe-only: would power on node", "node", node.Name)
+		return ErrObserveOnly
+	}
+
 	slog.Info("Powering on node", "node", node.Name)
 
 	if dryRun {
@@ -58,33 +116,79 @@ func PowerOnAndMarkBooted(ctx context.Context, node *NodeWrapper, cfg *config.Co
 		return nil
 	}
 
-	mac := GetMACAddressFromNode(*node.Node, NodeAnnotationConfig{
-		MAC: cfg.NodeAnnotations.MAC,
-	})
-	if mac == "" {
-		return fmt.Errorf("missing MAC address for node %q", node.Name)
-	}
+	var bootDuration time.Duration // zero when not measured
+	if cfg.DryRunPower {
+		slog.Info("Dry-run (power): would power on", "node", node.Name)
+	} else {
+		mac := GetMACAddressFromNode(*node.Node, NodeAnnotationConfig{
+			MAC: cfg.NodeAnnotations.MAC,
+		})
+		if mac == "" && power.RequiresMAC(cfg.PowerOnMode) {
+			return fmt.Errorf("node %q: %w", node.Name, ErrMissingMAC)
+		}
 
-	if err := powerOner.PowerOn(ctx, node.Name, mac); err != nil {
-		return fmt.Errorf("power on: %w", err)
-	}
+		if state != nil && cfg.PowerOnDedupWindow > 0 {
+			if state.IsPowerOnSuppressed(node.Name, time.Now(), cfg.PowerOnDedupWindow) {
+				slog.Info("Power-on recently sent — suppressing duplicate", "node", node.Name,
+					"window", cfg.PowerOnDedupWindow.String())
+				return ErrPowerOnSuppressed
+			}
+			state.MarkPowerOnAttempt(node.Name)
+		}
 
-	if err := UncordonNode(ctx, client, node.Name); err != nil {
-		slog.Warn("Failed to uncordon node", "node", node.Name, "err", err)
-		return err
+		if powerOner == nil {
+			slog.Error("Cannot power on node: power controller not configured", "node", node.Name)
+			return fmt.Errorf("node %q: %w", node.Name, power.ErrNotConfigured)
+		}
+
+		start := time.Now()
+		if err := powerOner.PowerOn(ctx, node.Name, mac); err != nil {
+			return fmt.Errorf("power on: %w", err)
+		}
+		// PowerOn waits for Ready, so its duration is the boot time.
+		bootDuration = time.Since(start)
 	}
 
-	if err := ClearPoweredOffAnnotation(ctx, client, node.Name); err != nil {
-		slog.Warn("Failed to clear powered-off annotation", "node", node.Name, "err", err)
-		return err
+	if cfg.DryRunK8s {
+		slog.Info("Dry-run (k8s): would uncordon node and update power-state annotations", "node", node.Name)
+	} else {
+		if err := UncordonNode(ctx, client, node.Name, cfg.CordonWithTaint()); err != nil {
+			slog.Warn("Failed to uncordon node", "node", node.Name, "err", err)
+			return err
+		}
+
+		bootedAt := time.Now().UTC().Format(time.RFC3339)
+		changes := map[string]*string{
+			AnnotationPoweredOff:     nil,
+			AnnotationRecyclePending: nil,
+			AnnotationBootedAt:       &bootedAt,
+		}
+		if bootDuration > 0 {
+			smoothed := SmoothBootDuration(*node.Node, bootDuration).String()
+			changes[AnnotationBootDuration] = &smoothed
+		}
+		if err := PatchAnnotations(ctx, client, node.Name, changes); err != nil {
+			slog.Warn("Failed to update power-state annotations", "node", node.Name, "err", err)
+			return fmt.Errorf("update annotations: %w", err)
+		}
 	}
 
 	state.MarkGlobalShutdown()
 	state.MarkBooted(node.Name)
+	state.MarkPowerOn()
 
 	return nil
 }
 
+// ForcePowerOnResult is the outcome of powering on one node during ForcePowerOnAllNodes. Err is
+// the power-on error, or the readiness failure when forcePowerOnReadyTimeout is set.
+type ForcePowerOnResult struct {
+	Node *NodeWrapper
+	Err  error
+}
+
+// ForcePowerOnAllNodes powers on every managed node that is not Ready, after the batch plan is
+// approved. It returns one result per node it tried to power on, and an error joining the failures.
 func ForcePowerOnAllNodes(
 	ctx context.Context,
 	client kubernetes.Interface,
@@ -92,35 +196,121 @@ func ForcePowerOnAllNodes(
 	state *NodeStateTracker,
 	powerOner power.PowerOnController,
 	dryRun bool,
-) error {
+) ([]ForcePowerOnResult, error) {
 	slog.Warn("ForcePowerOnAllNodes is active — overriding strategy logic and powering on all managed nodes")
 
-	nodes, err := ListManagedNodes(ctx, client, ManagedNodeFilter{
-		ManagedLabel:  cfg.NodeLabels.Managed,
-		DisabledLabel: cfg.NodeLabels.Disabled,
-		IgnoreLabels:  cfg.IgnoreLabels,
-	})
+	nodes, err := ListManagedNodes(ctx, client, NewManagedNodeFilter(cfg))
 	if err != nil {
-		return fmt.Errorf("listing managed nodes: %w", err)
+		return nil, fmt.Errorf("listing managed nodes: %w", err)
 	}
 
+	// Plan first: the whole batch is logged (and optionally approved) before any node is touched.
 	now := time.Now()
-	for _, node := range nodes {
-		if IsNodeReady(&node) {
+	plan := BatchPlan{Operation: "force-power-on"}
+	var targets []*NodeWrapper
+	for i := range nodes {
+		node := &nodes[i]
+		if IsNodeReady(node) {
 			slog.Info("Skipping node already marked Ready", "node", node.Name)
 			continue
 		}
 
-		wrapped := NewNodeWrapper(&node, state, now, NodeAnnotationConfig{
+		wrapped := NewNodeWrapper(node, state, now, NodeAnnotationConfig{
 			MAC: cfg.NodeAnnotations.MAC,
 		}, cfg.IgnoreLabels)
-
-		slog.Info("Force powering on", "node", node.Name)
-		if err := PowerOnAndMarkBooted(ctx, wrapped, cfg, client, powerOner, state, dryRun); err != nil {
-			slog.Warn("Failed to force power on node", "node", node.Name, "err", err)
+		if wrapped.IsObserveOnly() {
+			slog.Info("Observe-only: would force power on node", "node", node.Name)
 			continue
 		}
+		if IsInMaintenance(node) {
+			slog.Info("Skipping node in maintenance", "node", node.Name)
+			continue
+		}
+
+		plan.Steps = append(plan.Steps, PlanStep{Node: node.Name, Action: "power-on"})
+		targets = append(targets, wrapped)
+	}
+	if len(plan.Steps) == 0 {
+		return nil, nil
 	}
 
-	return nil
+	plan.Log()
+	if !PlanApproved(ctx, client, cfg, plan, dryRun) {
+		return nil, nil
+	}
+
+	var failures []error
+	var poweredOn []string
+	results := make([]ForcePowerOnResult, 0, len(targets))
+	for _, wrapped := range targets {
+		slog.Info("Force powering on", "node", wrapped.Name)
+		err := PowerOnAndMarkBooted(ctx, wrapped, cfg, client, powerOner, state, dryRun)
+		results = append(results, ForcePowerOnResult{Node: wrapped, Err: err})
+		switch {
+		case errors.Is(err, ErrPowerOnSuppressed):
+			poweredOn = append(poweredOn, wrapped.Name) // an earlier power-on is still in flight
+		case err != nil:
+			slog.Warn("Failed to force power on node", "node", wrapped.Name, "err", err)
+			failures = append(failures, fmt.Errorf("%s: %w", wrapped.Name, err))
+		default:
+			poweredOn = append(poweredOn, wrapped.Name)
+		}
+	}
+	if dryRun {
+		return results, nil
+	}
+
+	ready := 0
+	if timeout := cfg.ForcePowerOnReadyTimeout; timeout > 0 && !cfg.IsPowerDryRun() && len(poweredOn) > 0 {
+		notReady := waitForNodesReady(ctx, client, poweredOn, timeout, time.Duration(cfg.BootPollIntervalSeconds)*time.Second)
+		for i := range results {
+			res := &results[i]
+			if !slices.Contains(poweredOn, res.Node.Name) {
+				continue
+			}
+			if notReady[res.Node.Name] {
+				res.Err = fmt.Errorf("not Ready within %s", timeout)
+				failures = append(failures, fmt.Errorf("%s: %w", res.Node.Name, res.Err))
+			} else {
+				ready++
+			}
+		}
+	}
+
+	slog.Info("Force power-on finished", "targets", len(targets), "poweredOn", len(poweredOn),
+		"ready", ready, "failed", len(failures))
+	if len(failures) > 0 {
+		return results, fmt.Errorf("force power-on failed for %d of %d nodes: %w", len(failures), len(targets), errors.Join(failures...))
+	}
+	return results, nil
+}
+
+// waitForNodesReady polls until every named node is Ready or timeout passes, and returns the
+// nodes that never became Ready.
+func waitForNodesReady(ctx context.Context, client kubernetes.Interface, names []string, timeout, interval time.Duration) map[string]bool {
+	pending := map[string]bool{}
+	for _, name := range names {
+		pending[name] = true
+	}
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		for name := range pending {
+			n, err := client.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+			if err == nil && IsNodeReady(n) {
+				delete(pending, name)
+			}
+		}
+		remaining := time.Until(deadline)
+		if len(pending) == 0 || remaining <= 0 {
+			return pending
+		}
+		select {
+		case <-ctx.Done():
+			return pending
+		case <-time.After(min(interval, remaining)):
+		}
+	}
 }
@@ -20,11 +20,13 @@ type mockPower struct {
 	called  bool
 	fail    bool
 	lastMAC string
+	delay   time.Duration // simulated boot time
 }
 
 func (m *mockPower) PowerOn(ctx context.Context, node, mac string) error {
 	m.called = true
 	m.lastMAC = mac
+	time.Sleep(m.delay)
 	if m.fail {
 		return errors.New("mock failure")
 	}
@@ -56,7 +58,7 @@ func TestForcePowerOnAllNodes_DryRunSkips(t *testing.T) {
 	state := nodeops.NewNodeStateTracker()
 	powerMock := &mockPower{}
 
-	err := nodeops.ForcePowerOnAllNodes(context.Background(), client, cfg, state, powerMock, true)
+	_, err := nodeops.ForcePowerOnAllNodes(context.Background(), client, cfg, state, powerMock, true)
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
@@ -65,6 +67,79 @@ func TestForcePowerOnAllNodes_DryRunSkips(t *testing.T) {
 	}
 }
 
+func TestForcePowerOnAllNodes_ReportsOutcomes(t *testing.T) {
+	offNode := func(name string) *v1.Node {
+		return &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Labels:      map[string]string{"scaling-managed-by-cba": "true"},
+				Annotations: map[string]string{"cba.dev/mac": "00:11:22:33:44:55"},
+			},
+			Status: v1.NodeStatus{Conditions: []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionFalse}}},
+		}
+	}
+	cases := []struct {
+		name         string
+		fail         bool
+		readyTimeout time.Duration
+		wantErr      bool
+	}{
+		{"power-on failure is reported", true, 0, true},
+		{"no readiness wait", false, 0, false},
+		{"node not Ready within timeout", false, 10 * time.Millisecond, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := corefake.NewSimpleClientset(offNode("node1"))
+			cfg := &config.Config{
+				NodeLabels:               config.NodeLabelConfig{Managed: "scaling-managed-by-cba"},
+				NodeAnnotations:          config.NodeAnnotationConfig{MAC: "cba.dev/mac"},
+				ForcePowerOnReadyTimeout: tc.readyTimeout,
+			}
+			powerMock := &mockPower{fail: tc.fail}
+			_, err := nodeops.ForcePowerOnAllNodes(context.Background(), client, cfg, nodeops.NewNodeStateTracker(), powerMock, false)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("err = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestForcePowerOnAllNodes_ConfirmsReadiness(t *testing.T) {
+	ctx := context.Background()
+	client := corefake.NewSimpleClientset(&v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "node1",
+			Labels:      map[string]string{"scaling-managed-by-cba": "true"},
+			Annotations: map[string]string{"cba.dev/mac": "00:11:22:33:44:55"},
+		},
+		Status: v1.NodeStatus{Conditions: []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionFalse}}},
+	})
+	cfg := &config.Config{
+		NodeLabels:               config.NodeLabelConfig{Managed: "scaling-managed-by-cba"},
+		NodeAnnotations:          config.NodeAnnotationConfig{MAC: "cba.dev/mac"},
+		ForcePowerOnReadyTimeout: time.Minute,
+	}
+	booting := &bootingPower{client: client}
+
+	if _, err := nodeops.ForcePowerOnAllNodes(ctx, client, cfg, nodeops.NewNodeStateTracker(), booting, false); err != nil {
+		t.Errorf("expected node to be confirmed Ready, got %v", err)
+	}
+}
+
+// bootingPower marks the node Ready when powering it on.
+type bootingPower struct{ client *corefake.Clientset }
+
+func (b *bootingPower) PowerOn(ctx context.Context, node, _ string) error {
+	n, err := b.client.CoreV1().Nodes().Get(ctx, node, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	n.Status.Conditions = []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionTrue}}
+	_, err = b.client.CoreV1().Nodes().UpdateStatus(ctx, n, metav1.UpdateOptions{})
+	return err
+}
+
 func TestPowerOnAndMarkBooted_HandlesPowerFailure(t *testing.T) {
 	node := &v1.Node{
 		ObjectMeta: metav1.ObjectMeta{
@@ -93,6 +168,36 @@ func TestPowerOnAndMarkBooted_HandlesPowerFailure(t *testing.T) {
 	}
 }
 
+func TestPowerOnAndMarkBooted_MACOnlyRequiredForWOL(t *testing.T) {
+	for _, tc := range []struct {
+		mode    string
+		wantErr error
+	}{
+		{"ipmi", nil},
+		{"redfish", nil},
+		{"exec", nil},
+		{"wol", nodeops.ErrMissingMAC},
+		{"wol-direct", nodeops.ErrMissingMAC},
+	} {
+		t.Run(tc.mode, func(t *testing.T) {
+			node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "bmc-node"}} // no MAC annotation
+			state := nodeops.NewNodeStateTracker()
+			powerMock := &mockPower{}
+			cfg := &config.Config{PowerOnMode: tc.mode}
+
+			err := nodeops.PowerOnAndMarkBooted(context.Background(),
+				nodeops.NewNodeWrapper(node, state, time.Now(), nodeops.NodeAnnotationConfig{}, nil),
+				cfg, corefake.NewSimpleClientset(node), powerMock, state, false)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tc.wantErr)
+			}
+			if powerMock.called != (tc.wantErr == nil) {
+				t.Errorf("PowerOn called = %v, want %v", powerMock.called, tc.wantErr == nil)
+			}
+		})
+	}
+}
+
 func TestClearPoweredOffAnnotation_Success(t *testing.T) {
 	client := corefake.NewSimpleClientset(&v1.Node{
 		ObjectMeta: metav1.ObjectMeta{
@@ -124,7 +229,7 @@ func TestUncordonNode_Success(t *testing.T) {
 		ObjectMeta: metav1.ObjectMeta{Name: "node1"},
 		Spec:       v1.NodeSpec{Unschedulable: true},
 	})
-	err := nodeops.UncordonNode(context.Background(), client, "node1")
+	err := nodeops.UncordonNode(context.Background(), client, "node1", false)
 	if err != nil {
 		t.Errorf("expected uncordon to succeed, got: %v", err)
 	}
@@ -135,8 +240,126 @@ func TestUncordonNode_NoOp(t *testing.T) {
 		ObjectMeta: metav1.ObjectMeta{Name: "node2"},
 		Spec:       v1.NodeSpec{Unschedulable: false},
 	})
-	err := nodeops.UncordonNode(context.Background(), client, "node2")
+	err := nodeops.UncordonNode(context.Background(), client, "node2", false)
 	if err != nil {
 		t.Errorf("expected no-op success, got: %v", err)
 	}
 }
+
+func TestUncordonNode_TaintMode(t *testing.T) {
+	client := corefake.NewSimpleClientset(&v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+		Spec: v1.NodeSpec{
+			Unschedulable: true, // owned by someone else in taint mode
+			Taints: []v1.Taint{
+				{Key: "other", Effect: v1.TaintEffectNoExecute},
+				{Key: nodeops.TaintPoweringOff, Effect: v1.TaintEffectNoSchedule},
+			},
+		},
+	})
+	if err := nodeops.UncordonNode(context.Background(), client, "node1", true); err != nil {
+		t.Fatalf("uncordon: %v", err)
+	}
+
+	got, _ := client.CoreV1().Nodes().Get(context.Background(), "node1", metav1.GetOptions{})
+	if !got.Spec.Unschedulable {
+		t.Error("taint mode must not clear spec.unschedulable")
+	}
+	if len(got.Spec.Taints) != 1 || got.Spec.Taints[0].Key != "other" {
+		t.Errorf("expected only the powering-off taint removed, got %v", got.Spec.Taints)
+	}
+}
+
+func TestCordon(t *testing.T) {
+	node := &v1.Node{}
+	nodeops.Cordon(node, true)
+	nodeops.Cordon(node, true)
+	if node.Spec.Unschedulable {
+		t.Error("taint mode must not set spec.unschedulable")
+	}
+	if len(node.Spec.Taints) != 1 || node.Spec.Taints[0].Key != nodeops.TaintPoweringOff ||
+		node.Spec.Taints[0].Effect != v1.TaintEffectNoSchedule {
+		t.Errorf("expected a single powering-off taint, got %v", node.Spec.Taints)
+	}
+	if !nodeops.IsCordoned(node) {
+		t.Error("tainted node should count as cordoned")
+	}
+
+	plain := &v1.Node{}
+	nodeops.Cordon(plain, false)
+	if !plain.Spec.Unschedulable || len(plain.Spec.Taints) != 0 || !nodeops.IsCordoned(plain) {
+		t.Errorf("unschedulable mode: got %+v", plain.Spec)
+	}
+}
+
+func TestPowerOnAndMarkBooted_DedupWindow(t *testing.T) {
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "node1",
+			Annotations: map[string]string{"cba.dev/mac": "00:11:22:33:44:55"},
+		},
+	}
+	client := corefake.NewSimpleClientset(node)
+	state := nodeops.NewNodeStateTracker()
+	cfg := &config.Config{
+		NodeAnnotations:    config.NodeAnnotationConfig{MAC: "cba.dev/mac"},
+		PowerOnDedupWindow: 50 * time.Millisecond,
+	}
+	wrapped := nodeops.NewNodeWrapper(node, state, time.Now(), nodeops.NodeAnnotationConfig{MAC: "cba.dev/mac"}, nil)
+
+	first := &mockPower{}
+	if err := nodeops.PowerOnAndMarkBooted(context.Background(), wrapped, cfg, client, first, state, false); err != nil {
+		t.Fatalf("first power-on: unexpected error: %v", err)
+	}
+	if !first.called {
+		t.Fatalf("expected first power-on to be sent")
+	}
+
+	second := &mockPower{}
+	err := nodeops.PowerOnAndMarkBooted(context.Background(), wrapped, cfg, client, second, state, false)
+	if !errors.Is(err, nodeops.ErrPowerOnSuppressed) {
+		t.Errorf("expected ErrPowerOnSuppressed within window, got %v", err)
+	}
+	if second.called {
+		t.Errorf("power-on within the dedup window must not be sent")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	third := &mockPower{}
+	if err := nodeops.PowerOnAndMarkBooted(context.Background(), wrapped, cfg, client, third, state, false); err != nil {
+		t.Fatalf("power-on after window: unexpected error: %v", err)
+	}
+	if !third.called {
+		t.Errorf("expected power-on after the window to be sent")
+	}
+}
+
+func TestPowerOnAndMarkBooted_RecordsBootDuration(t *testing.T) {
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node1",
+			Annotations: map[string]string{
+				"cba.dev/mac":                  "00:11:22:33:44:55",
+				nodeops.AnnotationBootDuration: "100s",
+			},
+		},
+	}
+	client := corefake.NewSimpleClientset(node)
+	state := nodeops.NewNodeStateTracker()
+	cfg := &config.Config{NodeAnnotations: config.NodeAnnotationConfig{MAC: "cba.dev/mac"}}
+	wrapped := nodeops.NewNodeWrapper(node, state, time.Now(), nodeops.NodeAnnotationConfig{MAC: "cba.dev/mac"}, nil)
+
+	power := &mockPower{delay: 1100 * time.Millisecond}
+	if err := nodeops.PowerOnAndMarkBooted(context.Background(), wrapped, cfg, client, power, state, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	updated, err := client.CoreV1().Nodes().Get(context.Background(), "node1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get node: %v", err)
+	}
+	// 0.7*100s + 0.3*1.1s, rounded to the second.
+	if got := updated.Annotations[nodeops.AnnotationBootDuration]; got != "1m10s" {
+		t.Errorf("boot duration = %q, want 1m10s", got)
+	}
+}
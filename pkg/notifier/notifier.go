@@ -0,0 +1,108 @@
+// Package notifier tells external systems (e.g. a Slack channel) about the power actions CBA takes.
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+)
+
+const (
+	ActionPowerOff = "power-off"
+	ActionPowerOn  = "power-on"
+)
+
+const defaultTimeout = 10 * time.Second
+
+// Event describes one node CBA powered on or off.
+type Event struct {
+	Action    string    `json:"action"` // ActionPowerOff or ActionPowerOn
+	Node      string    `json:"node"`
+	Reason    string    `json:"reason"` // the action category, e.g. "scale-down" or "rotate"
+	Timestamp time.Time `json:"timestamp"`
+	DryRun    bool      `json:"dryRun"`
+}
+
+// Notifier delivers events. Callers treat errors as log-only.
+type Notifier interface {
+	Notify(ctx context.Context, ev Event) error
+}
+
+// New returns the notifier configured by cfg, or nil when notifications are disabled.
+func New(cfg config.NotificationsConfig) Notifier {
+	if !cfg.Enabled {
+		return nil
+	}
+	timeout := defaultTimeout
+	if cfg.TimeoutSeconds > 0 {
+		timeout = time.Duration(cfg.TimeoutSeconds) * time.Second
+	}
+	return &WebhookNotifier{URL: cfg.WebhookURL, Headers: cfg.Headers, Timeout: timeout}
+}
+
+// WebhookNotifier POSTs each event as JSON to URL. The payload carries a "text" summary as well,
+// so a Slack incoming webhook can receive it directly.
+type WebhookNotifier struct {
+	URL     string
+	Headers map[string]string // e.g. Authorization
+	Timeout time.Duration     // per request; defaults to 10s
+	Client  *http.Client      // nil uses http.DefaultClient
+}
+
+type webhookPayload struct {
+	Event
+	Text string `json:"text"`
+}
+
+func (w *WebhookNotifier) Notify(ctx context.Context, ev Event) error {
+	timeout := w.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	reqCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	payload, err := json.Marshal(webhookPayload{Event: ev, Text: summary(ev)})
+	if err != nil {
+		return fmt.Errorf("encoding notification: %w", err)
+	}
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodPost, w.URL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("creating notification request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range w.Headers {
+		req.Header.Set(k, v)
+	}
+
+	client := w.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("calling notification webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+	return nil
+}
+
+func summary(ev Event) string {
+	verb := "powered off"
+	if ev.Action == ActionPowerOn {
+		verb = "powered on"
+	}
+	text := fmt.Sprintf("CBA %s node %s (%s)", verb, ev.Node, ev.Reason)
+	if ev.DryRun {
+		text = "[dry-run] " + text
+	}
+	return text
+}
@@ -0,0 +1,76 @@
+package notifier_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/notifier"
+)
+
+func TestWebhookNotifier_PostsEvent(t *testing.T) {
+	var got map[string]any
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decoding payload: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	n := notifier.New(config.NotificationsConfig{
+		Enabled:    true,
+		WebhookURL: srv.URL,
+		Headers:    map[string]string{"Authorization": "Bearer token"},
+	})
+	ev := notifier.Event{
+		Action:    notifier.ActionPowerOff,
+		Node:      "node1",
+		Reason:    "scale-down",
+		Timestamp: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		DryRun:    true,
+	}
+	if err := n.Notify(context.Background(), ev); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]any{
+		"action":    "power-off",
+		"node":      "node1",
+		"reason":    "scale-down",
+		"timestamp": "2025-01-02T03:04:05Z",
+		"dryRun":    true,
+		"text":      "[dry-run] CBA powered off node node1 (scale-down)",
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s = %v, want %v", k, got[k], v)
+		}
+	}
+	if auth != "Bearer token" {
+		t.Errorf("Authorization header = %q, want configured value", auth)
+	}
+}
+
+func TestWebhookNotifier_Errors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	n := &notifier.WebhookNotifier{URL: srv.URL}
+	if err := n.Notify(context.Background(), notifier.Event{Node: "node1"}); err == nil {
+		t.Error("expected error for non-2xx response")
+	}
+}
+
+func TestNew_Disabled(t *testing.T) {
+	if n := notifier.New(config.NotificationsConfig{WebhookURL: "http://example"}); n != nil {
+		t.Errorf("expected nil notifier when disabled, got %T", n)
+	}
+}
@@ -0,0 +1,32 @@
+package power
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// CommandRunner runs an external command. Backends that shell out (ipmitool, custom CLIs) take one
+// so tests can stub the process.
+type CommandRunner interface {
+	// Run executes name with args; env entries ("KEY=value") are added to the controller's environment.
+	Run(ctx context.Context, name string, args []string, env []string) ([]byte, error)
+}
+
+// ExecRunner runs commands with os/exec.
+type ExecRunner struct{}
+
+func (ExecRunner) Run(ctx context.Context, name string, args []string, env []string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Env = append(os.Environ(), env...)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	if err := cmd.Run(); err != nil {
+		return out.Bytes(), fmt.Errorf("%s: %w: %s", name, err, strings.TrimSpace(out.String()))
+	}
+	return out.Bytes(), nil
+}
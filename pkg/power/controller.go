@@ -2,7 +2,11 @@ package power
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/agenthttp"
 	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes"
 	"log/slog"
 	"time"
@@ -11,13 +15,35 @@ import (
 const (
 	ShutdownModeDisabled = "disabled"
 	ShutdownModeHTTP     = "http"
+	ShutdownModeExec     = "exec"
+	ShutdownModeRedfish  = "redfish"
 )
 
+// Node annotations that may hold a node's WOL MAC. They live here because nodeops imports this
+// package; nodeops.AnnotationMACManual and nodeops.AnnotationMACAuto refer to them.
 const (
-	PowerOnModeDisabled = "disabled"
-	PowerOnModeWOL      = "wol"
+	AnnotationMACManual = "cba.dev/mac-address-override" // manual override (takes precedence)
+	AnnotationMACAuto   = "cba.dev/mac-address"          // default auto-discovered MAC
 )
 
+const (
+	PowerOnModeDisabled  = "disabled"
+	PowerOnModeWOL       = "wol"
+	PowerOnModeWOLDirect = "wol-direct"
+	PowerOnModeIPMI      = "ipmi"
+	PowerOnModeExec      = "exec"
+	PowerOnModeRedfish   = "redfish"
+)
+
+// RequiresMAC reports whether powerOnMode wakes nodes by MAC address. The other backends address
+// a node by name or BMC and may be given an empty MAC.
+func RequiresMAC(powerOnMode string) bool {
+	return powerOnMode == PowerOnModeWOL || powerOnMode == PowerOnModeWOLDirect
+}
+
+// ErrNotConfigured is returned when a power action is attempted without a power controller.
+var ErrNotConfigured = errors.New("power controller not configured")
+
 type PowerOnController interface {
 	PowerOn(ctx context.Context, nodeName string, mac string) error
 }
@@ -26,46 +52,155 @@ type ShutdownController interface {
 	Shutdown(ctx context.Context, nodeName string) error
 }
 
-func NewControllersFromConfig(cfg *config.Config, client kubernetes.Interface) (ShutdownController, PowerOnController) {
+// NewControllersFromConfig builds the configured controllers; agent calls go through agent (nil: no auth).
+// An empty mode means disabled; an unknown one is an error.
+func NewControllersFromConfig(cfg *config.Config, client kubernetes.Interface, agent *agenthttp.Client) (ShutdownController, PowerOnController, error) {
+	var redfish *RedfishController
+	if cfg.ShutdownMode == ShutdownModeRedfish || cfg.PowerOnMode == PowerOnModeRedfish {
+		redfish = newRedfishController(cfg, client)
+	}
+
 	var shutdowner ShutdownController
 	switch cfg.ShutdownMode {
-	case ShutdownModeDisabled:
+	case ShutdownModeDisabled, "":
 		shutdowner = &NoopShutdownController{}
 	case ShutdownModeHTTP:
 		shutdowner = &ShutdownHTTPController{
-			DryRun:    cfg.DryRun,
+			DryRun:    cfg.IsPowerDryRun(),
 			Port:      cfg.ShutdownManager.Port,
 			Namespace: cfg.ShutdownManager.Namespace,
 			PodLabel:  cfg.ShutdownManager.PodLabel,
 			Client:    client,
+			HTTP:      agent,
+		}
+	case ShutdownModeExec:
+		shutdowner = &ExecShutdownController{
+			DryRun:            cfg.IsPowerDryRun(),
+			Client:            client,
+			Command:           cfg.Exec.Shutdown,
+			Timeout:           time.Duration(cfg.Exec.TimeoutSeconds) * time.Second,
+			MACAnnotationKeys: macAnnotationKeys(cfg),
 		}
+	case ShutdownModeRedfish:
+		shutdowner = redfish
 	default:
-		slog.Warn("Unknown shutdown mode; falling back to", "mode", ShutdownModeDisabled)
-		shutdowner = &NoopShutdownController{}
+		return nil, nil, fmt.Errorf("unknown shutdownMode %q", cfg.ShutdownMode)
+	}
+	if cfg.ShutdownMaxRetries > 0 && (cfg.ShutdownMode == ShutdownModeHTTP || cfg.ShutdownMode == ShutdownModeExec) {
+		shutdowner = &RetryingShutdownController{
+			Inner:      shutdowner,
+			MaxRetries: cfg.ShutdownMaxRetries,
+			Interval:   cfg.ShutdownRetryInterval,
+		}
+	}
+
+	var reachability ReachabilityProbe
+	if probe := cfg.BootReachabilityProbe; probe.Port > 0 {
+		reachability = &TCPReachabilityProbe{Port: probe.Port, Timeout: time.Duration(probe.TimeoutSeconds) * time.Second}
 	}
 
 	var powerOner PowerOnController
 	switch cfg.PowerOnMode {
-	case PowerOnModeDisabled:
+	case PowerOnModeDisabled, "":
 		powerOner = &NoopPowerOnController{}
 	case PowerOnModeWOL:
 		powerOner = &WakeOnLanController{
-			DryRun:         cfg.DryRun,
+			DryRun:         cfg.IsPowerDryRun(),
 			BroadcastAddr:  cfg.WOLBroadcastAddr,
 			BootTimeoutSec: time.Duration(cfg.WOLBootTimeoutSec) * time.Second,
+			PollInterval:   time.Duration(cfg.BootPollIntervalSeconds) * time.Second,
 			Client:         client,
 			MaxRetries:     3,
 			Namespace:      cfg.WolAgent.Namespace,
 			PodLabel:       cfg.WolAgent.PodLabel,
 			Port:           cfg.WolAgent.Port,
+			HTTP:           agent,
+			Reachability:   reachability,
+
+			MatchRenamedNodes: cfg.WOLMatchRenamedNodes,
+			MACAnnotationKeys: macAnnotationKeys(cfg),
+			ManagedSelector:   managedNodeSelector(cfg),
 		}
+	case PowerOnModeWOLDirect:
+		powerOner = &DirectWOLController{
+			DryRun:         cfg.IsPowerDryRun(),
+			Client:         client,
+			BroadcastAddrs: cfg.WOLBroadcastAddresses(),
+			MaxRetries:     3,
+			BootTimeout:    time.Duration(cfg.WOLBootTimeoutSec) * time.Second,
+			PollInterval:   time.Duration(cfg.BootPollIntervalSeconds) * time.Second,
+		}
+	case PowerOnModeIPMI:
+		powerOner = &IPMIPowerOnController{
+			DryRun:         cfg.IsPowerDryRun(),
+			Client:         client,
+			Command:        cfg.IPMI.Command,
+			Interface:      cfg.IPMI.Interface,
+			DefaultUser:    cfg.IPMI.User,
+			PasswordSecret: cfg.IPMI.PasswordSecret,
+			Timeout:        time.Duration(cfg.IPMI.TimeoutSeconds) * time.Second,
+			BootTimeout:    time.Duration(cfg.WOLBootTimeoutSec) * time.Second,
+			PollInterval:   time.Duration(cfg.BootPollIntervalSeconds) * time.Second,
+		}
+	case PowerOnModeExec:
+		powerOner = &ExecPowerController{
+			DryRun:       cfg.IsPowerDryRun(),
+			Client:       client,
+			Command:      cfg.Exec.PowerOn,
+			Timeout:      time.Duration(cfg.Exec.TimeoutSeconds) * time.Second,
+			BootTimeout:  time.Duration(cfg.WOLBootTimeoutSec) * time.Second,
+			PollInterval: time.Duration(cfg.BootPollIntervalSeconds) * time.Second,
+		}
+	case PowerOnModeRedfish:
+		powerOner = redfish
 	default:
-		slog.Warn("Unknown power-on mode; falling back to", "mode", PowerOnModeDisabled)
-		powerOner = &NoopPowerOnController{}
+		return nil, nil, fmt.Errorf("unknown powerOnMode %q", cfg.PowerOnMode)
 	}
 
 	slog.Debug("Using configured shutdown mode", "mode", cfg.ShutdownMode)
 	slog.Debug("Using configured power-on mode", "mode", cfg.PowerOnMode)
 
-	return shutdowner, powerOner
+	return shutdowner, powerOner, nil
+}
+
+func newRedfishController(cfg *config.Config, client kubernetes.Interface) *RedfishController {
+	timeout := time.Duration(cfg.Redfish.TimeoutSeconds) * time.Second
+	return &RedfishController{
+		DryRun:          cfg.IsPowerDryRun(),
+		Client:          client,
+		HTTP:            NewRedfishHTTPClient(cfg.Redfish.InsecureSkipVerify, timeout),
+		DefaultEndpoint: cfg.Redfish.Endpoint,
+		DefaultSystemID: cfg.Redfish.SystemID,
+		DefaultUser:     cfg.Redfish.User,
+		PasswordSecret:  cfg.Redfish.PasswordSecret,
+		MaxRetries:      cfg.Redfish.MaxRetries,
+		BootTimeout:     time.Duration(cfg.WOLBootTimeoutSec) * time.Second,
+		ShutdownTimeout: time.Duration(cfg.Redfish.ShutdownTimeoutSeconds) * time.Second,
+		PollInterval:    time.Duration(cfg.BootPollIntervalSeconds) * time.Second,
+	}
+}
+
+// macAnnotationKeys lists the node annotations that may hold a node's WOL MAC, in precedence order.
+func macAnnotationKeys(cfg *config.Config) []string {
+	keys := []string{AnnotationMACManual, AnnotationMACAuto}
+	if cfg.NodeAnnotations.MAC != "" {
+		keys = append(keys, cfg.NodeAnnotations.MAC)
+	}
+	return keys
+}
+
+// managedNodeSelector selects the nodes the autoscaler manages, as nodeops.ManagedNodeFilter does
+// (nodeops imports this package). An invalid managedSelector matches nothing; nil means no restriction.
+func managedNodeSelector(cfg *config.Config) labels.Selector {
+	sel, err := cfg.NodeLabels.Selector()
+	if err != nil {
+		return labels.Nothing()
+	}
+	if sel != nil {
+		return sel
+	}
+	if cfg.NodeLabels.Managed == "" {
+		return nil
+	}
+	return labels.SelectorFromSet(labels.Set{cfg.NodeLabels.Managed: "true"})
 }
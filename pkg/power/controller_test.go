@@ -36,7 +36,10 @@ func TestNewControllersFromConfig(t *testing.T) {
 		},
 	}
 
-	shutdowner, powerOner := power.NewControllersFromConfig(cfg, client)
+	shutdowner, powerOner, err := power.NewControllersFromConfig(cfg, client, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 
 	if shutdowner == nil {
 		t.Errorf("Expected shutdown controller, got nil")
@@ -46,6 +49,23 @@ func TestNewControllersFromConfig(t *testing.T) {
 	}
 }
 
+func TestNewControllersFromConfig_UnknownMode(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	for _, cfg := range []*config.Config{
+		{ShutdownMode: "ssh"},
+		{PowerOnMode: "magic"},
+	} {
+		if _, _, err := power.NewControllersFromConfig(cfg, client, nil); err == nil {
+			t.Errorf("expected error for modes %q/%q, got none", cfg.ShutdownMode, cfg.PowerOnMode)
+		}
+	}
+
+	shutdowner, powerOner, err := power.NewControllersFromConfig(&config.Config{}, client, nil)
+	if err != nil || shutdowner == nil || powerOner == nil {
+		t.Errorf("empty modes should build noop controllers, got %v/%v/%v", shutdowner, powerOner, err)
+	}
+}
+
 func TestNoopControllers(t *testing.T) {
 	ctx := context.Background()
 
@@ -0,0 +1,153 @@
+package power
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"text/template"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/clock"
+)
+
+const defaultExecTimeout = 30 * time.Second
+
+// ExecTarget is the data available to exec command templates.
+type ExecTarget struct {
+	Node string
+	MAC  string
+	IP   string // the node's InternalIP, if known
+}
+
+// execCommand is an argv whose elements are text/template strings. Arguments are rendered one by
+// one and run without a shell, so placeholder values can't inject extra commands.
+type execCommand struct {
+	Args    []string
+	Runner  CommandRunner // nil: ExecRunner
+	Timeout time.Duration // defaults to 30s
+}
+
+// Render fills the placeholders of every argument.
+func (c execCommand) Render(target ExecTarget) ([]string, error) {
+	if len(c.Args) == 0 {
+		return nil, fmt.Errorf("no command configured")
+	}
+	out := make([]string, 0, len(c.Args))
+	for _, arg := range c.Args {
+		tmpl, err := template.New("arg").Option("missingkey=error").Parse(arg)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %q: %w", arg, err)
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, target); err != nil {
+			return nil, fmt.Errorf("rendering %q: %w", arg, err)
+		}
+		out = append(out, buf.String())
+	}
+	return out, nil
+}
+
+func (c execCommand) run(ctx context.Context, argv []string) error {
+	runner := c.Runner
+	if runner == nil {
+		runner = ExecRunner{}
+	}
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = defaultExecTimeout
+	}
+	callCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	_, err := runner.Run(callCtx, argv[0], argv[1:], nil)
+	return err
+}
+
+// ExecPowerController powers nodes on by running an operator-supplied command (e.g. a PDU CLI),
+// then waits for the node to become Ready.
+type ExecPowerController struct {
+	DryRun       bool
+	Client       kubernetes.Interface
+	Command      []string // argv templates; placeholders {{.Node}}, {{.MAC}}, {{.IP}}
+	Runner       CommandRunner
+	Timeout      time.Duration
+	BootTimeout  time.Duration
+	PollInterval time.Duration
+	Clock        clock.Clock
+}
+
+func (c *ExecPowerController) PowerOn(ctx context.Context, nodeName string, mac string) error {
+	cmd := execCommand{Args: c.Command, Runner: c.Runner, Timeout: c.Timeout}
+	argv, err := cmd.Render(execTarget(ctx, c.Client, nodeName, mac, nil))
+	if err != nil {
+		return fmt.Errorf("exec power-on command: %w", err)
+	}
+	if c.DryRun {
+		slog.Info("Dry-run: would run power-on command", "node", nodeName, "command", strings.Join(argv, " "))
+		return nil
+	}
+	slog.Info("Running power-on command", "node", nodeName, "command", argv[0])
+	if err := cmd.run(ctx, argv); err != nil {
+		return fmt.Errorf("exec power-on %s: %w", nodeName, err)
+	}
+	return waitForReadiness(ctx, c.Client, c.Clock, nodeName, true, c.BootTimeout, c.PollInterval)
+}
+
+// ExecShutdownController powers nodes off by running an operator-supplied command.
+type ExecShutdownController struct {
+	DryRun  bool
+	Client  kubernetes.Interface
+	Command []string // argv templates; placeholders {{.Node}}, {{.MAC}}, {{.IP}}
+	Runner  CommandRunner
+	Timeout time.Duration
+	// MACAnnotationKeys are checked in order to fill {{.MAC}}.
+	MACAnnotationKeys []string
+}
+
+func (c *ExecShutdownController) Shutdown(ctx context.Context, nodeName string) error {
+	cmd := execCommand{Args: c.Command, Runner: c.Runner, Timeout: c.Timeout}
+	argv, err := cmd.Render(execTarget(ctx, c.Client, nodeName, "", c.MACAnnotationKeys))
+	if err != nil {
+		return fmt.Errorf("exec shutdown command: %w", err)
+	}
+	if c.DryRun {
+		slog.Info("Dry-run: would run shutdown command", "node", nodeName, "command", strings.Join(argv, " "))
+		return nil
+	}
+	slog.Info("Running shutdown command", "node", nodeName, "command", argv[0])
+	if err := cmd.run(ctx, argv); err != nil {
+		return fmt.Errorf("exec shutdown %s: %w", nodeName, err)
+	}
+	return nil
+}
+
+// execTarget gathers template data for node. A missing Node object leaves IP (and MAC) empty.
+func execTarget(ctx context.Context, client kubernetes.Interface, nodeName, mac string, macKeys []string) ExecTarget {
+	target := ExecTarget{Node: nodeName, MAC: mac}
+	if client == nil {
+		return target
+	}
+	n, err := client.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		slog.Debug("Exec backend: reading node failed; IP placeholder left empty", "node", nodeName, "err", err)
+		return target
+	}
+	target.IP = internalIP(n)
+	if target.MAC == "" {
+		target.MAC = firstAnnotation(n, macKeys)
+	}
+	return target
+}
+
+func firstAnnotation(n *v1.Node, keys []string) string {
+	for _, key := range keys {
+		if v := n.Annotations[key]; v != "" {
+			return v
+		}
+	}
+	return ""
+}
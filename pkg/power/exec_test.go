@@ -0,0 +1,111 @@
+package power_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corefake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/power"
+)
+
+func execNode() *v1.Node {
+	return &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node1", Annotations: map[string]string{"cba.dev/mac-address": "aa:bb:cc:dd:ee:ff"}},
+		Status: v1.NodeStatus{
+			Addresses:  []v1.NodeAddress{{Type: v1.NodeInternalIP, Address: "10.0.0.5"}},
+			Conditions: []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionTrue}},
+		},
+	}
+}
+
+func TestExecPowerController_PowerOn(t *testing.T) {
+	tests := []struct {
+		name     string
+		command  []string
+		dryRun   bool
+		runErr   error
+		wantErr  string
+		wantCall []string
+	}{
+		{
+			name:     "renders node, MAC and IP",
+			command:  []string{"pdu-ctl", "--outlet={{.Node}}", "--mac", "{{.MAC}}", "--ip", "{{.IP}}", "on"},
+			wantCall: []string{"pdu-ctl", "--outlet=node1", "--mac", "11:22:33:44:55:66", "--ip", "10.0.0.5", "on"},
+		},
+		{
+			name:    "dry run renders but does not run",
+			command: []string{"pdu-ctl", "{{.Node}}"},
+			dryRun:  true,
+		},
+		{
+			name:    "unknown placeholder",
+			command: []string{"pdu-ctl", "{{.Rack}}"},
+			wantErr: "rendering",
+		},
+		{
+			name:    "command failure",
+			command: []string{"pdu-ctl", "{{.Node}}"},
+			runErr:  errors.New("exit status 1"),
+			wantErr: "exit status 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &stubRunner{err: tt.runErr}
+			ctrl := &power.ExecPowerController{
+				DryRun:       tt.dryRun,
+				Client:       corefake.NewSimpleClientset(execNode()),
+				Command:      tt.command,
+				Runner:       runner,
+				BootTimeout:  time.Second,
+				PollInterval: time.Millisecond,
+			}
+
+			err := ctrl.PowerOn(context.Background(), "node1", "11:22:33:44:55:66")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.dryRun {
+				if len(runner.calls) != 0 {
+					t.Fatalf("dry run must not run commands, got %v", runner.calls)
+				}
+				return
+			}
+			if len(runner.calls) != 1 || !reflect.DeepEqual(runner.calls[0], tt.wantCall) {
+				t.Errorf("expected call %v, got %v", tt.wantCall, runner.calls)
+			}
+		})
+	}
+}
+
+func TestExecShutdownController_Shutdown(t *testing.T) {
+	runner := &stubRunner{}
+	ctrl := &power.ExecShutdownController{
+		Client:            corefake.NewSimpleClientset(execNode()),
+		Command:           []string{"ssh", "root@{{.IP}}", "poweroff", "# {{.Node}} {{.MAC}}"},
+		Runner:            runner,
+		MACAnnotationKeys: []string{"cba.dev/mac-address"},
+	}
+
+	if err := ctrl.Shutdown(context.Background(), "node1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"ssh", "root@10.0.0.5", "poweroff", "# node1 aa:bb:cc:dd:ee:ff"}
+	if len(runner.calls) != 1 || !reflect.DeepEqual(runner.calls[0], want) {
+		t.Errorf("expected call %v, got %v", want, runner.calls)
+	}
+}
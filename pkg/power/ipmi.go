@@ -0,0 +1,116 @@
+package power
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/clock"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+)
+
+// Per-node BMC annotations, shared by the BMC-backed controllers.
+const (
+	AnnotationBMCAddress = "cba.dev/bmc-address" // BMC host or IP
+	AnnotationBMCUser    = "cba.dev/bmc-user"    // BMC user; falls back to the configured default
+)
+
+const defaultIPMITimeout = 10 * time.Second
+
+// IPMIPowerOnController powers nodes on through their BMC with `ipmitool chassis power on`, then
+// waits for the node to become Ready. The password is read from a Secret and handed to ipmitool
+// via IPMI_PASSWORD (-E), so it never appears on the command line.
+type IPMIPowerOnController struct {
+	DryRun         bool
+	Client         kubernetes.Interface
+	Runner         CommandRunner // nil: ExecRunner
+	Command        string        // ipmitool binary; defaults to "ipmitool"
+	Interface      string        // ipmitool -I; defaults to "lanplus"
+	DefaultUser    string
+	PasswordSecret config.SecretKeyRef
+	Timeout        time.Duration // per ipmitool call; defaults to 10s
+	BootTimeout    time.Duration
+	PollInterval   time.Duration
+	Clock          clock.Clock
+}
+
+func (c *IPMIPowerOnController) PowerOn(ctx context.Context, nodeName string, _ string) error {
+	n, err := c.Client.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("reading node %s: %w", nodeName, err)
+	}
+	host := n.Annotations[AnnotationBMCAddress]
+	if host == "" {
+		return fmt.Errorf("node %s has no %s annotation", nodeName, AnnotationBMCAddress)
+	}
+	user := n.Annotations[AnnotationBMCUser]
+	if user == "" {
+		user = c.DefaultUser
+	}
+	args := c.args(host, user, "chassis", "power", "on")
+
+	if c.DryRun {
+		slog.Info("Dry-run: would power on node via IPMI", "node", nodeName, "command", c.command()+" "+strings.Join(args, " "))
+		return nil
+	}
+
+	password, err := readSecretKey(ctx, c.Client, c.PasswordSecret)
+	if err != nil {
+		return fmt.Errorf("reading BMC password: %w", err)
+	}
+	callCtx, cancel := context.WithTimeout(ctx, c.timeout())
+	defer cancel()
+	slog.Info("Powering on node via IPMI", "node", nodeName, "bmc", host)
+	if _, err := c.runner().Run(callCtx, c.command(), args, []string{"IPMI_PASSWORD=" + password}); err != nil {
+		return fmt.Errorf("IPMI power on %s via %s: %w", nodeName, host, err)
+	}
+
+	return waitForReadiness(ctx, c.Client, c.Clock, nodeName, true, c.BootTimeout, c.PollInterval)
+}
+
+func (c *IPMIPowerOnController) args(host, user string, cmd ...string) []string {
+	iface := c.Interface
+	if iface == "" {
+		iface = "lanplus"
+	}
+	return append([]string{"-I", iface, "-H", host, "-U", user, "-E"}, cmd...)
+}
+
+func (c *IPMIPowerOnController) command() string {
+	if c.Command != "" {
+		return c.Command
+	}
+	return "ipmitool"
+}
+
+func (c *IPMIPowerOnController) runner() CommandRunner {
+	if c.Runner != nil {
+		return c.Runner
+	}
+	return ExecRunner{}
+}
+
+func (c *IPMIPowerOnController) timeout() time.Duration {
+	if c.Timeout > 0 {
+		return c.Timeout
+	}
+	return defaultIPMITimeout
+}
+
+// readSecretKey returns data[ref.Key] of the referenced Secret, trimmed of surrounding whitespace.
+func readSecretKey(ctx context.Context, client kubernetes.Interface, ref config.SecretKeyRef) (string, error) {
+	secret, err := client.CoreV1().Secrets(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("reading secret %s/%s: %w", ref.Namespace, ref.Name, err)
+	}
+	raw, ok := secret.Data[ref.Key]
+	if !ok {
+		return "", fmt.Errorf("secret %s/%s has no key %q", ref.Namespace, ref.Name, ref.Key)
+	}
+	return strings.TrimSpace(string(raw)), nil
+}
@@ -0,0 +1,119 @@
+package power_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corefake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/power"
+)
+
+// stubRunner records commands instead of running them.
+type stubRunner struct {
+	calls [][]string
+	env   [][]string
+	err   error
+}
+
+func (s *stubRunner) Run(_ context.Context, name string, args []string, env []string) ([]byte, error) {
+	s.calls = append(s.calls, append([]string{name}, args...))
+	s.env = append(s.env, env)
+	return nil, s.err
+}
+
+func bmcNode(annotations map[string]string) *v1.Node {
+	return &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node1", Annotations: annotations},
+		Status:     v1.NodeStatus{Conditions: []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionTrue}}},
+	}
+}
+
+func bmcSecret() *v1.Secret {
+	return &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "bmc", Namespace: "cba"},
+		Data:       map[string][]byte{"password": []byte("s3cret\n")},
+	}
+}
+
+func TestIPMIPowerOnController_PowerOn(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		dryRun      bool
+		runErr      error
+		wantErr     string
+		wantCmd     []string
+	}{
+		{
+			name:        "powers on with per-node user",
+			annotations: map[string]string{power.AnnotationBMCAddress: "10.0.0.21", power.AnnotationBMCUser: "root"},
+			wantCmd:     []string{"ipmitool", "-I", "lanplus", "-H", "10.0.0.21", "-U", "root", "-E", "chassis", "power", "on"},
+		},
+		{
+			name:        "falls back to the configured user",
+			annotations: map[string]string{power.AnnotationBMCAddress: "10.0.0.21"},
+			wantCmd:     []string{"ipmitool", "-I", "lanplus", "-H", "10.0.0.21", "-U", "admin", "-E", "chassis", "power", "on"},
+		},
+		{
+			name:    "missing BMC address",
+			wantErr: power.AnnotationBMCAddress,
+		},
+		{
+			name:        "unreachable BMC is an error",
+			annotations: map[string]string{power.AnnotationBMCAddress: "10.0.0.21"},
+			runErr:      errors.New("Unable to establish IPMI v2 / RMCP+ session"),
+			wantErr:     "RMCP+ session",
+		},
+		{
+			name:        "dry run only logs",
+			annotations: map[string]string{power.AnnotationBMCAddress: "10.0.0.21"},
+			dryRun:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &stubRunner{err: tt.runErr}
+			ctrl := &power.IPMIPowerOnController{
+				DryRun:         tt.dryRun,
+				Client:         corefake.NewSimpleClientset(bmcNode(tt.annotations), bmcSecret()),
+				Runner:         runner,
+				DefaultUser:    "admin",
+				PasswordSecret: config.SecretKeyRef{Namespace: "cba", Name: "bmc", Key: "password"},
+				BootTimeout:    time.Second,
+				PollInterval:   time.Millisecond,
+			}
+
+			err := ctrl.PowerOn(context.Background(), "node1", "")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantCmd == nil {
+				if len(runner.calls) != 0 {
+					t.Fatalf("dry run must not execute ipmitool, got %v", runner.calls)
+				}
+				return
+			}
+			if len(runner.calls) != 1 || !reflect.DeepEqual(runner.calls[0], tt.wantCmd) {
+				t.Fatalf("expected %v, got %v", tt.wantCmd, runner.calls)
+			}
+			if !reflect.DeepEqual(runner.env[0], []string{"IPMI_PASSWORD=s3cret"}) {
+				t.Errorf("password must be passed via IPMI_PASSWORD, got env %v", runner.env[0])
+			}
+		})
+	}
+}
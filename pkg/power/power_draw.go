@@ -0,0 +1,10 @@
+package power
+
+import "context"
+
+// PowerDrawProbe reads a node's current power consumption, typically from its BMC
+// (e.g. the Redfish Power resource). Power controllers backed by a BMC implement it
+// alongside PowerOnController; the reconciler picks it up via type assertion.
+type PowerDrawProbe interface {
+	PowerDraw(ctx context.Context, nodeName string) (watts float64, err error)
+}
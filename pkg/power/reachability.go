@@ -0,0 +1,48 @@
+package power
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"strconv"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// ReachabilityProbe reports whether a booting host already answers on the network. It is an
+// earlier "alive" signal than Kubernetes Ready, which also waits for the kubelet to register.
+type ReachabilityProbe interface {
+	Reachable(ctx context.Context, node *v1.Node) bool
+}
+
+// TCPReachabilityProbe treats a host as up once a TCP connection to its InternalIP on Port
+// succeeds. TCP is used instead of ICMP so the controller needs no raw-socket capability.
+type TCPReachabilityProbe struct {
+	Port    int
+	Timeout time.Duration
+}
+
+func (p *TCPReachabilityProbe) Reachable(ctx context.Context, node *v1.Node) bool {
+	ip := internalIP(node)
+	if ip == "" {
+		slog.Debug("Reachability probe: node has no InternalIP", "node", node.Name)
+		return false
+	}
+	dialer := net.Dialer{Timeout: p.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(ip, strconv.Itoa(p.Port)))
+	if err != nil {
+		return false
+	}
+	_ = conn.Close()
+	return true
+}
+
+func internalIP(node *v1.Node) string {
+	for _, addr := range node.Status.Addresses {
+		if addr.Type == v1.NodeInternalIP {
+			return addr.Address
+		}
+	}
+	return ""
+}
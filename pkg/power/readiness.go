@@ -0,0 +1,40 @@
+package power
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/clock"
+)
+
+// waitForReadiness polls node until its Ready condition equals wantReady or timeout passes.
+func waitForReadiness(ctx context.Context, client kubernetes.Interface, clk clock.Clock, node string, wantReady bool, timeout, interval time.Duration) error {
+	if clk == nil {
+		clk = clock.RealClock{}
+	}
+	if interval <= 0 {
+		interval = defaultBootPollInterval
+	}
+	start := clk.Now()
+	for {
+		n, err := client.CoreV1().Nodes().Get(ctx, node, metav1.GetOptions{})
+		if err != nil {
+			slog.Debug("Waiting for node readiness", "node", node, "err", err)
+		} else if nodeIsReady(n) == wantReady {
+			slog.Info("Node reached desired readiness", "node", node, "ready", wantReady, "after", clk.Since(start).String())
+			return nil
+		}
+		if clk.Since(start) >= timeout {
+			return fmt.Errorf("node %s did not become ready=%t within %s", node, wantReady, timeout)
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for node %s readiness: %w", node, ctx.Err())
+		case <-clk.After(interval):
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package power
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/clock"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+)
+
+// AnnotationRedfishSystemID selects the Redfish ComputerSystem (and Chassis) member of a node's BMC.
+const AnnotationRedfishSystemID = "cba.dev/redfish-system-id"
+
+const (
+	redfishResetGracefulShutdown = "GracefulShutdown"
+	redfishResetOn               = "On"
+
+	defaultRedfishTimeout      = 10 * time.Second
+	defaultRedfishRetryBackoff = 2 * time.Second
+)
+
+// RedfishController powers nodes off and on through the Redfish ComputerSystem.Reset action of
+// their BMC, then waits for the node to reach NotReady / Ready. The BMC endpoint and system id
+// come from cba.dev/bmc-address and cba.dev/redfish-system-id, falling back to the defaults.
+type RedfishController struct {
+	DryRun          bool
+	Client          kubernetes.Interface
+	HTTP            *http.Client // nil: NewRedfishHTTPClient(false, 10s)
+	DefaultEndpoint string       // BMC host or base URL; "https://" is assumed without a scheme
+	DefaultSystemID string       // defaults to "1"
+	DefaultUser     string
+	PasswordSecret  config.SecretKeyRef
+	MaxRetries      int           // attempts per reset request; at least 1
+	RetryBackoff    time.Duration // doubled after every failed attempt; defaults to 2s
+	BootTimeout     time.Duration
+	ShutdownTimeout time.Duration
+	PollInterval    time.Duration
+	Clock           clock.Clock
+}
+
+// NewRedfishHTTPClient returns the client used for BMC calls. BMCs commonly ship self-signed
+// certificates, hence the opt-out of verification.
+func NewRedfishHTTPClient(insecureSkipVerify bool, timeout time.Duration) *http.Client {
+	if timeout <= 0 {
+		timeout = defaultRedfishTimeout
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: insecureSkipVerify}
+	return &http.Client{Transport: transport, Timeout: timeout}
+}
+
+func (c *RedfishController) Shutdown(ctx context.Context, nodeName string) error {
+	if err := c.reset(ctx, nodeName, redfishResetGracefulShutdown); err != nil {
+		return err
+	}
+	if c.DryRun {
+		return nil
+	}
+	return waitForReadiness(ctx, c.Client, c.Clock, nodeName, false, c.ShutdownTimeout, c.PollInterval)
+}
+
+func (c *RedfishController) PowerOn(ctx context.Context, nodeName string, _ string) error {
+	if err := c.reset(ctx, nodeName, redfishResetOn); err != nil {
+		return err
+	}
+	if c.DryRun {
+		return nil
+	}
+	return waitForReadiness(ctx, c.Client, c.Clock, nodeName, true, c.BootTimeout, c.PollInterval)
+}
+
+// PowerDraw reads PowerControl[0].PowerConsumedWatts from the node's Redfish Power resource.
+func (c *RedfishController) PowerDraw(ctx context.Context, nodeName string) (float64, error) {
+	target, err := c.target(ctx, nodeName)
+	if err != nil {
+		return 0, err
+	}
+	url := fmt.Sprintf("%s/redfish/v1/Chassis/%s/Power", target.endpoint, target.systemID)
+	body, err := c.do(ctx, target, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	var power struct {
+		PowerControl []struct {
+			PowerConsumedWatts *float64 `json:"PowerConsumedWatts"`
+		} `json:"PowerControl"`
+	}
+	if err := json.Unmarshal(body, &power); err != nil {
+		return 0, fmt.Errorf("decoding Redfish power of %s: %w", nodeName, err)
+	}
+	if len(power.PowerControl) == 0 || power.PowerControl[0].PowerConsumedWatts == nil {
+		return 0, fmt.Errorf("redfish power of %s: no PowerConsumedWatts reading", nodeName)
+	}
+	return *power.PowerControl[0].PowerConsumedWatts, nil
+}
+
+type redfishTarget struct {
+	endpoint string
+	systemID string
+	user     string
+}
+
+func (c *RedfishController) target(ctx context.Context, nodeName string) (redfishTarget, error) {
+	t := redfishTarget{endpoint: c.DefaultEndpoint, systemID: c.DefaultSystemID, user: c.DefaultUser}
+	n, err := c.Client.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		return t, fmt.Errorf("reading node %s: %w", nodeName, err)
+	}
+	t.endpoint = annotationOr(n, AnnotationBMCAddress, t.endpoint)
+	t.systemID = annotationOr(n, AnnotationRedfishSystemID, t.systemID)
+	t.user = annotationOr(n, AnnotationBMCUser, t.user)
+	if t.endpoint == "" {
+		return t, fmt.Errorf("node %s has no %s annotation and no default Redfish endpoint is set", nodeName, AnnotationBMCAddress)
+	}
+	if !strings.Contains(t.endpoint, "://") {
+		t.endpoint = "https://" + t.endpoint
+	}
+	t.endpoint = strings.TrimRight(t.endpoint, "/")
+	if t.systemID == "" {
+		t.systemID = "1"
+	}
+	return t, nil
+}
+
+func annotationOr(n *v1.Node, key, fallback string) string {
+	if v := n.Annotations[key]; v != "" {
+		return v
+	}
+	return fallback
+}
+
+// reset posts ComputerSystem.Reset with resetType, retrying with exponential backoff.
+func (c *RedfishController) reset(ctx context.Context, nodeName, resetType string) error {
+	target, err := c.target(ctx, nodeName)
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("%s/redfish/v1/Systems/%s/Actions/ComputerSystem.Reset", target.endpoint, target.systemID)
+	if c.DryRun {
+		slog.Info("Dry-run: would send Redfish reset", "node", nodeName, "resetType", resetType, "url", url)
+		return nil
+	}
+	payload, _ := json.Marshal(map[string]string{"ResetType": resetType})
+
+	attempts := max(c.MaxRetries, 1)
+	backoff := c.RetryBackoff
+	if backoff <= 0 {
+		backoff = defaultRedfishRetryBackoff
+	}
+	for attempt := 1; ; attempt++ {
+		slog.Info("Sending Redfish reset", "node", nodeName, "resetType", resetType, "bmc", target.endpoint, "attempt", attempt)
+		_, err = c.do(ctx, target, http.MethodPost, url, payload)
+		if err == nil {
+			return nil
+		}
+		if attempt >= attempts {
+			return fmt.Errorf("redfish %s for %s failed after %d attempts: %w", resetType, nodeName, attempts, err)
+		}
+		slog.Warn("Redfish reset failed; retrying", "node", nodeName, "resetType", resetType, "attempt", attempt, "err", err, "backoff", backoff.String())
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("redfish %s for %s: %w", resetType, nodeName, ctx.Err())
+		case <-c.clock().After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (c *RedfishController) do(ctx context.Context, target redfishTarget, method, url string, payload []byte) ([]byte, error) {
+	password, err := readSecretKey(ctx, c.Client, c.PasswordSecret)
+	if err != nil {
+		return nil, fmt.Errorf("reading BMC password: %w", err)
+	}
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, fmt.Errorf("creating Redfish request: %w", err)
+	}
+	req.SetBasicAuth(target.user, password)
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	httpClient := c.HTTP
+	if httpClient == nil {
+		httpClient = NewRedfishHTTPClient(false, 0)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("calling %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("%s %s: unexpected status %s: %s", method, url, resp.Status, strings.TrimSpace(string(respBody)))
+	}
+	return respBody, nil
+}
+
+func (c *RedfishController) clock() clock.Clock {
+	if c.Clock != nil {
+		return c.Clock
+	}
+	return clock.RealClock{}
+}
@@ -0,0 +1,149 @@
+package power_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	corefake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/power"
+)
+
+// fakeBMC serves the Redfish endpoints used by RedfishController, failing the first failResets resets.
+type fakeBMC struct {
+	mu         sync.Mutex
+	failResets int
+	resets     []string
+	paths      []string
+	users      []string
+}
+
+func (b *fakeBMC) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	user, _, _ := r.BasicAuth()
+	b.users = append(b.users, user)
+	b.paths = append(b.paths, r.URL.Path)
+	if r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/Power") {
+		_, _ = w.Write([]byte(`{"PowerControl":[{"PowerConsumedWatts":182.5}]}`))
+		return
+	}
+	var body struct{ ResetType string }
+	_ = json.NewDecoder(r.Body).Decode(&body)
+	if b.failResets > 0 {
+		b.failResets--
+		http.Error(w, "busy", http.StatusServiceUnavailable)
+		return
+	}
+	b.resets = append(b.resets, body.ResetType)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func redfishController(t *testing.T, bmc *fakeBMC, ready bool, annotations map[string]string) *power.RedfishController {
+	t.Helper()
+	srv := httptest.NewServer(bmc)
+	t.Cleanup(srv.Close)
+	if annotations == nil {
+		annotations = map[string]string{power.AnnotationBMCAddress: srv.URL}
+	}
+	node := bmcNode(annotations)
+	if !ready {
+		node.Status.Conditions[0].Status = v1.ConditionFalse
+	}
+	return &power.RedfishController{
+		Client:          corefake.NewSimpleClientset(node, bmcSecret()),
+		HTTP:            srv.Client(),
+		DefaultEndpoint: srv.URL,
+		DefaultUser:     "admin",
+		PasswordSecret:  config.SecretKeyRef{Namespace: "cba", Name: "bmc", Key: "password"},
+		MaxRetries:      3,
+		RetryBackoff:    time.Millisecond,
+		BootTimeout:     time.Second,
+		ShutdownTimeout: time.Second,
+		PollInterval:    time.Millisecond,
+	}
+}
+
+func TestRedfishController_Reset(t *testing.T) {
+	bmc := &fakeBMC{}
+	ctrl := redfishController(t, bmc, false, map[string]string{power.AnnotationRedfishSystemID: "System.Embedded.1", power.AnnotationBMCUser: "root"})
+	if err := ctrl.Shutdown(context.Background(), "node1"); err != nil {
+		t.Fatalf("Shutdown: unexpected error: %v", err)
+	}
+
+	ctrl = redfishController(t, bmc, true, nil)
+	if err := ctrl.PowerOn(context.Background(), "node1", ""); err != nil {
+		t.Fatalf("PowerOn: unexpected error: %v", err)
+	}
+
+	if want := []string{"GracefulShutdown", "On"}; strings.Join(bmc.resets, ",") != strings.Join(want, ",") {
+		t.Errorf("expected resets %v, got %v", want, bmc.resets)
+	}
+	if want := "/redfish/v1/Systems/System.Embedded.1/Actions/ComputerSystem.Reset"; bmc.paths[0] != want {
+		t.Errorf("expected path %q, got %q", want, bmc.paths[0])
+	}
+	if want := "/redfish/v1/Systems/1/Actions/ComputerSystem.Reset"; bmc.paths[1] != want {
+		t.Errorf("expected default system path %q, got %q", want, bmc.paths[1])
+	}
+	if bmc.users[0] != "root" || bmc.users[1] != "admin" {
+		t.Errorf("expected users [root admin], got %v", bmc.users)
+	}
+}
+
+func TestRedfishController_RetriesWithBackoff(t *testing.T) {
+	bmc := &fakeBMC{failResets: 2}
+	ctrl := redfishController(t, bmc, true, nil)
+	if err := ctrl.PowerOn(context.Background(), "node1", ""); err != nil {
+		t.Fatalf("expected success on third attempt, got: %v", err)
+	}
+	if len(bmc.paths) != 3 || len(bmc.resets) != 1 {
+		t.Errorf("expected 3 attempts and 1 accepted reset, got %d attempts, resets %v", len(bmc.paths), bmc.resets)
+	}
+
+	bmc = &fakeBMC{failResets: 5}
+	ctrl = redfishController(t, bmc, true, nil)
+	err := ctrl.PowerOn(context.Background(), "node1", "")
+	if err == nil || !strings.Contains(err.Error(), "after 3 attempts") {
+		t.Fatalf("expected failure after 3 attempts, got %v", err)
+	}
+}
+
+func TestRedfishController_WaitsForNotReady(t *testing.T) {
+	ctrl := redfishController(t, &fakeBMC{}, true, nil)
+	ctrl.ShutdownTimeout = 10 * time.Millisecond
+	err := ctrl.Shutdown(context.Background(), "node1")
+	if err == nil || !strings.Contains(err.Error(), "ready=false") {
+		t.Fatalf("expected timeout waiting for NotReady, got %v", err)
+	}
+}
+
+func TestRedfishController_DryRun(t *testing.T) {
+	bmc := &fakeBMC{}
+	ctrl := redfishController(t, bmc, true, nil)
+	ctrl.DryRun = true
+	if err := ctrl.Shutdown(context.Background(), "node1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(bmc.paths) != 0 {
+		t.Errorf("dry run must not call the BMC, got %v", bmc.paths)
+	}
+}
+
+func TestRedfishController_PowerDraw(t *testing.T) {
+	ctrl := redfishController(t, &fakeBMC{}, true, nil)
+	watts, err := ctrl.PowerDraw(context.Background(), "node1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if watts != 182.5 {
+		t.Errorf("expected 182.5W, got %v", watts)
+	}
+}
@@ -0,0 +1,55 @@
+package power
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"k8s.io/utils/clock"
+)
+
+const defaultShutdownRetryInterval = 2 * time.Second
+
+// RetryingShutdownController retries a failed Shutdown of Inner with exponential backoff, so a
+// transient network or agent error does not abort the power-off. Dry-run is left to Inner.
+type RetryingShutdownController struct {
+	Inner      ShutdownController
+	MaxRetries int           // retries after the first attempt
+	Interval   time.Duration // first wait, doubled after every failed retry; defaults to 2s
+	Clock      clock.Clock
+}
+
+func (c *RetryingShutdownController) Shutdown(ctx context.Context, nodeName string) error {
+	attempts := max(c.MaxRetries, 0) + 1
+	backoff := c.Interval
+	if backoff <= 0 {
+		backoff = defaultShutdownRetryInterval
+	}
+	for attempt := 1; ; attempt++ {
+		err := c.Inner.Shutdown(ctx, nodeName)
+		if err == nil {
+			if attempt > 1 {
+				slog.Info("Shutdown succeeded after retry", "node", nodeName, "attempt", attempt, "maxAttempts", attempts)
+			}
+			return nil
+		}
+		if attempt >= attempts {
+			return fmt.Errorf("shutdown of %s failed after %d attempts: %w", nodeName, attempts, err)
+		}
+		slog.Warn("Shutdown failed; retrying", "node", nodeName, "attempt", attempt, "maxAttempts", attempts, "err", err, "backoff", backoff.String())
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("shutdown of %s: %w", nodeName, ctx.Err())
+		case <-c.clock().After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (c *RetryingShutdownController) clock() clock.Clock {
+	if c.Clock != nil {
+		return c.Clock
+	}
+	return clock.RealClock{}
+}
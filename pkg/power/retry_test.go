@@ -0,0 +1,85 @@
+package power_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/power"
+	corefake "k8s.io/client-go/kubernetes/fake"
+)
+
+type flakyShutdown struct {
+	failures int
+	calls    int
+}
+
+func (f *flakyShutdown) Shutdown(_ context.Context, _ string) error {
+	f.calls++
+	if f.calls <= f.failures {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func TestRetryingShutdownController(t *testing.T) {
+	cases := []struct {
+		name      string
+		failures  int
+		retries   int
+		wantErr   bool
+		wantCalls int
+	}{
+		{"first attempt succeeds", 0, 2, false, 1},
+		{"succeeds on last retry", 2, 2, false, 3},
+		{"gives up after retries", 5, 2, true, 3},
+		{"no retries", 1, 0, true, 1},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inner := &flakyShutdown{failures: tc.failures}
+			c := &power.RetryingShutdownController{Inner: inner, MaxRetries: tc.retries, Interval: time.Millisecond}
+			err := c.Shutdown(context.Background(), "node1")
+			if (err != nil) != tc.wantErr {
+				t.Errorf("err = %v, wantErr %v", err, tc.wantErr)
+			}
+			if inner.calls != tc.wantCalls {
+				t.Errorf("calls = %d, want %d", inner.calls, tc.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRetryingShutdownController_StopsOnContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	inner := &flakyShutdown{failures: 5}
+	c := &power.RetryingShutdownController{Inner: inner, MaxRetries: 3, Interval: time.Hour}
+	if err := c.Shutdown(ctx, "node1"); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if inner.calls != 1 {
+		t.Errorf("calls = %d, want 1", inner.calls)
+	}
+}
+
+func TestNewControllersFromConfig_WrapsShutdownWithRetry(t *testing.T) {
+	cfg := &config.Config{
+		ShutdownMode:       power.ShutdownModeExec,
+		Exec:               config.ExecPowerConfig{Shutdown: []string{"true"}},
+		ShutdownMaxRetries: 2,
+		DryRun:             true,
+	}
+	shutdowner, _, err := power.NewControllersFromConfig(cfg, corefake.NewSimpleClientset(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := shutdowner.(*power.RetryingShutdownController); !ok {
+		t.Fatalf("expected a retrying shutdown controller, got %T", shutdowner)
+	}
+	if err := shutdowner.Shutdown(context.Background(), "node1"); err != nil {
+		t.Errorf("dry-run shutdown should succeed, got %v", err)
+	}
+}
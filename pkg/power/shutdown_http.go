@@ -7,6 +7,7 @@ import (
 	"log/slog"
 	"net/http"
 
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/agenthttp"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes"
@@ -18,6 +19,7 @@ type ShutdownHTTPController struct {
 	Namespace string
 	PodLabel  string
 	Client    kubernetes.Interface
+	HTTP      *agenthttp.Client // nil: plain http without auth
 }
 
 func (s *ShutdownHTTPController) Shutdown(ctx context.Context, node string) error {
@@ -57,13 +59,13 @@ func (s *ShutdownHTTPController) FindShutdownPodIP(ctx context.Context, node str
 }
 
 func (s *ShutdownHTTPController) SendShutdownRequest(ctx context.Context, podIP, node string) error {
-	url := fmt.Sprintf("http://%s:%d/shutdown", podIP, s.Port)
+	url := s.HTTP.URL(podIP, s.Port, "/shutdown")
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
 	if err != nil {
 		return fmt.Errorf("creating shutdown request: %w", err)
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := s.HTTP.Do(req)
 	if err != nil {
 		return fmt.Errorf("calling shutdown endpoint: %w", err)
 	}
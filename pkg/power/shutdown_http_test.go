@@ -14,6 +14,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	corefake "k8s.io/client-go/kubernetes/fake"
 
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/agenthttp"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
 	"github.com/docent-net/cluster-bare-autoscaler/pkg/power"
 )
 
@@ -94,6 +96,34 @@ func TestSendShutdownRequest_Success(t *testing.T) {
 	}
 }
 
+func TestSendShutdownRequest_SendsAgentAuthHeaders(t *testing.T) {
+	var gotToken string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotToken = r.Header.Get("X-Agent-Token")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	u, _ := url.Parse(server.URL)
+	host, portStr, _ := net.SplitHostPort(u.Host)
+	port, _ := strconv.Atoi(portStr)
+
+	agent, err := agenthttp.NewClient(config.AgentHTTPConfig{
+		Headers: map[string]string{"X-Agent-Token": "abc"},
+	}, corefake.NewSimpleClientset())
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	ctrl := &power.ShutdownHTTPController{Port: port, HTTP: agent}
+
+	if err := ctrl.SendShutdownRequest(context.Background(), host, "node2"); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if gotToken != "abc" {
+		t.Errorf("X-Agent-Token = %q, want abc", gotToken)
+	}
+}
+
 func TestSendShutdownRequest_Failure(t *testing.T) {
 	ctrl := &power.ShutdownHTTPController{
 		Port: 65534, // very unlikely to be open
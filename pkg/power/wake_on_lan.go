@@ -4,15 +4,24 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net/url"
+	"strings"
+
+	"github.com/docent-net/cluster-bare-autoscaler/internal/bootstrap/metrics"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/agenthttp"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/clock"
 	"log/slog"
 	"net/http"
 	"time"
 )
 
+// defaultBootPollInterval is used between readiness checks when PollInterval is unset.
+const defaultBootPollInterval = 5 * time.Second
+
 type WakeOnLanController struct {
 	DryRun         bool
 	Client         kubernetes.Interface
@@ -22,6 +31,19 @@ type WakeOnLanController struct {
 	BootTimeoutSec time.Duration
 	BroadcastAddr  string
 	MaxRetries     int
+	PollInterval   time.Duration     // between readiness checks; defaults to 5s
+	HTTP           *agenthttp.Client // nil: plain http without auth
+	Clock          clock.Clock       // nil: real clock; tests inject a fake
+	Reachability   ReachabilityProbe // optional early "host up" signal, logged separately from Ready
+
+	// MatchRenamedNodes also accepts a different Node with the woken MAC (under MACAnnotationKeys)
+	// or the original provider ID, for hosts that were re-provisioned and rejoined under a new name.
+	// Without a provider ID the match relies on the MAC annotation, which a freshly re-provisioned
+	// node only gets once MAC discovery runs; until then the wait times out as before.
+	MatchRenamedNodes bool
+	MACAnnotationKeys []string
+	// ManagedSelector restricts the renamed-node lookup to managed nodes; nil lists every Node.
+	ManagedSelector labels.Selector
 }
 
 func (w *WakeOnLanController) PowerOn(ctx context.Context, node string, mac string) error {
@@ -35,6 +57,13 @@ func (w *WakeOnLanController) PowerOn(ctx context.Context, node string, mac stri
 		return fmt.Errorf("finding WOL agent pod IP: %w", err)
 	}
 
+	var providerID string
+	if w.MatchRenamedNodes {
+		if orig, err := w.Client.CoreV1().Nodes().Get(ctx, node, metav1.GetOptions{}); err == nil {
+			providerID = orig.Spec.ProviderID
+		}
+	}
+
 	for attempt := 1; attempt <= w.MaxRetries; attempt++ {
 		slog.Info("Sending WOL magic packet via remote agent", "node", node, "mac", mac, "bcast", w.BroadcastAddr, "attempt", attempt)
 
@@ -42,16 +71,37 @@ func (w *WakeOnLanController) PowerOn(ctx context.Context, node string, mac stri
 			slog.Warn("WOL agent call failed", "node", node, "err", err, "attempt", attempt)
 		}
 
-		start := time.Now()
-		for time.Since(start) < w.BootTimeoutSec {
-			isReady, err := w.checkNodeReady(ctx, node)
+		start := w.clock().Now()
+		hostUp := false
+		for w.clock().Since(start) < w.BootTimeoutSec {
+			n, isReady, err := w.checkNodeReady(ctx, node)
+			if w.MatchRenamedNodes && !isReady {
+				if alt, altReady := w.findRenamedNode(ctx, node, mac, providerID); alt != nil {
+					n, isReady, err = alt, altReady, nil
+				}
+			}
+			if err == nil && !hostUp && w.Reachability != nil && w.Reachability.Reachable(ctx, n) {
+				hostUp = true
+				elapsed := w.clock().Since(start)
+				slog.Info("Node host is up (network reachable), waiting for Ready", "node", node, "after", elapsed.String())
+				metrics.PowerOnHostUpSeconds.Observe(elapsed.Seconds())
+			}
 			if err != nil {
 				slog.Debug("Waiting for node readiness", "node", node, "err", err)
 			} else if isReady {
-				slog.Info("Node became ready", "node", node)
+				elapsed := w.clock().Since(start)
+				if n.Name != node {
+					slog.Warn("Node rejoined under a different name", "node", node, "newName", n.Name, "mac", mac, "providerID", providerID)
+				}
+				slog.Info("Node became ready", "node", node, "as", n.Name, "after", elapsed.String())
+				metrics.PowerOnReadySeconds.Observe(elapsed.Seconds())
 				return nil
 			}
-			time.Sleep(5 * time.Second)
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("waiting for node %s readiness: %w", node, ctx.Err())
+			case <-w.clock().After(w.pollInterval()):
+			}
 		}
 
 		slog.Warn("Node did not become ready after WOL attempt", "node", node, "attempt", attempt, "maxRetries", w.MaxRetries)
@@ -60,15 +110,30 @@ func (w *WakeOnLanController) PowerOn(ctx context.Context, node string, mac stri
 	return fmt.Errorf("WOL failed: node %s did not become ready after %d attempts", node, w.MaxRetries)
 }
 
+func (w *WakeOnLanController) clock() clock.Clock {
+	if w.Clock != nil {
+		return w.Clock
+	}
+	return clock.RealClock{}
+}
+
+func (w *WakeOnLanController) pollInterval() time.Duration {
+	if w.PollInterval > 0 {
+		return w.PollInterval
+	}
+	return defaultBootPollInterval
+}
+
 func (w *WakeOnLanController) sendWOLRequest(ctx context.Context, ip string, mac string) error {
-	url := fmt.Sprintf("http://%s:%d/wake?mac=%s&broadcast=%s", ip, w.Port, mac, w.BroadcastAddr)
+	query := url.Values{"mac": {mac}, "broadcast": {w.BroadcastAddr}}
+	target := w.HTTP.URL(ip, w.Port, "/wake?"+query.Encode())
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, nil)
 	if err != nil {
 		return fmt.Errorf("creating WOL request: %w", err)
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := w.HTTP.Do(req)
 	if err != nil {
 		return fmt.Errorf("sending WOL request: %w", err)
 	}
@@ -79,21 +144,66 @@ func (w *WakeOnLanController) sendWOLRequest(ctx context.Context, ip string, mac
 		return fmt.Errorf("WOL request failed: %s", string(body))
 	}
 
-	slog.Debug("WOL request sent successfully", "mac", mac, "url", url)
+	slog.Debug("WOL request sent successfully", "mac", mac, "url", target)
 	return nil
 }
 
-func (w *WakeOnLanController) checkNodeReady(ctx context.Context, node string) (bool, error) {
+func (w *WakeOnLanController) checkNodeReady(ctx context.Context, node string) (*v1.Node, bool, error) {
 	n, err := w.Client.CoreV1().Nodes().Get(ctx, node, metav1.GetOptions{})
 	if err != nil {
-		return false, err
+		return nil, false, err
+	}
+	return n, nodeIsReady(n), nil
+}
+
+// findRenamedNode looks for a managed Node other than node that carries the same MAC or provider ID.
+// A node without a provider ID is only found once its MAC annotation is set.
+// Lookup errors are treated as "not found"; the by-name check keeps polling either way.
+func (w *WakeOnLanController) findRenamedNode(ctx context.Context, node, mac, providerID string) (*v1.Node, bool) {
+	opts := metav1.ListOptions{}
+	if w.ManagedSelector != nil {
+		opts.LabelSelector = w.ManagedSelector.String()
+	}
+	list, err := w.Client.CoreV1().Nodes().List(ctx, opts)
+	if err != nil {
+		slog.Debug("Listing nodes for renamed-node match failed", "node", node, "err", err)
+		return nil, false
 	}
+	for i := range list.Items {
+		n := &list.Items[i]
+		if n.Name == node || !w.sameHost(n, mac, providerID) {
+			continue
+		}
+		if w.ManagedSelector != nil && !w.ManagedSelector.Matches(labels.Set(n.Labels)) {
+			continue
+		}
+		return n, nodeIsReady(n)
+	}
+	return nil, false
+}
+
+func (w *WakeOnLanController) sameHost(n *v1.Node, mac, providerID string) bool {
+	if providerID != "" && n.Spec.ProviderID == providerID {
+		return true
+	}
+	if mac == "" {
+		return false
+	}
+	for _, key := range w.MACAnnotationKeys {
+		if v := n.Annotations[key]; v != "" && strings.EqualFold(v, mac) {
+			return true
+		}
+	}
+	return false
+}
+
+func nodeIsReady(n *v1.Node) bool {
 	for _, cond := range n.Status.Conditions {
 		if cond.Type == v1.NodeReady && cond.Status == v1.ConditionTrue {
-			return true, nil
+			return true
 		}
 	}
-	return false, nil
+	return false
 }
 
 func (w *WakeOnLanController) findWOLAgentPodIP(ctx context.Context) (string, error) {
@@ -2,6 +2,7 @@ package power_test
 
 import (
 	"context"
+	"errors"
 	"net"
 	"net/http"
 	"net/http/httptest"
@@ -13,9 +14,16 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	corefake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	clocktesting "k8s.io/utils/clock/testing"
 
+	"github.com/docent-net/cluster-bare-autoscaler/internal/bootstrap/metrics"
 	"github.com/docent-net/cluster-bare-autoscaler/pkg/power"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 )
 
 func TestWakeOnLanController_PowerOn_DryRun(t *testing.T) {
@@ -164,3 +172,330 @@ func parseHostPort(t *testing.T, rawURL string) (string, int) {
 	}
 	return host, port
 }
+
+func TestWakeOnLanController_PowerOn_ContextCancelledMidPoll(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ip, port := parseHostPort(t, server.URL)
+
+	client := corefake.NewSimpleClientset(&v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "wol-agent",
+			Namespace: "default",
+			Labels:    map[string]string{"app": "wol-agent"},
+		},
+		Status: v1.PodStatus{PodIP: ip},
+	}, &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+	})
+
+	ctrl := &power.WakeOnLanController{
+		Client:         client,
+		Namespace:      "default",
+		PodLabel:       "wol-agent",
+		Port:           port,
+		BootTimeoutSec: time.Minute,
+		MaxRetries:     3,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := ctrl.PowerOn(ctx, "node1", "00:11:22:33:44:55")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context deadline error, got: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("PowerOn did not return promptly after cancellation: %s", elapsed)
+	}
+}
+
+func TestWakeOnLanController_PowerOn_PollIntervalGovernsReadinessChecks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ip, port := parseHostPort(t, server.URL)
+
+	client := corefake.NewSimpleClientset(&v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "wol-agent",
+			Namespace: "default",
+			Labels:    map[string]string{"app": "wol-agent"},
+		},
+		Status: v1.PodStatus{PodIP: ip},
+	}, &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+	})
+	var checks int
+	client.PrependReactor("get", "nodes", func(k8stesting.Action) (bool, runtime.Object, error) {
+		checks++
+		return false, nil, nil
+	})
+
+	fakeClock := clocktesting.NewFakeClock(time.Now())
+	ctrl := &power.WakeOnLanController{
+		Client:         client,
+		Namespace:      "default",
+		PodLabel:       "wol-agent",
+		Port:           port,
+		BootTimeoutSec: time.Minute,
+		PollInterval:   20 * time.Second,
+		MaxRetries:     1,
+		Clock:          fakeClock,
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- ctrl.PowerOn(context.Background(), "node1", "00:11:22:33:44:55") }()
+
+	for {
+		select {
+		case err := <-done:
+			if err == nil || !strings.Contains(err.Error(), "did not become ready") {
+				t.Fatalf("expected readiness timeout, got: %v", err)
+			}
+			// Checks at t=0s, 20s and 40s; at 60s the boot timeout has elapsed.
+			if checks != 3 {
+				t.Errorf("readiness checks = %d, want 3 for a 1m timeout polled every 20s", checks)
+			}
+			return
+		case <-time.After(time.Millisecond):
+			if fakeClock.HasWaiters() {
+				fakeClock.Step(20 * time.Second)
+			}
+		}
+	}
+}
+
+func TestWakeOnLanController_PowerOn_HostUpBeforeReady(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	ip, port := parseHostPort(t, server.URL)
+
+	// Stub host: reachable over TCP from the start, but only Ready on the third check.
+	host, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer host.Close()
+	_, hostPort := parseHostPort(t, "tcp://"+host.Addr().String())
+
+	client := corefake.NewSimpleClientset(&v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "wol-agent",
+			Namespace: "default",
+			Labels:    map[string]string{"app": "wol-agent"},
+		},
+		Status: v1.PodStatus{PodIP: ip},
+	})
+	checks := 0
+	client.PrependReactor("get", "nodes", func(k8stesting.Action) (bool, runtime.Object, error) {
+		checks++
+		n := &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+			Status:     v1.NodeStatus{Addresses: []v1.NodeAddress{{Type: v1.NodeInternalIP, Address: "127.0.0.1"}}},
+		}
+		if checks >= 3 {
+			n.Status.Conditions = []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionTrue}}
+		}
+		return true, n, nil
+	})
+
+	fakeClock := clocktesting.NewFakeClock(time.Now())
+	ctrl := &power.WakeOnLanController{
+		Client:         client,
+		Namespace:      "default",
+		PodLabel:       "wol-agent",
+		Port:           port,
+		BootTimeoutSec: time.Minute,
+		PollInterval:   10 * time.Second,
+		MaxRetries:     1,
+		Clock:          fakeClock,
+		Reachability:   &power.TCPReachabilityProbe{Port: hostPort, Timeout: time.Second},
+	}
+
+	hostUpBefore, readyBefore := histogramSum(t, metrics.PowerOnHostUpSeconds), histogramSum(t, metrics.PowerOnReadySeconds)
+
+	done := make(chan error, 1)
+	go func() { done <- ctrl.PowerOn(context.Background(), "node1", "00:11:22:33:44:55") }()
+	for waiting := true; waiting; {
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatalf("expected node to become ready, got: %v", err)
+			}
+			waiting = false
+		case <-time.After(time.Millisecond):
+			if fakeClock.HasWaiters() {
+				fakeClock.Step(10 * time.Second)
+			}
+		}
+	}
+
+	if got := histogramSum(t, metrics.PowerOnHostUpSeconds) - hostUpBefore; got != 0 {
+		t.Errorf("host up recorded after %vs, want 0s (reachable on first check)", got)
+	}
+	if got := histogramSum(t, metrics.PowerOnReadySeconds) - readyBefore; got != 20 {
+		t.Errorf("ready recorded after %vs, want 20s (third check)", got)
+	}
+}
+
+func TestTCPReachabilityProbe_ClosedPort(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	_, port := parseHostPort(t, "tcp://"+l.Addr().String())
+	l.Close()
+
+	probe := &power.TCPReachabilityProbe{Port: port, Timeout: time.Second}
+	node := &v1.Node{Status: v1.NodeStatus{Addresses: []v1.NodeAddress{{Type: v1.NodeInternalIP, Address: "127.0.0.1"}}}}
+	if probe.Reachable(context.Background(), node) {
+		t.Errorf("expected closed port to be unreachable")
+	}
+	if probe.Reachable(context.Background(), &v1.Node{}) {
+		t.Errorf("expected node without InternalIP to be unreachable")
+	}
+}
+
+func histogramSum(t *testing.T, h prometheus.Histogram) float64 {
+	t.Helper()
+	var m dto.Metric
+	if err := h.Write(&m); err != nil {
+		t.Fatalf("reading histogram: %v", err)
+	}
+	return m.GetHistogram().GetSampleSum()
+}
+
+func TestWakeOnLanController_PowerOn_MatchesRenamedNodeByMAC(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ip, port := parseHostPort(t, server.URL)
+
+	newObjects := func() []runtime.Object {
+		return []runtime.Object{
+			&v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "wol-agent", Namespace: "default", Labels: map[string]string{"app": "wol-agent"}},
+				Status:     v1.PodStatus{PodIP: ip},
+			},
+			// Stale object under the old name never becomes Ready.
+			&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}},
+			&v1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "node1-reprovisioned",
+					Annotations: map[string]string{"cba.dev/mac-address": "00:11:22:33:44:55"},
+				},
+				Status: v1.NodeStatus{Conditions: []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionTrue}}},
+			},
+		}
+	}
+
+	for _, match := range []bool{true, false} {
+		ctrl := &power.WakeOnLanController{
+			Client:            corefake.NewSimpleClientset(newObjects()...),
+			Namespace:         "default",
+			PodLabel:          "wol-agent",
+			Port:              port,
+			BootTimeoutSec:    time.Second,
+			PollInterval:      100 * time.Millisecond,
+			MaxRetries:        1,
+			MatchRenamedNodes: match,
+			MACAnnotationKeys: []string{"cba.dev/mac-address"},
+		}
+
+		err := ctrl.PowerOn(context.Background(), "node1", "00:11:22:33:44:55")
+		if match && err != nil {
+			t.Errorf("expected renamed node with the same MAC to count as booted, got: %v", err)
+		}
+		if !match && err == nil {
+			t.Errorf("expected timeout when renamed-node matching is disabled")
+		}
+	}
+}
+
+func TestWakeOnLanController_PowerOn_RenamedNodeNeedsManagedLabelAndMAC(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ip, port := parseHostPort(t, server.URL)
+	managed := map[string]string{"cba.dev/is-managed": "true"}
+	ready := v1.NodeStatus{Conditions: []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionTrue}}}
+
+	tests := []struct {
+		name    string
+		renamed *v1.Node
+		wantErr bool
+	}{
+		{
+			name: "managed node with the MAC annotation",
+			renamed: &v1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "node1-reprovisioned", Labels: managed,
+					Annotations: map[string]string{"cba.dev/mac-address": "00:11:22:33:44:55"}},
+				Status: ready,
+			},
+		},
+		{
+			// Freshly re-provisioned: no provider ID and MAC discovery has not annotated it yet.
+			name: "managed node without the MAC annotation",
+			renamed: &v1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "node1-reprovisioned", Labels: managed},
+				Status:     ready,
+			},
+			wantErr: true,
+		},
+		{
+			name: "unmanaged node with the MAC annotation",
+			renamed: &v1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "other-node",
+					Annotations: map[string]string{"cba.dev/mac-address": "00:11:22:33:44:55"}},
+				Status: ready,
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := corefake.NewSimpleClientset(
+				&v1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: "wol-agent", Namespace: "default", Labels: map[string]string{"app": "wol-agent"}},
+					Status:     v1.PodStatus{PodIP: ip},
+				},
+				&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1", Labels: managed}},
+				tt.renamed,
+			)
+			ctrl := &power.WakeOnLanController{
+				Client:            client,
+				Namespace:         "default",
+				PodLabel:          "wol-agent",
+				Port:              port,
+				BootTimeoutSec:    time.Second,
+				PollInterval:      100 * time.Millisecond,
+				MaxRetries:        1,
+				MatchRenamedNodes: true,
+				MACAnnotationKeys: []string{"cba.dev/mac-address"},
+				ManagedSelector:   labels.SelectorFromSet(managed),
+			}
+
+			err := ctrl.PowerOn(context.Background(), "node1", "00:11:22:33:44:55")
+			if tt.wantErr && err == nil {
+				t.Errorf("expected timeout, renamed node should not have matched")
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("expected renamed node to count as booted, got: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package power
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"strconv"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/clock"
+)
+
+// DefaultWOLPort is the UDP port magic packets are sent to (discard service), as in wol-agent.
+const DefaultWOLPort = 9
+
+// DirectWOLController sends Wake-on-LAN magic packets from the controller pod itself, for setups
+// where CBA already sits on the node network and the wol-agent DaemonSet is unnecessary. Each
+// attempt broadcasts to every address in BroadcastAddrs, so nodes spanning VLANs are reached.
+type DirectWOLController struct {
+	DryRun         bool
+	Client         kubernetes.Interface
+	BroadcastAddrs []string
+	Port           int // defaults to DefaultWOLPort
+	MaxRetries     int // packet rounds before giving up; at least 1
+	BootTimeout    time.Duration
+	PollInterval   time.Duration
+	Clock          clock.Clock
+
+	// Send transmits one magic packet; nil uses SendMagicPacket. Tests stub it.
+	Send func(mac, broadcastAddr string, port int) error
+}
+
+func (d *DirectWOLController) PowerOn(ctx context.Context, node string, mac string) error {
+	if mac == "" {
+		return fmt.Errorf("node %s has no MAC address for WOL", node)
+	}
+	if len(d.BroadcastAddrs) == 0 {
+		return errors.New("direct WOL: no broadcast address configured")
+	}
+	if d.DryRun {
+		slog.Info("Dry-run: would send WOL magic packet", "node", node, "mac", mac, "bcast", d.BroadcastAddrs)
+		return nil
+	}
+
+	attempts := max(d.MaxRetries, 1)
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		sent := 0
+		for _, bcast := range d.BroadcastAddrs {
+			slog.Info("Sending WOL magic packet", "node", node, "mac", mac, "bcast", bcast, "attempt", attempt)
+			if sendErr := d.send(mac, bcast); sendErr != nil {
+				slog.Warn("Sending WOL magic packet failed", "node", node, "bcast", bcast, "err", sendErr)
+				continue
+			}
+			sent++
+		}
+		if sent == 0 {
+			err = fmt.Errorf("no magic packet could be sent for node %s", node)
+			continue
+		}
+
+		if err = waitForReadiness(ctx, d.Client, d.Clock, node, true, d.BootTimeout, d.PollInterval); err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return err
+		}
+		slog.Warn("Node did not become ready after WOL attempt", "node", node, "attempt", attempt, "maxRetries", attempts)
+	}
+	return fmt.Errorf("direct WOL failed for node %s after %d attempts: %w", node, attempts, err)
+}
+
+func (d *DirectWOLController) send(mac, bcast string) error {
+	port := d.Port
+	if port == 0 {
+		port = DefaultWOLPort
+	}
+	if d.Send != nil {
+		return d.Send(mac, bcast, port)
+	}
+	return SendMagicPacket(mac, bcast, port)
+}
+
+// SendMagicPacket broadcasts a WOL magic packet (6×0xFF followed by the MAC 16 times) for macAddr.
+// It mirrors sendMagicPacket in wol-agent, which is built standalone.
+func SendMagicPacket(macAddr, broadcastAddr string, port int) error {
+	mac, err := net.ParseMAC(macAddr)
+	if err != nil {
+		return fmt.Errorf("invalid MAC address: %w", err)
+	}
+	packet := append(bytes.Repeat([]byte{0xFF}, 6), bytes.Repeat(mac, 16)...)
+
+	addr, err := net.ResolveUDPAddr("udp", net.JoinHostPort(broadcastAddr, strconv.Itoa(port)))
+	if err != nil {
+		return fmt.Errorf("invalid broadcast address %q: %w", broadcastAddr, err)
+	}
+	conn, err := net.DialUDP("udp", nil, addr)
+	if err != nil {
+		return fmt.Errorf("UDP dial error: %w", err)
+	}
+	defer conn.Close()
+
+	_, err = conn.Write(packet)
+	return err
+}
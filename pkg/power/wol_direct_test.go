@@ -0,0 +1,103 @@
+package power_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	corefake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/power"
+)
+
+type sentPacket struct {
+	mac, bcast string
+	port       int
+}
+
+func TestDirectWOLController_PowerOn(t *testing.T) {
+	tests := []struct {
+		name     string
+		ready    bool
+		dryRun   bool
+		sendErr  error
+		wantErr  string
+		wantSent int
+	}{
+		{name: "sends to every broadcast address", ready: true, wantSent: 2},
+		{name: "dry run sends nothing", ready: true, dryRun: true, wantSent: 0},
+		{name: "retries until attempts run out", ready: false, wantErr: "after 2 attempts", wantSent: 4},
+		{name: "send failure", ready: true, sendErr: errors.New("network unreachable"), wantErr: "no magic packet", wantSent: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := bmcNode(nil)
+			if !tt.ready {
+				node.Status.Conditions[0].Status = v1.ConditionFalse
+			}
+			var sent []sentPacket
+			ctrl := &power.DirectWOLController{
+				DryRun:         tt.dryRun,
+				Client:         corefake.NewSimpleClientset(node),
+				BroadcastAddrs: []string{"192.168.1.255", "192.168.2.255"},
+				MaxRetries:     2,
+				BootTimeout:    5 * time.Millisecond,
+				PollInterval:   time.Millisecond,
+				Send: func(mac, bcast string, port int) error {
+					sent = append(sent, sentPacket{mac, bcast, port})
+					return tt.sendErr
+				},
+			}
+
+			err := ctrl.PowerOn(context.Background(), "node1", "aa:bb:cc:dd:ee:ff")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(sent) != tt.wantSent {
+				t.Fatalf("expected %d packets, got %d: %v", tt.wantSent, len(sent), sent)
+			}
+			if len(sent) > 0 && (sent[0] != sentPacket{"aa:bb:cc:dd:ee:ff", "192.168.1.255", power.DefaultWOLPort} || sent[1].bcast != "192.168.2.255") {
+				t.Errorf("unexpected packets: %v", sent)
+			}
+		})
+	}
+}
+
+func TestSendMagicPacket(t *testing.T) {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer conn.Close()
+
+	port := conn.LocalAddr().(*net.UDPAddr).Port
+	if err := power.SendMagicPacket("aa:bb:cc:dd:ee:ff", "127.0.0.1", port); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	buf := make([]byte, 256)
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := conn.ReadFromUDP(buf)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if n != 102 {
+		t.Fatalf("expected 102-byte magic packet, got %d bytes", n)
+	}
+	if buf[5] != 0xFF || buf[6] != 0xAA || buf[101] != 0xFF {
+		t.Errorf("unexpected packet layout: % x", buf[:n])
+	}
+
+	if err := power.SendMagicPacket("not-a-mac", "127.0.0.1", port); err == nil {
+		t.Error("expected error for invalid MAC")
+	}
+}
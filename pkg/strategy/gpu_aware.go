@@ -0,0 +1,64 @@
+package strategy
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// DefaultGPUResourceName is the extended resource GPUAwareScaleDown looks for when none is configured.
+const DefaultGPUResourceName = "nvidia.com/gpu"
+
+// GPUAwareScaleDown denies scale-down while any running or pending pod on the candidate requests
+// one of ResourceNames, regardless of what the load strategies see.
+type GPUAwareScaleDown struct {
+	Client        kubernetes.Interface
+	ResourceNames []string // empty: DefaultGPUResourceName
+}
+
+func (g *GPUAwareScaleDown) Name() string {
+	return "GPUAware"
+}
+
+func (g *GPUAwareScaleDown) ShouldScaleDown(ctx context.Context, nodeName string) (bool, error) {
+	pods, err := g.Client.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+		FieldSelector: "spec.nodeName=" + nodeName,
+	})
+	if err != nil {
+		return false, fmt.Errorf("listing pods on %s: %w", nodeName, err)
+	}
+
+	names := g.ResourceNames
+	if len(names) == 0 {
+		names = []string{DefaultGPUResourceName}
+	}
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName != nodeName || pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+		if res, ok := requestedGPUResource(&pod, names); ok {
+			slog.Info("GPU workload on node blocks scale-down", "node", nodeName, "pod", pod.Namespace+"/"+pod.Name, "resource", res)
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// requestedGPUResource returns the first of names that any container of pod requests or limits.
+func requestedGPUResource(pod *v1.Pod, names []string) (string, bool) {
+	containers := append(append([]v1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...)
+	for _, c := range containers {
+		for _, name := range names {
+			for _, list := range []v1.ResourceList{c.Resources.Requests, c.Resources.Limits} {
+				if q, ok := list[v1.ResourceName(name)]; ok && !q.IsZero() {
+					return name, true
+				}
+			}
+		}
+	}
+	return "", false
+}
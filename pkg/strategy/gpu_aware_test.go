@@ -0,0 +1,57 @@
+package strategy
+
+import (
+	"context"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func gpuPod(name, node, resourceName string, phase v1.PodPhase) *v1.Pod {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       v1.PodSpec{NodeName: node, Containers: []v1.Container{{Name: "main"}}},
+		Status:     v1.PodStatus{Phase: phase},
+	}
+	if resourceName != "" {
+		pod.Spec.Containers[0].Resources.Limits = v1.ResourceList{v1.ResourceName(resourceName): resource.MustParse("1")}
+	}
+	return pod
+}
+
+func TestGPUAwareScaleDown(t *testing.T) {
+	client := corefake.NewSimpleClientset(
+		gpuPod("inference", "gpu-node", DefaultGPUResourceName, v1.PodRunning),
+		gpuPod("web", "cpu-node", "", v1.PodRunning),
+		gpuPod("finished-training", "idle-gpu-node", DefaultGPUResourceName, v1.PodSucceeded),
+		gpuPod("amd-job", "amd-node", "amd.com/gpu", v1.PodRunning),
+	)
+
+	tests := []struct {
+		name      string
+		node      string
+		resources []string
+		want      bool
+	}{
+		{name: "GPU pod blocks", node: "gpu-node", want: false},
+		{name: "non-GPU node allowed", node: "cpu-node", want: true},
+		{name: "completed GPU pod ignored", node: "idle-gpu-node", want: true},
+		{name: "other resource ignored by default", node: "amd-node", want: true},
+		{name: "configured resource blocks", node: "amd-node", resources: []string{"amd.com/gpu"}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &GPUAwareScaleDown{Client: client, ResourceNames: tt.resources}
+			got, err := g.ShouldScaleDown(context.Background(), tt.node)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ShouldScaleDown(%s) = %v, want %v", tt.node, got, tt.want)
+			}
+		})
+	}
+}
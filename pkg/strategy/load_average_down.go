@@ -2,11 +2,14 @@ package strategy
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"maps"
 	"time"
 
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/agenthttp"
 	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	"go.opentelemetry.io/otel/trace"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -23,6 +26,16 @@ type LoadAverageScaleDown struct {
 	DryRunClusterLoadOverride *float64
 	ClusterEvalMode           ClusterLoadEvalMode
 	IgnoreLabels              map[string]string
+	UnavailablePolicy         LoadUnavailablePolicy
+	AllowLoadOverrides        bool
+	MinLoadSamples            int
+	LoadNormalization         LoadNormalization
+	AggregateDenominator      AggregateDenominator
+	MissingMetricsBehavior    MissingMetricsBehavior
+	AgentHTTP                 *agenthttp.Client
+	LoadSource                NodeLoadSource // nil: metrics DaemonSet
+	LoadCache                 *LoadCache     // nil: no per-loop caching
+	LoadFetchConcurrency      int
 }
 
 func (l *LoadAverageScaleDown) Name() string {
@@ -32,8 +45,14 @@ func (l *LoadAverageScaleDown) Name() string {
 func (l *LoadAverageScaleDown) ShouldScaleDown(ctx context.Context, nodeName string) (bool, error) {
 	normalized, err := l.getNormalizedLoadForNode(ctx, nodeName)
 	if err != nil {
+		if l.UnavailablePolicy == LoadUnavailableFailOpen && l.loadEntirelyUnavailable(ctx) {
+			slog.Warn("Load metrics entirely unavailable — failing open for scale-down",
+				"node", nodeName, "err", err, "policy", l.UnavailablePolicy)
+			return true, nil
+		}
 		return false, err
 	}
+	trace.SpanFromContext(ctx).SetAttributes(AttrNodeLoad.Float64(normalized))
 
 	if normalized >= l.NodeThreshold {
 		slog.Info("Node load too high for scale-down", "node", nodeName, "load", normalized, "threshold", l.NodeThreshold)
@@ -44,6 +63,7 @@ func (l *LoadAverageScaleDown) ShouldScaleDown(ctx context.Context, nodeName str
 	if err != nil {
 		return false, nil
 	}
+	trace.SpanFromContext(ctx).SetAttributes(AttrClusterLoad.Float64(aggregate))
 
 	slog.Info("Cluster-wide load evaluation",
 		"aggregateLoad", aggregate,
@@ -64,11 +84,39 @@ func (l *LoadAverageScaleDown) getNormalizedLoadForNode(ctx context.Context, nod
 		slog.Info("Dry-run override: using normalized load value", "node", nodeName, "value", *l.DryRunNodeLoadOverride)
 		return *l.DryRunNodeLoadOverride, nil
 	}
-	return NewClusterLoadUtils(l.Client, l.Namespace, l.PodLabel, l.HTTPPort, l.HTTPTimeout).FetchNormalizedLoad(ctx, nodeName)
+	utils := l.loadUtils()
+	load, err := utils.FetchNormalizedLoad(ctx, nodeName)
+	if errors.Is(err, ErrNoMetricsPod) {
+		if substitute, ok := utils.MissingMetricsLoad(); ok {
+			slog.Info("No metrics pod on node; using substitute load", "node", nodeName, "behavior", l.MissingMetricsBehavior)
+			return substitute, nil
+		}
+	}
+	return load, err
 }
 
-func (l *LoadAverageScaleDown) getClusterAggregateLoad(ctx context.Context, excludeNode string) (float64, error) {
+func (l *LoadAverageScaleDown) loadUtils() *ClusterLoadUtils {
 	utils := NewClusterLoadUtils(l.Client, l.Namespace, l.PodLabel, l.HTTPPort, l.HTTPTimeout)
+	utils.AllowLoadOverrides = l.AllowLoadOverrides
+	utils.MinSamples = l.MinLoadSamples
+	utils.Denominator = l.AggregateDenominator
+	utils.MissingMetrics = l.MissingMetricsBehavior
+	utils.Normalization = l.LoadNormalization
+	utils.HTTP = l.AgentHTTP
+	utils.Source = l.LoadSource
+	utils.Cache = l.LoadCache
+	utils.Concurrency = l.LoadFetchConcurrency
+	return utils
+}
+
+// loadEntirelyUnavailable reports whether no eligible node (candidate included) reports load at all.
+func (l *LoadAverageScaleDown) loadEntirelyUnavailable(ctx context.Context) bool {
+	_, err := l.getClusterAggregateLoad(ctx, "")
+	return IsLoadUnavailable(err)
+}
+
+func (l *LoadAverageScaleDown) getClusterAggregateLoad(ctx context.Context, excludeNode string) (float64, error) {
+	utils := l.loadUtils()
 
 	exclude := map[string]string{}
 	if l.Cfg.NodeLabels.Disabled != "" {
@@ -2,7 +2,9 @@ package strategy
 
 import (
 	"context"
+	"errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"math"
 	"testing"
 	"time"
 
@@ -10,6 +12,7 @@ import (
 	corefake "k8s.io/client-go/kubernetes/fake"
 
 	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
 )
 
 func TestDryRunOverride(t *testing.T) {
@@ -132,6 +135,148 @@ func TestShouldScaleDown_ClusterWideThresholdBlocks(t *testing.T) {
 	}
 }
 
+func TestShouldScaleDown_LoadUnavailablePolicy(t *testing.T) {
+	cases := []struct {
+		name   string
+		policy LoadUnavailablePolicy
+		want   bool
+	}{
+		{"fail closed denies", LoadUnavailableFailClosed, false},
+		{"fail open allows", LoadUnavailableFailOpen, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// No metrics pods at all: every load fetch fails.
+			strategy := &LoadAverageScaleDown{
+				Client: corefake.NewSimpleClientset(
+					&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}},
+					&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node2"}},
+				),
+				Cfg:               &config.Config{},
+				Namespace:         "default",
+				PodLabel:          "app=test-metrics",
+				HTTPPort:          9100,
+				HTTPTimeout:       time.Second,
+				NodeThreshold:     0.5,
+				UnavailablePolicy: tc.policy,
+			}
+
+			ok, _ := strategy.ShouldScaleDown(context.Background(), "node1")
+			if ok != tc.want {
+				t.Errorf("expected scale-down=%v with policy %s, got %v", tc.want, tc.policy, ok)
+			}
+		})
+	}
+}
+
+func TestGetClusterAggregateLoad_NoSamplesIsLoadUnavailable(t *testing.T) {
+	utils := NewClusterLoadUtils(
+		corefake.NewSimpleClientset(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}}),
+		"default", "app=test-metrics", 9100, time.Second,
+	)
+
+	_, err := utils.GetClusterAggregateLoad(context.Background(), nil, "", nil, ClusterEvalAverage)
+	if !IsLoadUnavailable(err) {
+		t.Fatalf("expected ErrLoadUnavailable, got %v", err)
+	}
+}
+
+func TestGetClusterAggregateLoad_MinSamples(t *testing.T) {
+	override := map[string]string{nodeops.AnnotationLoadOverride: "0.2"}
+	utils := NewClusterLoadUtils(
+		corefake.NewSimpleClientset(
+			&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1", Annotations: override}},
+			&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node2", Annotations: override}},
+		),
+		"default", "app=test-metrics", 9100, time.Second,
+	)
+	utils.AllowLoadOverrides = true
+
+	utils.MinSamples = 2
+	if _, err := utils.GetClusterAggregateLoad(context.Background(), nil, "", nil, ClusterEvalAverage); err != nil {
+		t.Fatalf("expected aggregate at the sample minimum, got %v", err)
+	}
+
+	utils.MinSamples = 3
+	_, err := utils.GetClusterAggregateLoad(context.Background(), nil, "", nil, ClusterEvalAverage)
+	if !errors.Is(err, ErrInsufficientLoadSamples) || IsLoadUnavailable(err) {
+		t.Fatalf("expected ErrInsufficientLoadSamples only, got %v", err)
+	}
+}
+
+func TestParseLoadUnavailablePolicy(t *testing.T) {
+	if got := ParseLoadUnavailablePolicy("failOpen"); got != LoadUnavailableFailOpen {
+		t.Errorf("expected failOpen, got %s", got)
+	}
+	for _, in := range []string{"", "failClosed", "bogus"} {
+		if got := ParseLoadUnavailablePolicy(in); got != LoadUnavailableFailClosed {
+			t.Errorf("expected failClosed for %q, got %s", in, got)
+		}
+	}
+}
+
+func TestFetchNormalizedLoad_PerNodeOverride(t *testing.T) {
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name:        "canary",
+		Annotations: map[string]string{nodeops.AnnotationLoadOverride: "0.05"},
+	}}
+
+	cases := []struct {
+		name    string
+		allow   bool
+		wantErr bool
+	}{
+		{"override honored when enabled", true, false},
+		{"override ignored when disabled", false, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// No metrics pod exists, so only the override can produce a value.
+			utils := NewClusterLoadUtils(corefake.NewSimpleClientset(node), "default", "app=test-metrics", 9100, time.Second)
+			utils.AllowLoadOverrides = tc.allow
+
+			got, err := utils.FetchNormalizedLoad(context.Background(), "canary")
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected error without override, got load %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != 0.05 {
+				t.Errorf("expected override load 0.05, got %v", got)
+			}
+		})
+	}
+}
+
+func TestShouldScaleDown_PerNodeOverrideBlocks(t *testing.T) {
+	strategy := newTestStrategyWithDefaults(t, "node1", func(s *LoadAverageScaleDown) {
+		s.AllowLoadOverrides = true
+		s.ClusterWideThreshold = 0.5
+		s.DryRunClusterLoadOverride = ptr(0.1)
+	})
+	busy := &v1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name:        "busy",
+		Annotations: map[string]string{nodeops.AnnotationLoadOverride: "0.95"},
+	}}
+	if _, err := strategy.Client.CoreV1().Nodes().Create(context.Background(), busy, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	ok, err := strategy.ShouldScaleDown(context.Background(), "busy")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ok {
+		t.Errorf("expected scale-down to be denied for a node forced busy via annotation")
+	}
+}
+
 func TestAggregationFunctions(t *testing.T) {
 	cases := []struct {
 		name     string
@@ -211,3 +356,189 @@ func newTestStrategyWithDefaults(t *testing.T, name string, opts ...func(*LoadAv
 func ptr[T any](v T) *T {
 	return &v
 }
+
+func TestShouldScaleDown_ExcludeFromAggregateAnnotation(t *testing.T) {
+	newStrategy := func(excludeNoisy bool) *LoadAverageScaleDown {
+		noisy := map[string]string{nodeops.AnnotationLoadOverride: "0.3"}
+		if excludeNoisy {
+			noisy[nodeops.AnnotationExcludeFromAggregate] = "true"
+		}
+		calm := map[string]string{nodeops.AnnotationLoadOverride: "0.1"}
+		return &LoadAverageScaleDown{
+			Client: corefake.NewSimpleClientset(
+				&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "noisy", Annotations: noisy}},
+				&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "calm1", Annotations: calm}},
+				&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "calm2", Annotations: calm}},
+			),
+			Cfg:                  &config.Config{},
+			Namespace:            "default",
+			PodLabel:             "app=test-metrics",
+			HTTPPort:             9100,
+			HTTPTimeout:          time.Second,
+			NodeThreshold:        0.5,
+			ClusterWideThreshold: 0.2,
+			ClusterEvalMode:      ClusterEvalAverage,
+			AllowLoadOverrides:   true,
+		}
+	}
+
+	cases := []struct {
+		name         string
+		excludeNoisy bool
+		candidate    string
+		want         bool
+	}{
+		{"noisy node skews the aggregate", false, "calm1", false},
+		{"annotated noisy node is left out of the aggregate", true, "calm1", true},
+		{"annotated node is still a scale-down target", true, "noisy", true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ok, err := newStrategy(tc.excludeNoisy).ShouldScaleDown(context.Background(), tc.candidate)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ok != tc.want {
+				t.Errorf("ShouldScaleDown(%s) = %v, want %v", tc.candidate, ok, tc.want)
+			}
+		})
+	}
+}
+
+func TestGetClusterAggregateLoad_AggregateDenominator(t *testing.T) {
+	busy := map[string]string{nodeops.AnnotationLoadOverride: "0.8"}
+	off := map[string]string{nodeops.AnnotationPoweredOff: "2024-01-01T00:00:00Z"}
+	client := corefake.NewSimpleClientset(
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "on1", Annotations: busy}},
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "on2", Annotations: busy}},
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "off1", Annotations: off}},
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "off2", Annotations: off}},
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "off3", Annotations: off}},
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "off4", Annotations: off}},
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name: "off-ignored", Annotations: off, Labels: map[string]string{"ignore": "true"},
+		}},
+	)
+
+	tests := []struct {
+		denominator AggregateDenominator
+		want        float64
+	}{
+		{AggregateOnNodes, 0.8},
+		{AggregateAllManaged, 1.6 / 6},
+	}
+	for _, tc := range tests {
+		t.Run(string(tc.denominator), func(t *testing.T) {
+			utils := NewClusterLoadUtils(client, "default", "app=test-metrics", 9100, time.Second)
+			utils.AllowLoadOverrides = true
+			utils.Denominator = tc.denominator
+
+			got, err := utils.GetClusterAggregateLoad(context.Background(), map[string]string{"ignore": "true"}, "", nil, ClusterEvalAverage)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if math.Abs(got-tc.want) > 1e-9 {
+				t.Errorf("aggregate = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGetClusterAggregateLoad_AllManagedKeepsSampleGuards(t *testing.T) {
+	client := corefake.NewSimpleClientset(
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "on", Annotations: map[string]string{nodeops.AnnotationLoadOverride: "0.8"}}},
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "off", Annotations: map[string]string{nodeops.AnnotationPoweredOff: "2024-01-01T00:00:00Z"}}},
+	)
+	utils := NewClusterLoadUtils(client, "default", "app=test-metrics", 9100, time.Second)
+	utils.AllowLoadOverrides = true
+	utils.Denominator = AggregateAllManaged
+	utils.MinSamples = 2
+
+	_, err := utils.GetClusterAggregateLoad(context.Background(), nil, "", nil, ClusterEvalAverage)
+	if !errors.Is(err, ErrInsufficientLoadSamples) {
+		t.Fatalf("expected powered-off nodes not to count toward minSamples, got %v", err)
+	}
+}
+
+func TestGetClusterAggregateLoad_MissingMetricsBehavior(t *testing.T) {
+	client := corefake.NewSimpleClientset(
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "n1", Annotations: map[string]string{nodeops.AnnotationLoadOverride: "0.2"}}},
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "n2", Annotations: map[string]string{nodeops.AnnotationLoadOverride: "0.4"}}},
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "no-agent"}},
+	)
+
+	tests := []struct {
+		behavior MissingMetricsBehavior
+		want     float64
+	}{
+		{MissingMetricsSkip, 0.3},
+		{MissingMetricsTreatAsZero, 0.2},
+		{MissingMetricsTreatAsHigh, (0.6 + MissingMetricsHighLoad) / 3},
+	}
+	for _, tc := range tests {
+		t.Run(string(tc.behavior), func(t *testing.T) {
+			utils := NewClusterLoadUtils(client, "default", "app=test-metrics", 9100, time.Second)
+			utils.AllowLoadOverrides = true
+			utils.MissingMetrics = tc.behavior
+
+			got, err := utils.GetClusterAggregateLoad(context.Background(), nil, "", nil, ClusterEvalAverage)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if math.Abs(got-tc.want) > 1e-9*math.Max(1, tc.want) {
+				t.Errorf("aggregate = %v, want %v", got, tc.want)
+			}
+		})
+	}
+
+	utils := NewClusterLoadUtils(client, "default", "app=test-metrics", 9100, time.Second)
+	utils.AllowLoadOverrides = true
+	utils.MissingMetrics = MissingMetricsTreatAsZero
+	utils.MinSamples = 3
+	if _, err := utils.GetClusterAggregateLoad(context.Background(), nil, "", nil, ClusterEvalAverage); !errors.Is(err, ErrInsufficientLoadSamples) {
+		t.Fatalf("expected substitutes not to count toward minSamples, got %v", err)
+	}
+}
+
+func TestShouldScaleDown_MissingMetricsBehavior(t *testing.T) {
+	client := corefake.NewSimpleClientset(
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "peer", Annotations: map[string]string{nodeops.AnnotationLoadOverride: "0.1"}}},
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "no-agent"}},
+	)
+
+	tests := []struct {
+		behavior MissingMetricsBehavior
+		want     bool
+		wantErr  bool
+	}{
+		{MissingMetricsSkip, false, true},
+		{MissingMetricsTreatAsHigh, false, false},
+		{MissingMetricsTreatAsZero, true, false},
+	}
+	for _, tc := range tests {
+		t.Run(string(tc.behavior), func(t *testing.T) {
+			s := &LoadAverageScaleDown{
+				Client:                 client,
+				Cfg:                    &config.Config{},
+				PodLabel:               "app=test-metrics",
+				Namespace:              "default",
+				HTTPPort:               9100,
+				HTTPTimeout:            time.Second,
+				NodeThreshold:          0.5,
+				ClusterWideThreshold:   0.5,
+				ClusterEvalMode:        ClusterEvalAverage,
+				AllowLoadOverrides:     true,
+				MissingMetricsBehavior: tc.behavior,
+			}
+
+			ok, err := s.ShouldScaleDown(context.Background(), "no-agent")
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tc.wantErr)
+			}
+			if ok != tc.want {
+				t.Errorf("scale-down = %v, want %v", ok, tc.want)
+			}
+		})
+	}
+}
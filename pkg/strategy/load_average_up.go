@@ -2,9 +2,12 @@ package strategy
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"time"
 
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/agenthttp"
+	"go.opentelemetry.io/otel/trace"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -18,6 +21,16 @@ type LoadAverageScaleUp struct {
 	ClusterWideThreshold float64
 	DryRunOverride       *float64
 	IgnoreLabels         map[string]string
+	UnavailablePolicy    LoadUnavailablePolicy
+	AllowLoadOverrides   bool
+	MinLoadSamples       int
+	LoadNormalization    LoadNormalization
+	AggregateDenominator AggregateDenominator
+	MissingMetrics       MissingMetricsBehavior
+	AgentHTTP            *agenthttp.Client
+	LoadSource           NodeLoadSource // nil: metrics DaemonSet
+	LoadCache            *LoadCache     // nil: no per-loop caching
+	LoadFetchConcurrency int
 
 	ShutdownCandidates func(ctx context.Context) []string
 }
@@ -39,13 +52,32 @@ func (s *LoadAverageScaleUp) ShouldScaleUp(ctx context.Context) (string, bool, e
 		slog.Info("Dry-run override: using cluster-wide load", "value", aggregate)
 	} else {
 		utils := NewClusterLoadUtils(s.Client, s.Namespace, s.PodLabel, s.HTTPPort, s.HTTPTimeout)
+		utils.AllowLoadOverrides = s.AllowLoadOverrides
+		utils.MinSamples = s.MinLoadSamples
+		utils.Denominator = s.AggregateDenominator
+		utils.MissingMetrics = s.MissingMetrics
+		utils.Normalization = s.LoadNormalization
+		utils.HTTP = s.AgentHTTP
+		utils.Source = s.LoadSource
+		utils.Cache = s.LoadCache
+		utils.Concurrency = s.LoadFetchConcurrency
 		var err error
 		aggregate, err = utils.GetClusterAggregateLoad(ctx, s.IgnoreLabels, "", s.DryRunOverride, s.ClusterEvalMode)
 		if err != nil {
+			if s.UnavailablePolicy == LoadUnavailableFailOpen && (IsLoadUnavailable(err) || errors.Is(err, ErrInsufficientLoadSamples)) {
+				slog.Warn("Load metrics unavailable or insufficient — failing open for scale-up",
+					"candidate", candidates[0], "policy", s.UnavailablePolicy, "err", err)
+				return candidates[0], true, nil
+			}
+			if errors.Is(err, ErrInsufficientLoadSamples) {
+				slog.Info("Scale-up denied: insufficient load data", "err", err)
+			}
 			return "", false, nil
 		}
 	}
 
+	trace.SpanFromContext(ctx).SetAttributes(AttrClusterLoad.Float64(aggregate))
+
 	slog.Info("Cluster-wide load evaluation",
 		"aggregateLoad", aggregate,
 		"clusterWideThreshold", s.ClusterWideThreshold,
@@ -2,9 +2,11 @@ package strategy
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	corefake "k8s.io/client-go/kubernetes/fake"
@@ -72,6 +74,82 @@ func TestLoadAverageScaleUp_WithClusterLoadEval(t *testing.T) {
 	}
 }
 
+func TestLoadAverageScaleUp_LoadUnavailablePolicy(t *testing.T) {
+	cases := []struct {
+		name   string
+		policy LoadUnavailablePolicy
+		want   bool
+	}{
+		{"fail closed skips scale-up", LoadUnavailableFailClosed, false},
+		{"fail open boots a node", LoadUnavailableFailOpen, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// No metrics pods exist, so every load fetch fails.
+			strategy := newTestUpStrategyWithDefaults(func(s *LoadAverageScaleUp) {
+				s.UnavailablePolicy = tc.policy
+			})
+
+			node, ok, err := strategy.ShouldScaleUp(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ok != tc.want {
+				t.Fatalf("expected scale-up=%v with policy %s, got %v", tc.want, tc.policy, ok)
+			}
+			if ok && node != "node-a" {
+				t.Errorf("expected node-a as fail-open candidate, got %s", node)
+			}
+		})
+	}
+}
+
+func TestLoadAverageScaleUp_MinLoadSamples(t *testing.T) {
+	cases := []struct {
+		name      string
+		reporting int
+		policy    LoadUnavailablePolicy
+		want      bool
+	}{
+		{"below minimum denies", 2, LoadUnavailableFailClosed, false},
+		{"at minimum allows", 3, LoadUnavailableFailClosed, true},
+		{"below minimum fails open when configured", 1, LoadUnavailableFailOpen, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Only nodes carrying a load override report a (high) load; the rest have no metrics pod.
+			client := corefake.NewSimpleClientset()
+			for i := 0; i < 4; i++ {
+				n := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("n%d", i)}}
+				if i < tc.reporting {
+					n.Annotations = map[string]string{nodeops.AnnotationLoadOverride: "0.9"}
+				}
+				if _, err := client.CoreV1().Nodes().Create(context.Background(), n, metav1.CreateOptions{}); err != nil {
+					t.Fatalf("create node: %v", err)
+				}
+			}
+
+			strategy := newTestUpStrategyWithDefaults(func(s *LoadAverageScaleUp) {
+				s.Client = client
+				s.AllowLoadOverrides = true
+				s.ClusterEvalMode = ClusterEvalAverage
+				s.MinLoadSamples = 3
+				s.UnavailablePolicy = tc.policy
+			})
+
+			_, ok, err := strategy.ShouldScaleUp(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ok != tc.want {
+				t.Errorf("expected scale-up=%v with %d samples, got %v", tc.want, tc.reporting, ok)
+			}
+		})
+	}
+}
+
 func newTestUpStrategyWithDefaults(opts ...func(*LoadAverageScaleUp)) *LoadAverageScaleUp {
 	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}}
 
@@ -5,17 +5,29 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/agenthttp"
 	"github.com/docent-net/cluster-bare-autoscaler/pkg/nodeops"
 	"log/slog"
+	"math"
 	"net/http"
 	"sort"
+	"strconv"
+	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
+// Span attributes recording the load values a decision was based on. They are set on
+// the caller's active span, if any.
+const (
+	AttrNodeLoad    = attribute.Key("cba.load.node")
+	AttrClusterLoad = attribute.Key("cba.load.cluster")
+)
+
 type ClusterLoadEvalMode string
 
 const (
@@ -26,6 +38,55 @@ const (
 	ClusterEvalP75     ClusterLoadEvalMode = "p75"
 )
 
+// ErrLoadUnavailable is returned when not a single eligible node reported its load,
+// e.g. because the metrics DaemonSet is down cluster-wide.
+var ErrLoadUnavailable = errors.New("no cluster load data")
+
+// ErrInsufficientLoadSamples is returned when fewer nodes than ClusterLoadUtils.MinSamples
+// reported load, so the aggregate would not be representative.
+var ErrInsufficientLoadSamples = errors.New("too few cluster load samples")
+
+// ErrNoMetricsPod is returned when a node has no running metrics DaemonSet pod to ask for its load.
+var ErrNoMetricsPod = errors.New("no metrics pod")
+
+// LoadUnavailablePolicy decides what a load strategy does when load data is entirely unavailable.
+type LoadUnavailablePolicy string
+
+const (
+	LoadUnavailableFailClosed LoadUnavailablePolicy = "failClosed"
+	LoadUnavailableFailOpen   LoadUnavailablePolicy = "failOpen"
+)
+
+// AggregateDenominator selects which nodes a cluster-wide aggregate is taken over.
+type AggregateDenominator string
+
+const (
+	AggregateOnNodes    AggregateDenominator = "onNodes"    // nodes that report load
+	AggregateAllManaged AggregateDenominator = "allManaged" // plus powered-off nodes at zero load
+)
+
+// MissingMetricsBehavior decides how a node without a metrics pod enters load decisions.
+type MissingMetricsBehavior string
+
+const (
+	MissingMetricsSkip        MissingMetricsBehavior = "skip"        // leave the node out (default)
+	MissingMetricsTreatAsHigh MissingMetricsBehavior = "treatAsHigh" // count it as fully busy
+	MissingMetricsTreatAsZero MissingMetricsBehavior = "treatAsZero" // count it as idle
+)
+
+// MissingMetricsHighLoad is the load a treatAsHigh node counts with: above any real threshold, yet
+// finite so averages and percentiles stay defined.
+const MissingMetricsHighLoad = math.MaxFloat32
+
+// LoadNormalization selects how a node's raw load15 is turned into the value compared against thresholds.
+type LoadNormalization string
+
+const (
+	LoadPerLogicalCore  LoadNormalization = "perLogicalCore"  // load15 / logical CPUs (default)
+	LoadPerPhysicalCore LoadNormalization = "perPhysicalCore" // load15 / physical cores
+	LoadAbsolute        LoadNormalization = "absolute"        // raw load15
+)
+
 var evalFuncs = map[ClusterLoadEvalMode]func([]float64) float64{
 	ClusterEvalAverage: average,
 	ClusterEvalMedian:  median,
@@ -39,6 +100,27 @@ type ClusterLoadUtils struct {
 	PodLabel    string
 	HTTPPort    int
 	HTTPTimeout time.Duration
+	// HTTP sends the /load calls; nil uses plain http without auth.
+	HTTP *agenthttp.Client
+
+	// AllowLoadOverrides makes FetchNormalizedLoad honor the per-node load-override annotation.
+	AllowLoadOverrides bool
+	// MinSamples is the fewest load samples GetClusterAggregateLoad accepts; 0 disables the guard.
+	MinSamples int
+	// Normalization selects the divisor applied to load15; empty means LoadPerLogicalCore.
+	Normalization LoadNormalization
+	// Source, when set, replaces the metrics DaemonSet as the provider of normalized node load.
+	Source NodeLoadSource
+	// Concurrency bounds parallel per-node load fetches in FetchClusterLoads; below 1 fetches one at a time.
+	Concurrency int
+	// Cache shares /load replies across every ClusterLoadUtils of one reconcile loop; nil fetches each time.
+	Cache *LoadCache
+	// Denominator AggregateAllManaged makes GetClusterAggregateLoad count powered-off nodes as
+	// zero-load samples; empty means AggregateOnNodes.
+	Denominator AggregateDenominator
+	// MissingMetrics decides whether nodes without a metrics pod are skipped (default) or counted
+	// with a substitute load; substitutes are not samples for MinSamples.
+	MissingMetrics MissingMetricsBehavior
 }
 
 func NewClusterLoadUtils(client kubernetes.Interface, ns, label string, port int, timeout time.Duration) *ClusterLoadUtils {
@@ -52,80 +134,207 @@ func NewClusterLoadUtils(client kubernetes.Interface, ns, label string, port int
 }
 
 func (u *ClusterLoadUtils) GetEligibleClusterLoads(ctx context.Context, ignore map[string]string, exclude string) ([]float64, map[string]float64, error) {
-	nodes, err := u.Client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	names, _, err := u.aggregateNodes(ctx, ignore, exclude)
 	if err != nil {
 		return nil, nil, err
 	}
+	loads, nodeToLoad, missing := u.fetchClusterLoads(ctx, names)
+	if load, ok := u.MissingMetricsLoad(); ok {
+		for _, name := range missing {
+			loads = append(loads, load)
+			nodeToLoad[name] = load
+		}
+	}
+	return loads, nodeToLoad, nil
+}
+
+// MissingMetricsLoad returns the load a node without a metrics pod counts with, or false when
+// such nodes are skipped.
+func (u *ClusterLoadUtils) MissingMetricsLoad() (float64, bool) {
+	switch u.MissingMetrics {
+	case MissingMetricsTreatAsHigh:
+		return MissingMetricsHighLoad, true
+	case MissingMetricsTreatAsZero:
+		return 0, true
+	}
+	return 0, false
+}
+
+// aggregateNodes returns the running nodes whose load enters the aggregate and the number of
+// powered-off nodes that would otherwise pass the same filters.
+func (u *ClusterLoadUtils) aggregateNodes(ctx context.Context, ignore map[string]string, exclude string) ([]string, int, error) {
+	nodes, err := u.Client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, 0, err
+	}
 
 	var names []string
+	off := 0
 	for _, n := range nodes.Items {
-		if _, isDown := n.Annotations[nodeops.AnnotationPoweredOff]; isDown {
-			slog.Debug("Skipping load fetch: node has powered-off annotation", "node", n.Name)
+		if n.Annotations[nodeops.AnnotationExcludeFromAggregate] == "true" {
+			slog.Debug("Skipping load fetch: node excluded from aggregate by annotation", "node", n.Name)
+			continue
+		}
+		if n.Name == exclude || nodeops.ShouldIgnoreNodeDueToLabels(n, ignore) {
 			continue
 		}
 
-		if n.Name != exclude && !nodeops.ShouldIgnoreNodeDueToLabels(n, ignore) {
-			names = append(names, n.Name)
+		if _, isDown := n.Annotations[nodeops.AnnotationPoweredOff]; isDown {
+			slog.Debug("Skipping load fetch: node has powered-off annotation", "node", n.Name)
+			off++
+			continue
 		}
+		names = append(names, n.Name)
 	}
-	return u.FetchClusterLoads(ctx, names)
+	return names, off, nil
 }
 
 func (u *ClusterLoadUtils) FetchClusterLoads(ctx context.Context, nodeNames []string) ([]float64, map[string]float64, error) {
-	var results []float64
+	loads, nodeToLoad, _ := u.fetchClusterLoads(ctx, nodeNames)
+	return loads, nodeToLoad, nil
+}
+
+// fetchClusterLoads returns the loads that could be read, in nodeNames order, and the nodes that have
+// no metrics pod. Up to Concurrency nodes are fetched at once; once ctx is done no new fetch starts.
+func (u *ClusterLoadUtils) fetchClusterLoads(ctx context.Context, nodeNames []string) ([]float64, map[string]float64, []string) {
+	type result struct {
+		load float64
+		err  error
+	}
+	results := make([]result, len(nodeNames))
+
+	sem := make(chan struct{}, max(u.Concurrency, 1))
+	var wg sync.WaitGroup
+	for i, name := range nodeNames {
+		if err := ctx.Err(); err != nil {
+			results[i].err = err
+			continue
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i].err = ctx.Err()
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			load, err := u.FetchNormalizedLoad(ctx, name)
+			results[i] = result{load: load, err: err}
+		}()
+	}
+	wg.Wait()
+
 	var loads []float64
+	var missing []string
 	nodeToLoad := make(map[string]float64)
-
-	for _, name := range nodeNames {
-		load, err := u.FetchNormalizedLoad(ctx, name)
-		if err != nil {
+	for i, name := range nodeNames {
+		if err := results[i].err; err != nil {
+			if errors.Is(err, ErrNoMetricsPod) {
+				missing = append(missing, name)
+			}
 			slog.Warn("Skipping node due to error", "node", name, "err", err)
 			continue
 		}
-		loads = append(loads, load)
-		nodeToLoad[name] = load
-
-		results = append(results, load)
+		loads = append(loads, results[i].load)
+		nodeToLoad[name] = results[i].load
 	}
-	return loads, nodeToLoad, nil
+	return loads, nodeToLoad, missing
 }
 
 func (u *ClusterLoadUtils) FetchNormalizedLoad(ctx context.Context, nodeName string) (float64, error) {
+	if u.AllowLoadOverrides {
+		if val, ok := u.loadOverrideForNode(ctx, nodeName); ok {
+			return val, nil
+		}
+	}
+	if u.Source != nil {
+		return u.Source.NodeLoad(ctx, nodeName)
+	}
+
+	data, err := u.Cache.get(ctx, nodeName, u.fetchAgentLoad)
+	if err != nil {
+		return 0, err
+	}
+	return NormalizeLoad(data.Load15, data.CPUCount, data.PhysicalCoreCount, u.Normalization)
+}
+
+// agentLoad is a node's reply from the metrics DaemonSet /load endpoint.
+type agentLoad struct {
+	Load15            float64 `json:"load15"`
+	CPUCount          int     `json:"cpuCount"`
+	PhysicalCoreCount int     `json:"physicalCoreCount"`
+}
+
+func (u *ClusterLoadUtils) fetchAgentLoad(ctx context.Context, nodeName string) (agentLoad, error) {
 	pod, err := u.findMetricsPodForNode(ctx, nodeName)
 	if err != nil {
-		return 0, fmt.Errorf("finding metrics pod: %w", err)
+		return agentLoad{}, fmt.Errorf("finding metrics pod: %w", err)
 	}
 
-	url := fmt.Sprintf("http://%s:%d/load", pod.Status.PodIP, u.HTTPPort)
+	url := u.HTTP.URL(pod.Status.PodIP, u.HTTPPort, "/load")
 	reqCtx, cancel := context.WithTimeout(ctx, u.HTTPTimeout)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, url, nil)
 	if err != nil {
-		return 0, err
+		return agentLoad{}, err
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := u.HTTP.Do(req)
 	if err != nil {
-		return 0, fmt.Errorf("calling load endpoint: %w", err)
+		return agentLoad{}, fmt.Errorf("calling load endpoint: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return 0, fmt.Errorf("unexpected status: %s", resp.Status)
+		return agentLoad{}, fmt.Errorf("unexpected status: %s", resp.Status)
 	}
 
-	var data struct {
-		Load15   float64 `json:"load15"`
-		CPUCount int     `json:"cpuCount"`
-	}
+	var data agentLoad
 	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		return 0, fmt.Errorf("decode failed: %w", err)
+		return agentLoad{}, fmt.Errorf("decode failed: %w", err)
 	}
-	if data.CPUCount == 0 {
+	return data, nil
+}
+
+// NormalizeLoad applies mode to a raw load15 sample. perPhysicalCore falls back to the logical
+// count when the metrics agent predates physicalCoreCount reporting.
+func NormalizeLoad(load15 float64, logicalCPUs, physicalCores int, mode LoadNormalization) (float64, error) {
+	switch mode {
+	case LoadAbsolute:
+		return load15, nil
+	case LoadPerPhysicalCore:
+		if physicalCores > 0 {
+			return load15 / float64(physicalCores), nil
+		}
+		slog.Debug("Physical core count not reported; normalizing per logical CPU")
+	}
+	if logicalCPUs == 0 {
 		return 0, errors.New("CPUCount is zero")
 	}
-	return data.Load15 / float64(data.CPUCount), nil
+	return load15 / float64(logicalCPUs), nil
+}
+
+// loadOverrideForNode returns the value of the node's load-override annotation, if set and valid.
+func (u *ClusterLoadUtils) loadOverrideForNode(ctx context.Context, nodeName string) (float64, bool) {
+	node, err := u.Client.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		slog.Debug("Could not read node for load override", "node", nodeName, "err", err)
+		return 0, false
+	}
+	raw, ok := node.Annotations[nodeops.AnnotationLoadOverride]
+	if !ok || raw == "" {
+		return 0, false
+	}
+	val, err := strconv.ParseFloat(raw, 64)
+	if err != nil || val < 0 {
+		slog.Warn("Ignoring invalid load override annotation", "node", nodeName, "value", raw)
+		return 0, false
+	}
+	slog.Info("Per-node load override in effect", "node", nodeName, "load", val)
+	return val, true
 }
 
 func (u *ClusterLoadUtils) findMetricsPodForNode(ctx context.Context, nodeName string) (*v1.Pod, error) {
@@ -141,7 +350,7 @@ func (u *ClusterLoadUtils) findMetricsPodForNode(ctx context.Context, nodeName s
 			return &p, nil
 		}
 	}
-	return nil, fmt.Errorf("no metrics pod for node %s", nodeName)
+	return nil, fmt.Errorf("%w for node %s", ErrNoMetricsPod, nodeName)
 }
 
 func EvaluateAggregate(loads []float64, mode ClusterLoadEvalMode) float64 {
@@ -151,6 +360,50 @@ func EvaluateAggregate(loads []float64, mode ClusterLoadEvalMode) float64 {
 	return average(loads)
 }
 
+// ParseLoadUnavailablePolicy maps a config value to a policy; anything unknown fails closed.
+func ParseLoadUnavailablePolicy(policy string) LoadUnavailablePolicy {
+	if policy == string(LoadUnavailableFailOpen) {
+		return LoadUnavailableFailOpen
+	}
+	return LoadUnavailableFailClosed
+}
+
+// IsLoadUnavailable reports whether err means that no node reported load data at all.
+func IsLoadUnavailable(err error) bool {
+	return errors.Is(err, ErrLoadUnavailable)
+}
+
+// ParseAggregateDenominator maps a config value to a denominator; anything unknown means AggregateOnNodes.
+func ParseAggregateDenominator(mode string) AggregateDenominator {
+	if mode == string(AggregateAllManaged) {
+		return AggregateAllManaged
+	}
+	return AggregateOnNodes
+}
+
+// ParseMissingMetricsBehavior maps a config value to a behavior; anything unknown means MissingMetricsSkip.
+func ParseMissingMetricsBehavior(behavior string) MissingMetricsBehavior {
+	switch behavior {
+	case string(MissingMetricsTreatAsHigh):
+		return MissingMetricsTreatAsHigh
+	case string(MissingMetricsTreatAsZero):
+		return MissingMetricsTreatAsZero
+	}
+	return MissingMetricsSkip
+}
+
+// ParseLoadNormalization maps a config value to a mode; anything unknown normalizes per logical CPU.
+func ParseLoadNormalization(mode string) LoadNormalization {
+	switch mode {
+	case string(LoadPerPhysicalCore):
+		return LoadPerPhysicalCore
+	case string(LoadAbsolute):
+		return LoadAbsolute
+	default:
+		return LoadPerLogicalCore
+	}
+}
+
 func ParseClusterEvalMode(mode string) ClusterLoadEvalMode {
 	switch mode {
 	case "median":
@@ -216,15 +469,36 @@ func (u *ClusterLoadUtils) GetClusterAggregateLoad(
 		return *override, nil
 	}
 
-	loads, nodeLoads, err := u.GetEligibleClusterLoads(ctx, ignoreLabels, excludeNode)
-	if err != nil || len(loads) == 0 {
-		slog.Warn("No eligible cluster load data available", "err", err)
-		return 0, fmt.Errorf("no cluster load data")
+	names, off, err := u.aggregateNodes(ctx, ignoreLabels, excludeNode)
+	if err != nil {
+		slog.Warn("Failed to collect cluster load data", "err", err)
+		return 0, fmt.Errorf("collecting cluster load: %w", err)
+	}
+	loads, nodeLoads, missing := u.fetchClusterLoads(ctx, names)
+	if len(loads) == 0 {
+		slog.Warn("No eligible cluster load data available")
+		return 0, ErrLoadUnavailable
+	}
+	if len(loads) < u.MinSamples {
+		slog.Warn("Too few cluster load samples for a reliable aggregate", "samples", len(loads), "minSamples", u.MinSamples)
+		return 0, fmt.Errorf("%w: got %d, need %d", ErrInsufficientLoadSamples, len(loads), u.MinSamples)
 	}
 
 	for node, val := range nodeLoads {
 		slog.Info("Cluster load sample", "node", node, "normalizedLoad", val)
 	}
+	if u.Denominator == AggregateAllManaged && off > 0 {
+		// Padded after the sample guards: idle powered-off capacity is not a load reading.
+		slog.Debug("Counting powered-off nodes as zero load", "poweredOff", off)
+		loads = append(loads, make([]float64, off)...)
+	}
+	if load, ok := u.MissingMetricsLoad(); ok && len(missing) > 0 {
+		// Also after the sample guards, so a cluster-wide DaemonSet outage still reads as unavailable.
+		slog.Info("Counting nodes without a metrics pod", "nodes", missing, "behavior", u.MissingMetrics)
+		for range missing {
+			loads = append(loads, load)
+		}
+	}
 
 	return EvaluateAggregate(loads, mode), nil
 }
@@ -0,0 +1,185 @@
+package strategy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestFetchNormalizedLoad_NormalizationModes(t *testing.T) {
+	// 8 logical CPUs on 4 physical cores (SMT), same raw load for every mode.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"load15": 6, "cpuCount": 8, "physicalCoreCount": 4}`))
+	}))
+	defer srv.Close()
+	u, _ := url.Parse(srv.URL)
+	port, _ := strconv.Atoi(u.Port())
+
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "metrics", Namespace: "default", Labels: map[string]string{"app": "test-metrics"}},
+		Spec:       v1.PodSpec{NodeName: "node1"},
+		Status:     v1.PodStatus{PodIP: "127.0.0.1"},
+	}
+
+	cases := []struct {
+		mode LoadNormalization
+		want float64
+	}{
+		{"", 0.75},
+		{LoadPerLogicalCore, 0.75},
+		{LoadPerPhysicalCore, 1.5},
+		{LoadAbsolute, 6},
+	}
+	for _, tc := range cases {
+		t.Run(string(tc.mode), func(t *testing.T) {
+			utils := NewClusterLoadUtils(corefake.NewSimpleClientset(pod), "default", "app=test-metrics", port, time.Second)
+			utils.Normalization = tc.mode
+
+			got, err := utils.FetchNormalizedLoad(context.Background(), "node1")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestNormalizeLoad_PhysicalCoresNotReported(t *testing.T) {
+	got, err := NormalizeLoad(6, 8, 0, LoadPerPhysicalCore)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != 0.75 {
+		t.Errorf("expected fallback to logical CPUs (0.75), got %v", got)
+	}
+	if _, err := NormalizeLoad(6, 0, 0, LoadPerLogicalCore); err == nil {
+		t.Error("expected error for zero CPU count")
+	}
+	if got, err := NormalizeLoad(6, 0, 0, LoadAbsolute); err != nil || got != 6 {
+		t.Errorf("absolute mode must not need a CPU count, got %v, %v", got, err)
+	}
+}
+
+func TestParseLoadNormalization(t *testing.T) {
+	for in, want := range map[string]LoadNormalization{
+		"perPhysicalCore": LoadPerPhysicalCore,
+		"absolute":        LoadAbsolute,
+		"perLogicalCore":  LoadPerLogicalCore,
+		"":                LoadPerLogicalCore,
+		"bogus":           LoadPerLogicalCore,
+	} {
+		if got := ParseLoadNormalization(in); got != want {
+			t.Errorf("ParseLoadNormalization(%q) = %s, want %s", in, got, want)
+		}
+	}
+}
+
+func TestFetchNormalizedLoad_Cache(t *testing.T) {
+	var hits int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		_, _ = w.Write([]byte(`{"load15": 2, "cpuCount": 4}`))
+	}))
+	defer srv.Close()
+	u, _ := url.Parse(srv.URL)
+	port, _ := strconv.Atoi(u.Port())
+
+	client := corefake.NewSimpleClientset(&v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "metrics", Namespace: "default", Labels: map[string]string{"app": "test-metrics"}},
+		Spec:       v1.PodSpec{NodeName: "node1"},
+		Status:     v1.PodStatus{PodIP: "127.0.0.1"},
+	})
+	fetch := func(cache *LoadCache, normalization LoadNormalization) float64 {
+		t.Helper()
+		utils := NewClusterLoadUtils(client, "default", "app=test-metrics", port, time.Second)
+		utils.Cache = cache
+		utils.Normalization = normalization
+		got, err := utils.FetchNormalizedLoad(context.Background(), "node1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return got
+	}
+
+	cache := NewLoadCache()
+	fetch(cache, LoadPerLogicalCore)
+	if got := fetch(cache, LoadAbsolute); got != 2 {
+		t.Errorf("cached reply must be normalized per caller, got %v", got)
+	}
+	if hits != 1 {
+		t.Fatalf("expected one /load call with a shared cache, got %d", hits)
+	}
+
+	cache.Reset()
+	fetch(cache, LoadPerLogicalCore)
+	if hits != 2 {
+		t.Fatalf("expected Reset to force a new /load call, got %d calls", hits)
+	}
+
+	fetch(nil, LoadPerLogicalCore)
+	fetch(nil, LoadPerLogicalCore)
+	if hits != 4 {
+		t.Fatalf("expected a nil cache to fetch every time, got %d calls", hits)
+	}
+}
+
+func TestFetchClusterLoads_Concurrency(t *testing.T) {
+	var inFlight, peak, hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(100 * time.Millisecond)
+		_, _ = w.Write([]byte(`{"load15": 1, "cpuCount": 4}`))
+	}))
+	defer srv.Close()
+	u, _ := url.Parse(srv.URL)
+	port, _ := strconv.Atoi(u.Port())
+
+	client := corefake.NewSimpleClientset()
+	names := []string{"n1", "n2", "n3", "n4", "n5"}
+	for _, name := range names {
+		_, _ = client.CoreV1().Pods("default").Create(context.Background(), &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "metrics-" + name, Namespace: "default", Labels: map[string]string{"app": "test-metrics"}},
+			Spec:       v1.PodSpec{NodeName: name},
+			Status:     v1.PodStatus{PodIP: "127.0.0.1"},
+		}, metav1.CreateOptions{})
+	}
+	utils := NewClusterLoadUtils(client, "default", "app=test-metrics", port, time.Second)
+	utils.Concurrency = 2
+
+	loads, byNode, err := utils.FetchClusterLoads(context.Background(), append(names, "no-agent"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(loads) != len(names) || len(byNode) != len(names) {
+		t.Fatalf("expected %d loads with the agentless node skipped, got %v", len(names), byNode)
+	}
+	if got := peak.Load(); got != 2 {
+		t.Errorf("expected at most and up to 2 concurrent /load calls, peak was %d", got)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	hits.Store(0)
+	if loads, _, _ := utils.FetchClusterLoads(ctx, names); len(loads) != 0 || hits.Load() != 0 {
+		t.Errorf("expected a cancelled context to skip every node, got %d loads and %d calls", len(loads), hits.Load())
+	}
+}
@@ -0,0 +1,51 @@
+package strategy
+
+import (
+	"context"
+	"sync"
+)
+
+// LoadCache remembers each node's /load reply for one reconcile loop, so the load strategies and
+// rotation asking about the same node share a single HTTP round-trip. Failures are remembered as
+// well. A nil cache fetches every time. Safe for concurrent use.
+type LoadCache struct {
+	mu      sync.Mutex
+	entries map[string]loadCacheEntry
+}
+
+type loadCacheEntry struct {
+	load agentLoad
+	err  error
+}
+
+func NewLoadCache() *LoadCache {
+	return &LoadCache{entries: map[string]loadCacheEntry{}}
+}
+
+// Reset forgets every cached reply; the reconciler calls it at the start of each loop.
+func (c *LoadCache) Reset() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.entries)
+}
+
+func (c *LoadCache) get(ctx context.Context, nodeName string, fetch func(context.Context, string) (agentLoad, error)) (agentLoad, error) {
+	if c == nil {
+		return fetch(ctx, nodeName)
+	}
+	c.mu.Lock()
+	entry, ok := c.entries[nodeName]
+	c.mu.Unlock()
+	if ok {
+		return entry.load, entry.err
+	}
+
+	load, err := fetch(ctx, nodeName)
+	c.mu.Lock()
+	c.entries[nodeName] = loadCacheEntry{load: load, err: err}
+	c.mu.Unlock()
+	return load, err
+}
@@ -11,6 +11,7 @@ import (
 
 type MinNodeCountScaleUp struct {
 	Cfg          *config.Config
+	MinNodes     func() int // optional; overrides Cfg.MinNodes (e.g. when resolved from an external source)
 	ActiveNodes  func(ctx context.Context) ([]v1.Node, error)
 	ShutdownList func(ctx context.Context) []string
 }
@@ -25,8 +26,9 @@ func (s *MinNodeCountScaleUp) ShouldScaleUp(ctx context.Context) (string, bool,
 		return "", false, err
 	}
 
-	if len(active) >= s.Cfg.MinNodes {
-		slog.Debug("MinNodeCountScaleUp: current nodes meet or exceed minNodes", "current", len(active), "minNodes", s.Cfg.MinNodes)
+	minNodes := s.minNodes()
+	if len(active) >= minNodes {
+		slog.Debug("MinNodeCountScaleUp: current nodes meet or exceed minNodes", "current", len(active), "minNodes", minNodes)
 		return "", false, nil
 	}
 
@@ -35,7 +37,7 @@ func (s *MinNodeCountScaleUp) ShouldScaleUp(ctx context.Context) (string, bool,
 		slog.Debug("MinNodeCountScaleUp: below minNodes but no available shutdown nodes to power on",
 			"activeNodes", len(active),
 			"shutdownCandidates", len(shutdown),
-			"minNodes", s.Cfg.MinNodes)
+			"minNodes", minNodes)
 
 		return "", false, nil
 	}
@@ -45,7 +47,14 @@ func (s *MinNodeCountScaleUp) ShouldScaleUp(ctx context.Context) (string, bool,
 		"candidate", shutdown[0],
 		"activeNodes", len(active),
 		"shutdownCandidates", len(shutdown),
-		"minNodes", s.Cfg.MinNodes)
+		"minNodes", minNodes)
 
 	return shutdown[0], true, nil
 }
+
+func (s *MinNodeCountScaleUp) minNodes() int {
+	if s.MinNodes != nil {
+		return s.MinNodes()
+	}
+	return s.Cfg.MinNodes
+}
@@ -0,0 +1,74 @@
+package strategy
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+)
+
+// DefaultNodeTypeLabel is used to look up nodeCapacityByType when no typeLabel is configured.
+const DefaultNodeTypeLabel = "node.kubernetes.io/instance-type"
+
+// NodeCapacity returns the allocatable CPU and memory to reason about when fitting pods onto node.
+// A powered-off node reports no live allocatable, so the capacity declared for its type in
+// nodeCapacityByType takes precedence; otherwise Status.Allocatable is used.
+// The second return value is false when neither source knows the node's capacity.
+func NodeCapacity(node v1.Node, cfg *config.Config) (v1.ResourceList, bool) {
+	label := cfg.NodeCapacityByType.TypeLabel
+	if label == "" {
+		label = DefaultNodeTypeLabel
+	}
+	if declared, ok := cfg.NodeCapacityByType.Types[node.Labels[label]]; ok {
+		capacity := v1.ResourceList{}
+		if declared.CPU != "" {
+			capacity[v1.ResourceCPU] = resource.MustParse(declared.CPU) // validated at config load
+		}
+		if declared.Memory != "" {
+			capacity[v1.ResourceMemory] = resource.MustParse(declared.Memory)
+		}
+		return capacity, true
+	}
+
+	if len(node.Status.Allocatable) > 0 {
+		return node.Status.Allocatable, true
+	}
+	return nil, false
+}
+
+// FitsOnNode reports whether the given CPU/memory requests fit within node's capacity.
+// Nodes with unknown capacity never fit.
+func FitsOnNode(node v1.Node, cfg *config.Config, requests v1.ResourceList) bool {
+	capacity, ok := NodeCapacity(node, cfg)
+	if !ok {
+		return false
+	}
+	for _, name := range []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory} {
+		req, requested := requests[name]
+		if !requested || req.IsZero() {
+			continue
+		}
+		avail, known := capacity[name]
+		if !known || req.Cmp(avail) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// PodRequests sums the CPU and memory requests of pod's containers.
+func PodRequests(pod v1.Pod) v1.ResourceList {
+	total := v1.ResourceList{}
+	for _, c := range pod.Spec.Containers {
+		for _, name := range []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory} {
+			req, ok := c.Resources.Requests[name]
+			if !ok {
+				continue
+			}
+			sum := total[name]
+			sum.Add(req)
+			total[name] = sum
+		}
+	}
+	return total
+}
@@ -0,0 +1,96 @@
+package strategy
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+)
+
+func offNode(name, nodeType string) v1.Node {
+	return v1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name:   name,
+		Labels: map[string]string{DefaultNodeTypeLabel: nodeType},
+	}}
+}
+
+func requests(cpu, mem string) v1.ResourceList {
+	return v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse(cpu),
+		v1.ResourceMemory: resource.MustParse(mem),
+	}
+}
+
+func TestFitsOnNode_DeclaredCapacityForOffNode(t *testing.T) {
+	cfg := &config.Config{NodeCapacityByType: config.NodeCapacityByTypeConfig{
+		Types: map[string]config.NodeCapacity{
+			"small": {CPU: "4", Memory: "16Gi"},
+			"large": {CPU: "32", Memory: "128Gi"},
+		},
+	}}
+
+	tests := []struct {
+		name string
+		node v1.Node
+		req  v1.ResourceList
+		want bool
+	}{
+		{"fits declared small node", offNode("a", "small"), requests("2", "8Gi"), true},
+		{"cpu exceeds declared small node", offNode("a", "small"), requests("6", "8Gi"), false},
+		{"memory exceeds declared small node", offNode("a", "small"), requests("2", "32Gi"), false},
+		{"large node justifies fit", offNode("b", "large"), requests("6", "32Gi"), true},
+		{"unknown type without allocatable never fits", offNode("c", "mystery"), requests("1", "1Gi"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FitsOnNode(tt.node, cfg, tt.req); got != tt.want {
+				t.Errorf("FitsOnNode = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNodeCapacity_FallsBackToAllocatable(t *testing.T) {
+	cfg := &config.Config{}
+	node := newNode("live", "8", "32Gi")
+
+	capacity, ok := NodeCapacity(node, cfg)
+	if !ok {
+		t.Fatal("expected capacity from Status.Allocatable")
+	}
+	if cpu := capacity.Cpu().MilliValue(); cpu != 8000 {
+		t.Errorf("cpu = %dm, want 8000m", cpu)
+	}
+}
+
+func TestNodeCapacity_CustomTypeLabel(t *testing.T) {
+	cfg := &config.Config{NodeCapacityByType: config.NodeCapacityByTypeConfig{
+		TypeLabel: "hw.example.com/model",
+		Types:     map[string]config.NodeCapacity{"r640": {CPU: "31500m", Memory: "120Gi"}},
+	}}
+	node := v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "n", Labels: map[string]string{"hw.example.com/model": "r640"}}}
+
+	if !FitsOnNode(node, cfg, requests("30", "100Gi")) {
+		t.Error("expected fit using capacity declared under the custom type label")
+	}
+}
+
+func TestPodRequests_SumsContainers(t *testing.T) {
+	pod := v1.Pod{Spec: v1.PodSpec{Containers: []v1.Container{
+		{Resources: v1.ResourceRequirements{Requests: requests("1500m", "2Gi")}},
+		{Resources: v1.ResourceRequirements{Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("500m")}}},
+		{},
+	}}}
+
+	got := PodRequests(pod)
+	if cpu := got[v1.ResourceCPU]; cpu.Cmp(resource.MustParse("2")) != 0 {
+		t.Errorf("cpu = %s, want 2", cpu.String())
+	}
+	if mem := got[v1.ResourceMemory]; mem.Cmp(resource.MustParse("2Gi")) != 0 {
+		t.Errorf("memory = %s, want 2Gi", mem.String())
+	}
+}
@@ -0,0 +1,149 @@
+package strategy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// PrometheusLoadStrategy takes scale decisions from PromQL queries instead of the metrics
+// DaemonSet. It implements both ScaleDownStrategy and ScaleUpStrategy; query errors deny.
+type PrometheusLoadStrategy struct {
+	URL                string // Prometheus base URL
+	NodeQuery          string // "$node" is replaced by the candidate's name
+	ClusterQuery       string
+	NodeThreshold      float64
+	ScaleDownThreshold float64
+	ScaleUpThreshold   float64
+	Timeout            time.Duration
+	HTTP               *http.Client // nil: http.DefaultClient
+
+	ShutdownCandidates func(ctx context.Context) []string // scale-up only
+}
+
+func (p *PrometheusLoadStrategy) Name() string {
+	return "Prometheus"
+}
+
+func (p *PrometheusLoadStrategy) ShouldScaleDown(ctx context.Context, nodeName string) (bool, error) {
+	nodeLoad, err := p.Query(ctx, strings.ReplaceAll(p.NodeQuery, "$node", nodeName))
+	if err != nil {
+		return false, fmt.Errorf("node query: %w", err)
+	}
+	trace.SpanFromContext(ctx).SetAttributes(AttrNodeLoad.Float64(nodeLoad))
+	if nodeLoad >= p.NodeThreshold {
+		slog.Info("Node load too high for scale-down", "node", nodeName, "load", nodeLoad, "threshold", p.NodeThreshold, "source", "prometheus")
+		return false, nil
+	}
+
+	clusterLoad, err := p.Query(ctx, p.ClusterQuery)
+	if err != nil {
+		return false, fmt.Errorf("cluster query: %w", err)
+	}
+	trace.SpanFromContext(ctx).SetAttributes(AttrClusterLoad.Float64(clusterLoad))
+	if clusterLoad >= p.ScaleDownThreshold {
+		slog.Info("Cluster-wide load too high to scale down node", "aggregateLoad", clusterLoad, "threshold", p.ScaleDownThreshold, "source", "prometheus")
+		return false, nil
+	}
+	return true, nil
+}
+
+func (p *PrometheusLoadStrategy) ShouldScaleUp(ctx context.Context) (string, bool, error) {
+	candidates := p.ShutdownCandidates(ctx)
+	if len(candidates) == 0 {
+		slog.Debug("Prometheus: no shutdown candidates available")
+		return "", false, nil
+	}
+
+	clusterLoad, err := p.Query(ctx, p.ClusterQuery)
+	if err != nil {
+		return "", false, fmt.Errorf("cluster query: %w", err)
+	}
+	trace.SpanFromContext(ctx).SetAttributes(AttrClusterLoad.Float64(clusterLoad))
+	slog.Info("Cluster-wide load evaluation", "aggregateLoad", clusterLoad, "clusterWideThreshold", p.ScaleUpThreshold, "source", "prometheus")
+
+	if clusterLoad < p.ScaleUpThreshold {
+		return "", false, nil
+	}
+	return candidates[0], true, nil
+}
+
+// Query runs an instant query and returns its value. The result must be a scalar or a vector with
+// exactly one sample.
+func (p *PrometheusLoadStrategy) Query(ctx context.Context, query string) (float64, error) {
+	reqCtx, cancel := context.WithTimeout(ctx, p.Timeout)
+	defer cancel()
+
+	endpoint := strings.TrimSuffix(p.URL, "/") + "/api/v1/query?" + url.Values{"query": {query}}.Encode()
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return 0, err
+	}
+	client := p.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("calling prometheus: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Status string `json:"status"`
+		Error  string `json:"error"`
+		Data   struct {
+			ResultType string          `json:"resultType"`
+			Result     json.RawMessage `json:"result"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return 0, fmt.Errorf("decode failed: %w", err)
+	}
+	if body.Status != "success" {
+		return 0, fmt.Errorf("query %q failed: %s (%s)", query, body.Error, resp.Status)
+	}
+
+	var sample [2]any
+	switch body.Data.ResultType {
+	case "scalar":
+		if err := json.Unmarshal(body.Data.Result, &sample); err != nil {
+			return 0, fmt.Errorf("decode scalar: %w", err)
+		}
+	case "vector":
+		var vector []struct {
+			Value [2]any `json:"value"`
+		}
+		if err := json.Unmarshal(body.Data.Result, &vector); err != nil {
+			return 0, fmt.Errorf("decode vector: %w", err)
+		}
+		if len(vector) != 1 {
+			return 0, fmt.Errorf("query %q returned %d series, want 1", query, len(vector))
+		}
+		sample = vector[0].Value
+	default:
+		return 0, fmt.Errorf("query %q returned unsupported result type %q", query, body.Data.ResultType)
+	}
+
+	raw, ok := sample[1].(string)
+	if !ok {
+		return 0, fmt.Errorf("query %q returned a malformed sample", query)
+	}
+	val, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return 0, fmt.Errorf("query %q: %w", query, err)
+	}
+	if math.IsNaN(val) {
+		return 0, fmt.Errorf("query %q returned NaN", query)
+	}
+	return val, nil
+}
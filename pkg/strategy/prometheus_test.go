@@ -0,0 +1,103 @@
+package strategy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// promServer answers instant queries from results, keyed by the query string.
+func promServer(t *testing.T, results map[string]string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/query" {
+			http.NotFound(w, r)
+			return
+		}
+		body, ok := results[r.URL.Query().Get("query")]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"status":"error","error":"unknown query"}`))
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func vectorResult(val string) string {
+	return `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1700000000,"` + val + `"]}]}}`
+}
+
+func TestPrometheusLoadStrategy_ShouldScaleDown(t *testing.T) {
+	srv := promServer(t, map[string]string{
+		`node_load15{node="idle"}`: vectorResult("0.1"),
+		`node_load15{node="busy"}`: vectorResult("0.9"),
+		`node_load15{node="gone"}`: `{"status":"success","data":{"resultType":"vector","result":[]}}`,
+		"cluster_load":             `{"status":"success","data":{"resultType":"scalar","result":[1700000000,"0.3"]}}`,
+	})
+
+	tests := []struct {
+		name          string
+		node          string
+		downThreshold float64
+		want          bool
+		wantErr       bool
+	}{
+		{name: "idle node and cluster", node: "idle", downThreshold: 0.5, want: true},
+		{name: "node above threshold", node: "busy", downThreshold: 0.5, want: false},
+		{name: "cluster above threshold", node: "idle", downThreshold: 0.2, want: false},
+		{name: "empty node result denies", node: "gone", downThreshold: 0.5, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &PrometheusLoadStrategy{
+				URL:                srv.URL,
+				NodeQuery:          `node_load15{node="$node"}`,
+				ClusterQuery:       "cluster_load",
+				NodeThreshold:      0.5,
+				ScaleDownThreshold: tt.downThreshold,
+				Timeout:            time.Second,
+			}
+			got, err := s.ShouldScaleDown(context.Background(), tt.node)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ShouldScaleDown = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrometheusLoadStrategy_ShouldScaleUp(t *testing.T) {
+	srv := promServer(t, map[string]string{
+		"cluster_load": vectorResult("0.8"),
+		"broken":       vectorResult("NaN"),
+	})
+	candidates := func(context.Context) []string { return []string{"off-1", "off-2"} }
+
+	s := &PrometheusLoadStrategy{URL: srv.URL + "/", ClusterQuery: "cluster_load", ScaleUpThreshold: 0.75, Timeout: time.Second, ShutdownCandidates: candidates}
+	node, ok, err := s.ShouldScaleUp(context.Background())
+	if err != nil || !ok || node != "off-1" {
+		t.Fatalf("ShouldScaleUp = %q, %v, %v; want off-1, true, nil", node, ok, err)
+	}
+
+	s.ScaleUpThreshold = 0.9
+	if _, ok, _ := s.ShouldScaleUp(context.Background()); ok {
+		t.Error("expected no scale-up below the threshold")
+	}
+
+	s.ClusterQuery = "broken"
+	if _, ok, err := s.ShouldScaleUp(context.Background()); ok || err == nil {
+		t.Errorf("expected a NaN result to deny with an error, got ok=%v err=%v", ok, err)
+	}
+
+	s.ClusterQuery = "unknown"
+	if _, ok, err := s.ShouldScaleUp(context.Background()); ok || err == nil {
+		t.Errorf("expected a failed query to deny with an error, got ok=%v err=%v", ok, err)
+	}
+}
@@ -4,11 +4,13 @@ import (
 	"context"
 	"fmt"
 	"k8s.io/client-go/kubernetes"
+	"sort"
 
 	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/metrics/pkg/client/clientset/versioned"
+	"k8s.io/utils/clock"
 	"log/slog"
 )
 
@@ -18,6 +20,7 @@ type ResourceAwareScaleDown struct {
 	NodeLister    func(context.Context) ([]v1.Node, error)
 	PodLister     func(context.Context) ([]v1.Pod, error)
 	MetricsClient versioned.Interface
+	Clock         clock.PassiveClock // judges metrics age; nil means the real clock
 }
 
 func (r *ResourceAwareScaleDown) ShouldScaleDown(ctx context.Context, nodeName string) (bool, error) {
@@ -26,19 +29,31 @@ func (r *ResourceAwareScaleDown) ShouldScaleDown(ctx context.Context, nodeName s
 		return false, fmt.Errorf("listing nodes: %w", err)
 	}
 
-	pods, err := r.PodLister(ctx)
+	allPods, err := r.PodLister(ctx)
 	if err != nil {
 		return false, fmt.Errorf("listing pods: %w", err)
 	}
+	pods := r.ScopePods(allPods)
 
-	nodeUsages, err := r.MetricsClient.MetricsV1beta1().NodeMetricses().List(ctx, metav1.ListOptions{})
+	var usageMap map[string]v1.ResourceList
+	var stale []string
+	if len(r.Cfg.WorkloadNamespaces) > 0 {
+		usageMap, stale, err = r.workloadUsage(ctx, nodes, pods)
+	} else {
+		usageMap, stale, err = r.nodeUsage(ctx)
+	}
 	if err != nil {
-		return false, fmt.Errorf("fetching node metrics: %w", err)
+		return false, err
 	}
-
-	usageMap := make(map[string]v1.ResourceList)
-	for _, usage := range nodeUsages.Items {
-		usageMap[usage.Name] = usage.Usage
+	usageKnown := len(stale) == 0
+	if !usageKnown {
+		sort.Strings(stale)
+		if r.Cfg.ResourceAware.StaleMetricsPolicy == config.StaleMetricsRequests {
+			slog.Info("Stale metrics — deciding on requests only", "nodes", stale, "maxMetricsAge", r.Cfg.ResourceAware.MaxMetricsAge.String())
+		} else {
+			slog.Info("Stale metrics — denying scale-down", "nodes", stale, "maxMetricsAge", r.Cfg.ResourceAware.MaxMetricsAge.String())
+			return false, nil
+		}
 	}
 
 	totalCPURequest, totalMemRequest := r.SumRequests(pods)
@@ -48,7 +63,7 @@ func (r *ResourceAwareScaleDown) ShouldScaleDown(ctx context.Context, nodeName s
 	marginMem := clusterMem * int64(r.Cfg.ResourceBufferMemoryPerc) / 100
 
 	canScaleRequestOK := totalCPURequest+marginCPU <= clusterCPU && totalMemRequest+marginMem <= clusterMem
-	canScaleUsageOK := usedCPU+marginCPU <= clusterCPU && usedMem+marginMem <= clusterMem
+	canScaleUsageOK := !usageKnown || usedCPU+marginCPU <= clusterCPU && usedMem+marginMem <= clusterMem
 
 	slog.Info("Request-based scale-down check",
 		"canScaleRequestOK", canScaleRequestOK,
@@ -72,13 +87,174 @@ func (r *ResourceAwareScaleDown) ShouldScaleDown(ctx context.Context, nodeName s
 		"nodeCandidate", nodeName,
 	)
 
-	return canScaleRequestOK && canScaleUsageOK, nil
+	ephemeralOK := r.EphemeralStorageFits(nodes, pods, nodeName)
+
+	_, hasCandidateUsage := usageMap[nodeName]
+	candidateIdleOK := !usageKnown || !hasCandidateUsage || r.CandidateIdle(nodeName, nodeCPU, nodeMem, usedCPU, usedMem)
+
+	return canScaleRequestOK && canScaleUsageOK && ephemeralOK && candidateIdleOK, nil
+}
+
+// EphemeralStorageFits reports whether all ephemeral-storage requests, plus resourceBufferEphemeralPerc
+// of the remaining allocatable, fit on the nodes left after removing nodeName. Nodes can run out of
+// scratch space long before CPU or memory. Passes trivially when no pod requests ephemeral storage.
+func (r *ResourceAwareScaleDown) EphemeralStorageFits(nodes []v1.Node, pods []v1.Pod, nodeName string) bool {
+	var requested int64
+	for _, pod := range pods {
+		for _, c := range pod.Spec.Containers {
+			if eph := c.Resources.Requests.StorageEphemeral(); eph != nil {
+				requested += eph.Value()
+			}
+		}
+	}
+	if requested == 0 {
+		return true
+	}
+
+	var remaining int64
+	for _, node := range nodes {
+		if node.Name == nodeName {
+			continue
+		}
+		if eph := node.Status.Allocatable.StorageEphemeral(); eph != nil {
+			remaining += eph.Value()
+		}
+	}
+	margin := remaining * int64(r.Cfg.ResourceBufferEphemeralPerc) / 100
+	ok := requested+margin <= remaining
+
+	slog.Info("Ephemeral-storage scale-down check",
+		"canScaleEphemeralOK", ok,
+		"totalEphemeralRequest", requested,
+		"clusterEphemeral", remaining,
+		"bufferEphemeral", margin,
+		"nodeCandidate", nodeName,
+	)
+	return ok
+}
+
+// CandidateIdle reports whether the candidate's own usage is within resourceAware.maxCandidateUsageFraction
+// of its allocatable. Load average lags behind bursts; a node that just finished a big job still
+// shows high usage here.
+func (r *ResourceAwareScaleDown) CandidateIdle(nodeName string, nodeCPU, nodeMem, usedCPU, usedMem int64) bool {
+	maxFrac := r.Cfg.ResourceAware.MaxCandidateUsageFraction
+	if maxFrac <= 0 {
+		return true
+	}
+	var cpuFrac, memFrac float64
+	if nodeCPU > 0 {
+		cpuFrac = float64(usedCPU) / float64(nodeCPU)
+	}
+	if nodeMem > 0 {
+		memFrac = float64(usedMem) / float64(nodeMem)
+	}
+	if cpuFrac > maxFrac || memFrac > maxFrac {
+		slog.Info("Candidate node recently busy — denying scale-down",
+			"node", nodeName, "cpuUsageFraction", cpuFrac, "memUsageFraction", memFrac, "maxFraction", maxFrac)
+		return false
+	}
+	return true
 }
 
 func (r *ResourceAwareScaleDown) Name() string {
 	return "ResourceAware"
 }
 
+// ScopePods keeps only pods in the configured workloadNamespaces (all pods when unset).
+func (r *ResourceAwareScaleDown) ScopePods(pods []v1.Pod) []v1.Pod {
+	if len(r.Cfg.WorkloadNamespaces) == 0 {
+		return pods
+	}
+	scoped := make([]v1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		if r.Cfg.InWorkloadScope(pod.Namespace) {
+			scoped = append(scoped, pod)
+		}
+	}
+	return scoped
+}
+
+func (r *ResourceAwareScaleDown) nodeUsage(ctx context.Context) (map[string]v1.ResourceList, []string, error) {
+	nodeUsages, err := r.MetricsClient.MetricsV1beta1().NodeMetricses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("fetching node metrics: %w", err)
+	}
+
+	usageMap := make(map[string]v1.ResourceList)
+	var stale []string
+	for _, usage := range nodeUsages.Items {
+		if r.metricsStale(usage.Timestamp) {
+			stale = append(stale, usage.Name)
+			continue
+		}
+		usageMap[usage.Name] = usage.Usage
+	}
+	return usageMap, stale, nil
+}
+
+// metricsStale reports whether a sample taken at ts is older than resourceAware.maxMetricsAge.
+func (r *ResourceAwareScaleDown) metricsStale(ts metav1.Time) bool {
+	maxAge := r.Cfg.ResourceAware.MaxMetricsAge
+	if maxAge <= 0 {
+		return false
+	}
+	var clk clock.PassiveClock = clock.RealClock{}
+	if r.Clock != nil {
+		clk = r.Clock
+	}
+	return clk.Since(ts.Time) > maxAge
+}
+
+// workloadUsage sums pod metrics of in-scope pods per node, so co-located workloads outside
+// workloadNamespaces don't count as usage. Nodes without in-scope pods report zero usage; nodes
+// with a stale pod sample are returned as stale.
+func (r *ResourceAwareScaleDown) workloadUsage(ctx context.Context, nodes []v1.Node, pods []v1.Pod) (map[string]v1.ResourceList, []string, error) {
+	usageMap := make(map[string]v1.ResourceList, len(nodes))
+	for _, n := range nodes {
+		usageMap[n.Name] = v1.ResourceList{}
+	}
+
+	podNode := make(map[string]string, len(pods))
+	for _, pod := range pods {
+		podNode[pod.Namespace+"/"+pod.Name] = pod.Spec.NodeName
+	}
+
+	staleNodes := map[string]struct{}{}
+	for _, ns := range r.Cfg.WorkloadNamespaces {
+		podUsages, err := r.MetricsClient.MetricsV1beta1().PodMetricses(ns).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, nil, fmt.Errorf("fetching pod metrics in %s: %w", ns, err)
+		}
+		for _, pm := range podUsages.Items {
+			nodeName, ok := podNode[pm.Namespace+"/"+pm.Name]
+			if !ok || nodeName == "" {
+				continue
+			}
+			total, ok := usageMap[nodeName]
+			if !ok {
+				continue
+			}
+			if r.metricsStale(pm.Timestamp) {
+				staleNodes[nodeName] = struct{}{}
+				continue
+			}
+			for _, c := range pm.Containers {
+				for res, qty := range c.Usage {
+					sum := total[res]
+					sum.Add(qty)
+					total[res] = sum
+				}
+			}
+		}
+	}
+	var stale []string
+	for nodeName := range staleNodes {
+		delete(usageMap, nodeName)
+		stale = append(stale, nodeName)
+	}
+	return usageMap, stale, nil
+}
+
 func (r *ResourceAwareScaleDown) SumRequests(pods []v1.Pod) (int64, int64) {
 	var totalCPURequest, totalMemRequest int64
 	for _, pod := range pods {
@@ -3,14 +3,19 @@ package strategy
 import (
 	"context"
 	"testing"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
 
 	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
 	"k8s.io/metrics/pkg/client/clientset/versioned/fake"
+	clocktesting "k8s.io/utils/clock/testing"
 )
 
 func TestResourceAwareScaleDown_BlocksOnCPUOnly(t *testing.T) {
@@ -160,6 +165,60 @@ func TestResourceAwareScaleDown_BlocksShutdownIfTooTight(t *testing.T) {
 	}
 }
 
+func TestResourceAwareScaleDown_EphemeralStorage(t *testing.T) {
+	withEphemeral := func(n v1.Node, eph string) v1.Node {
+		n.Status.Allocatable[v1.ResourceEphemeralStorage] = resource.MustParse(eph)
+		return n
+	}
+	podWithEphemeral := func(name, eph, node string) v1.Pod {
+		p := newPod(name, "100m", "128Mi", node)
+		p.Spec.Containers[0].Resources.Requests[v1.ResourceEphemeralStorage] = resource.MustParse(eph)
+		return p
+	}
+
+	tests := []struct {
+		name   string
+		pods   []v1.Pod
+		buffer int
+		want   bool
+	}{
+		{"scratch-heavy pods block despite cpu/mem room", []v1.Pod{podWithEphemeral("p1", "9Gi", "node1"), podWithEphemeral("p2", "4Gi", "node2")}, 10, false},
+		{"buffer alone tips it over", []v1.Pod{podWithEphemeral("p1", "9500Mi", "node1")}, 10, false},
+		{"fits with buffer", []v1.Pod{podWithEphemeral("p1", "4Gi", "node1"), podWithEphemeral("p2", "4Gi", "node2")}, 10, true},
+		{"no ephemeral requests", []v1.Pod{newPod("p1", "100m", "128Mi", "node1")}, 10, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strat := &ResourceAwareScaleDown{
+				Cfg: &config.Config{
+					ResourceBufferCPUPerc:       10,
+					ResourceBufferMemoryPerc:    10,
+					ResourceBufferEphemeralPerc: tt.buffer,
+				},
+				NodeLister: func(ctx context.Context) ([]v1.Node, error) {
+					return []v1.Node{
+						withEphemeral(newNode("node1", "8", "32Gi"), "10Gi"),
+						withEphemeral(newNode("node2", "8", "32Gi"), "10Gi"), // candidate
+					}, nil
+				},
+				PodLister: func(ctx context.Context) ([]v1.Pod, error) {
+					return tt.pods, nil
+				},
+				MetricsClient: fake.NewSimpleClientset(),
+			}
+
+			ok, err := strat.ShouldScaleDown(context.Background(), "node2")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ok != tt.want {
+				t.Errorf("ShouldScaleDown = %v, want %v", ok, tt.want)
+			}
+		})
+	}
+}
+
 func newNode(name string, cpu string, mem string) v1.Node {
 	return v1.Node{
 		ObjectMeta: metav1.ObjectMeta{Name: name},
@@ -191,3 +250,248 @@ func newPod(name string, cpu string, mem string, nodeName string) v1.Pod {
 		},
 	}
 }
+
+func metricsClientWithUsage(usage map[string][2]string) *fake.Clientset {
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("list", "nodes", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		list := &metricsv1beta1.NodeMetricsList{}
+		for name, u := range usage {
+			list.Items = append(list.Items, metricsv1beta1.NodeMetrics{
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+				Usage: v1.ResourceList{
+					v1.ResourceCPU:    resource.MustParse(u[0]),
+					v1.ResourceMemory: resource.MustParse(u[1]),
+				},
+			})
+		}
+		return true, list, nil
+	})
+	return client
+}
+
+func TestResourceAwareScaleDown_CandidateUsageFraction(t *testing.T) {
+	tests := []struct {
+		name      string
+		candidate [2]string // cpu, memory usage of node2
+		want      bool
+	}{
+		{"idle candidate allowed", [2]string{"200m", "1Gi"}, true},
+		{"busy CPU denies", [2]string{"1500m", "1Gi"}, false},
+		{"busy memory denies", [2]string{"200m", "6Gi"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strat := &ResourceAwareScaleDown{
+				Cfg: &config.Config{
+					ResourceBufferCPUPerc:    10,
+					ResourceBufferMemoryPerc: 10,
+					ResourceAware:            config.ResourceAwareConfig{MaxCandidateUsageFraction: 0.5},
+				},
+				NodeLister: func(ctx context.Context) ([]v1.Node, error) {
+					return []v1.Node{
+						newNode("node1", "4", "16Gi"),
+						newNode("node2", "2", "8Gi"),
+					}, nil
+				},
+				PodLister: func(ctx context.Context) ([]v1.Pod, error) {
+					return nil, nil
+				},
+				MetricsClient: metricsClientWithUsage(map[string][2]string{
+					"node1": {"100m", "1Gi"},
+					"node2": tt.candidate,
+				}),
+			}
+
+			ok, err := strat.ShouldScaleDown(context.Background(), "node2")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ok != tt.want {
+				t.Errorf("ShouldScaleDown = %v, want %v", ok, tt.want)
+			}
+		})
+	}
+}
+
+func inNamespace(pod v1.Pod, ns string) v1.Pod {
+	pod.Namespace = ns
+	return pod
+}
+
+func TestResourceAwareScaleDown_WorkloadNamespacesScopeRequests(t *testing.T) {
+	pods := []v1.Pod{
+		inNamespace(newPod("tenant-pod", "500m", "1Gi", "node1"), "tenant"),
+		inNamespace(newPod("other-pod", "1900m", "1Gi", "node1"), "other"),
+	}
+
+	tests := []struct {
+		name       string
+		namespaces []string
+		want       bool
+	}{
+		{"all namespaces count", nil, false},
+		{"out-of-scope pods excluded", []string{"tenant"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strat := &ResourceAwareScaleDown{
+				Cfg: &config.Config{
+					ResourceBufferCPUPerc:    10,
+					ResourceBufferMemoryPerc: 10,
+					WorkloadNamespaces:       tt.namespaces,
+				},
+				NodeLister: func(ctx context.Context) ([]v1.Node, error) {
+					return []v1.Node{
+						newNode("node1", "2000m", "8Gi"),
+						newNode("node2", "2000m", "8Gi"),
+					}, nil
+				},
+				PodLister: func(ctx context.Context) ([]v1.Pod, error) {
+					return pods, nil
+				},
+				MetricsClient: fake.NewSimpleClientset(),
+			}
+
+			ok, err := strat.ShouldScaleDown(context.Background(), "node2")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ok != tt.want {
+				t.Errorf("ShouldScaleDown = %v, want %v", ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestResourceAwareScaleDown_WorkloadNamespacesScopeUsage(t *testing.T) {
+	pods := []v1.Pod{
+		inNamespace(newPod("tenant-pod", "100m", "100Mi", "node2"), "tenant"),
+		inNamespace(newPod("other-pod", "100m", "100Mi", "node2"), "other"),
+	}
+	podUsage := map[string]string{"tenant/tenant-pod": "200m", "other/other-pod": "1800m"}
+
+	// Node-level metrics show node2 busy because of the other tenant's pod.
+	metrics := metricsClientWithUsage(map[string][2]string{
+		"node1": {"100m", "1Gi"},
+		"node2": {"2000m", "1Gi"},
+	})
+	metrics.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		list := &metricsv1beta1.PodMetricsList{}
+		for _, pod := range pods {
+			if pod.Namespace != action.GetNamespace() {
+				continue
+			}
+			list.Items = append(list.Items, metricsv1beta1.PodMetrics{
+				ObjectMeta: metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace},
+				Containers: []metricsv1beta1.ContainerMetrics{{
+					Name: pod.Name + "-container",
+					Usage: v1.ResourceList{
+						v1.ResourceCPU:    resource.MustParse(podUsage[pod.Namespace+"/"+pod.Name]),
+						v1.ResourceMemory: resource.MustParse("100Mi"),
+					},
+				}},
+			})
+		}
+		return true, list, nil
+	})
+
+	tests := []struct {
+		name       string
+		namespaces []string
+		want       bool
+	}{
+		{"node-level usage denies", nil, false},
+		{"only in-scope pod usage counts", []string{"tenant"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strat := &ResourceAwareScaleDown{
+				Cfg: &config.Config{
+					ResourceBufferCPUPerc:    10,
+					ResourceBufferMemoryPerc: 10,
+					ResourceAware:            config.ResourceAwareConfig{MaxCandidateUsageFraction: 0.5},
+					WorkloadNamespaces:       tt.namespaces,
+				},
+				NodeLister: func(ctx context.Context) ([]v1.Node, error) {
+					return []v1.Node{
+						newNode("node1", "4", "16Gi"),
+						newNode("node2", "2", "8Gi"),
+					}, nil
+				},
+				PodLister: func(ctx context.Context) ([]v1.Pod, error) {
+					return pods, nil
+				},
+				MetricsClient: metrics,
+			}
+
+			ok, err := strat.ShouldScaleDown(context.Background(), "node2")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ok != tt.want {
+				t.Errorf("ShouldScaleDown = %v, want %v", ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestResourceAwareScaleDown_StaleMetrics(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		age    time.Duration // age of node1's sample; node2 is always fresh
+		policy string
+		want   bool
+	}{
+		{"fresh metrics allow", 10 * time.Second, config.StaleMetricsDeny, true},
+		{"stale metrics deny", 5 * time.Minute, config.StaleMetricsDeny, false},
+		{"stale metrics fall back to requests", 5 * time.Minute, config.StaleMetricsRequests, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset()
+			client.PrependReactor("list", "nodes", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				sample := func(name string, age time.Duration) metricsv1beta1.NodeMetrics {
+					return metricsv1beta1.NodeMetrics{
+						ObjectMeta: metav1.ObjectMeta{Name: name},
+						Timestamp:  metav1.NewTime(now.Add(-age)),
+						Usage: v1.ResourceList{
+							v1.ResourceCPU:    resource.MustParse("100m"),
+							v1.ResourceMemory: resource.MustParse("1Gi"),
+						},
+					}
+				}
+				return true, &metricsv1beta1.NodeMetricsList{Items: []metricsv1beta1.NodeMetrics{
+					sample("node1", tt.age), sample("node2", 0),
+				}}, nil
+			})
+			strat := &ResourceAwareScaleDown{
+				Cfg: &config.Config{
+					ResourceBufferCPUPerc:    10,
+					ResourceBufferMemoryPerc: 10,
+					ResourceAware:            config.ResourceAwareConfig{MaxMetricsAge: time.Minute, StaleMetricsPolicy: tt.policy},
+				},
+				NodeLister: func(ctx context.Context) ([]v1.Node, error) {
+					return []v1.Node{newNode("node1", "4", "16Gi"), newNode("node2", "2", "8Gi")}, nil
+				},
+				PodLister: func(ctx context.Context) ([]v1.Pod, error) {
+					return []v1.Pod{newPod("p", "500m", "1Gi", "node1")}, nil
+				},
+				MetricsClient: client,
+				Clock:         clocktesting.NewFakePassiveClock(now),
+			}
+
+			ok, err := strat.ShouldScaleDown(context.Background(), "node2")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ok != tt.want {
+				t.Errorf("ShouldScaleDown = %v, want %v", ok, tt.want)
+			}
+		})
+	}
+}
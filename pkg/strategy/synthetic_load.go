@@ -0,0 +1,64 @@
+package strategy
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	"k8s.io/utils/clock"
+)
+
+// NodeLoadSource supplies normalized per-node load in place of the metrics DaemonSet.
+type NodeLoadSource interface {
+	NodeLoad(ctx context.Context, nodeName string) (float64, error)
+}
+
+// SyntheticLoadSource replays a scripted load profile, for CI and demos. Time is measured from Start.
+type SyntheticLoadSource struct {
+	Profile config.SyntheticLoadProfile
+	Start   time.Time
+	Clock   clock.PassiveClock // nil: real clock
+}
+
+// NewSyntheticLoadSource starts replaying profile now.
+func NewSyntheticLoadSource(profile config.SyntheticLoadProfile, clk clock.PassiveClock) *SyntheticLoadSource {
+	if clk == nil {
+		clk = clock.RealClock{}
+	}
+	return &SyntheticLoadSource{Profile: profile, Start: clk.Now(), Clock: clk}
+}
+
+func (s *SyntheticLoadSource) NodeLoad(_ context.Context, nodeName string) (float64, error) {
+	curve, ok := s.Profile.Nodes[nodeName]
+	if !ok {
+		curve = s.Profile.Cluster
+	}
+	if len(curve) == 0 {
+		return 0, fmt.Errorf("synthetic profile has no curve for node %s", nodeName)
+	}
+	clk := s.Clock
+	if clk == nil {
+		clk = clock.RealClock{}
+	}
+	return loadAt(curve, clk.Since(s.Start), s.Profile.Loop), nil
+}
+
+// loadAt interpolates curve linearly at elapsed. Points are sorted by At (config validation).
+func loadAt(curve []config.LoadPoint, elapsed time.Duration, loop bool) float64 {
+	last := curve[len(curve)-1]
+	if loop && last.At > 0 {
+		elapsed %= last.At
+	}
+	if elapsed <= curve[0].At {
+		return curve[0].Load
+	}
+	for i := 1; i < len(curve); i++ {
+		prev, next := curve[i-1], curve[i]
+		if elapsed <= next.At {
+			frac := float64(elapsed-prev.At) / float64(next.At-prev.At)
+			return prev.Load + frac*(next.Load-prev.Load)
+		}
+	}
+	return last.Load
+}
@@ -0,0 +1,50 @@
+package strategy
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/docent-net/cluster-bare-autoscaler/pkg/config"
+	clocktesting "k8s.io/utils/clock/testing"
+)
+
+func TestSyntheticLoadSource_NodeLoad(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	profile := config.SyntheticLoadProfile{
+		Cluster: []config.LoadPoint{{At: 0, Load: 0.2}, {At: 10 * time.Minute, Load: 0.8}},
+		Nodes:   map[string][]config.LoadPoint{"hot": {{At: time.Minute, Load: 1.5}}},
+	}
+	tests := []struct {
+		name    string
+		node    string
+		elapsed time.Duration
+		loop    bool
+		want    float64
+	}{
+		{"cluster start", "a", 0, false, 0.2},
+		{"cluster interpolated", "a", 5 * time.Minute, false, 0.5},
+		{"cluster holds last point", "a", time.Hour, false, 0.8},
+		{"cluster loops", "a", 12*time.Minute + 30*time.Second, true, 0.35},
+		{"node curve before first point", "hot", 0, false, 1.5},
+		{"node curve overrides cluster", "hot", time.Hour, false, 1.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clk := clocktesting.NewFakePassiveClock(start)
+			p := profile
+			p.Loop = tt.loop
+			src := NewSyntheticLoadSource(p, clk)
+			clk.SetTime(start.Add(tt.elapsed))
+
+			got, err := src.NodeLoad(context.Background(), tt.node)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Fatalf("expected load %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
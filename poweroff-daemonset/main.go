@@ -10,26 +10,6 @@ import (
 	"strings"
 )
 
-const shutdownSocket = "/run/cba-shutdown.sock"
-
-func shutdownHandler(w http.ResponseWriter, r *http.Request) {
-	go func() {
-		log.Println("Received shutdown request, sending to systemd socket...")
-
-		conn, err := net.Dial("unix", shutdownSocket)
-		if err != nil {
-			log.Printf("Failed to dial systemd socket: %v", err)
-			return
-		}
-		defer conn.Close()
-
-		_, _ = conn.Write([]byte("shutdown\n"))
-	}()
-
-	w.WriteHeader(http.StatusOK)
-	fmt.Fprintln(w, "Shutdown signal sent via systemd socket")
-}
-
 func findMainInterfaceAndMAC() (string, string, error) {
 	data, err := os.ReadFile("/proc/net/route")
 	if err != nil {
@@ -62,6 +42,29 @@ func findMainInterfaceAndMAC() (string, string, error) {
 	return mainIface, iface.HardwareAddr.String(), nil
 }
 
+type nicAddress struct {
+	Name string `json:"name"`
+	MAC  string `json:"mac"`
+}
+
+// listInterfaceMACs returns every non-loopback interface that has a hardware address,
+// so the controller can pick a WOL-capable NIC on multi-homed nodes.
+func listInterfaceMACs() []nicAddress {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		log.Println("[/mac] Failed to list interfaces:", err)
+		return nil
+	}
+	var out []nicAddress
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagLoopback != 0 || len(iface.HardwareAddr) == 0 {
+			continue
+		}
+		out = append(out, nicAddress{Name: iface.Name, MAC: iface.HardwareAddr.String()})
+	}
+	return out
+}
+
 func macHandler(w http.ResponseWriter, r *http.Request) {
 	iface, mac, err := findMainInterfaceAndMAC()
 	if err != nil {
@@ -70,16 +73,25 @@ func macHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp := map[string]string{
-		"interface": iface,
-		"mac":       mac,
+	resp := struct {
+		Interface  string       `json:"interface"`
+		MAC        string       `json:"mac"`
+		Interfaces []nicAddress `json:"interfaces"`
+	}{
+		Interface:  iface,
+		MAC:        mac,
+		Interfaces: listInterfaceMACs(),
 	}
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(resp)
 }
 
 func main() {
-	http.HandleFunc("/shutdown", shutdownHandler)
+	s, err := shutdownerFromEnv()
+	if err != nil {
+		log.Fatalf("Invalid shutdown configuration: %v", err)
+	}
+	http.HandleFunc("/shutdown", s.handler)
 	http.HandleFunc("/mac", macHandler)
 	log.Println("Listening on :9101 for requests")
 	if err := http.ListenAndServe(":9101", nil); err != nil {
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Shutdown mechanisms, selected with SHUTDOWN_MODE.
+const (
+	modeSocket  = "socket"  // write to the host's socket-activated cba-shutdown.service (default)
+	modeCommand = "command" // chroot into the host root and run SHUTDOWN_COMMAND
+	modeLogind  = "logind"  // call org.freedesktop.login1.Manager.PowerOff on the host's system bus
+)
+
+const (
+	defaultShutdownSocket  = "/run/cba-shutdown.sock"
+	defaultHostRoot        = "/host"
+	defaultShutdownCommand = "systemctl poweroff"
+	shutdownTimeout        = 30 * time.Second
+)
+
+// executor runs a command to completion; tests replace it.
+type executor func(ctx context.Context, name string, args ...string) error
+
+func runCommand(ctx context.Context, name string, args ...string) error {
+	out, err := exec.CommandContext(ctx, name, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s: %w: %s", name, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// shutdowner triggers a host power-off using one of the SHUTDOWN_MODE mechanisms.
+type shutdowner struct {
+	mode     string
+	socket   string
+	hostRoot string
+	command  []string
+
+	exec executor
+	dial func(network, address string) (net.Conn, error)
+}
+
+// shutdownerFromEnv reads SHUTDOWN_MODE, SHUTDOWN_SOCKET, HOST_ROOT and SHUTDOWN_COMMAND.
+func shutdownerFromEnv() (*shutdowner, error) {
+	s := &shutdowner{
+		mode:     envOr("SHUTDOWN_MODE", modeSocket),
+		socket:   envOr("SHUTDOWN_SOCKET", defaultShutdownSocket),
+		hostRoot: envOr("HOST_ROOT", defaultHostRoot),
+		command:  strings.Fields(envOr("SHUTDOWN_COMMAND", defaultShutdownCommand)),
+		exec:     runCommand,
+		dial:     net.Dial,
+	}
+	switch s.mode {
+	case modeSocket, modeLogind:
+	case modeCommand:
+		if len(s.command) == 0 {
+			return nil, fmt.Errorf("SHUTDOWN_COMMAND must not be empty in %s mode", modeCommand)
+		}
+	default:
+		return nil, fmt.Errorf("unknown SHUTDOWN_MODE %q (want %s, %s or %s)", s.mode, modeSocket, modeCommand, modeLogind)
+	}
+	return s, nil
+}
+
+func envOr(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+// shutdown powers the host off with the configured mechanism.
+func (s *shutdowner) shutdown(ctx context.Context) error {
+	switch s.mode {
+	case modeCommand:
+		return s.exec(ctx, "chroot", append([]string{s.hostRoot}, s.command...)...)
+	case modeLogind:
+		return s.exec(ctx, "dbus-send", "--system", "--print-reply",
+			"--dest=org.freedesktop.login1", "/org/freedesktop/login1",
+			"org.freedesktop.login1.Manager.PowerOff", "boolean:false")
+	default:
+		conn, err := s.dial("unix", s.socket)
+		if err != nil {
+			return fmt.Errorf("dialing systemd socket: %w", err)
+		}
+		defer conn.Close()
+		_, err = conn.Write([]byte("shutdown\n"))
+		return err
+	}
+}
+
+// handler answers right away and powers off in the background, so the reply makes it out before
+// the host goes down.
+func (s *shutdowner) handler(w http.ResponseWriter, _ *http.Request) {
+	go func() {
+		log.Printf("Received shutdown request, using %s mode...", s.mode)
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := s.shutdown(ctx); err != nil {
+			log.Printf("Shutdown via %s failed: %v", s.mode, err)
+		}
+	}()
+
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "Shutdown signal sent via %s\n", s.mode)
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type recordedCall struct {
+	name string
+	args []string
+}
+
+func recordingExecutor(calls chan<- recordedCall) executor {
+	return func(_ context.Context, name string, args ...string) error {
+		calls <- recordedCall{name: name, args: args}
+		return nil
+	}
+}
+
+func TestShutdowner_Dispatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     string
+		wantName string
+		wantArgs []string
+	}{
+		{
+			name:     "command mode chroots into the host",
+			mode:     modeCommand,
+			wantName: "chroot",
+			wantArgs: []string{"/host", "systemctl", "poweroff"},
+		},
+		{
+			name:     "logind mode calls PowerOff over DBus",
+			mode:     modeLogind,
+			wantName: "dbus-send",
+			wantArgs: []string{"--system", "--print-reply", "--dest=org.freedesktop.login1", "/org/freedesktop/login1",
+				"org.freedesktop.login1.Manager.PowerOff", "boolean:false"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SHUTDOWN_MODE", tt.mode)
+			s, err := shutdownerFromEnv()
+			if err != nil {
+				t.Fatalf("shutdownerFromEnv: %v", err)
+			}
+			calls := make(chan recordedCall, 1)
+			s.exec = recordingExecutor(calls)
+			s.dial = func(string, string) (net.Conn, error) {
+				t.Error("socket must not be dialed outside socket mode")
+				return nil, nil
+			}
+
+			rec := httptest.NewRecorder()
+			s.handler(rec, httptest.NewRequest(http.MethodPost, "/shutdown", nil))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200", rec.Code)
+			}
+
+			select {
+			case c := <-calls:
+				if c.name != tt.wantName || !reflect.DeepEqual(c.args, tt.wantArgs) {
+					t.Errorf("ran %s %v, want %s %v", c.name, c.args, tt.wantName, tt.wantArgs)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("shutdown was not triggered")
+			}
+		})
+	}
+}
+
+func TestShutdowner_SocketModeIsDefault(t *testing.T) {
+	t.Setenv("SHUTDOWN_MODE", "")
+	s, err := shutdownerFromEnv()
+	if err != nil {
+		t.Fatalf("shutdownerFromEnv: %v", err)
+	}
+	server, client := net.Pipe()
+	dialed := make(chan string, 1)
+	s.dial = func(network, address string) (net.Conn, error) {
+		dialed <- network + ":" + address
+		return client, nil
+	}
+	s.exec = func(context.Context, string, ...string) error {
+		t.Error("no command must run in socket mode")
+		return nil
+	}
+
+	got := make(chan string, 1)
+	go func() {
+		buf := make([]byte, 64)
+		n, _ := server.Read(buf)
+		got <- string(buf[:n])
+	}()
+	if err := s.shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+	if addr := <-dialed; addr != "unix:/run/cba-shutdown.sock" {
+		t.Errorf("dialed %s, want unix:/run/cba-shutdown.sock", addr)
+	}
+	if msg := <-got; msg != "shutdown\n" {
+		t.Errorf("wrote %q, want %q", msg, "shutdown\n")
+	}
+}
+
+func TestShutdownerFromEnv_RejectsUnknownMode(t *testing.T) {
+	t.Setenv("SHUTDOWN_MODE", "acpi")
+	if _, err := shutdownerFromEnv(); err == nil {
+		t.Fatal("expected error for unknown SHUTDOWN_MODE, got none")
+	}
+}